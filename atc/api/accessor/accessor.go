@@ -102,9 +102,10 @@ func (a *access) rolesForTeam(auth atc.TeamAuth) []string {
 	for role, auth := range auth {
 		userAuth := auth["users"]
 		groupAuth := auth["groups"]
+		teamAuth := auth["teams"]
 
 		// backwards compatibility for allow-all-users
-		if len(userAuth) == 0 && len(groupAuth) == 0 {
+		if len(userAuth) == 0 && len(groupAuth) == 0 && len(teamAuth) == 0 {
 			roleSet[role] = true
 		}
 
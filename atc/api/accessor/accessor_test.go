@@ -172,6 +172,21 @@ var _ = Describe("Accessor", func() {
 					Expect(result).To(BeTrue())
 				})
 			})
+
+			Context("when the team only grants a role to other teams", func() {
+				BeforeEach(func() {
+					fakeTeam1.NameReturns("some-team")
+					fakeTeam1.AuthReturns(atc.TeamAuth{
+						"pipeline-operator": map[string][]string{
+							"teams": {"other-team"},
+						},
+					})
+				})
+
+				It("returns false", func() {
+					Expect(result).To(BeFalse())
+				})
+			})
 		})
 	})
 
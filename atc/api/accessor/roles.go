@@ -14,6 +14,8 @@ const (
 var DefaultRoles = map[string]string{
 	atc.SaveConfig:                    MemberRole,
 	atc.GetConfig:                     ViewerRole,
+	atc.DiffConfig:                    ViewerRole,
+	atc.WhatIfConfig:                  ViewerRole,
 	atc.GetCC:                         ViewerRole,
 	atc.GetBuild:                      ViewerRole,
 	atc.GetBuildPlan:                  ViewerRole,
@@ -21,6 +23,8 @@ var DefaultRoles = map[string]string{
 	atc.ListBuilds:                    ViewerRole,
 	atc.BuildEvents:                   ViewerRole,
 	atc.BuildResources:                ViewerRole,
+	atc.GetBuildInputs:                ViewerRole,
+	atc.GetBuildAnnotations:           ViewerRole,
 	atc.AbortBuild:                    OperatorRole,
 	atc.GetBuildPreparation:           ViewerRole,
 	atc.GetJob:                        ViewerRole,
@@ -69,6 +73,7 @@ var DefaultRoles = map[string]string{
 	atc.ListPipelineBuilds:            ViewerRole,
 	atc.CreatePipelineBuild:           MemberRole,
 	atc.PipelineBadge:                 ViewerRole,
+	atc.GetPipelineOwnership:          ViewerRole,
 	atc.RegisterWorker:                MemberRole,
 	atc.LandWorker:                    MemberRole,
 	atc.RetireWorker:                  MemberRole,
@@ -642,6 +642,168 @@ var _ = Describe("Builds API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/builds/:build_id/inputs", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.TeamNameReturns("some-team")
+			build.JobIDReturns(42)
+			build.JobNameReturns("job1")
+			build.PipelineIDReturns(42)
+			build.PipelineReturns(fakePipeline, true, nil)
+			dbBuildFactory.BuildReturns(build, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/builds/3/inputs")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated and the pipeline is private", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(false)
+				fakePipeline.PublicReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated and authorized", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(true)
+				fakeAccess.IsAuthorizedReturns(true)
+			})
+
+			Context("when the build has set pipeline artifacts", func() {
+				BeforeEach(func() {
+					build.SetPipelineArtifactsReturns([]atc.SetPipelineArtifact{
+						{
+							Name:       "some-resource",
+							Path:       "pipeline.yml",
+							Digest:     "sha256:abc",
+							SizeBytes:  42,
+							StreamedAt: 1234,
+						},
+					}, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns Content-Type 'application/json'", func() {
+					expectedHeaderEntries := map[string]string{
+						"Content-Type": "application/json",
+					}
+					Expect(response).Should(IncludeHeaderEntries(expectedHeaderEntries))
+				})
+
+				It("returns the artifacts", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"set_pipeline.artifacts": [
+							{
+								"name": "some-resource",
+								"path": "pipeline.yml",
+								"digest": "sha256:abc",
+								"size_bytes": 42,
+								"streamed_at": 1234
+							}
+						]
+					}`))
+				})
+			})
+
+			Context("when getting the artifacts fails", func() {
+				BeforeEach(func() {
+					build.SetPipelineArtifactsReturns(nil, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/annotations", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.TeamNameReturns("some-team")
+			build.JobIDReturns(42)
+			build.JobNameReturns("job1")
+			build.PipelineIDReturns(42)
+			build.PipelineReturns(fakePipeline, true, nil)
+			dbBuildFactory.BuildReturns(build, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/builds/3/annotations")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated and the pipeline is private", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(false)
+				fakePipeline.PublicReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated and authorized", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(true)
+				fakeAccess.IsAuthorizedReturns(true)
+			})
+
+			Context("when the build has annotations", func() {
+				BeforeEach(func() {
+					build.AnnotationsReturns(map[string]string{"set_pipeline_version": "3"}, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns Content-Type 'application/json'", func() {
+					expectedHeaderEntries := map[string]string{
+						"Content-Type": "application/json",
+					}
+					Expect(response).Should(IncludeHeaderEntries(expectedHeaderEntries))
+				})
+
+				It("returns the annotations", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{"set_pipeline_version": "3"}`))
+				})
+			})
+
+			Context("when getting the annotations fails", func() {
+				BeforeEach(func() {
+					build.AnnotationsReturns(nil, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
 	Describe("GET /api/v1/builds/:build_id/resources", func() {
 		var response *http.Response
 
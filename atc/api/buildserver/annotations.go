@@ -0,0 +1,30 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc/db"
+)
+
+func (s *Server) GetBuildAnnotations(build db.Build) http.Handler {
+	logger := s.logger.Session("get-build-annotations")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		annotations, err := build.Annotations()
+		if err != nil {
+			logger.Error("failed-to-get-build-annotations", err, lager.Data{"buildID": r.FormValue(":build_id")})
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(annotations)
+		if err != nil {
+			logger.Error("failed-to-encode-build-annotations", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+func (s *Server) GetBuildInputs(build db.Build) http.Handler {
+	logger := s.logger.Session("get-build-inputs")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		artifacts, err := build.SetPipelineArtifacts()
+		if err != nil {
+			logger.Error("failed-to-get-set-pipeline-artifacts", err, lager.Data{"buildID": r.FormValue(":build_id")})
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(atc.BuildInputs{
+			SetPipelineArtifacts: artifacts,
+		})
+		if err != nil {
+			logger.Error("failed-to-encode-build-inputs", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
@@ -415,6 +415,27 @@ var _ = Describe("Config API", func() {
 
 			})
 
+			Context("when the pipeline is frozen", func() {
+				BeforeEach(func() {
+					fakePipeline.FrozenReturns(true)
+
+					request.Header.Set("Content-Type", "application/json")
+
+					payload, err := json.Marshal(pipelineConfig)
+					Expect(err).NotTo(HaveOccurred())
+
+					request.Body = gbytes.BufferWithBytes(payload)
+				})
+
+				It("returns 423", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusLocked))
+				})
+
+				It("does not save anything", func() {
+					Expect(dbTeam.SavePipelineCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when a config version is specified", func() {
 				BeforeEach(func() {
 					request.Header.Set(atc.ConfigVersionHeader, "42")
@@ -1194,7 +1215,9 @@ jobs:
 								{
 									"name":   "some-job",
 									"public": true,
-									"plan":   []atc.Step{},
+									"plan": []map[string]interface{}{
+										{"task": "some-task", "file": "some/config/path.yml"},
+									},
 								},
 							},
 						})
@@ -1222,9 +1245,16 @@ jobs:
 						Expect(savedConfig).To(Equal(atc.Config{
 							Jobs: atc.JobConfigs{
 								{
-									Name:         "some-job",
-									Public:       true,
-									PlanSequence: []atc.Step{},
+									Name:   "some-job",
+									Public: true,
+									PlanSequence: []atc.Step{
+										{
+											Config: &atc.TaskStep{
+												Name:       "some-task",
+												ConfigPath: "some/config/path.yml",
+											},
+										},
+									},
 								},
 							},
 						}))
@@ -1319,4 +1349,257 @@ jobs:
 			})
 		})
 	})
+
+	Describe("POST /api/v1/teams/:team_name/pipelines/:name/diff", func() {
+		var (
+			request  *http.Request
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			payload, err := yaml.Marshal(pipelineConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = requestGenerator.CreateRequest(atc.DiffConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, bytes.NewBuffer(payload))
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Header.Set("Content-Type", "application/x-yaml")
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(true)
+				fakeAccess.IsAuthorizedReturns(true)
+			})
+
+			Context("when the pipeline is found", func() {
+				var fakePipeline *dbfakes.FakePipeline
+
+				BeforeEach(func() {
+					existingConfig := pipelineConfig
+					existingConfig.Resources = atc.ResourceConfigs{
+						{
+							Name:   "some-resource",
+							Type:   "some-type",
+							Source: atc.Source{"source-config": "some-other-value"},
+						},
+						{
+							Name: "removed-resource",
+							Type: "some-type",
+						},
+					}
+					existingConfig.Jobs = nil
+
+					fakePipeline = new(dbfakes.FakePipeline)
+					fakePipeline.ConfigReturns(existingConfig, nil)
+					dbTeam.PipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns Content-Type 'application/json'", func() {
+					expectedHeaderEntries := map[string]string{
+						"Content-Type": "application/json",
+					}
+					Expect(response).Should(IncludeHeaderEntries(expectedHeaderEntries))
+				})
+
+				It("returns the diff against the current config", func() {
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{
+						"added": [{"type": "job", "name": "some-job"}],
+						"removed": [{"type": "resource", "name": "removed-resource"}],
+						"modified": [{"type": "resource", "name": "some-resource"}]
+					}`))
+				})
+
+				It("does not save the config", func() {
+					Expect(dbTeam.SavePipelineCallCount()).To(Equal(0))
+				})
+
+				Context("when getting the config fails", func() {
+					BeforeEach(func() {
+						fakePipeline.ConfigReturns(atc.Config{}, errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the pipeline is not found", func() {
+				BeforeEach(func() {
+					dbTeam.PipelineReturns(nil, false, nil)
+				})
+
+				It("reports everything as added", func() {
+					var diff atc.DiffConfigResponse
+					err := json.NewDecoder(response.Body).Decode(&diff)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(diff.Added).To(ConsistOf(
+						atc.ConfigDiffEntry{Type: "group", Name: "some-group"},
+						atc.ConfigDiffEntry{Type: "var_source", Name: "some"},
+						atc.ConfigDiffEntry{Type: "resource", Name: "some-resource"},
+						atc.ConfigDiffEntry{Type: "resource_type", Name: "custom-resource"},
+						atc.ConfigDiffEntry{Type: "job", Name: "some-job"},
+					))
+					Expect(diff.Removed).To(BeEmpty())
+					Expect(diff.Modified).To(BeEmpty())
+				})
+			})
+
+			Context("when the team is not found", func() {
+				BeforeEach(func() {
+					dbTeamFactory.FindTeamReturns(nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the config is invalid", func() {
+				BeforeEach(func() {
+					payload := []byte(`{"jobs": [{"name": "some-job"}]}`)
+
+					request.Body = gbytes.BufferWithBytes(payload)
+					request.ContentLength = int64(len(payload))
+					request.Header.Set("Content-Type", "application/json")
+				})
+
+				It("returns 400 with the validation errors", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{
+						"errors": ["invalid jobs:\n\tjobs.some-job has no steps in its plan\n"]
+					}`))
+				})
+			})
+
+			Context("when the Content-Type is unsupported", func() {
+				BeforeEach(func() {
+					request.Header.Set("Content-Type", "application/x-toml")
+				})
+
+				It("returns Unsupported Media Type", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnsupportedMediaType))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("POST /api/v1/teams/:team_name/pipelines/:name/what-if", func() {
+		var (
+			request  *http.Request
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			payload, err := yaml.Marshal(pipelineConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = requestGenerator.CreateRequest(atc.WhatIfConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, bytes.NewBuffer(payload))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(true)
+				fakeAccess.IsAuthorizedReturns(true)
+			})
+
+			Context("when the pipeline is found", func() {
+				BeforeEach(func() {
+					existingConfig := pipelineConfig
+					existingConfig.Resources = atc.ResourceConfigs{
+						{
+							Name:   "some-resource",
+							Type:   "some-type",
+							Source: atc.Source{"source-config": "some-other-value"},
+						},
+						{
+							Name: "removed-resource",
+							Type: "some-type",
+						},
+					}
+					existingConfig.Jobs = nil
+
+					fakePipeline := new(dbfakes.FakePipeline)
+					fakePipeline.ConfigReturns(existingConfig, nil)
+					dbTeam.PipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("returns the same diff as the diff endpoint for the raw YAML body", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{
+						"added": [{"type": "job", "name": "some-job"}],
+						"removed": [{"type": "resource", "name": "removed-resource"}],
+						"modified": [{"type": "resource", "name": "some-resource"}]
+					}`))
+				})
+
+				It("looks up the pipeline of the team", func() {
+					Expect(dbTeamFactory.FindTeamArgsForCall(0)).To(Equal("a-team"))
+					Expect(dbTeam.PipelineArgsForCall(0)).To(Equal(atc.PipelineRef{Name: "a-pipeline"}))
+				})
+
+				It("does not save the config", func() {
+					Expect(dbTeam.SavePipelineCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the config is malformed", func() {
+				BeforeEach(func() {
+					payload := []byte(`{{{`)
+
+					request.Body = gbytes.BufferWithBytes(payload)
+					request.ContentLength = int64(len(payload))
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
 })
@@ -0,0 +1,114 @@
+package configserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/configvalidate"
+	"github.com/tedsuo/rata"
+)
+
+// DiffConfig compares the config in the request body against the pipeline's
+// current config without saving it. A pipeline that does not exist yet is
+// compared against an empty config. A body without a Content-Type is read as
+// raw pipeline YAML.
+func (s *Server) DiffConfig(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("diff-config")
+
+	var config atc.Config
+	switch r.Header.Get("Content-type") {
+	case "", "application/json", "application/x-yaml":
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			s.handleBadRequest(w, fmt.Sprintf("read failed: %s", err))
+			return
+		}
+
+		err = atc.UnmarshalConfig(body, &config)
+		if err != nil {
+			logger.Error("malformed-request-payload", err, lager.Data{
+				"content-type": r.Header.Get("Content-Type"),
+			})
+
+			s.handleBadRequest(w, fmt.Sprintf("malformed config: %s", err))
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	_, errorMessages := configvalidate.Validate(config)
+	if len(errorMessages) > 0 {
+		logger.Info("ignoring-invalid-config", lager.Data{"errors": errorMessages})
+		s.handleBadRequest(w, errorMessages...)
+		return
+	}
+
+	existingConfig, ok := s.existingConfig(logger, w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(existingConfig.DiffSummary(config))
+	if err != nil {
+		logger.Error("failed-to-encode-diff", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// existingConfig returns the current config of the pipeline in the request's
+// path, or an empty config if the pipeline does not exist. If the lookup
+// fails the response has already been written and ok is false.
+func (s *Server) existingConfig(logger lager.Logger, w http.ResponseWriter, r *http.Request) (atc.Config, bool) {
+	teamName := rata.Param(r, "team_name")
+	pipelineName := rata.Param(r, "pipeline_name")
+	pipelineRef := atc.PipelineRef{Name: pipelineName}
+
+	var err error
+	pipelineRef.InstanceVars, err = atc.InstanceVarsFromQueryParams(r.URL.Query())
+	if err != nil {
+		logger.Error("malformed-instance-vars", err)
+		s.handleBadRequest(w, fmt.Sprintf("instance vars are malformed: %v", err))
+		return atc.Config{}, false
+	}
+
+	team, found, err := s.teamFactory.FindTeam(teamName)
+	if err != nil {
+		logger.Error("failed-to-find-team", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return atc.Config{}, false
+	}
+
+	if !found {
+		logger.Debug("team-not-found", lager.Data{"team": teamName})
+		w.WriteHeader(http.StatusNotFound)
+		return atc.Config{}, false
+	}
+
+	pipeline, found, err := team.Pipeline(pipelineRef)
+	if err != nil {
+		logger.Error("failed-to-find-pipeline", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return atc.Config{}, false
+	}
+
+	if !found {
+		return atc.Config{}, true
+	}
+
+	config, err := pipeline.Config()
+	if err != nil {
+		logger.Error("failed-to-get-pipeline-config", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return atc.Config{}, false
+	}
+
+	return config, true
+}
@@ -84,6 +84,7 @@ func NewHandler(
 	pipelineHandlerFactory := pipelineserver.NewScopedHandlerFactory(dbTeamFactory)
 	buildHandlerFactory := buildserver.NewScopedHandlerFactory(logger)
 	teamHandlerFactory := NewTeamScopedHandlerFactory(logger, dbTeamFactory)
+	rejectFrozenHandlerFactory := pipelineserver.NewRejectFrozenHandlerFactory(dbTeamFactory)
 
 	buildServer := buildserver.NewServer(logger, externalURL, dbTeamFactory, dbBuildFactory, eventHandlerFactory)
 	jobServer := jobserver.NewServer(logger, externalURL, secretManager, dbJobFactory, dbCheckFactory)
@@ -105,8 +106,10 @@ func NewHandler(
 	wallServer := wallserver.NewServer(dbWall, logger)
 
 	handlers := map[string]http.Handler{
-		atc.GetConfig:  http.HandlerFunc(configServer.GetConfig),
-		atc.SaveConfig: http.HandlerFunc(configServer.SaveConfig),
+		atc.GetConfig:    http.HandlerFunc(configServer.GetConfig),
+		atc.SaveConfig:   rejectFrozenHandlerFactory.RejectFrozen(http.HandlerFunc(configServer.SaveConfig)),
+		atc.DiffConfig:   http.HandlerFunc(configServer.DiffConfig),
+		atc.WhatIfConfig: http.HandlerFunc(configServer.DiffConfig),
 
 		atc.GetCC: http.HandlerFunc(ccServer.GetCC),
 
@@ -114,6 +117,8 @@ func NewHandler(
 		atc.CreateBuild:         teamHandlerFactory.HandlerFor(buildServer.CreateBuild),
 		atc.GetBuild:            buildHandlerFactory.HandlerFor(buildServer.GetBuild),
 		atc.BuildResources:      buildHandlerFactory.HandlerFor(buildServer.BuildResources),
+		atc.GetBuildInputs:      buildHandlerFactory.HandlerFor(buildServer.GetBuildInputs),
+		atc.GetBuildAnnotations: buildHandlerFactory.HandlerFor(buildServer.GetBuildAnnotations),
 		atc.AbortBuild:          buildHandlerFactory.HandlerFor(buildServer.AbortBuild),
 		atc.GetBuildPlan:        buildHandlerFactory.HandlerFor(buildServer.GetBuildPlan),
 		atc.GetBuildPreparation: buildHandlerFactory.HandlerFor(buildServer.GetBuildPreparation),
@@ -139,21 +144,22 @@ func NewHandler(
 
 		atc.ClearTaskCache: pipelineHandlerFactory.HandlerFor(jobServer.ClearTaskCache),
 
-		atc.ListAllPipelines:    http.HandlerFunc(pipelineServer.ListAllPipelines),
-		atc.ListPipelines:       http.HandlerFunc(pipelineServer.ListPipelines),
-		atc.GetPipeline:         pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipeline),
-		atc.DeletePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.DeletePipeline),
-		atc.OrderPipelines:      http.HandlerFunc(pipelineServer.OrderPipelines),
-		atc.PausePipeline:       pipelineHandlerFactory.HandlerFor(pipelineServer.PausePipeline),
-		atc.ArchivePipeline:     pipelineHandlerFactory.HandlerFor(pipelineServer.ArchivePipeline),
-		atc.UnpausePipeline:     pipelineHandlerFactory.HandlerFor(pipelineServer.UnpausePipeline),
-		atc.ExposePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.ExposePipeline),
-		atc.HidePipeline:        pipelineHandlerFactory.HandlerFor(pipelineServer.HidePipeline),
-		atc.GetVersionsDB:       pipelineHandlerFactory.HandlerFor(pipelineServer.GetVersionsDB),
-		atc.RenamePipeline:      teamHandlerFactory.HandlerFor(pipelineServer.RenamePipeline),
-		atc.ListPipelineBuilds:  pipelineHandlerFactory.HandlerFor(pipelineServer.ListPipelineBuilds),
-		atc.CreatePipelineBuild: pipelineHandlerFactory.HandlerFor(pipelineServer.CreateBuild),
-		atc.PipelineBadge:       pipelineHandlerFactory.HandlerFor(pipelineServer.PipelineBadge),
+		atc.ListAllPipelines:     http.HandlerFunc(pipelineServer.ListAllPipelines),
+		atc.ListPipelines:        http.HandlerFunc(pipelineServer.ListPipelines),
+		atc.GetPipeline:          pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipeline),
+		atc.DeletePipeline:       pipelineHandlerFactory.HandlerFor(pipelineServer.DeletePipeline),
+		atc.OrderPipelines:       http.HandlerFunc(pipelineServer.OrderPipelines),
+		atc.PausePipeline:        pipelineHandlerFactory.HandlerFor(pipelineServer.PausePipeline),
+		atc.ArchivePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.ArchivePipeline),
+		atc.UnpausePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.UnpausePipeline),
+		atc.ExposePipeline:       pipelineHandlerFactory.HandlerFor(pipelineServer.ExposePipeline),
+		atc.HidePipeline:         pipelineHandlerFactory.HandlerFor(pipelineServer.HidePipeline),
+		atc.GetVersionsDB:        pipelineHandlerFactory.HandlerFor(pipelineServer.GetVersionsDB),
+		atc.RenamePipeline:       teamHandlerFactory.HandlerFor(pipelineServer.RenamePipeline),
+		atc.ListPipelineBuilds:   pipelineHandlerFactory.HandlerFor(pipelineServer.ListPipelineBuilds),
+		atc.CreatePipelineBuild:  pipelineHandlerFactory.HandlerFor(pipelineServer.CreateBuild),
+		atc.PipelineBadge:        pipelineHandlerFactory.HandlerFor(pipelineServer.PipelineBadge),
+		atc.GetPipelineOwnership: pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipelineOwnership),
 
 		atc.ListAllResources:        http.HandlerFunc(resourceServer.ListAllResources),
 		atc.ListResources:           pipelineHandlerFactory.HandlerFor(resourceServer.ListResources),
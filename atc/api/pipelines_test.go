@@ -47,6 +47,7 @@ var _ = Describe("Pipelines API", func() {
 		publicPipeline.DisplayReturns(&atc.DisplayConfig{
 			BackgroundImage: "background.jpg",
 		})
+		publicPipeline.DescriptionReturns("Deploys the API service")
 		publicPipeline.LastUpdatedReturns(time.Unix(1, 0))
 
 		anotherPublicPipeline = new(dbfakes.FakePipeline)
@@ -136,7 +137,8 @@ var _ = Describe("Pipelines API", func() {
 					],
 					"display": {
 						"background_image": "background.jpg"
-					}
+					},
+					"description": "Deploys the API service"
 				},
 				{
 					"id": 2,
@@ -309,7 +311,8 @@ var _ = Describe("Pipelines API", func() {
 						],
 						"display": {
 							"background_image": "background.jpg"
-						}
+						},
+						"description": "Deploys the API service"
 					}
 				]`))
 			})
@@ -798,6 +801,107 @@ var _ = Describe("Pipelines API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/ownership", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			dbTeamFactory.FindTeamReturns(fakeTeam, true, nil)
+			dbPipeline.NameReturns("some-pipeline")
+			fakeTeam.PipelineReturns(dbPipeline, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/ownership")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthorizedReturns(false)
+				dbPipeline.PublicReturns(false)
+			})
+
+			Context("when user is authenticated", func() {
+				BeforeEach(func() {
+					fakeAccess.IsAuthenticatedReturns(true)
+				})
+
+				It("returns 403", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+			})
+
+			Context("when user is not authenticated", func() {
+				BeforeEach(func() {
+					fakeAccess.IsAuthenticatedReturns(false)
+				})
+
+				It("returns 401", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				fakeAccess.IsAuthenticatedReturns(true)
+				fakeAccess.IsAuthorizedReturns(true)
+			})
+
+			Context("when the pipeline has ownership", func() {
+				BeforeEach(func() {
+					dbPipeline.OwnershipReturns(atc.PipelineOwnership{
+						Team:    "platform-eng",
+						Contact: "platform@example.com",
+					}, true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns application/json", func() {
+					expectedHeaderEntries := map[string]string{
+						"Content-Type": "application/json",
+					}
+					Expect(response).Should(IncludeHeaderEntries(expectedHeaderEntries))
+				})
+
+				It("returns the ownership", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"team": "platform-eng",
+						"contact": "platform@example.com"
+					}`))
+				})
+			})
+
+			Context("when the pipeline has no ownership", func() {
+				BeforeEach(func() {
+					dbPipeline.OwnershipReturns(atc.PipelineOwnership{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when getting the ownership fails", func() {
+				BeforeEach(func() {
+					dbPipeline.OwnershipReturns(atc.PipelineOwnership{}, false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
 	Describe("DELETE /api/v1/teams/:team_name/pipelines/:pipeline_name", func() {
 		var response *http.Response
 
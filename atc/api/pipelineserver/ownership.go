@@ -0,0 +1,35 @@
+package pipelineserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc/db"
+)
+
+func (s *Server) GetPipelineOwnership(pipeline db.Pipeline) http.Handler {
+	logger := s.logger.Session("get-pipeline-ownership")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ownership, found, err := pipeline.Ownership()
+		if err != nil {
+			logger.Error("failed-to-get-pipeline-ownership", err, lager.Data{"pipeline": pipeline.Name()})
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		err = json.NewEncoder(w).Encode(ownership)
+		if err != nil {
+			logger.Error("failed-to-encode-pipeline-ownership", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
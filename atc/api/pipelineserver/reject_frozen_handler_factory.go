@@ -0,0 +1,66 @@
+package pipelineserver
+
+import (
+	"net/http"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+type RejectFrozenHandlerFactory struct {
+	teamFactory db.TeamFactory
+}
+
+func NewRejectFrozenHandlerFactory(factory db.TeamFactory) RejectFrozenHandlerFactory {
+	return RejectFrozenHandlerFactory{
+		teamFactory: factory,
+	}
+}
+
+func (f RejectFrozenHandlerFactory) RejectFrozen(handler http.Handler) http.Handler {
+	return RejectFrozenHandler{
+		teamFactory:     f.teamFactory,
+		delegateHandler: handler,
+	}
+}
+
+// RejectFrozenHandler rejects requests for pipelines that have been frozen by
+// a set_pipeline step. Requests that do not refer to an existing pipeline are
+// passed on to the delegate handler, which is left to validate them.
+type RejectFrozenHandler struct {
+	teamFactory     db.TeamFactory
+	delegateHandler http.Handler
+}
+
+func (rf RejectFrozenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	teamName := r.FormValue(":team_name")
+	pipelineName := r.FormValue(":pipeline_name")
+	pipelineRef := atc.PipelineRef{Name: pipelineName}
+	var err error
+	pipelineRef.InstanceVars, err = atc.InstanceVarsFromQueryParams(r.URL.Query())
+	if err != nil {
+		rf.delegateHandler.ServeHTTP(w, r)
+		return
+	}
+
+	team, found, err := rf.teamFactory.FindTeam(teamName)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if found {
+		pipeline, found, err := team.Pipeline(pipelineRef)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if found && pipeline.Frozen() {
+			http.Error(w, "pipeline is frozen; update it through the set_pipeline step that configures it", http.StatusLocked)
+			return
+		}
+	}
+
+	rf.delegateHandler.ServeHTTP(w, r)
+}
@@ -16,6 +16,7 @@ func Pipeline(savedPipeline db.Pipeline) atc.Pipeline {
 		Archived:     savedPipeline.Archived(),
 		Groups:       savedPipeline.Groups(),
 		Display:      savedPipeline.Display(),
+		Description:  savedPipeline.Description(),
 		LastUpdated:  savedPipeline.LastUpdated().Unix(),
 	}
 }
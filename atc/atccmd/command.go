@@ -39,9 +39,12 @@ import (
 	"github.com/concourse/concourse/atc/db/lock"
 	"github.com/concourse/concourse/atc/db/migration"
 	"github.com/concourse/concourse/atc/engine"
+	"github.com/concourse/concourse/atc/eventbus"
+	"github.com/concourse/concourse/atc/exec"
 	"github.com/concourse/concourse/atc/gc"
 	"github.com/concourse/concourse/atc/lidar"
 	"github.com/concourse/concourse/atc/metric"
+	"github.com/concourse/concourse/atc/notifications"
 	"github.com/concourse/concourse/atc/policy"
 	"github.com/concourse/concourse/atc/resource"
 	"github.com/concourse/concourse/atc/scheduler"
@@ -64,6 +67,7 @@ import (
 
 	"github.com/cppforlife/go-semi-semantic/version"
 	"github.com/hashicorp/go-multierror"
+	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/jessevdk/go-flags"
 	gocache "github.com/patrickmn/go-cache"
 	"github.com/tedsuo/ifrit"
@@ -75,6 +79,9 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/time/rate"
 	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	// dynamically registered metric emitters
 	_ "github.com/concourse/concourse/atc/metric/emitter"
@@ -89,7 +96,7 @@ import (
 	_ "github.com/concourse/concourse/atc/creds/kubernetes"
 	_ "github.com/concourse/concourse/atc/creds/secretsmanager"
 	_ "github.com/concourse/concourse/atc/creds/ssm"
-	_ "github.com/concourse/concourse/atc/creds/vault"
+	"github.com/concourse/concourse/atc/creds/vault"
 )
 
 const algorithmLimitRows = 100
@@ -153,6 +160,38 @@ type RunCommand struct {
 	ResourceWithWebhookCheckingInterval time.Duration `long:"resource-with-webhook-checking-interval" default:"1m" description:"Interval on which to check for new versions of resources that has webhook defined."`
 	MaxChecksPerSecond                  int           `long:"max-checks-per-second" description:"Maximum number of checks that can be started per second. If not specified, this will be calculated as (# of resources)/(resource checking interval). -1 value will remove this maximum limit of checks per second."`
 
+	ReadOnlyMode           bool `long:"read-only-mode" description:"Reject all changes to pipeline configs, e.g. while recovering from a disaster."`
+	DisableSetPipelineStep bool `long:"disable-set-pipeline-step" description:"Fail all set_pipeline steps, so that pipelines can only be changed with fly."`
+
+	SetPipelineRemoteTargets []string `long:"set-pipeline-remote-target" description:"URL of a remote Concourse which set_pipeline steps may set pipelines on. Can be specified multiple times. No remote targets are allowed unless configured."`
+
+	MaxVarFiles                     int           `long:"max-var-files" default:"20" description:"Maximum number of var files a set_pipeline step may load."`
+	SetPipelineFileCacheTTL         time.Duration `long:"set-pipeline-file-cache-ttl" default:"5m" description:"How long files fetched by set_pipeline steps are cached. Set to 0 to disable the cache."`
+	SetPipelineFileCacheSize        int           `long:"set-pipeline-file-cache-size" default:"100" description:"Maximum number of files fetched by set_pipeline steps to cache per team."`
+	MaxConcurrentSetPipelinePerTeam int           `long:"max-concurrent-set-pipeline-per-team" default:"5" description:"Maximum number of set_pipeline steps of a team that may run at once. Set to 0 to disable the limit."`
+
+	SetPipelineConfigMaps struct {
+		InClusterConfig bool   `long:"in-cluster" description:"Enables the in-cluster client for fetching set_pipeline files from ConfigMaps."`
+		ConfigPath      string `long:"config-path" description:"Path to Kubernetes config when running ATC outside Kubernetes."`
+		NamespacePrefix string `long:"namespace-prefix" default:"concourse-" description:"Prefix to use for Kubernetes namespaces under which ConfigMaps will be looked up."`
+	} `group:"Kubernetes ConfigMaps for set_pipeline" namespace:"set-pipeline-configmap"`
+
+	Notifications struct {
+		SMTPAddress  string `long:"smtp-address" description:"Address (host:port) of the SMTP server used to send email notifications configured by set_pipeline steps."`
+		SMTPFrom     string `long:"smtp-from" default:"concourse@localhost" description:"Sender address of email notifications."`
+		SMTPUsername string `long:"smtp-username" description:"Username to authenticate with the SMTP server."`
+		SMTPPassword string `long:"smtp-password" description:"Password to authenticate with the SMTP server."`
+
+		Workers   int `long:"workers" default:"5" description:"Number of notifications configured by set_pipeline steps which are sent at once."`
+		QueueSize int `long:"queue-size" default:"1000" description:"Maximum number of notifications waiting to be sent. Further notifications are dropped."`
+
+		WebhookURLs []string `long:"webhook-url" description:"URL prefix of the webhooks which set_pipeline steps may configure as notification recipients. Can be specified multiple times. No webhooks are allowed unless configured."`
+	} `group:"Build Notifications" namespace:"notifications"`
+
+	EventBus struct {
+		URL flag.URL `long:"url" description:"URL of the message broker which set_pipeline steps publish pipeline updated CloudEvents to. Either an http(s):// endpoint or nats://host:port/subject."`
+	} `group:"Event Bus" namespace:"event-bus"`
+
 	ContainerPlacementStrategyOptions worker.ContainerPlacementStrategyOptions `group:"Container Placement Strategy"`
 
 	BaggageclaimResponseHeaderTimeout time.Duration `long:"baggageclaim-response-header-timeout" default:"1m" description:"How long to wait for Baggageclaim to send the response header."`
@@ -519,6 +558,10 @@ func (cmd *RunCommand) Runner(positionalArguments []string) (ifrit.Runner, error
 	atc.EnableBuildRerunWhenWorkerDisappears = cmd.FeatureFlags.EnableBuildRerunWhenWorkerDisappears
 	atc.EnableAcrossStep = cmd.FeatureFlags.EnableAcrossStep
 	atc.EnablePipelineInstances = cmd.FeatureFlags.EnablePipelineInstances
+	atc.ReadOnlyMode = cmd.ReadOnlyMode
+	atc.DisableSetPipelineStep = cmd.DisableSetPipelineStep
+	atc.SetPipelineRemoteTargets = cmd.SetPipelineRemoteTargets
+	atc.NotificationWebhookURLs = cmd.Notifications.WebhookURLs
 
 	if cmd.BaseResourceTypeDefaults.Path() != "" {
 		content, err := ioutil.ReadFile(cmd.BaseResourceTypeDefaults.Path())
@@ -1071,6 +1114,16 @@ func (cmd *RunCommand) backendComponents(
 		return nil, err
 	}
 
+	configMapFetcher, err := cmd.configMapFetcher()
+	if err != nil {
+		return nil, err
+	}
+
+	eventPublisher, err := cmd.eventPublisher()
+	if err != nil {
+		return nil, err
+	}
+
 	rateLimiter := db.NewResourceCheckRateLimiter(
 		rate.Limit(cmd.MaxChecksPerSecond),
 		cmd.ResourceCheckingInterval,
@@ -1080,6 +1133,7 @@ func (cmd *RunCommand) backendComponents(
 	)
 
 	engine := cmd.constructEngine(
+		cmd.notifier(logger),
 		pool,
 		artifactStreamer,
 		artifactSourcer,
@@ -1095,6 +1149,8 @@ func (cmd *RunCommand) backendComponents(
 		lockFactory,
 		rateLimiter,
 		policyChecker,
+		configMapFetcher,
+		eventPublisher,
 	)
 
 	// In case that a user configures resource-checking-interval, but forgets to
@@ -1636,6 +1692,107 @@ func (cmd *RunCommand) chooseBuildContainerStrategy() (worker.ContainerPlacement
 	return worker.NewContainerPlacementStrategy(cmd.ContainerPlacementStrategyOptions)
 }
 
+func (cmd *RunCommand) configMapFetcher() (exec.ConfigMapFetcher, error) {
+	configMaps := cmd.SetPipelineConfigMaps
+
+	var config *rest.Config
+	var err error
+	switch {
+	case configMaps.InClusterConfig && configMaps.ConfigPath != "":
+		return nil, errors.New("either set-pipeline-configmap-in-cluster or set-pipeline-configmap-config-path can be used, not both")
+	case configMaps.InClusterConfig:
+		config, err = rest.InClusterConfig()
+	case configMaps.ConfigPath != "":
+		config, err = clientcmd.BuildConfigFromFlags("", configMaps.ConfigPath)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return exec.NewKubernetesConfigMapFetcher(clientset, configMaps.NamespacePrefix), nil
+}
+
+// eventPublisher returns the publisher of the configured event bus, if any.
+func (cmd *RunCommand) eventPublisher() (eventbus.Publisher, error) {
+	if cmd.EventBus.URL.URL == nil {
+		return nil, nil
+	}
+
+	return eventbus.NewPublisher(cmd.EventBus.URL.URL, cmd.ExternalURL.String(), &http.Client{Timeout: 30 * time.Second})
+}
+
+// notifier returns the notifier which sends the build notifications
+// configured by set_pipeline steps in the background.
+func (cmd *RunCommand) notifier(logger lager.Logger) notifications.Notifier {
+	return notifications.NewQueuedNotifier(
+		logger.Session("notifications"),
+		notifications.NewNotifier(
+			cmd.ExternalURL.String(),
+			&http.Client{},
+			notifications.SMTPConfig{
+				Address:  cmd.Notifications.SMTPAddress,
+				From:     cmd.Notifications.SMTPFrom,
+				Username: cmd.Notifications.SMTPUsername,
+				Password: cmd.Notifications.SMTPPassword,
+			},
+		),
+		cmd.Notifications.Workers,
+		cmd.Notifications.QueueSize,
+		30*time.Second,
+	)
+}
+
+// vaultSecretReader returns a reader using the client of the Vault credential
+// manager, if it is the one which is configured, for set_pipeline steps to
+// read vault_dynamic_vars with. It must be called after the credential
+// manager has been initialized.
+func (cmd *RunCommand) vaultSecretReader(secretManager creds.Secrets) exec.VaultSecretReader {
+	for _, manager := range cmd.CredentialManagers {
+		vaultManager, ok := manager.(*vault.VaultManager)
+		if ok && vaultManager.IsConfigured() && vaultManager.Client != nil {
+			return vaultDynamicVarsReader{
+				client:  vaultManager.Client,
+				secrets: secretManager,
+			}
+		}
+	}
+
+	return nil
+}
+
+// vaultDynamicVarsReader reads secrets with the Vault client, under the same
+// lookup paths as the secret manager uses to resolve vars.
+type vaultDynamicVarsReader struct {
+	client  *vault.APIClient
+	secrets creds.Secrets
+}
+
+func (reader vaultDynamicVarsReader) Read(path string) (*vaultapi.Secret, error) {
+	return reader.client.Read(path)
+}
+
+func (reader vaultDynamicVarsReader) NewSecretLookupPaths(teamName string, pipelineName string, allowRootPath bool) []creds.SecretLookupPath {
+	return reader.secrets.NewSecretLookupPaths(teamName, pipelineName, allowRootPath)
+}
+
+// credentialCacheInvalidator returns the credential cache for set_pipeline
+// steps to invalidate with force_credential_refresh, if secrets are cached.
+func credentialCacheInvalidator(secretManager creds.Secrets) exec.CredentialCacheInvalidator {
+	cachedSecrets, ok := secretManager.(*creds.CachedSecrets)
+	if !ok {
+		return nil
+	}
+
+	return cachedSecrets
+}
+
 func (cmd *RunCommand) configureAuthForDefaultTeam(teamFactory db.TeamFactory) error {
 	team, found, err := teamFactory.FindTeam(atc.DefaultTeamName)
 	if err != nil {
@@ -1660,6 +1817,7 @@ func (cmd *RunCommand) configureAuthForDefaultTeam(teamFactory db.TeamFactory) e
 }
 
 func (cmd *RunCommand) constructEngine(
+	notifier notifications.Notifier,
 	workerPool worker.Pool,
 	artifactStreamer worker.ArtifactStreamer,
 	artifactSourcer worker.ArtifactSourcer,
@@ -1675,6 +1833,8 @@ func (cmd *RunCommand) constructEngine(
 	lockFactory lock.LockFactory,
 	rateLimiter engine.RateLimiter,
 	policyChecker policy.Checker,
+	configMapFetcher exec.ConfigMapFetcher,
+	eventPublisher eventbus.Publisher,
 ) engine.Engine {
 	return engine.NewEngine(
 		engine.NewStepperFactory(
@@ -1690,6 +1850,13 @@ func (cmd *RunCommand) constructEngine(
 				defaultLimits,
 				strategy,
 				cmd.GlobalResourceCheckTimeout,
+				cmd.MaxVarFiles,
+				exec.NewSetPipelineFileCache(clock.NewClock(), cmd.SetPipelineFileCacheTTL, cmd.SetPipelineFileCacheSize),
+				exec.NewSetPipelineLimiter(cmd.MaxConcurrentSetPipelinePerTeam),
+				configMapFetcher,
+				cmd.vaultSecretReader(secretManager),
+				eventPublisher,
+				credentialCacheInvalidator(secretManager),
 			),
 			cmd.ExternalURL.String(),
 			rateLimiter,
@@ -1700,6 +1867,7 @@ func (cmd *RunCommand) constructEngine(
 		),
 		secretManager,
 		cmd.varSourcePool,
+		notifier,
 	)
 }
 
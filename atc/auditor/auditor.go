@@ -62,6 +62,8 @@ func (a *auditor) ValidateAction(action string) bool {
 		atc.ListBuilds,
 		atc.BuildEvents,
 		atc.BuildResources,
+		atc.GetBuildInputs,
+		atc.GetBuildAnnotations,
 		atc.AbortBuild,
 		atc.GetBuildPreparation,
 		atc.ListBuildsWithVersionAsInput,
@@ -102,7 +104,8 @@ func (a *auditor) ValidateAction(action string) bool {
 		atc.RenamePipeline,
 		atc.ListPipelineBuilds,
 		atc.CreatePipelineBuild,
-		atc.PipelineBadge:
+		atc.PipelineBadge,
+		atc.GetPipelineOwnership:
 		return a.EnablePipelineAuditLog
 	case atc.ListAllResources,
 		atc.ListResources,
@@ -123,6 +126,8 @@ func (a *auditor) ValidateAction(action string) bool {
 	case
 		atc.SaveConfig,
 		atc.GetConfig,
+		atc.DiffConfig,
+		atc.WhatIfConfig,
 		atc.GetCC,
 		atc.GetVersionsDB,
 		atc.ClearTaskCache,
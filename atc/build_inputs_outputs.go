@@ -23,3 +23,18 @@ type ResourceVersion struct {
 	Version  Version         `json:"version"`
 	Enabled  bool            `json:"enabled"`
 }
+
+// BuildInputs describes the files consumed by a build's set_pipeline steps.
+type BuildInputs struct {
+	SetPipelineArtifacts []SetPipelineArtifact `json:"set_pipeline.artifacts"`
+}
+
+// SetPipelineArtifact is a file streamed by a set_pipeline step, i.e. its
+// pipeline config or one of its var files.
+type SetPipelineArtifact struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Digest     string `json:"digest"`
+	SizeBytes  int    `json:"size_bytes"`
+	StreamedAt int64  `json:"streamed_at"`
+}
@@ -251,6 +251,73 @@ func (visitor *planVisitor) VisitSetPipeline(step *atc.SetPipelineStep) error {
 		Vars:         step.Vars,
 		VarFiles:     step.VarFiles,
 		InstanceVars: step.InstanceVars,
+
+		SkipReachabilityCheck:    step.SkipReachabilityCheck,
+		TriggerChecks:            step.TriggerChecks,
+		InheritPinnedVersions:    step.InheritPinnedVersions,
+		LockResourceTypeVersions: step.LockResourceTypeVersions,
+		PinBuildInputs:           step.PinBuildInputs,
+		TemplateEngine:           step.TemplateEngine,
+		CleanupOnFailure:         step.CleanupOnFailure,
+		ArchiveUnlisted:          step.ArchiveUnlisted,
+		ManagedPrefix:            step.ManagedPrefix,
+		Extends:                  step.Extends,
+		Display:                  step.Display,
+		SlackWebhook:             step.SlackWebhook,
+		SlackChannel:             step.SlackChannel,
+		Freeze:                   step.Freeze,
+		MaxParseRetries:          step.MaxParseRetries,
+		RenameFrom:               step.RenameFrom,
+		ArchiveOld:               step.ArchiveOld,
+		StrategicMergeFiles:      step.StrategicMergeFiles,
+		PostSaveSleep:            step.PostSaveSleep,
+		MinATCVersion:            step.MinATCVersion,
+		When:                     step.When,
+		PreserveResourceHistory:  step.PreserveResourceHistory,
+		TeamVar:                  step.TeamVar,
+		CanaryJobs:               step.CanaryJobs,
+		TriggerChecksJitter:      step.TriggerChecksJitter,
+		CloneFrom:                step.CloneFrom,
+		ApplyJobs:                step.ApplyJobs,
+		Target:                   step.Target,
+		WaitForSuccess:           step.WaitForSuccess,
+		WaitTimeout:              step.WaitTimeout,
+		RollbackOnError:          step.RollbackOnError,
+		RollbackWindow:           step.RollbackWindow,
+		BannedPatterns:           step.BannedPatterns,
+		ResetBuildHistory:        step.ResetBuildHistory,
+		MergeStrategy:            step.MergeStrategy,
+		EncryptFields:            step.EncryptFields,
+		LintImage:                step.LintImage,
+		LintPolicyFile:           step.LintPolicyFile,
+		Notifications:            step.Notifications,
+		VaultDynamicVars:         step.VaultDynamicVars,
+		CheckImageResourceTypes:  step.CheckImageResourceTypes,
+		MinJobs:                  step.MinJobs,
+		NotifyEventBus:           step.NotifyEventBus,
+		ApplyResources:           step.ApplyResources,
+		DiffFormat:               step.DiffFormat,
+		GitHubDeployment:         step.GitHubDeployment,
+		DiffOutput:               step.DiffOutput,
+		Scope:                    step.Scope,
+		VarPrecedence:            step.VarPrecedence,
+		CheckImageTags:           step.CheckImageTags,
+		FeatureFlagCheck:         step.FeatureFlagCheck,
+		SBOM:                     step.SBOM,
+		NamePattern:              step.NamePattern,
+		PrefetchVarFiles:         step.PrefetchVarFiles,
+		ForceCredentialRefresh:   step.ForceCredentialRefresh,
+		TriggerFirstJob:          step.TriggerFirstJob,
+		LabelResources:           step.LabelResources,
+		PreValidateScript:        step.PreValidateScript,
+		PreValidateImage:         step.PreValidateImage,
+		MaxParallelJobs:          step.MaxParallelJobs,
+		CreateTeamIfMissing:      step.CreateTeamIfMissing,
+		QueueDepthCheck:          step.QueueDepthCheck,
+		QueueDepthThreshold:      step.QueueDepthThreshold,
+		Description:              step.Description,
+		Owners:                   step.Owners,
+		ArchiveAfter:             step.ArchiveAfter,
 	})
 
 	return nil
@@ -267,6 +334,17 @@ func (visitor *planVisitor) VisitLoadVar(step *atc.LoadVarStep) error {
 	return nil
 }
 
+func (visitor *planVisitor) VisitValidatePipeline(step *atc.ValidatePipelineStep) error {
+	visitor.plan = visitor.planFactory.NewPlan(atc.ValidatePipelinePlan{
+		Name:     step.Name,
+		File:     step.File,
+		VarFiles: step.VarFiles,
+		Vars:     step.Vars,
+	})
+
+	return nil
+}
+
 func (visitor *planVisitor) VisitTry(step *atc.TryStep) error {
 	err := step.Step.Config.Visit(visitor)
 	if err != nil {
@@ -286,6 +364,12 @@ func (visitor *planVisitor) VisitTimeout(step *atc.TimeoutStep) error {
 		return err
 	}
 
+	// the set_pipeline step enforces its own deadline, which must not cut the
+	// step short of the configured timeout
+	if visitor.plan.SetPipeline != nil {
+		visitor.plan.SetPipeline.Timeout = step.Duration
+	}
+
 	visitor.plan = visitor.planFactory.NewPlan(atc.TimeoutPlan{
 		Duration: step.Duration,
 		Step:     visitor.plan,
@@ -199,6 +199,7 @@ type ResourceType struct {
 	CheckEvery *CheckEvery `json:"check_every,omitempty"`
 	Tags       Tags        `json:"tags,omitempty"`
 	Params     Params      `json:"params,omitempty"`
+	Version    Version     `json:"version,omitempty"`
 }
 
 type DisplayConfig struct {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/aryann/difflib"
@@ -34,7 +35,24 @@ func name(v interface{}) string {
 	return reflect.ValueOf(v).FieldByName("Name").String()
 }
 
+// Name returns the name of the object that has changed.
+func (diff Diff) Name() string {
+	if diff.After != nil {
+		return name(diff.After)
+	}
+
+	return name(diff.Before)
+}
+
+// renderFunc renders the difference between the before and after YAML of an
+// object.
+type renderFunc func(to io.Writer, a, b string)
+
 func (diff Diff) Render(to io.Writer, label string) {
+	diff.render(to, label, renderDiff)
+}
+
+func (diff Diff) render(to io.Writer, label string, render renderFunc) {
 
 	if diff.Before != nil && diff.After != nil {
 		fmt.Fprintf(to, ansi.Color("%s %s has changed:", "yellow")+"\n", label, name(diff.Before))
@@ -42,37 +60,41 @@ func (diff Diff) Render(to io.Writer, label string) {
 		payloadA, _ := yaml.Marshal(diff.Before)
 		payloadB, _ := yaml.Marshal(diff.After)
 
-		renderDiff(to, string(payloadA), string(payloadB))
+		render(to, string(payloadA), string(payloadB))
 	} else if diff.Before != nil {
 		fmt.Fprintf(to, ansi.Color("%s %s has been removed:", "yellow")+"\n", label, name(diff.Before))
 
 		payloadA, _ := yaml.Marshal(diff.Before)
 
-		renderDiff(to, string(payloadA), "")
+		render(to, string(payloadA), "")
 	} else {
 		fmt.Fprintf(to, ansi.Color("%s %s has been added:", "yellow")+"\n", label, name(diff.After))
 
 		payloadB, _ := yaml.Marshal(diff.After)
 
-		renderDiff(to, "", string(payloadB))
+		render(to, "", string(payloadB))
 	}
 }
 
 func (diff DisplayDiff) Render(to io.Writer) {
+	diff.render(to, renderDiff)
+}
+
+func (diff DisplayDiff) render(to io.Writer, render renderFunc) {
 	label := "display configuration"
 	if diff.Before != nil && diff.After != nil {
 		fmt.Fprintf(to, ansi.Color("%s has changed:", "yellow")+"\n", label)
 		payloadA, _ := yaml.Marshal(diff.Before)
 		payloadB, _ := yaml.Marshal(diff.After)
-		renderDiff(to, string(payloadA), string(payloadB))
+		render(to, string(payloadA), string(payloadB))
 	} else if diff.Before != nil {
 		fmt.Fprintf(to, ansi.Color("%s has been removed:", "yellow")+"\n", label)
 		payloadA, _ := yaml.Marshal(diff.Before)
-		renderDiff(to, string(payloadA), "")
+		render(to, string(payloadA), "")
 	} else {
 		fmt.Fprintf(to, ansi.Color("%s has been added:", "yellow")+"\n", label)
 		payloadB, _ := yaml.Marshal(diff.After)
-		renderDiff(to, "", string(payloadB))
+		render(to, "", string(payloadB))
 	}
 }
 
@@ -193,6 +215,27 @@ func groupDiffIndices(oldIndex GroupIndex, newIndex GroupIndex) Diffs {
 	return diffs
 }
 
+// countLine summarises the diffs of a section whose objects numbered before
+// in the old config, e.g. "# 2 added, 1 removed, 3 unchanged".
+func (diffs Diffs) countLine(before int) string {
+	var added, removed int
+	modified := map[string]bool{}
+
+	for _, diff := range diffs {
+		switch {
+		case diff.Before == nil:
+			added++
+		case diff.After == nil:
+			removed++
+		default:
+			// a reordered group that has also changed shows up twice
+			modified[diff.Name()] = true
+		}
+	}
+
+	return fmt.Sprintf("# %d added, %d removed, %d unchanged", added, removed, before-removed-len(modified))
+}
+
 func diffIndices(oldIndex Index, newIndex Index) Diffs {
 	diffs := Diffs{}
 
@@ -260,6 +303,72 @@ func renderDiff(to io.Writer, a, b string) {
 	}
 }
 
+// renderSideBySide renders the old lines in a left column and the new lines in
+// a right column. Runs of removed and added lines are paired up so that a
+// changed line shows up on a single row.
+func renderSideBySide(to io.Writer, a, b string) {
+	diffs := difflib.Diff(strings.Split(a, "\n"), strings.Split(b, "\n"))
+
+	type row struct {
+		left, right string
+		changed     bool
+	}
+
+	var rows []row
+	var lefts, rights []string
+
+	flush := func() {
+		for i := 0; i < len(lefts) || i < len(rights); i++ {
+			r := row{changed: true}
+			if i < len(lefts) {
+				r.left = lefts[i]
+			}
+			if i < len(rights) {
+				r.right = rights[i]
+			}
+			rows = append(rows, r)
+		}
+
+		lefts, rights = nil, nil
+	}
+
+	for _, diff := range diffs {
+		switch diff.Delta {
+		case difflib.LeftOnly:
+			lefts = append(lefts, diff.Payload)
+		case difflib.RightOnly:
+			rights = append(rights, diff.Payload)
+		case difflib.Common:
+			flush()
+			rows = append(rows, row{left: diff.Payload, right: diff.Payload})
+		}
+	}
+
+	flush()
+
+	width := 0
+	for _, r := range rows {
+		if len(r.left) > width {
+			width = len(r.left)
+		}
+	}
+
+	for _, r := range rows {
+		// pad before colouring so that escape codes do not skew the columns
+		left := fmt.Sprintf("%-*s", width, r.left)
+		right := r.right
+
+		separator := "|"
+		if r.changed {
+			left = ansi.Color(left, "red")
+			right = ansi.Color(right, "green")
+			separator = ansi.Color(">", "yellow")
+		}
+
+		fmt.Fprintf(to, "%s %s %s\n", left, separator, right)
+	}
+}
+
 func practicallyDifferent(a, b interface{}) bool {
 	if reflect.DeepEqual(a, b) {
 		return false
@@ -274,7 +383,150 @@ func practicallyDifferent(a, b interface{}) bool {
 	return !bytes.Equal(marshalledA, marshalledB)
 }
 
+// JobDiffs returns the jobs that have been added, removed or changed in the
+// new config.
+func (c Config) JobDiffs(newConfig Config) Diffs {
+	return diffIndices(JobIndex(c.Jobs), JobIndex(newConfig.Jobs))
+}
+
+// ResourceDiffs returns the resources that have been added, removed or
+// changed in the new config.
+func (c Config) ResourceDiffs(newConfig Config) Diffs {
+	return diffIndices(ResourceIndex(c.Resources), ResourceIndex(newConfig.Resources))
+}
+
+// DiffSummary lists the objects that are added, removed or modified in the
+// new config, without rendering their contents.
+func (c Config) DiffSummary(newConfig Config) DiffConfigResponse {
+	summary := DiffConfigResponse{
+		Added:    []ConfigDiffEntry{},
+		Removed:  []ConfigDiffEntry{},
+		Modified: []ConfigDiffEntry{},
+	}
+
+	for _, section := range []struct {
+		kind  string
+		diffs Diffs
+	}{
+		{"group", groupDiffIndices(GroupIndex(c.Groups), GroupIndex(newConfig.Groups))},
+		{"var_source", diffIndices(VarSourceIndex(c.VarSources), VarSourceIndex(newConfig.VarSources))},
+		{"resource", diffIndices(ResourceIndex(c.Resources), ResourceIndex(newConfig.Resources))},
+		{"resource_type", diffIndices(ResourceTypeIndex(c.ResourceTypes), ResourceTypeIndex(newConfig.ResourceTypes))},
+		{"job", diffIndices(JobIndex(c.Jobs), JobIndex(newConfig.Jobs))},
+	} {
+		for _, diff := range section.diffs {
+			entry := ConfigDiffEntry{Type: section.kind, Name: diff.Name()}
+
+			switch {
+			case diff.Before == nil:
+				summary.Added = append(summary.Added, entry)
+			case diff.After == nil:
+				summary.Removed = append(summary.Removed, entry)
+			case !containsDiffEntry(summary.Modified, entry):
+				// a reordered group that has also changed shows up twice
+				summary.Modified = append(summary.Modified, entry)
+			}
+		}
+	}
+
+	if _, changed := diffDisplay(c.Display, newConfig.Display); changed {
+		entry := ConfigDiffEntry{Type: "display"}
+
+		switch {
+		case c.Display == nil:
+			summary.Added = append(summary.Added, entry)
+		case newConfig.Display == nil:
+			summary.Removed = append(summary.Removed, entry)
+		default:
+			summary.Modified = append(summary.Modified, entry)
+		}
+	}
+
+	return summary
+}
+
+func containsDiffEntry(entries []ConfigDiffEntry, entry ConfigDiffEntry) bool {
+	for _, e := range entries {
+		if e == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SummaryDiff prints only the number of objects that are added, removed or
+// modified in the new config, e.g. "+3 jobs, -1 resource".
+func (c Config) SummaryDiff(out io.Writer, newConfig Config) bool {
+	summary := c.DiffSummary(newConfig)
+
+	var counts []string
+	for _, section := range []struct {
+		sign    string
+		entries []ConfigDiffEntry
+	}{
+		{"+", summary.Added},
+		{"-", summary.Removed},
+		{"~", summary.Modified},
+	} {
+		for _, kind := range []string{"group", "var_source", "resource", "resource_type", "job", "display"} {
+			count := 0
+			for _, entry := range section.entries {
+				if entry.Type == kind {
+					count++
+				}
+			}
+
+			if count == 0 {
+				continue
+			}
+
+			noun := strings.ReplaceAll(kind, "_", " ")
+			if kind == "display" {
+				noun = "display configuration"
+			} else if count > 1 {
+				noun += "s"
+			}
+
+			counts = append(counts, fmt.Sprintf("%s%d %s", section.sign, count, noun))
+		}
+	}
+
+	if len(counts) == 0 {
+		return false
+	}
+
+	fmt.Fprintln(out, strings.Join(counts, ", "))
+
+	return true
+}
+
+// SideBySideDiff prints the same changes as Diff, with the old and new
+// contents of each object in two columns.
+func (c Config) SideBySideDiff(out io.Writer, newConfig Config) bool {
+	return c.diff(out, newConfig, renderSideBySide)
+}
+
 func (c Config) Diff(out io.Writer, newConfig Config) bool {
+	return c.diff(out, newConfig, renderDiff)
+}
+
+// terminalFormatting matches the colours of the rendered diff, and the
+// indentation which is backspaced over to make room for the +/- markers.
+var terminalFormatting = regexp.MustCompile("\x1b\\[[0-9;]*m|  \b\b")
+
+// UnifiedDiff prints the same changes as Diff without any terminal
+// formatting, e.g. for writing them to a file.
+func (c Config) UnifiedDiff(out io.Writer, newConfig Config) bool {
+	buf := new(bytes.Buffer)
+	diffExists := c.Diff(buf, newConfig)
+
+	out.Write(terminalFormatting.ReplaceAll(buf.Bytes(), nil))
+
+	return diffExists
+}
+
+func (c Config) diff(out io.Writer, newConfig Config, render renderFunc) bool {
 	var diffExists bool
 
 	indent := gexec.NewPrefixedWriter("  ", out)
@@ -282,57 +534,62 @@ func (c Config) Diff(out io.Writer, newConfig Config) bool {
 	groupDiffs := groupDiffIndices(GroupIndex(c.Groups), GroupIndex(newConfig.Groups))
 	if len(groupDiffs) > 0 {
 		diffExists = true
+		fmt.Fprintln(out, groupDiffs.countLine(len(c.Groups)))
 		fmt.Fprintln(out, "groups:")
 
 		for _, diff := range groupDiffs {
-			diff.Render(indent, "group")
+			diff.render(indent, "group", render)
 		}
 	}
 
 	varSourceDiffs := diffIndices(VarSourceIndex(c.VarSources), VarSourceIndex(newConfig.VarSources))
 	if len(varSourceDiffs) > 0 {
 		diffExists = true
-		fmt.Println("variable source:")
+		fmt.Fprintln(out, varSourceDiffs.countLine(len(c.VarSources)))
+		fmt.Fprintln(out, "variable source:")
 
 		for _, diff := range varSourceDiffs {
-			diff.Render(indent, "variable source")
+			diff.render(indent, "variable source", render)
 		}
 	}
 
 	resourceDiffs := diffIndices(ResourceIndex(c.Resources), ResourceIndex(newConfig.Resources))
 	if len(resourceDiffs) > 0 {
 		diffExists = true
+		fmt.Fprintln(out, resourceDiffs.countLine(len(c.Resources)))
 		fmt.Fprintln(out, "resources:")
 
 		for _, diff := range resourceDiffs {
-			diff.Render(indent, "resource")
+			diff.render(indent, "resource", render)
 		}
 	}
 
 	resourceTypeDiffs := diffIndices(ResourceTypeIndex(c.ResourceTypes), ResourceTypeIndex(newConfig.ResourceTypes))
 	if len(resourceTypeDiffs) > 0 {
 		diffExists = true
+		fmt.Fprintln(out, resourceTypeDiffs.countLine(len(c.ResourceTypes)))
 		fmt.Fprintln(out, "resource types:")
 
 		for _, diff := range resourceTypeDiffs {
-			diff.Render(indent, "resource type")
+			diff.render(indent, "resource type", render)
 		}
 	}
 
 	jobDiffs := diffIndices(JobIndex(c.Jobs), JobIndex(newConfig.Jobs))
 	if len(jobDiffs) > 0 {
 		diffExists = true
+		fmt.Fprintln(out, jobDiffs.countLine(len(c.Jobs)))
 		fmt.Fprintln(out, "jobs:")
 
 		for _, diff := range jobDiffs {
-			diff.Render(indent, "job")
+			diff.render(indent, "job", render)
 		}
 	}
 
 	displayDiff, diff := diffDisplay(c.Display, newConfig.Display)
 	if diff {
 		diffExists = true
-		displayDiff.Render(indent)
+		displayDiff.render(indent, render)
 	}
 
 	return diffExists
@@ -216,4 +216,180 @@ var _ = Describe("Config diff", func() {
 			})
 		})
 	})
+
+	Describe("DiffSummary", func() {
+		var oldConfig Config
+
+		BeforeEach(func() {
+			oldConfig = Config{
+				Groups: GroupConfigs{
+					{Name: "some-group", Jobs: []string{"some-job"}},
+					{Name: "other-group", Jobs: []string{"other-job"}},
+				},
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+					{Name: "removed-resource", Type: "git"},
+				},
+				Jobs: JobConfigs{
+					{Name: "some-job"},
+					{Name: "other-job"},
+				},
+			}
+		})
+
+		It("lists the objects that are added, removed and modified", func() {
+			newConfig := Config{
+				Groups: GroupConfigs{
+					{Name: "other-group", Jobs: []string{"other-job", "new-job"}},
+					{Name: "some-group", Jobs: []string{"some-job"}},
+				},
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "time"},
+				},
+				Jobs: JobConfigs{
+					{Name: "some-job"},
+					{Name: "other-job"},
+					{Name: "new-job"},
+				},
+				Display: &DisplayConfig{BackgroundImage: "some-background.jpg"},
+			}
+
+			Expect(oldConfig.DiffSummary(newConfig)).To(Equal(DiffConfigResponse{
+				Added: []ConfigDiffEntry{
+					{Type: "job", Name: "new-job"},
+					{Type: "display"},
+				},
+				Removed: []ConfigDiffEntry{
+					{Type: "resource", Name: "removed-resource"},
+				},
+				Modified: []ConfigDiffEntry{
+					{Type: "group", Name: "some-group"},
+					{Type: "group", Name: "other-group"},
+					{Type: "resource", Name: "some-resource"},
+				},
+			}))
+		})
+
+		It("returns empty lists when nothing has changed", func() {
+			Expect(oldConfig.DiffSummary(oldConfig)).To(Equal(DiffConfigResponse{
+				Added:    []ConfigDiffEntry{},
+				Removed:  []ConfigDiffEntry{},
+				Modified: []ConfigDiffEntry{},
+			}))
+		})
+	})
+
+	Describe("SummaryDiff", func() {
+		It("prints the number of objects that are added, removed and modified", func() {
+			oldConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+					{Name: "removed-resource", Type: "git"},
+				},
+				Jobs: JobConfigs{
+					{Name: "some-job"},
+				},
+			}
+
+			newConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "time"},
+				},
+				Jobs: JobConfigs{
+					{Name: "some-job"},
+					{Name: "new-job"},
+					{Name: "other-new-job"},
+				},
+			}
+
+			buffer := NewBuffer()
+			diff := oldConfig.SummaryDiff(buffer, newConfig)
+			Expect(diff).To(BeTrue())
+			Expect(string(buffer.Contents())).To(Equal("+2 jobs, -1 resource, ~1 resource\n"))
+		})
+
+		It("prints nothing when nothing has changed", func() {
+			buffer := NewBuffer()
+			diff := Config{}.SummaryDiff(buffer, Config{})
+			Expect(diff).To(BeFalse())
+			Expect(buffer.Contents()).To(BeEmpty())
+		})
+	})
+
+	Describe("section counts", func() {
+		It("prints the number of added, removed and unchanged objects before each section", func() {
+			oldConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "unchanged-resource", Type: "git"},
+					{Name: "changed-resource", Type: "git"},
+					{Name: "removed-resource", Type: "git"},
+				},
+				Jobs: JobConfigs{
+					{Name: "some-job"},
+				},
+			}
+
+			newConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "unchanged-resource", Type: "git"},
+					{Name: "changed-resource", Type: "time"},
+					{Name: "added-resource", Type: "git"},
+					{Name: "other-added-resource", Type: "git"},
+				},
+				Jobs: JobConfigs{
+					{Name: "some-job"},
+				},
+			}
+
+			buffer := NewBuffer()
+			diff := oldConfig.UnifiedDiff(buffer, newConfig)
+			Expect(diff).To(BeTrue())
+			Expect(buffer).To(Say("# 2 added, 1 removed, 1 unchanged\nresources:\n"))
+			Expect(string(buffer.Contents())).ToNot(ContainSubstring("jobs:"))
+		})
+	})
+
+	Describe("UnifiedDiff", func() {
+		It("prints the changes without terminal formatting", func() {
+			oldConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+				},
+			}
+
+			newConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "time"},
+				},
+			}
+
+			buffer := NewBuffer()
+			diff := oldConfig.UnifiedDiff(buffer, newConfig)
+			Expect(diff).To(BeTrue())
+			Expect(string(buffer.Contents())).To(Equal("# 0 added, 0 removed, 0 unchanged\nresources:\n  resource some-resource has changed:\n  name: some-resource\n  source: null\n- type: git\n+ type: time\n  \n"))
+		})
+	})
+
+	Describe("SideBySideDiff", func() {
+		It("prints the old and new contents in two columns", func() {
+			oldConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+				},
+			}
+
+			newConfig := Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "time"},
+				},
+			}
+
+			buffer := NewBuffer()
+			diff := oldConfig.SideBySideDiff(buffer, newConfig)
+			Expect(diff).To(BeTrue())
+			Expect(buffer).To(Say("resource some-resource has changed:"))
+			Expect(buffer).To(Say(`name: some-resource +\| name: some-resource`))
+			Expect(buffer).To(Say(`type: git.*>.*type: time`))
+		})
+	})
 })
@@ -0,0 +1,145 @@
+package atc
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeAction controls how the items of a top-level config key are merged
+// with the items of an existing config. Items are matched by name.
+type MergeAction string
+
+const (
+	// MergeReplace replaces the existing items with the new ones.
+	MergeReplace MergeAction = "replace"
+
+	// MergeUpsert adds new items and updates existing ones, leaving items
+	// which are not in the new config alone.
+	MergeUpsert MergeAction = "upsert"
+
+	// MergeUnion adds new items and never removes or changes existing ones.
+	// Groups with the same name get the union of their jobs and resources.
+	MergeUnion MergeAction = "union"
+)
+
+// MergeStrategy configures the MergeAction for each top-level config key.
+// Keys which are not configured are replaced.
+type MergeStrategy struct {
+	Jobs          MergeAction `json:"jobs,omitempty"`
+	Resources     MergeAction `json:"resources,omitempty"`
+	ResourceTypes MergeAction `json:"resource_types,omitempty"`
+	Groups        MergeAction `json:"groups,omitempty"`
+}
+
+func (strategy MergeStrategy) Validate() error {
+	for key, action := range map[string]MergeAction{
+		"jobs":           strategy.Jobs,
+		"resources":      strategy.Resources,
+		"resource_types": strategy.ResourceTypes,
+		"groups":         strategy.Groups,
+	} {
+		switch action {
+		case "", MergeReplace, MergeUpsert, MergeUnion:
+		default:
+			return fmt.Errorf("unknown merge strategy for %s: %s", key, action)
+		}
+	}
+
+	return nil
+}
+
+// MergeWith returns the config resulting from merging other into c according
+// to the strategy. Everything other than jobs, resources, resource types and
+// groups is taken from other.
+func (c Config) MergeWith(other Config, strategy MergeStrategy) Config {
+	merged := other
+
+	merged.Jobs = mergeNamed(strategy.Jobs, c.Jobs, other.Jobs).(JobConfigs)
+	merged.Resources = mergeNamed(strategy.Resources, c.Resources, other.Resources).(ResourceConfigs)
+	merged.ResourceTypes = mergeNamed(strategy.ResourceTypes, c.ResourceTypes, other.ResourceTypes).(ResourceTypes)
+
+	if strategy.Groups == MergeUnion {
+		merged.Groups = unionGroups(c.Groups, other.Groups)
+	} else {
+		merged.Groups = mergeNamed(strategy.Groups, c.Groups, other.Groups).(GroupConfigs)
+	}
+
+	return merged
+}
+
+// mergeNamed merges two slices of the same type whose elements have a Name
+// field.
+func mergeNamed(action MergeAction, base interface{}, other interface{}) interface{} {
+	if action == "" || action == MergeReplace {
+		return other
+	}
+
+	baseValue := reflect.ValueOf(base)
+	otherValue := reflect.ValueOf(other)
+
+	otherIndexes := map[string]int{}
+	for i := 0; i < otherValue.Len(); i++ {
+		otherIndexes[name(otherValue.Index(i).Interface())] = i
+	}
+
+	merged := reflect.MakeSlice(baseValue.Type(), 0, baseValue.Len()+otherValue.Len())
+	seen := map[string]bool{}
+
+	for i := 0; i < baseValue.Len(); i++ {
+		item := baseValue.Index(i)
+		itemName := name(item.Interface())
+		seen[itemName] = true
+
+		if j, found := otherIndexes[itemName]; found && action == MergeUpsert {
+			item = otherValue.Index(j)
+		}
+
+		merged = reflect.Append(merged, item)
+	}
+
+	for i := 0; i < otherValue.Len(); i++ {
+		item := otherValue.Index(i)
+		if !seen[name(item.Interface())] {
+			merged = reflect.Append(merged, item)
+		}
+	}
+
+	if merged.Len() == 0 {
+		return reflect.Zero(baseValue.Type()).Interface()
+	}
+
+	return merged.Interface()
+}
+
+func unionGroups(base GroupConfigs, other GroupConfigs) GroupConfigs {
+	merged := mergeNamed(MergeUnion, base, other).(GroupConfigs)
+
+	for i, group := range merged {
+		otherGroup, _, found := other.Lookup(group.Name)
+		if !found {
+			continue
+		}
+
+		merged[i] = GroupConfig{
+			Name:      group.Name,
+			Jobs:      unionStrings(group.Jobs, otherGroup.Jobs),
+			Resources: unionStrings(group.Resources, otherGroup.Resources),
+		}
+	}
+
+	return merged
+}
+
+func unionStrings(base []string, other []string) []string {
+	seen := map[string]bool{}
+
+	var union []string
+	for _, s := range append(append([]string{}, base...), other...) {
+		if !seen[s] {
+			seen[s] = true
+			union = append(union, s)
+		}
+	}
+
+	return union
+}
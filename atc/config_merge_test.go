@@ -0,0 +1,84 @@
+package atc_test
+
+import (
+	. "github.com/concourse/concourse/atc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config merge", func() {
+	var existing, other Config
+
+	BeforeEach(func() {
+		existing = Config{
+			Groups: GroupConfigs{
+				{Name: "some-group", Jobs: []string{"job-a"}},
+			},
+			Resources: ResourceConfigs{
+				{Name: "resource-a", Type: "git"},
+				{Name: "resource-b", Type: "git"},
+			},
+			Jobs: JobConfigs{
+				{Name: "job-a", Public: false},
+				{Name: "job-b"},
+			},
+		}
+
+		other = Config{
+			Groups: GroupConfigs{
+				{Name: "some-group", Jobs: []string{"job-a", "job-c"}},
+				{Name: "other-group", Jobs: []string{"job-c"}},
+			},
+			Resources: ResourceConfigs{
+				{Name: "resource-a", Type: "registry-image"},
+				{Name: "resource-c", Type: "git"},
+			},
+			Jobs: JobConfigs{
+				{Name: "job-a", Public: true},
+				{Name: "job-c"},
+			},
+		}
+	})
+
+	It("replaces every key by default", func() {
+		Expect(existing.MergeWith(other, MergeStrategy{})).To(Equal(other))
+	})
+
+	It("upserts items by name", func() {
+		merged := existing.MergeWith(other, MergeStrategy{Jobs: MergeUpsert, Resources: MergeUpsert})
+		Expect(merged.Jobs).To(Equal(JobConfigs{
+			{Name: "job-a", Public: true},
+			{Name: "job-b"},
+			{Name: "job-c"},
+		}))
+		Expect(merged.Resources).To(Equal(ResourceConfigs{
+			{Name: "resource-a", Type: "registry-image"},
+			{Name: "resource-b", Type: "git"},
+			{Name: "resource-c", Type: "git"},
+		}))
+		Expect(merged.Groups).To(Equal(other.Groups))
+	})
+
+	It("adds new items without changing existing ones for union", func() {
+		merged := existing.MergeWith(other, MergeStrategy{Jobs: MergeUnion, Groups: MergeUnion})
+		Expect(merged.Jobs).To(Equal(JobConfigs{
+			{Name: "job-a", Public: false},
+			{Name: "job-b"},
+			{Name: "job-c"},
+		}))
+		Expect(merged.Groups).To(Equal(GroupConfigs{
+			{Name: "some-group", Jobs: []string{"job-a", "job-c"}},
+			{Name: "other-group", Jobs: []string{"job-c"}},
+		}))
+	})
+
+	Describe("MergeStrategy.Validate", func() {
+		It("rejects unknown actions", func() {
+			Expect(MergeStrategy{Resources: "bogus"}.Validate()).To(MatchError("unknown merge strategy for resources: bogus"))
+		})
+
+		It("accepts known actions", func() {
+			Expect(MergeStrategy{Jobs: MergeReplace, Resources: MergeUpsert, Groups: MergeUnion}.Validate()).To(Succeed())
+		})
+	})
+})
@@ -307,6 +307,10 @@ func validateJobs(c atc.Config) ([]atc.ConfigWarning, error) {
 			errorMessages = append(errorMessages, identifier+" has no name")
 		}
 
+		if len(job.PlanSequence) == 0 {
+			errorMessages = append(errorMessages, identifier+" has no steps in its plan")
+		}
+
 		if job.BuildLogRetention != nil && job.BuildLogsToRetain != 0 {
 			errorMessages = append(
 				errorMessages,
@@ -355,6 +359,11 @@ func validateJobs(c atc.Config) ([]atc.ConfigWarning, error) {
 		warnings = append(warnings, validator.Warnings...)
 
 		errorMessages = append(errorMessages, validator.Errors...)
+
+		err = atc.ValidateUniqueStepNames(step)
+		if err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s.plan: %s", identifier, err))
+		}
 	}
 
 	return warnings, compositeErr(errorMessages)
@@ -431,10 +440,16 @@ func validateDisplay(c atc.Config) ([]atc.ConfigWarning, error) {
 		return warnings, nil
 	}
 
-	url, err := url.Parse(c.Display.BackgroundImage)
+	return warnings, ValidateDisplay(*c.Display)
+}
+
+// ValidateDisplay validates a display config, whether it was specified in a
+// pipeline config or elsewhere.
+func ValidateDisplay(display atc.DisplayConfig) error {
+	url, err := url.Parse(display.BackgroundImage)
 
 	if err != nil {
-		return warnings, fmt.Errorf("background_image is not a valid URL: %s", c.Display.BackgroundImage)
+		return fmt.Errorf("background_image is not a valid URL: %s", display.BackgroundImage)
 	}
 
 	switch url.Scheme {
@@ -443,8 +458,8 @@ func validateDisplay(c atc.Config) ([]atc.ConfigWarning, error) {
 	case "":
 		break
 	default:
-		return warnings, fmt.Errorf("background_image scheme must be either http, https or relative")
+		return fmt.Errorf("background_image scheme must be either http, https or relative")
 	}
 
-	return warnings, nil
+	return nil
 }
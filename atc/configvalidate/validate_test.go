@@ -103,6 +103,14 @@ var _ = Describe("ValidateConfig", func() {
 				},
 				{
 					Name: "some-empty-job",
+					PlanSequence: []atc.Step{
+						{
+							Config: &atc.TaskStep{
+								Name:       "some-task",
+								ConfigPath: "some/config/path.yml",
+							},
+						},
+					},
 				},
 			},
 		}
@@ -277,9 +285,23 @@ var _ = Describe("ValidateConfig", func() {
 			BeforeEach(func() {
 				config.Jobs = append(config.Jobs, atc.JobConfig{
 					Name: "stand-alone-job",
+					PlanSequence: []atc.Step{
+						{
+							Config: &atc.GetStep{
+								Name: "some-resource",
+							},
+						},
+					},
 				})
 				config.Jobs = append(config.Jobs, atc.JobConfig{
 					Name: "other-stand-alone-job",
+					PlanSequence: []atc.Step{
+						{
+							Config: &atc.GetStep{
+								Name: "some-resource",
+							},
+						},
+					},
 				})
 			})
 
@@ -649,7 +671,7 @@ var _ = Describe("ValidateConfig", func() {
 							{
 								Config: &atc.OnAbortStep{
 									Step: &atc.TaskStep{
-										Name:       "some-task",
+										Name:       "abort-task",
 										ConfigPath: "some/config/path.yml",
 									},
 									Hook: atc.Step{
@@ -662,7 +684,7 @@ var _ = Describe("ValidateConfig", func() {
 							{
 								Config: &atc.OnErrorStep{
 									Step: &atc.TaskStep{
-										Name:       "some-task",
+										Name:       "error-task",
 										ConfigPath: "some/config/path.yml",
 									},
 									Hook: atc.Step{
@@ -675,7 +697,7 @@ var _ = Describe("ValidateConfig", func() {
 							{
 								Config: &atc.OnFailureStep{
 									Step: &atc.TaskStep{
-										Name:       "some-task",
+										Name:       "failure-task",
 										ConfigPath: "some/config/path.yml",
 									},
 									Hook: atc.Step{
@@ -688,7 +710,7 @@ var _ = Describe("ValidateConfig", func() {
 							{
 								Config: &atc.OnSuccessStep{
 									Step: &atc.TaskStep{
-										Name:       "some-task",
+										Name:       "success-task",
 										ConfigPath: "some/config/path.yml",
 									},
 									Hook: atc.Step{
@@ -701,7 +723,7 @@ var _ = Describe("ValidateConfig", func() {
 							{
 								Config: &atc.EnsureStep{
 									Step: &atc.TaskStep{
-										Name:       "some-task",
+										Name:       "ensure-task",
 										ConfigPath: "some/config/path.yml",
 									},
 									Hook: atc.Step{
@@ -827,6 +849,17 @@ var _ = Describe("ValidateConfig", func() {
 			config.Groups = []atc.GroupConfig{}
 		})
 
+		Context("when a job has no steps", func() {
+			BeforeEach(func() {
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job has no steps in its plan"))
+			})
+		})
+
 		Context("when a job has no name", func() {
 			BeforeEach(func() {
 				job.Name = ""
@@ -880,6 +913,10 @@ var _ = Describe("ValidateConfig", func() {
 				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan.do[1].get(some-resource): repeated name"))
 				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan.do[2].get(some-resource): repeated name"))
 			})
+
+			It("does not also report the duplicate names", func() {
+				Expect(errorMessages[0]).ToNot(ContainSubstring("duplicate step names"))
+			})
 		})
 
 		Context("when a job has duplicate inputs with different resources", func() {
@@ -914,6 +951,82 @@ var _ = Describe("ValidateConfig", func() {
 			})
 		})
 
+		Context("when a job has steps with the same name", func() {
+			BeforeEach(func() {
+				job.PlanSequence = append(job.PlanSequence, atc.Step{
+					Config: &atc.SetPipelineStep{
+						Name: "some-pipeline",
+						File: "some-file",
+					},
+				})
+				job.PlanSequence = append(job.PlanSequence, atc.Step{
+					Config: &atc.InParallelStep{
+						Config: atc.InParallelConfig{
+							Steps: []atc.Step{
+								{
+									Config: &atc.TryStep{
+										Step: atc.Step{
+											Config: &atc.SetPipelineStep{
+												Name: "some-pipeline",
+												File: "some-other-file",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				})
+				job.PlanSequence = append(job.PlanSequence, atc.Step{
+					Config: &atc.TaskStep{
+						Name:       "some-step",
+						ConfigPath: "some-file",
+					},
+				})
+				job.PlanSequence = append(job.PlanSequence, atc.Step{
+					Config: &atc.DoStep{
+						Steps: []atc.Step{
+							{
+								Config: &atc.LoadVarStep{
+									Name: "some-step",
+									File: "some-file",
+								},
+							},
+						},
+					},
+				})
+
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid jobs:"))
+				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan: duplicate step names: some-pipeline, some-step"))
+			})
+		})
+
+		Context("when a job gets and puts the same resource", func() {
+			BeforeEach(func() {
+				job.PlanSequence = append(job.PlanSequence, atc.Step{
+					Config: &atc.GetStep{
+						Name: "some-resource",
+					},
+				})
+				job.PlanSequence = append(job.PlanSequence, atc.Step{
+					Config: &atc.PutStep{
+						Name: "some-resource",
+					},
+				})
+
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns no errors", func() {
+				Expect(errorMessages).To(HaveLen(0))
+			})
+		})
+
 		Context("when a job gets the same resource multiple times but with different names", func() {
 			BeforeEach(func() {
 				job.PlanSequence = append(job.PlanSequence, atc.Step{
@@ -1134,6 +1247,15 @@ var _ = Describe("ValidateConfig", func() {
 						},
 					}
 
+					job.PlanSequence = []atc.Step{
+						{
+							Config: &atc.TaskStep{
+								Name:       "some-task",
+								ConfigPath: "some/config/path.yml",
+							},
+						},
+					}
+
 					config.Jobs = append(config.Jobs, job)
 				})
 
@@ -1168,6 +1290,15 @@ var _ = Describe("ValidateConfig", func() {
 						},
 					}
 
+					job.PlanSequence = []atc.Step{
+						{
+							Config: &atc.TaskStep{
+								Name:       "some-task",
+								ConfigPath: "some/config/path.yml",
+							},
+						},
+					}
+
 					config.Jobs = append(config.Jobs, job)
 				})
 
@@ -1202,6 +1333,15 @@ var _ = Describe("ValidateConfig", func() {
 						},
 					}
 
+					job.PlanSequence = []atc.Step{
+						{
+							Config: &atc.TaskStep{
+								Name:       "some-task",
+								ConfigPath: "some/config/path.yml",
+							},
+						},
+					}
+
 					config.Jobs = append(config.Jobs, job)
 				})
 
@@ -1236,6 +1376,15 @@ var _ = Describe("ValidateConfig", func() {
 						},
 					}
 
+					job.PlanSequence = []atc.Step{
+						{
+							Config: &atc.TaskStep{
+								Name:       "some-task",
+								ConfigPath: "some/config/path.yml",
+							},
+						},
+					}
+
 					config.Jobs = append(config.Jobs, job)
 				})
 
@@ -1270,6 +1419,15 @@ var _ = Describe("ValidateConfig", func() {
 						},
 					}
 
+					job.PlanSequence = []atc.Step{
+						{
+							Config: &atc.TaskStep{
+								Name:       "some-task",
+								ConfigPath: "some/config/path.yml",
+							},
+						},
+					}
+
 					config.Jobs = append(config.Jobs, job)
 				})
 
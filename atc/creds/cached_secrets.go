@@ -3,6 +3,7 @@ package creds
 import (
 	"time"
 
+	"github.com/concourse/concourse/vars"
 	"github.com/patrickmn/go-cache"
 )
 
@@ -77,3 +78,30 @@ func (cs *CachedSecrets) Get(secretPath string) (interface{}, *time.Time, bool,
 func (cs *CachedSecrets) NewSecretLookupPaths(teamName string, pipelineName string, allowRootPath bool) []SecretLookupPath {
 	return cs.secrets.NewSecretLookupPaths(teamName, pipelineName, allowRootPath)
 }
+
+// Invalidate removes the secret from the cache, so that it is fetched from the
+// underlying secret manager the next time it is used.
+func (cs *CachedSecrets) Invalidate(secretPath string) {
+	cs.cache.Delete(secretPath)
+}
+
+// InvalidateVariable removes every secret the var may be looked up from by the
+// given team's pipeline from the cache.
+func (cs *CachedSecrets) InvalidateVariable(teamName string, pipelineName string, ref vars.Reference) error {
+	lookupPaths := cs.NewSecretLookupPaths(teamName, pipelineName, false)
+	if len(lookupPaths) == 0 {
+		cs.Invalidate(ref.Path)
+		return nil
+	}
+
+	for _, rule := range lookupPaths {
+		secretPath, err := rule.VariableToSecretPath(ref.Path)
+		if err != nil {
+			return err
+		}
+
+		cs.Invalidate(secretPath)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/concourse/concourse/atc/creds"
 	"github.com/concourse/concourse/atc/creds/credsfakes"
+	"github.com/concourse/concourse/vars"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -162,4 +163,38 @@ var _ = Describe("Caching of secrets", func() {
 		Expect(underlyingMisses).To(BeIdenticalTo(4))
 	})
 
+	It("should fetch invalidated secrets again", func() {
+		secretManager.GetStub = makeGetStub("foo", "value", nil, true, nil, &underlyingReads, &underlyingMisses)
+
+		_, _, _, _ = cachedSecretManager.Get("foo")
+		_, _, _, _ = cachedSecretManager.Get("foo")
+		Expect(underlyingReads).To(BeIdenticalTo(1))
+
+		cachedSecretManager.Invalidate("foo")
+
+		_, _, _, _ = cachedSecretManager.Get("foo")
+		Expect(underlyingReads).To(BeIdenticalTo(2))
+	})
+
+	It("should invalidate every lookup path of a var", func() {
+		secretManager.NewSecretLookupPathsReturns([]creds.SecretLookupPath{
+			creds.NewSecretLookupWithPrefix("/concourse/some-team/some-pipeline/"),
+			creds.NewSecretLookupWithPrefix("/concourse/some-team/"),
+		})
+		secretManager.GetReturns("value", nil, true, nil)
+
+		_, _, _, _ = cachedSecretManager.Get("/concourse/some-team/some-pipeline/foo")
+		_, _, _, _ = cachedSecretManager.Get("/concourse/some-team/foo")
+		_, _, _, _ = cachedSecretManager.Get("/concourse/some-team/bar")
+		Expect(secretManager.GetCallCount()).To(Equal(3))
+
+		err := cachedSecretManager.InvalidateVariable("some-team", "some-pipeline", vars.Reference{Path: "foo"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, _, _, _ = cachedSecretManager.Get("/concourse/some-team/some-pipeline/foo")
+		_, _, _, _ = cachedSecretManager.Get("/concourse/some-team/foo")
+		_, _, _, _ = cachedSecretManager.Get("/concourse/some-team/bar")
+		Expect(secretManager.GetCallCount()).To(Equal(5))
+	})
+
 })
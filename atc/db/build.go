@@ -27,6 +27,7 @@ const schema = "exec.v2"
 
 var ErrAdoptRerunBuildHasNoInputs = errors.New("inputs not ready for build to rerun")
 var ErrSetByNewerBuild = errors.New("pipeline set by a newer build")
+var ErrReadOnlyMode = errors.New("ATC is in read-only mode")
 
 type BuildInput struct {
 	Name       string
@@ -173,6 +174,12 @@ type Build interface {
 
 	SetInterceptible(bool) error
 
+	SetPipelineArtifacts() ([]atc.SetPipelineArtifact, error)
+	RecordSetPipelineArtifacts([]atc.SetPipelineArtifact) error
+
+	Annotations() (map[string]string, error)
+	SetAnnotation(key string, value string) error
+
 	Events(uint) (EventSource, error)
 	SaveEvent(event atc.Event) error
 
@@ -415,6 +422,130 @@ func (b *build) Interceptible() (bool, error) {
 	return interceptible, nil
 }
 
+const buildAnnotationsMetadataKey = "annotations"
+
+// Annotations returns the key/value pairs which steps of the build have
+// recorded about it, e.g. the config version set by a set_pipeline step.
+func (b *build) Annotations() (map[string]string, error) {
+	var payload sql.NullString
+	err := psql.Select().
+		Column(sq.Expr("metadata->(?::text)", buildAnnotationsMetadataKey)).
+		From("builds").
+		Where(sq.Eq{"id": b.id}).
+		RunWith(b.conn).
+		QueryRow().
+		Scan(&payload)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := map[string]string{}
+	if payload.Valid {
+		err = json.Unmarshal([]byte(payload.String), &annotations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return annotations, nil
+}
+
+// SetAnnotation records the annotation, replacing any earlier value of the
+// same key.
+func (b *build) SetAnnotation(key string, value string) error {
+	rows, err := psql.Update("builds").
+		Set("metadata", sq.Expr(
+			"jsonb_set(metadata, ARRAY[?::text], COALESCE(metadata->(?::text), '{}'::jsonb) || jsonb_build_object(?::text, ?::text))",
+			buildAnnotationsMetadataKey,
+			buildAnnotationsMetadataKey,
+			key,
+			value,
+		)).
+		Where(sq.Eq{
+			"id": b.id,
+		}).
+		RunWith(b.conn).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrBuildDisappeared
+	}
+
+	return nil
+}
+
+const setPipelineArtifactsMetadataKey = "set_pipeline.artifacts"
+
+// SetPipelineArtifacts returns the files streamed by the build's
+// set_pipeline steps.
+func (b *build) SetPipelineArtifacts() ([]atc.SetPipelineArtifact, error) {
+	var payload sql.NullString
+	err := psql.Select().
+		Column(sq.Expr("metadata->(?::text)", setPipelineArtifactsMetadataKey)).
+		From("builds").
+		Where(sq.Eq{"id": b.id}).
+		RunWith(b.conn).
+		QueryRow().
+		Scan(&payload)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := []atc.SetPipelineArtifact{}
+	if payload.Valid {
+		err = json.Unmarshal([]byte(payload.String), &artifacts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return artifacts, nil
+}
+
+// RecordSetPipelineArtifacts appends to the files streamed by the build's
+// set_pipeline steps.
+func (b *build) RecordSetPipelineArtifacts(artifacts []atc.SetPipelineArtifact) error {
+	payload, err := json.Marshal(artifacts)
+	if err != nil {
+		return err
+	}
+
+	rows, err := psql.Update("builds").
+		Set("metadata", sq.Expr(
+			"jsonb_set(metadata, ARRAY[?::text], COALESCE(metadata->(?::text), '[]'::jsonb) || ?::jsonb)",
+			setPipelineArtifactsMetadataKey,
+			setPipelineArtifactsMetadataKey,
+			string(payload),
+		)).
+		Where(sq.Eq{
+			"id": b.id,
+		}).
+		RunWith(b.conn).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrBuildDisappeared
+	}
+
+	return nil
+}
+
 func (b *build) SetInterceptible(i bool) error {
 	rows, err := psql.Update("builds").
 		Set("interceptible", i).
@@ -1710,6 +1841,10 @@ func (b *build) SavePipeline(
 	from ConfigVersion,
 	initiallyPaused bool,
 ) (Pipeline, bool, error) {
+	if atc.ReadOnlyMode {
+		return nil, false, ErrReadOnlyMode
+	}
+
 	tx, err := b.conn.Begin()
 	if err != nil {
 		return nil, false, err
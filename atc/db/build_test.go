@@ -342,6 +342,55 @@ var _ = Describe("Build", func() {
 		})
 	})
 
+	Describe("SetPipelineArtifacts", func() {
+		It("defaults to no artifacts", func() {
+			artifacts, err := build.SetPipelineArtifacts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifacts).To(BeEmpty())
+		})
+
+		It("appends the recorded artifacts", func() {
+			first := atc.SetPipelineArtifact{Name: "some-resource", Path: "pipeline.yml", Digest: "sha256:abc", SizeBytes: 1, StreamedAt: 1}
+			second := atc.SetPipelineArtifact{Name: "some-resource", Path: "vars.yml", Digest: "sha256:def", SizeBytes: 2, StreamedAt: 2}
+
+			err := build.RecordSetPipelineArtifacts([]atc.SetPipelineArtifact{first})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.RecordSetPipelineArtifacts([]atc.SetPipelineArtifact{second})
+			Expect(err).NotTo(HaveOccurred())
+
+			artifacts, err := build.SetPipelineArtifacts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifacts).To(Equal([]atc.SetPipelineArtifact{first, second}))
+		})
+	})
+
+	Describe("Annotations", func() {
+		It("defaults to no annotations", func() {
+			annotations, err := build.Annotations()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotations).To(BeEmpty())
+		})
+
+		It("returns the recorded annotations, keeping the latest value of each key", func() {
+			err := build.SetAnnotation("set_pipeline_version", "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SetAnnotation("other", "value")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SetAnnotation("set_pipeline_version", "2")
+			Expect(err).NotTo(HaveOccurred())
+
+			annotations, err := build.Annotations()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotations).To(Equal(map[string]string{
+				"set_pipeline_version": "2",
+				"other":                "value",
+			}))
+		})
+	})
+
 	Describe("Start", func() {
 		var err error
 		var started bool
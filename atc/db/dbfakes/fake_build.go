@@ -74,6 +74,18 @@ type FakeBuild struct {
 		result2 bool
 		result3 error
 	}
+	AnnotationsStub        func() (map[string]string, error)
+	annotationsMutex       sync.RWMutex
+	annotationsArgsForCall []struct {
+	}
+	annotationsReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	annotationsReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
 	ArtifactStub        func(int) (db.WorkerArtifact, error)
 	artifactMutex       sync.RWMutex
 	artifactArgsForCall []struct {
@@ -416,6 +428,17 @@ type FakeBuild struct {
 	reapTimeReturnsOnCall map[int]struct {
 		result1 time.Time
 	}
+	RecordSetPipelineArtifactsStub        func([]atc.SetPipelineArtifact) error
+	recordSetPipelineArtifactsMutex       sync.RWMutex
+	recordSetPipelineArtifactsArgsForCall []struct {
+		arg1 []atc.SetPipelineArtifact
+	}
+	recordSetPipelineArtifactsReturns struct {
+		result1 error
+	}
+	recordSetPipelineArtifactsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ReloadStub        func() (bool, error)
 	reloadMutex       sync.RWMutex
 	reloadArgsForCall []struct {
@@ -592,6 +615,18 @@ type FakeBuild struct {
 	schemaReturnsOnCall map[int]struct {
 		result1 string
 	}
+	SetAnnotationStub        func(string, string) error
+	setAnnotationMutex       sync.RWMutex
+	setAnnotationArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	setAnnotationReturns struct {
+		result1 error
+	}
+	setAnnotationReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetDrainedStub        func(bool) error
 	setDrainedMutex       sync.RWMutex
 	setDrainedArgsForCall []struct {
@@ -614,6 +649,18 @@ type FakeBuild struct {
 	setInterceptibleReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetPipelineArtifactsStub        func() ([]atc.SetPipelineArtifact, error)
+	setPipelineArtifactsMutex       sync.RWMutex
+	setPipelineArtifactsArgsForCall []struct {
+	}
+	setPipelineArtifactsReturns struct {
+		result1 []atc.SetPipelineArtifact
+		result2 error
+	}
+	setPipelineArtifactsReturnsOnCall map[int]struct {
+		result1 []atc.SetPipelineArtifact
+		result2 error
+	}
 	SpanContextStub        func() propagation.HTTPSupplier
 	spanContextMutex       sync.RWMutex
 	spanContextArgsForCall []struct {
@@ -959,6 +1006,62 @@ func (fake *FakeBuild) AdoptRerunInputsAndPipesReturnsOnCall(i int, result1 []db
 	}{result1, result2, result3}
 }
 
+func (fake *FakeBuild) Annotations() (map[string]string, error) {
+	fake.annotationsMutex.Lock()
+	ret, specificReturn := fake.annotationsReturnsOnCall[len(fake.annotationsArgsForCall)]
+	fake.annotationsArgsForCall = append(fake.annotationsArgsForCall, struct {
+	}{})
+	stub := fake.AnnotationsStub
+	fakeReturns := fake.annotationsReturns
+	fake.recordInvocation("Annotations", []interface{}{})
+	fake.annotationsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBuild) AnnotationsCallCount() int {
+	fake.annotationsMutex.RLock()
+	defer fake.annotationsMutex.RUnlock()
+	return len(fake.annotationsArgsForCall)
+}
+
+func (fake *FakeBuild) AnnotationsCalls(stub func() (map[string]string, error)) {
+	fake.annotationsMutex.Lock()
+	defer fake.annotationsMutex.Unlock()
+	fake.AnnotationsStub = stub
+}
+
+func (fake *FakeBuild) AnnotationsReturns(result1 map[string]string, result2 error) {
+	fake.annotationsMutex.Lock()
+	defer fake.annotationsMutex.Unlock()
+	fake.AnnotationsStub = nil
+	fake.annotationsReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) AnnotationsReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.annotationsMutex.Lock()
+	defer fake.annotationsMutex.Unlock()
+	fake.AnnotationsStub = nil
+	if fake.annotationsReturnsOnCall == nil {
+		fake.annotationsReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.annotationsReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuild) Artifact(arg1 int) (db.WorkerArtifact, error) {
 	fake.artifactMutex.Lock()
 	ret, specificReturn := fake.artifactReturnsOnCall[len(fake.artifactArgsForCall)]
@@ -2714,6 +2817,72 @@ func (fake *FakeBuild) ReapTimeReturnsOnCall(i int, result1 time.Time) {
 	}{result1}
 }
 
+func (fake *FakeBuild) RecordSetPipelineArtifacts(arg1 []atc.SetPipelineArtifact) error {
+	var arg1Copy []atc.SetPipelineArtifact
+	if arg1 != nil {
+		arg1Copy = make([]atc.SetPipelineArtifact, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.recordSetPipelineArtifactsMutex.Lock()
+	ret, specificReturn := fake.recordSetPipelineArtifactsReturnsOnCall[len(fake.recordSetPipelineArtifactsArgsForCall)]
+	fake.recordSetPipelineArtifactsArgsForCall = append(fake.recordSetPipelineArtifactsArgsForCall, struct {
+		arg1 []atc.SetPipelineArtifact
+	}{arg1Copy})
+	stub := fake.RecordSetPipelineArtifactsStub
+	fakeReturns := fake.recordSetPipelineArtifactsReturns
+	fake.recordInvocation("RecordSetPipelineArtifacts", []interface{}{arg1Copy})
+	fake.recordSetPipelineArtifactsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBuild) RecordSetPipelineArtifactsCallCount() int {
+	fake.recordSetPipelineArtifactsMutex.RLock()
+	defer fake.recordSetPipelineArtifactsMutex.RUnlock()
+	return len(fake.recordSetPipelineArtifactsArgsForCall)
+}
+
+func (fake *FakeBuild) RecordSetPipelineArtifactsCalls(stub func([]atc.SetPipelineArtifact) error) {
+	fake.recordSetPipelineArtifactsMutex.Lock()
+	defer fake.recordSetPipelineArtifactsMutex.Unlock()
+	fake.RecordSetPipelineArtifactsStub = stub
+}
+
+func (fake *FakeBuild) RecordSetPipelineArtifactsArgsForCall(i int) []atc.SetPipelineArtifact {
+	fake.recordSetPipelineArtifactsMutex.RLock()
+	defer fake.recordSetPipelineArtifactsMutex.RUnlock()
+	argsForCall := fake.recordSetPipelineArtifactsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBuild) RecordSetPipelineArtifactsReturns(result1 error) {
+	fake.recordSetPipelineArtifactsMutex.Lock()
+	defer fake.recordSetPipelineArtifactsMutex.Unlock()
+	fake.RecordSetPipelineArtifactsStub = nil
+	fake.recordSetPipelineArtifactsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) RecordSetPipelineArtifactsReturnsOnCall(i int, result1 error) {
+	fake.recordSetPipelineArtifactsMutex.Lock()
+	defer fake.recordSetPipelineArtifactsMutex.Unlock()
+	fake.RecordSetPipelineArtifactsStub = nil
+	if fake.recordSetPipelineArtifactsReturnsOnCall == nil {
+		fake.recordSetPipelineArtifactsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordSetPipelineArtifactsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBuild) Reload() (bool, error) {
 	fake.reloadMutex.Lock()
 	ret, specificReturn := fake.reloadReturnsOnCall[len(fake.reloadArgsForCall)]
@@ -3569,6 +3738,68 @@ func (fake *FakeBuild) SchemaReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeBuild) SetAnnotation(arg1 string, arg2 string) error {
+	fake.setAnnotationMutex.Lock()
+	ret, specificReturn := fake.setAnnotationReturnsOnCall[len(fake.setAnnotationArgsForCall)]
+	fake.setAnnotationArgsForCall = append(fake.setAnnotationArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetAnnotationStub
+	fakeReturns := fake.setAnnotationReturns
+	fake.recordInvocation("SetAnnotation", []interface{}{arg1, arg2})
+	fake.setAnnotationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBuild) SetAnnotationCallCount() int {
+	fake.setAnnotationMutex.RLock()
+	defer fake.setAnnotationMutex.RUnlock()
+	return len(fake.setAnnotationArgsForCall)
+}
+
+func (fake *FakeBuild) SetAnnotationCalls(stub func(string, string) error) {
+	fake.setAnnotationMutex.Lock()
+	defer fake.setAnnotationMutex.Unlock()
+	fake.SetAnnotationStub = stub
+}
+
+func (fake *FakeBuild) SetAnnotationArgsForCall(i int) (string, string) {
+	fake.setAnnotationMutex.RLock()
+	defer fake.setAnnotationMutex.RUnlock()
+	argsForCall := fake.setAnnotationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBuild) SetAnnotationReturns(result1 error) {
+	fake.setAnnotationMutex.Lock()
+	defer fake.setAnnotationMutex.Unlock()
+	fake.SetAnnotationStub = nil
+	fake.setAnnotationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) SetAnnotationReturnsOnCall(i int, result1 error) {
+	fake.setAnnotationMutex.Lock()
+	defer fake.setAnnotationMutex.Unlock()
+	fake.SetAnnotationStub = nil
+	if fake.setAnnotationReturnsOnCall == nil {
+		fake.setAnnotationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setAnnotationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBuild) SetDrained(arg1 bool) error {
 	fake.setDrainedMutex.Lock()
 	ret, specificReturn := fake.setDrainedReturnsOnCall[len(fake.setDrainedArgsForCall)]
@@ -3691,6 +3922,62 @@ func (fake *FakeBuild) SetInterceptibleReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBuild) SetPipelineArtifacts() ([]atc.SetPipelineArtifact, error) {
+	fake.setPipelineArtifactsMutex.Lock()
+	ret, specificReturn := fake.setPipelineArtifactsReturnsOnCall[len(fake.setPipelineArtifactsArgsForCall)]
+	fake.setPipelineArtifactsArgsForCall = append(fake.setPipelineArtifactsArgsForCall, struct {
+	}{})
+	stub := fake.SetPipelineArtifactsStub
+	fakeReturns := fake.setPipelineArtifactsReturns
+	fake.recordInvocation("SetPipelineArtifacts", []interface{}{})
+	fake.setPipelineArtifactsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBuild) SetPipelineArtifactsCallCount() int {
+	fake.setPipelineArtifactsMutex.RLock()
+	defer fake.setPipelineArtifactsMutex.RUnlock()
+	return len(fake.setPipelineArtifactsArgsForCall)
+}
+
+func (fake *FakeBuild) SetPipelineArtifactsCalls(stub func() ([]atc.SetPipelineArtifact, error)) {
+	fake.setPipelineArtifactsMutex.Lock()
+	defer fake.setPipelineArtifactsMutex.Unlock()
+	fake.SetPipelineArtifactsStub = stub
+}
+
+func (fake *FakeBuild) SetPipelineArtifactsReturns(result1 []atc.SetPipelineArtifact, result2 error) {
+	fake.setPipelineArtifactsMutex.Lock()
+	defer fake.setPipelineArtifactsMutex.Unlock()
+	fake.SetPipelineArtifactsStub = nil
+	fake.setPipelineArtifactsReturns = struct {
+		result1 []atc.SetPipelineArtifact
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) SetPipelineArtifactsReturnsOnCall(i int, result1 []atc.SetPipelineArtifact, result2 error) {
+	fake.setPipelineArtifactsMutex.Lock()
+	defer fake.setPipelineArtifactsMutex.Unlock()
+	fake.SetPipelineArtifactsStub = nil
+	if fake.setPipelineArtifactsReturnsOnCall == nil {
+		fake.setPipelineArtifactsReturnsOnCall = make(map[int]struct {
+			result1 []atc.SetPipelineArtifact
+			result2 error
+		})
+	}
+	fake.setPipelineArtifactsReturnsOnCall[i] = struct {
+		result1 []atc.SetPipelineArtifact
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuild) SpanContext() propagation.HTTPSupplier {
 	fake.spanContextMutex.Lock()
 	ret, specificReturn := fake.spanContextReturnsOnCall[len(fake.spanContextArgsForCall)]
@@ -4201,6 +4488,8 @@ func (fake *FakeBuild) VariablesReturnsOnCall(i int, result1 vars.Variables, res
 }
 
 func (fake *FakeBuild) Invocations() map[string][][]interface{} {
+	fake.annotationsMutex.RLock()
+	defer fake.annotationsMutex.RUnlock()
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.abortNotifierMutex.RLock()
@@ -4275,6 +4564,8 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.publicPlanMutex.RUnlock()
 	fake.reapTimeMutex.RLock()
 	defer fake.reapTimeMutex.RUnlock()
+	fake.recordSetPipelineArtifactsMutex.RLock()
+	defer fake.recordSetPipelineArtifactsMutex.RUnlock()
 	fake.reloadMutex.RLock()
 	defer fake.reloadMutex.RUnlock()
 	fake.rerunNumberMutex.RLock()
@@ -4305,10 +4596,14 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.savePipelineMutex.RUnlock()
 	fake.schemaMutex.RLock()
 	defer fake.schemaMutex.RUnlock()
+	fake.setAnnotationMutex.RLock()
+	defer fake.setAnnotationMutex.RUnlock()
 	fake.setDrainedMutex.RLock()
 	defer fake.setDrainedMutex.RUnlock()
 	fake.setInterceptibleMutex.RLock()
 	defer fake.setInterceptibleMutex.RUnlock()
+	fake.setPipelineArtifactsMutex.RLock()
+	defer fake.setPipelineArtifactsMutex.RUnlock()
 	fake.spanContextMutex.RLock()
 	defer fake.spanContextMutex.RUnlock()
 	fake.startMutex.RLock()
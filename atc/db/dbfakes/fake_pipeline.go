@@ -88,6 +88,36 @@ type FakePipeline struct {
 		result1 bool
 		result2 error
 	}
+	ClearArchiveAfterStub        func() error
+	clearArchiveAfterMutex       sync.RWMutex
+	clearArchiveAfterArgsForCall []struct {
+	}
+	clearArchiveAfterReturns struct {
+		result1 error
+	}
+	clearArchiveAfterReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ClearNotificationsStub        func() error
+	clearNotificationsMutex       sync.RWMutex
+	clearNotificationsArgsForCall []struct {
+	}
+	clearNotificationsReturns struct {
+		result1 error
+	}
+	clearNotificationsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ClearOwnershipStub        func() error
+	clearOwnershipMutex       sync.RWMutex
+	clearOwnershipArgsForCall []struct {
+	}
+	clearOwnershipReturns struct {
+		result1 error
+	}
+	clearOwnershipReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ConfigStub        func() (atc.Config, error)
 	configMutex       sync.RWMutex
 	configArgsForCall []struct {
@@ -100,6 +130,16 @@ type FakePipeline struct {
 		result1 atc.Config
 		result2 error
 	}
+	ConfigHashStub        func() string
+	configHashMutex       sync.RWMutex
+	configHashArgsForCall []struct {
+	}
+	configHashReturns struct {
+		result1 string
+	}
+	configHashReturnsOnCall map[int]struct {
+		result1 string
+	}
 	ConfigVersionStub        func() db.ConfigVersion
 	configVersionMutex       sync.RWMutex
 	configVersionArgsForCall []struct {
@@ -158,6 +198,16 @@ type FakePipeline struct {
 	deleteBuildEventsByBuildIDsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DescriptionStub        func() string
+	descriptionMutex       sync.RWMutex
+	descriptionArgsForCall []struct {
+	}
+	descriptionReturns struct {
+		result1 string
+	}
+	descriptionReturnsOnCall map[int]struct {
+		result1 string
+	}
 	DestroyStub        func() error
 	destroyMutex       sync.RWMutex
 	destroyArgsForCall []struct {
@@ -188,6 +238,16 @@ type FakePipeline struct {
 	exposeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	FrozenStub        func() bool
+	frozenMutex       sync.RWMutex
+	frozenArgsForCall []struct {
+	}
+	frozenReturns struct {
+		result1 bool
+	}
+	frozenReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	GetBuildsWithVersionAsInputStub        func(int, int) ([]db.Build, error)
 	getBuildsWithVersionAsInputMutex       sync.RWMutex
 	getBuildsWithVersionAsInputArgsForCall []struct {
@@ -315,6 +375,34 @@ type FakePipeline struct {
 	nameReturnsOnCall map[int]struct {
 		result1 string
 	}
+	NotificationsStub        func() (atc.PipelineNotifications, bool, error)
+	notificationsMutex       sync.RWMutex
+	notificationsArgsForCall []struct {
+	}
+	notificationsReturns struct {
+		result1 atc.PipelineNotifications
+		result2 bool
+		result3 error
+	}
+	notificationsReturnsOnCall map[int]struct {
+		result1 atc.PipelineNotifications
+		result2 bool
+		result3 error
+	}
+	OwnershipStub        func() (atc.PipelineOwnership, bool, error)
+	ownershipMutex       sync.RWMutex
+	ownershipArgsForCall []struct {
+	}
+	ownershipReturns struct {
+		result1 atc.PipelineOwnership
+		result2 bool
+		result3 error
+	}
+	ownershipReturnsOnCall map[int]struct {
+		result1 atc.PipelineOwnership
+		result2 bool
+		result3 error
+	}
 	ParentBuildIDStub        func() int
 	parentBuildIDMutex       sync.RWMutex
 	parentBuildIDArgsForCall []struct {
@@ -365,6 +453,17 @@ type FakePipeline struct {
 	publicReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	RecordRollbackStub        func(db.PipelineRollback) error
+	recordRollbackMutex       sync.RWMutex
+	recordRollbackArgsForCall []struct {
+		arg1 db.PipelineRollback
+	}
+	recordRollbackReturns struct {
+		result1 error
+	}
+	recordRollbackReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ReloadStub        func() (bool, error)
 	reloadMutex       sync.RWMutex
 	reloadArgsForCall []struct {
@@ -377,6 +476,16 @@ type FakePipeline struct {
 		result1 bool
 		result2 error
 	}
+	ResetBuildHistoryStub        func() error
+	resetBuildHistoryMutex       sync.RWMutex
+	resetBuildHistoryArgsForCall []struct {
+	}
+	resetBuildHistoryReturns struct {
+		result1 error
+	}
+	resetBuildHistoryReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ResourceStub        func(string) (db.Resource, bool, error)
 	resourceMutex       sync.RWMutex
 	resourceArgsForCall []struct {
@@ -476,6 +585,61 @@ type FakePipeline struct {
 		result1 db.Resources
 		result2 error
 	}
+	SetArchiveAfterStub        func(time.Duration) error
+	setArchiveAfterMutex       sync.RWMutex
+	setArchiveAfterArgsForCall []struct {
+		arg1 time.Duration
+	}
+	setArchiveAfterReturns struct {
+		result1 error
+	}
+	setArchiveAfterReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetDescriptionStub        func(string) error
+	setDescriptionMutex       sync.RWMutex
+	setDescriptionArgsForCall []struct {
+		arg1 string
+	}
+	setDescriptionReturns struct {
+		result1 error
+	}
+	setDescriptionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetFrozenStub        func(bool) error
+	setFrozenMutex       sync.RWMutex
+	setFrozenArgsForCall []struct {
+		arg1 bool
+	}
+	setFrozenReturns struct {
+		result1 error
+	}
+	setFrozenReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetNotificationsStub        func(atc.PipelineNotifications) error
+	setNotificationsMutex       sync.RWMutex
+	setNotificationsArgsForCall []struct {
+		arg1 atc.PipelineNotifications
+	}
+	setNotificationsReturns struct {
+		result1 error
+	}
+	setNotificationsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetOwnershipStub        func(atc.PipelineOwnership) error
+	setOwnershipMutex       sync.RWMutex
+	setOwnershipArgsForCall []struct {
+		arg1 atc.PipelineOwnership
+	}
+	setOwnershipReturns struct {
+		result1 error
+	}
+	setOwnershipReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetParentIDsStub        func(int, int) error
 	setParentIDsMutex       sync.RWMutex
 	setParentIDsArgsForCall []struct {
@@ -508,6 +672,16 @@ type FakePipeline struct {
 	teamNameReturnsOnCall map[int]struct {
 		result1 string
 	}
+	TriggerImmediateResourceChecksStub        func() error
+	triggerImmediateResourceChecksMutex       sync.RWMutex
+	triggerImmediateResourceChecksArgsForCall []struct {
+	}
+	triggerImmediateResourceChecksReturns struct {
+		result1 error
+	}
+	triggerImmediateResourceChecksReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UnpauseStub        func() error
 	unpauseMutex       sync.RWMutex
 	unpauseArgsForCall []struct {
@@ -518,6 +692,17 @@ type FakePipeline struct {
 	unpauseReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateDisplayStub        func(atc.DisplayConfig) error
+	updateDisplayMutex       sync.RWMutex
+	updateDisplayArgsForCall []struct {
+		arg1 atc.DisplayConfig
+	}
+	updateDisplayReturns struct {
+		result1 error
+	}
+	updateDisplayReturnsOnCall map[int]struct {
+		result1 error
+	}
 	VarSourcesStub        func() atc.VarSourceConfigs
 	varSourcesMutex       sync.RWMutex
 	varSourcesArgsForCall []struct {
@@ -907,6 +1092,165 @@ func (fake *FakePipeline) CheckPausedReturnsOnCall(i int, result1 bool, result2
 	}{result1, result2}
 }
 
+func (fake *FakePipeline) ClearArchiveAfter() error {
+	fake.clearArchiveAfterMutex.Lock()
+	ret, specificReturn := fake.clearArchiveAfterReturnsOnCall[len(fake.clearArchiveAfterArgsForCall)]
+	fake.clearArchiveAfterArgsForCall = append(fake.clearArchiveAfterArgsForCall, struct {
+	}{})
+	stub := fake.ClearArchiveAfterStub
+	fakeReturns := fake.clearArchiveAfterReturns
+	fake.recordInvocation("ClearArchiveAfter", []interface{}{})
+	fake.clearArchiveAfterMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) ClearArchiveAfterCallCount() int {
+	fake.clearArchiveAfterMutex.RLock()
+	defer fake.clearArchiveAfterMutex.RUnlock()
+	return len(fake.clearArchiveAfterArgsForCall)
+}
+
+func (fake *FakePipeline) ClearArchiveAfterCalls(stub func() error) {
+	fake.clearArchiveAfterMutex.Lock()
+	defer fake.clearArchiveAfterMutex.Unlock()
+	fake.ClearArchiveAfterStub = stub
+}
+
+func (fake *FakePipeline) ClearArchiveAfterReturns(result1 error) {
+	fake.clearArchiveAfterMutex.Lock()
+	defer fake.clearArchiveAfterMutex.Unlock()
+	fake.ClearArchiveAfterStub = nil
+	fake.clearArchiveAfterReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ClearArchiveAfterReturnsOnCall(i int, result1 error) {
+	fake.clearArchiveAfterMutex.Lock()
+	defer fake.clearArchiveAfterMutex.Unlock()
+	fake.ClearArchiveAfterStub = nil
+	if fake.clearArchiveAfterReturnsOnCall == nil {
+		fake.clearArchiveAfterReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clearArchiveAfterReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ClearNotifications() error {
+	fake.clearNotificationsMutex.Lock()
+	ret, specificReturn := fake.clearNotificationsReturnsOnCall[len(fake.clearNotificationsArgsForCall)]
+	fake.clearNotificationsArgsForCall = append(fake.clearNotificationsArgsForCall, struct {
+	}{})
+	stub := fake.ClearNotificationsStub
+	fakeReturns := fake.clearNotificationsReturns
+	fake.recordInvocation("ClearNotifications", []interface{}{})
+	fake.clearNotificationsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) ClearNotificationsCallCount() int {
+	fake.clearNotificationsMutex.RLock()
+	defer fake.clearNotificationsMutex.RUnlock()
+	return len(fake.clearNotificationsArgsForCall)
+}
+
+func (fake *FakePipeline) ClearNotificationsCalls(stub func() error) {
+	fake.clearNotificationsMutex.Lock()
+	defer fake.clearNotificationsMutex.Unlock()
+	fake.ClearNotificationsStub = stub
+}
+
+func (fake *FakePipeline) ClearNotificationsReturns(result1 error) {
+	fake.clearNotificationsMutex.Lock()
+	defer fake.clearNotificationsMutex.Unlock()
+	fake.ClearNotificationsStub = nil
+	fake.clearNotificationsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ClearNotificationsReturnsOnCall(i int, result1 error) {
+	fake.clearNotificationsMutex.Lock()
+	defer fake.clearNotificationsMutex.Unlock()
+	fake.ClearNotificationsStub = nil
+	if fake.clearNotificationsReturnsOnCall == nil {
+		fake.clearNotificationsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clearNotificationsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ClearOwnership() error {
+	fake.clearOwnershipMutex.Lock()
+	ret, specificReturn := fake.clearOwnershipReturnsOnCall[len(fake.clearOwnershipArgsForCall)]
+	fake.clearOwnershipArgsForCall = append(fake.clearOwnershipArgsForCall, struct {
+	}{})
+	stub := fake.ClearOwnershipStub
+	fakeReturns := fake.clearOwnershipReturns
+	fake.recordInvocation("ClearOwnership", []interface{}{})
+	fake.clearOwnershipMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) ClearOwnershipCallCount() int {
+	fake.clearOwnershipMutex.RLock()
+	defer fake.clearOwnershipMutex.RUnlock()
+	return len(fake.clearOwnershipArgsForCall)
+}
+
+func (fake *FakePipeline) ClearOwnershipCalls(stub func() error) {
+	fake.clearOwnershipMutex.Lock()
+	defer fake.clearOwnershipMutex.Unlock()
+	fake.ClearOwnershipStub = stub
+}
+
+func (fake *FakePipeline) ClearOwnershipReturns(result1 error) {
+	fake.clearOwnershipMutex.Lock()
+	defer fake.clearOwnershipMutex.Unlock()
+	fake.ClearOwnershipStub = nil
+	fake.clearOwnershipReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ClearOwnershipReturnsOnCall(i int, result1 error) {
+	fake.clearOwnershipMutex.Lock()
+	defer fake.clearOwnershipMutex.Unlock()
+	fake.ClearOwnershipStub = nil
+	if fake.clearOwnershipReturnsOnCall == nil {
+		fake.clearOwnershipReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clearOwnershipReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) Config() (atc.Config, error) {
 	fake.configMutex.Lock()
 	ret, specificReturn := fake.configReturnsOnCall[len(fake.configArgsForCall)]
@@ -963,6 +1307,59 @@ func (fake *FakePipeline) ConfigReturnsOnCall(i int, result1 atc.Config, result2
 	}{result1, result2}
 }
 
+func (fake *FakePipeline) ConfigHash() string {
+	fake.configHashMutex.Lock()
+	ret, specificReturn := fake.configHashReturnsOnCall[len(fake.configHashArgsForCall)]
+	fake.configHashArgsForCall = append(fake.configHashArgsForCall, struct {
+	}{})
+	stub := fake.ConfigHashStub
+	fakeReturns := fake.configHashReturns
+	fake.recordInvocation("ConfigHash", []interface{}{})
+	fake.configHashMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) ConfigHashCallCount() int {
+	fake.configHashMutex.RLock()
+	defer fake.configHashMutex.RUnlock()
+	return len(fake.configHashArgsForCall)
+}
+
+func (fake *FakePipeline) ConfigHashCalls(stub func() string) {
+	fake.configHashMutex.Lock()
+	defer fake.configHashMutex.Unlock()
+	fake.ConfigHashStub = stub
+}
+
+func (fake *FakePipeline) ConfigHashReturns(result1 string) {
+	fake.configHashMutex.Lock()
+	defer fake.configHashMutex.Unlock()
+	fake.ConfigHashStub = nil
+	fake.configHashReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakePipeline) ConfigHashReturnsOnCall(i int, result1 string) {
+	fake.configHashMutex.Lock()
+	defer fake.configHashMutex.Unlock()
+	fake.ConfigHashStub = nil
+	if fake.configHashReturnsOnCall == nil {
+		fake.configHashReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.configHashReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakePipeline) ConfigVersion() db.ConfigVersion {
 	fake.configVersionMutex.Lock()
 	ret, specificReturn := fake.configVersionReturnsOnCall[len(fake.configVersionArgsForCall)]
@@ -1258,6 +1655,59 @@ func (fake *FakePipeline) DeleteBuildEventsByBuildIDsReturnsOnCall(i int, result
 	}{result1}
 }
 
+func (fake *FakePipeline) Description() string {
+	fake.descriptionMutex.Lock()
+	ret, specificReturn := fake.descriptionReturnsOnCall[len(fake.descriptionArgsForCall)]
+	fake.descriptionArgsForCall = append(fake.descriptionArgsForCall, struct {
+	}{})
+	stub := fake.DescriptionStub
+	fakeReturns := fake.descriptionReturns
+	fake.recordInvocation("Description", []interface{}{})
+	fake.descriptionMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) DescriptionCallCount() int {
+	fake.descriptionMutex.RLock()
+	defer fake.descriptionMutex.RUnlock()
+	return len(fake.descriptionArgsForCall)
+}
+
+func (fake *FakePipeline) DescriptionCalls(stub func() string) {
+	fake.descriptionMutex.Lock()
+	defer fake.descriptionMutex.Unlock()
+	fake.DescriptionStub = stub
+}
+
+func (fake *FakePipeline) DescriptionReturns(result1 string) {
+	fake.descriptionMutex.Lock()
+	defer fake.descriptionMutex.Unlock()
+	fake.DescriptionStub = nil
+	fake.descriptionReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakePipeline) DescriptionReturnsOnCall(i int, result1 string) {
+	fake.descriptionMutex.Lock()
+	defer fake.descriptionMutex.Unlock()
+	fake.DescriptionStub = nil
+	if fake.descriptionReturnsOnCall == nil {
+		fake.descriptionReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.descriptionReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakePipeline) Destroy() error {
 	fake.destroyMutex.Lock()
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
@@ -1417,6 +1867,59 @@ func (fake *FakePipeline) ExposeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipeline) Frozen() bool {
+	fake.frozenMutex.Lock()
+	ret, specificReturn := fake.frozenReturnsOnCall[len(fake.frozenArgsForCall)]
+	fake.frozenArgsForCall = append(fake.frozenArgsForCall, struct {
+	}{})
+	stub := fake.FrozenStub
+	fakeReturns := fake.frozenReturns
+	fake.recordInvocation("Frozen", []interface{}{})
+	fake.frozenMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) FrozenCallCount() int {
+	fake.frozenMutex.RLock()
+	defer fake.frozenMutex.RUnlock()
+	return len(fake.frozenArgsForCall)
+}
+
+func (fake *FakePipeline) FrozenCalls(stub func() bool) {
+	fake.frozenMutex.Lock()
+	defer fake.frozenMutex.Unlock()
+	fake.FrozenStub = stub
+}
+
+func (fake *FakePipeline) FrozenReturns(result1 bool) {
+	fake.frozenMutex.Lock()
+	defer fake.frozenMutex.Unlock()
+	fake.FrozenStub = nil
+	fake.frozenReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePipeline) FrozenReturnsOnCall(i int, result1 bool) {
+	fake.frozenMutex.Lock()
+	defer fake.frozenMutex.Unlock()
+	fake.FrozenStub = nil
+	if fake.frozenReturnsOnCall == nil {
+		fake.frozenReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.frozenReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakePipeline) GetBuildsWithVersionAsInput(arg1 int, arg2 int) ([]db.Build, error) {
 	fake.getBuildsWithVersionAsInputMutex.Lock()
 	ret, specificReturn := fake.getBuildsWithVersionAsInputReturnsOnCall[len(fake.getBuildsWithVersionAsInputArgsForCall)]
@@ -2044,28 +2547,146 @@ func (fake *FakePipeline) NameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakePipeline) ParentBuildID() int {
-	fake.parentBuildIDMutex.Lock()
-	ret, specificReturn := fake.parentBuildIDReturnsOnCall[len(fake.parentBuildIDArgsForCall)]
-	fake.parentBuildIDArgsForCall = append(fake.parentBuildIDArgsForCall, struct {
+func (fake *FakePipeline) Notifications() (atc.PipelineNotifications, bool, error) {
+	fake.notificationsMutex.Lock()
+	ret, specificReturn := fake.notificationsReturnsOnCall[len(fake.notificationsArgsForCall)]
+	fake.notificationsArgsForCall = append(fake.notificationsArgsForCall, struct {
 	}{})
-	stub := fake.ParentBuildIDStub
-	fakeReturns := fake.parentBuildIDReturns
-	fake.recordInvocation("ParentBuildID", []interface{}{})
-	fake.parentBuildIDMutex.Unlock()
+	stub := fake.NotificationsStub
+	fakeReturns := fake.notificationsReturns
+	fake.recordInvocation("Notifications", []interface{}{})
+	fake.notificationsMutex.Unlock()
 	if stub != nil {
 		return stub()
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
-func (fake *FakePipeline) ParentBuildIDCallCount() int {
-	fake.parentBuildIDMutex.RLock()
-	defer fake.parentBuildIDMutex.RUnlock()
-	return len(fake.parentBuildIDArgsForCall)
+func (fake *FakePipeline) NotificationsCallCount() int {
+	fake.notificationsMutex.RLock()
+	defer fake.notificationsMutex.RUnlock()
+	return len(fake.notificationsArgsForCall)
+}
+
+func (fake *FakePipeline) NotificationsCalls(stub func() (atc.PipelineNotifications, bool, error)) {
+	fake.notificationsMutex.Lock()
+	defer fake.notificationsMutex.Unlock()
+	fake.NotificationsStub = stub
+}
+
+func (fake *FakePipeline) NotificationsReturns(result1 atc.PipelineNotifications, result2 bool, result3 error) {
+	fake.notificationsMutex.Lock()
+	defer fake.notificationsMutex.Unlock()
+	fake.NotificationsStub = nil
+	fake.notificationsReturns = struct {
+		result1 atc.PipelineNotifications
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipeline) NotificationsReturnsOnCall(i int, result1 atc.PipelineNotifications, result2 bool, result3 error) {
+	fake.notificationsMutex.Lock()
+	defer fake.notificationsMutex.Unlock()
+	fake.NotificationsStub = nil
+	if fake.notificationsReturnsOnCall == nil {
+		fake.notificationsReturnsOnCall = make(map[int]struct {
+			result1 atc.PipelineNotifications
+			result2 bool
+			result3 error
+		})
+	}
+	fake.notificationsReturnsOnCall[i] = struct {
+		result1 atc.PipelineNotifications
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipeline) Ownership() (atc.PipelineOwnership, bool, error) {
+	fake.ownershipMutex.Lock()
+	ret, specificReturn := fake.ownershipReturnsOnCall[len(fake.ownershipArgsForCall)]
+	fake.ownershipArgsForCall = append(fake.ownershipArgsForCall, struct {
+	}{})
+	stub := fake.OwnershipStub
+	fakeReturns := fake.ownershipReturns
+	fake.recordInvocation("Ownership", []interface{}{})
+	fake.ownershipMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakePipeline) OwnershipCallCount() int {
+	fake.ownershipMutex.RLock()
+	defer fake.ownershipMutex.RUnlock()
+	return len(fake.ownershipArgsForCall)
+}
+
+func (fake *FakePipeline) OwnershipCalls(stub func() (atc.PipelineOwnership, bool, error)) {
+	fake.ownershipMutex.Lock()
+	defer fake.ownershipMutex.Unlock()
+	fake.OwnershipStub = stub
+}
+
+func (fake *FakePipeline) OwnershipReturns(result1 atc.PipelineOwnership, result2 bool, result3 error) {
+	fake.ownershipMutex.Lock()
+	defer fake.ownershipMutex.Unlock()
+	fake.OwnershipStub = nil
+	fake.ownershipReturns = struct {
+		result1 atc.PipelineOwnership
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipeline) OwnershipReturnsOnCall(i int, result1 atc.PipelineOwnership, result2 bool, result3 error) {
+	fake.ownershipMutex.Lock()
+	defer fake.ownershipMutex.Unlock()
+	fake.OwnershipStub = nil
+	if fake.ownershipReturnsOnCall == nil {
+		fake.ownershipReturnsOnCall = make(map[int]struct {
+			result1 atc.PipelineOwnership
+			result2 bool
+			result3 error
+		})
+	}
+	fake.ownershipReturnsOnCall[i] = struct {
+		result1 atc.PipelineOwnership
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipeline) ParentBuildID() int {
+	fake.parentBuildIDMutex.Lock()
+	ret, specificReturn := fake.parentBuildIDReturnsOnCall[len(fake.parentBuildIDArgsForCall)]
+	fake.parentBuildIDArgsForCall = append(fake.parentBuildIDArgsForCall, struct {
+	}{})
+	stub := fake.ParentBuildIDStub
+	fakeReturns := fake.parentBuildIDReturns
+	fake.recordInvocation("ParentBuildID", []interface{}{})
+	fake.parentBuildIDMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) ParentBuildIDCallCount() int {
+	fake.parentBuildIDMutex.RLock()
+	defer fake.parentBuildIDMutex.RUnlock()
+	return len(fake.parentBuildIDArgsForCall)
 }
 
 func (fake *FakePipeline) ParentBuildIDCalls(stub func() int) {
@@ -2309,6 +2930,67 @@ func (fake *FakePipeline) PublicReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakePipeline) RecordRollback(arg1 db.PipelineRollback) error {
+	fake.recordRollbackMutex.Lock()
+	ret, specificReturn := fake.recordRollbackReturnsOnCall[len(fake.recordRollbackArgsForCall)]
+	fake.recordRollbackArgsForCall = append(fake.recordRollbackArgsForCall, struct {
+		arg1 db.PipelineRollback
+	}{arg1})
+	stub := fake.RecordRollbackStub
+	fakeReturns := fake.recordRollbackReturns
+	fake.recordInvocation("RecordRollback", []interface{}{arg1})
+	fake.recordRollbackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) RecordRollbackCallCount() int {
+	fake.recordRollbackMutex.RLock()
+	defer fake.recordRollbackMutex.RUnlock()
+	return len(fake.recordRollbackArgsForCall)
+}
+
+func (fake *FakePipeline) RecordRollbackCalls(stub func(db.PipelineRollback) error) {
+	fake.recordRollbackMutex.Lock()
+	defer fake.recordRollbackMutex.Unlock()
+	fake.RecordRollbackStub = stub
+}
+
+func (fake *FakePipeline) RecordRollbackArgsForCall(i int) db.PipelineRollback {
+	fake.recordRollbackMutex.RLock()
+	defer fake.recordRollbackMutex.RUnlock()
+	argsForCall := fake.recordRollbackArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) RecordRollbackReturns(result1 error) {
+	fake.recordRollbackMutex.Lock()
+	defer fake.recordRollbackMutex.Unlock()
+	fake.RecordRollbackStub = nil
+	fake.recordRollbackReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) RecordRollbackReturnsOnCall(i int, result1 error) {
+	fake.recordRollbackMutex.Lock()
+	defer fake.recordRollbackMutex.Unlock()
+	fake.RecordRollbackStub = nil
+	if fake.recordRollbackReturnsOnCall == nil {
+		fake.recordRollbackReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordRollbackReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) Reload() (bool, error) {
 	fake.reloadMutex.Lock()
 	ret, specificReturn := fake.reloadReturnsOnCall[len(fake.reloadArgsForCall)]
@@ -2365,6 +3047,59 @@ func (fake *FakePipeline) ReloadReturnsOnCall(i int, result1 bool, result2 error
 	}{result1, result2}
 }
 
+func (fake *FakePipeline) ResetBuildHistory() error {
+	fake.resetBuildHistoryMutex.Lock()
+	ret, specificReturn := fake.resetBuildHistoryReturnsOnCall[len(fake.resetBuildHistoryArgsForCall)]
+	fake.resetBuildHistoryArgsForCall = append(fake.resetBuildHistoryArgsForCall, struct {
+	}{})
+	stub := fake.ResetBuildHistoryStub
+	fakeReturns := fake.resetBuildHistoryReturns
+	fake.recordInvocation("ResetBuildHistory", []interface{}{})
+	fake.resetBuildHistoryMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) ResetBuildHistoryCallCount() int {
+	fake.resetBuildHistoryMutex.RLock()
+	defer fake.resetBuildHistoryMutex.RUnlock()
+	return len(fake.resetBuildHistoryArgsForCall)
+}
+
+func (fake *FakePipeline) ResetBuildHistoryCalls(stub func() error) {
+	fake.resetBuildHistoryMutex.Lock()
+	defer fake.resetBuildHistoryMutex.Unlock()
+	fake.ResetBuildHistoryStub = stub
+}
+
+func (fake *FakePipeline) ResetBuildHistoryReturns(result1 error) {
+	fake.resetBuildHistoryMutex.Lock()
+	defer fake.resetBuildHistoryMutex.Unlock()
+	fake.ResetBuildHistoryStub = nil
+	fake.resetBuildHistoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ResetBuildHistoryReturnsOnCall(i int, result1 error) {
+	fake.resetBuildHistoryMutex.Lock()
+	defer fake.resetBuildHistoryMutex.Unlock()
+	fake.ResetBuildHistoryStub = nil
+	if fake.resetBuildHistoryReturnsOnCall == nil {
+		fake.resetBuildHistoryReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resetBuildHistoryReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) Resource(arg1 string) (db.Resource, bool, error) {
 	fake.resourceMutex.Lock()
 	ret, specificReturn := fake.resourceReturnsOnCall[len(fake.resourceArgsForCall)]
@@ -2812,6 +3547,311 @@ func (fake *FakePipeline) ResourcesReturnsOnCall(i int, result1 db.Resources, re
 	}{result1, result2}
 }
 
+func (fake *FakePipeline) SetArchiveAfter(arg1 time.Duration) error {
+	fake.setArchiveAfterMutex.Lock()
+	ret, specificReturn := fake.setArchiveAfterReturnsOnCall[len(fake.setArchiveAfterArgsForCall)]
+	fake.setArchiveAfterArgsForCall = append(fake.setArchiveAfterArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.SetArchiveAfterStub
+	fakeReturns := fake.setArchiveAfterReturns
+	fake.recordInvocation("SetArchiveAfter", []interface{}{arg1})
+	fake.setArchiveAfterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) SetArchiveAfterCallCount() int {
+	fake.setArchiveAfterMutex.RLock()
+	defer fake.setArchiveAfterMutex.RUnlock()
+	return len(fake.setArchiveAfterArgsForCall)
+}
+
+func (fake *FakePipeline) SetArchiveAfterCalls(stub func(time.Duration) error) {
+	fake.setArchiveAfterMutex.Lock()
+	defer fake.setArchiveAfterMutex.Unlock()
+	fake.SetArchiveAfterStub = stub
+}
+
+func (fake *FakePipeline) SetArchiveAfterArgsForCall(i int) time.Duration {
+	fake.setArchiveAfterMutex.RLock()
+	defer fake.setArchiveAfterMutex.RUnlock()
+	argsForCall := fake.setArchiveAfterArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) SetArchiveAfterReturns(result1 error) {
+	fake.setArchiveAfterMutex.Lock()
+	defer fake.setArchiveAfterMutex.Unlock()
+	fake.SetArchiveAfterStub = nil
+	fake.setArchiveAfterReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetArchiveAfterReturnsOnCall(i int, result1 error) {
+	fake.setArchiveAfterMutex.Lock()
+	defer fake.setArchiveAfterMutex.Unlock()
+	fake.SetArchiveAfterStub = nil
+	if fake.setArchiveAfterReturnsOnCall == nil {
+		fake.setArchiveAfterReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setArchiveAfterReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetDescription(arg1 string) error {
+	fake.setDescriptionMutex.Lock()
+	ret, specificReturn := fake.setDescriptionReturnsOnCall[len(fake.setDescriptionArgsForCall)]
+	fake.setDescriptionArgsForCall = append(fake.setDescriptionArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SetDescriptionStub
+	fakeReturns := fake.setDescriptionReturns
+	fake.recordInvocation("SetDescription", []interface{}{arg1})
+	fake.setDescriptionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) SetDescriptionCallCount() int {
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	return len(fake.setDescriptionArgsForCall)
+}
+
+func (fake *FakePipeline) SetDescriptionCalls(stub func(string) error) {
+	fake.setDescriptionMutex.Lock()
+	defer fake.setDescriptionMutex.Unlock()
+	fake.SetDescriptionStub = stub
+}
+
+func (fake *FakePipeline) SetDescriptionArgsForCall(i int) string {
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	argsForCall := fake.setDescriptionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) SetDescriptionReturns(result1 error) {
+	fake.setDescriptionMutex.Lock()
+	defer fake.setDescriptionMutex.Unlock()
+	fake.SetDescriptionStub = nil
+	fake.setDescriptionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetDescriptionReturnsOnCall(i int, result1 error) {
+	fake.setDescriptionMutex.Lock()
+	defer fake.setDescriptionMutex.Unlock()
+	fake.SetDescriptionStub = nil
+	if fake.setDescriptionReturnsOnCall == nil {
+		fake.setDescriptionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setDescriptionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetFrozen(arg1 bool) error {
+	fake.setFrozenMutex.Lock()
+	ret, specificReturn := fake.setFrozenReturnsOnCall[len(fake.setFrozenArgsForCall)]
+	fake.setFrozenArgsForCall = append(fake.setFrozenArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetFrozenStub
+	fakeReturns := fake.setFrozenReturns
+	fake.recordInvocation("SetFrozen", []interface{}{arg1})
+	fake.setFrozenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) SetFrozenCallCount() int {
+	fake.setFrozenMutex.RLock()
+	defer fake.setFrozenMutex.RUnlock()
+	return len(fake.setFrozenArgsForCall)
+}
+
+func (fake *FakePipeline) SetFrozenCalls(stub func(bool) error) {
+	fake.setFrozenMutex.Lock()
+	defer fake.setFrozenMutex.Unlock()
+	fake.SetFrozenStub = stub
+}
+
+func (fake *FakePipeline) SetFrozenArgsForCall(i int) bool {
+	fake.setFrozenMutex.RLock()
+	defer fake.setFrozenMutex.RUnlock()
+	argsForCall := fake.setFrozenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) SetFrozenReturns(result1 error) {
+	fake.setFrozenMutex.Lock()
+	defer fake.setFrozenMutex.Unlock()
+	fake.SetFrozenStub = nil
+	fake.setFrozenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetFrozenReturnsOnCall(i int, result1 error) {
+	fake.setFrozenMutex.Lock()
+	defer fake.setFrozenMutex.Unlock()
+	fake.SetFrozenStub = nil
+	if fake.setFrozenReturnsOnCall == nil {
+		fake.setFrozenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setFrozenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetNotifications(arg1 atc.PipelineNotifications) error {
+	fake.setNotificationsMutex.Lock()
+	ret, specificReturn := fake.setNotificationsReturnsOnCall[len(fake.setNotificationsArgsForCall)]
+	fake.setNotificationsArgsForCall = append(fake.setNotificationsArgsForCall, struct {
+		arg1 atc.PipelineNotifications
+	}{arg1})
+	stub := fake.SetNotificationsStub
+	fakeReturns := fake.setNotificationsReturns
+	fake.recordInvocation("SetNotifications", []interface{}{arg1})
+	fake.setNotificationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) SetNotificationsCallCount() int {
+	fake.setNotificationsMutex.RLock()
+	defer fake.setNotificationsMutex.RUnlock()
+	return len(fake.setNotificationsArgsForCall)
+}
+
+func (fake *FakePipeline) SetNotificationsCalls(stub func(atc.PipelineNotifications) error) {
+	fake.setNotificationsMutex.Lock()
+	defer fake.setNotificationsMutex.Unlock()
+	fake.SetNotificationsStub = stub
+}
+
+func (fake *FakePipeline) SetNotificationsArgsForCall(i int) atc.PipelineNotifications {
+	fake.setNotificationsMutex.RLock()
+	defer fake.setNotificationsMutex.RUnlock()
+	argsForCall := fake.setNotificationsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) SetNotificationsReturns(result1 error) {
+	fake.setNotificationsMutex.Lock()
+	defer fake.setNotificationsMutex.Unlock()
+	fake.SetNotificationsStub = nil
+	fake.setNotificationsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetNotificationsReturnsOnCall(i int, result1 error) {
+	fake.setNotificationsMutex.Lock()
+	defer fake.setNotificationsMutex.Unlock()
+	fake.SetNotificationsStub = nil
+	if fake.setNotificationsReturnsOnCall == nil {
+		fake.setNotificationsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setNotificationsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetOwnership(arg1 atc.PipelineOwnership) error {
+	fake.setOwnershipMutex.Lock()
+	ret, specificReturn := fake.setOwnershipReturnsOnCall[len(fake.setOwnershipArgsForCall)]
+	fake.setOwnershipArgsForCall = append(fake.setOwnershipArgsForCall, struct {
+		arg1 atc.PipelineOwnership
+	}{arg1})
+	stub := fake.SetOwnershipStub
+	fakeReturns := fake.setOwnershipReturns
+	fake.recordInvocation("SetOwnership", []interface{}{arg1})
+	fake.setOwnershipMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) SetOwnershipCallCount() int {
+	fake.setOwnershipMutex.RLock()
+	defer fake.setOwnershipMutex.RUnlock()
+	return len(fake.setOwnershipArgsForCall)
+}
+
+func (fake *FakePipeline) SetOwnershipCalls(stub func(atc.PipelineOwnership) error) {
+	fake.setOwnershipMutex.Lock()
+	defer fake.setOwnershipMutex.Unlock()
+	fake.SetOwnershipStub = stub
+}
+
+func (fake *FakePipeline) SetOwnershipArgsForCall(i int) atc.PipelineOwnership {
+	fake.setOwnershipMutex.RLock()
+	defer fake.setOwnershipMutex.RUnlock()
+	argsForCall := fake.setOwnershipArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) SetOwnershipReturns(result1 error) {
+	fake.setOwnershipMutex.Lock()
+	defer fake.setOwnershipMutex.Unlock()
+	fake.SetOwnershipStub = nil
+	fake.setOwnershipReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SetOwnershipReturnsOnCall(i int, result1 error) {
+	fake.setOwnershipMutex.Lock()
+	defer fake.setOwnershipMutex.Unlock()
+	fake.SetOwnershipStub = nil
+	if fake.setOwnershipReturnsOnCall == nil {
+		fake.setOwnershipReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setOwnershipReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) SetParentIDs(arg1 int, arg2 int) error {
 	fake.setParentIDsMutex.Lock()
 	ret, specificReturn := fake.setParentIDsReturnsOnCall[len(fake.setParentIDsArgsForCall)]
@@ -2980,6 +4020,59 @@ func (fake *FakePipeline) TeamNameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakePipeline) TriggerImmediateResourceChecks() error {
+	fake.triggerImmediateResourceChecksMutex.Lock()
+	ret, specificReturn := fake.triggerImmediateResourceChecksReturnsOnCall[len(fake.triggerImmediateResourceChecksArgsForCall)]
+	fake.triggerImmediateResourceChecksArgsForCall = append(fake.triggerImmediateResourceChecksArgsForCall, struct {
+	}{})
+	stub := fake.TriggerImmediateResourceChecksStub
+	fakeReturns := fake.triggerImmediateResourceChecksReturns
+	fake.recordInvocation("TriggerImmediateResourceChecks", []interface{}{})
+	fake.triggerImmediateResourceChecksMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) TriggerImmediateResourceChecksCallCount() int {
+	fake.triggerImmediateResourceChecksMutex.RLock()
+	defer fake.triggerImmediateResourceChecksMutex.RUnlock()
+	return len(fake.triggerImmediateResourceChecksArgsForCall)
+}
+
+func (fake *FakePipeline) TriggerImmediateResourceChecksCalls(stub func() error) {
+	fake.triggerImmediateResourceChecksMutex.Lock()
+	defer fake.triggerImmediateResourceChecksMutex.Unlock()
+	fake.TriggerImmediateResourceChecksStub = stub
+}
+
+func (fake *FakePipeline) TriggerImmediateResourceChecksReturns(result1 error) {
+	fake.triggerImmediateResourceChecksMutex.Lock()
+	defer fake.triggerImmediateResourceChecksMutex.Unlock()
+	fake.TriggerImmediateResourceChecksStub = nil
+	fake.triggerImmediateResourceChecksReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) TriggerImmediateResourceChecksReturnsOnCall(i int, result1 error) {
+	fake.triggerImmediateResourceChecksMutex.Lock()
+	defer fake.triggerImmediateResourceChecksMutex.Unlock()
+	fake.TriggerImmediateResourceChecksStub = nil
+	if fake.triggerImmediateResourceChecksReturnsOnCall == nil {
+		fake.triggerImmediateResourceChecksReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.triggerImmediateResourceChecksReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) Unpause() error {
 	fake.unpauseMutex.Lock()
 	ret, specificReturn := fake.unpauseReturnsOnCall[len(fake.unpauseArgsForCall)]
@@ -3033,6 +4126,67 @@ func (fake *FakePipeline) UnpauseReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipeline) UpdateDisplay(arg1 atc.DisplayConfig) error {
+	fake.updateDisplayMutex.Lock()
+	ret, specificReturn := fake.updateDisplayReturnsOnCall[len(fake.updateDisplayArgsForCall)]
+	fake.updateDisplayArgsForCall = append(fake.updateDisplayArgsForCall, struct {
+		arg1 atc.DisplayConfig
+	}{arg1})
+	stub := fake.UpdateDisplayStub
+	fakeReturns := fake.updateDisplayReturns
+	fake.recordInvocation("UpdateDisplay", []interface{}{arg1})
+	fake.updateDisplayMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipeline) UpdateDisplayCallCount() int {
+	fake.updateDisplayMutex.RLock()
+	defer fake.updateDisplayMutex.RUnlock()
+	return len(fake.updateDisplayArgsForCall)
+}
+
+func (fake *FakePipeline) UpdateDisplayCalls(stub func(atc.DisplayConfig) error) {
+	fake.updateDisplayMutex.Lock()
+	defer fake.updateDisplayMutex.Unlock()
+	fake.UpdateDisplayStub = stub
+}
+
+func (fake *FakePipeline) UpdateDisplayArgsForCall(i int) atc.DisplayConfig {
+	fake.updateDisplayMutex.RLock()
+	defer fake.updateDisplayMutex.RUnlock()
+	argsForCall := fake.updateDisplayArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePipeline) UpdateDisplayReturns(result1 error) {
+	fake.updateDisplayMutex.Lock()
+	defer fake.updateDisplayMutex.Unlock()
+	fake.UpdateDisplayStub = nil
+	fake.updateDisplayReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) UpdateDisplayReturnsOnCall(i int, result1 error) {
+	fake.updateDisplayMutex.Lock()
+	defer fake.updateDisplayMutex.Unlock()
+	fake.UpdateDisplayStub = nil
+	if fake.updateDisplayReturnsOnCall == nil {
+		fake.updateDisplayReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateDisplayReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) VarSources() atc.VarSourceConfigs {
 	fake.varSourcesMutex.Lock()
 	ret, specificReturn := fake.varSourcesReturnsOnCall[len(fake.varSourcesArgsForCall)]
@@ -3153,6 +4307,14 @@ func (fake *FakePipeline) VariablesReturnsOnCall(i int, result1 vars.Variables,
 }
 
 func (fake *FakePipeline) Invocations() map[string][][]interface{} {
+	fake.clearArchiveAfterMutex.RLock()
+	defer fake.clearArchiveAfterMutex.RUnlock()
+	fake.clearNotificationsMutex.RLock()
+	defer fake.clearNotificationsMutex.RUnlock()
+	fake.clearOwnershipMutex.RLock()
+	defer fake.clearOwnershipMutex.RUnlock()
+	fake.descriptionMutex.RLock()
+	defer fake.descriptionMutex.RUnlock()
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.archiveMutex.RLock()
@@ -3169,6 +4331,8 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.checkPausedMutex.RUnlock()
 	fake.configMutex.RLock()
 	defer fake.configMutex.RUnlock()
+	fake.configHashMutex.RLock()
+	defer fake.configHashMutex.RUnlock()
 	fake.configVersionMutex.RLock()
 	defer fake.configVersionMutex.RUnlock()
 	fake.createOneOffBuildMutex.RLock()
@@ -3185,6 +4349,8 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.displayMutex.RUnlock()
 	fake.exposeMutex.RLock()
 	defer fake.exposeMutex.RUnlock()
+	fake.frozenMutex.RLock()
+	defer fake.frozenMutex.RUnlock()
 	fake.getBuildsWithVersionAsInputMutex.RLock()
 	defer fake.getBuildsWithVersionAsInputMutex.RUnlock()
 	fake.getBuildsWithVersionAsOutputMutex.RLock()
@@ -3207,6 +4373,10 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.loadDebugVersionsDBMutex.RUnlock()
 	fake.nameMutex.RLock()
 	defer fake.nameMutex.RUnlock()
+	fake.notificationsMutex.RLock()
+	defer fake.notificationsMutex.RUnlock()
+	fake.ownershipMutex.RLock()
+	defer fake.ownershipMutex.RUnlock()
 	fake.parentBuildIDMutex.RLock()
 	defer fake.parentBuildIDMutex.RUnlock()
 	fake.parentJobIDMutex.RLock()
@@ -3217,8 +4387,12 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.pausedMutex.RUnlock()
 	fake.publicMutex.RLock()
 	defer fake.publicMutex.RUnlock()
+	fake.recordRollbackMutex.RLock()
+	defer fake.recordRollbackMutex.RUnlock()
 	fake.reloadMutex.RLock()
 	defer fake.reloadMutex.RUnlock()
+	fake.resetBuildHistoryMutex.RLock()
+	defer fake.resetBuildHistoryMutex.RUnlock()
 	fake.resourceMutex.RLock()
 	defer fake.resourceMutex.RUnlock()
 	fake.resourceByIDMutex.RLock()
@@ -3233,14 +4407,28 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.resourceVersionMutex.RUnlock()
 	fake.resourcesMutex.RLock()
 	defer fake.resourcesMutex.RUnlock()
+	fake.setArchiveAfterMutex.RLock()
+	defer fake.setArchiveAfterMutex.RUnlock()
+	fake.setDescriptionMutex.RLock()
+	defer fake.setDescriptionMutex.RUnlock()
+	fake.setFrozenMutex.RLock()
+	defer fake.setFrozenMutex.RUnlock()
+	fake.setNotificationsMutex.RLock()
+	defer fake.setNotificationsMutex.RUnlock()
+	fake.setOwnershipMutex.RLock()
+	defer fake.setOwnershipMutex.RUnlock()
 	fake.setParentIDsMutex.RLock()
 	defer fake.setParentIDsMutex.RUnlock()
 	fake.teamIDMutex.RLock()
 	defer fake.teamIDMutex.RUnlock()
 	fake.teamNameMutex.RLock()
 	defer fake.teamNameMutex.RUnlock()
+	fake.triggerImmediateResourceChecksMutex.RLock()
+	defer fake.triggerImmediateResourceChecksMutex.RUnlock()
 	fake.unpauseMutex.RLock()
 	defer fake.unpauseMutex.RUnlock()
+	fake.updateDisplayMutex.RLock()
+	defer fake.updateDisplayMutex.RUnlock()
 	fake.varSourcesMutex.RLock()
 	defer fake.varSourcesMutex.RUnlock()
 	fake.variablesMutex.RLock()
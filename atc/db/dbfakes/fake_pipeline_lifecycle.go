@@ -18,6 +18,16 @@ type FakePipelineLifecycle struct {
 	archiveAbandonedPipelinesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ArchiveExpiredPipelinesStub        func() error
+	archiveExpiredPipelinesMutex       sync.RWMutex
+	archiveExpiredPipelinesArgsForCall []struct {
+	}
+	archiveExpiredPipelinesReturns struct {
+		result1 error
+	}
+	archiveExpiredPipelinesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RemoveBuildEventsForDeletedPipelinesStub        func() error
 	removeBuildEventsForDeletedPipelinesMutex       sync.RWMutex
 	removeBuildEventsForDeletedPipelinesArgsForCall []struct {
@@ -85,6 +95,59 @@ func (fake *FakePipelineLifecycle) ArchiveAbandonedPipelinesReturnsOnCall(i int,
 	}{result1}
 }
 
+func (fake *FakePipelineLifecycle) ArchiveExpiredPipelines() error {
+	fake.archiveExpiredPipelinesMutex.Lock()
+	ret, specificReturn := fake.archiveExpiredPipelinesReturnsOnCall[len(fake.archiveExpiredPipelinesArgsForCall)]
+	fake.archiveExpiredPipelinesArgsForCall = append(fake.archiveExpiredPipelinesArgsForCall, struct {
+	}{})
+	stub := fake.ArchiveExpiredPipelinesStub
+	fakeReturns := fake.archiveExpiredPipelinesReturns
+	fake.recordInvocation("ArchiveExpiredPipelines", []interface{}{})
+	fake.archiveExpiredPipelinesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePipelineLifecycle) ArchiveExpiredPipelinesCallCount() int {
+	fake.archiveExpiredPipelinesMutex.RLock()
+	defer fake.archiveExpiredPipelinesMutex.RUnlock()
+	return len(fake.archiveExpiredPipelinesArgsForCall)
+}
+
+func (fake *FakePipelineLifecycle) ArchiveExpiredPipelinesCalls(stub func() error) {
+	fake.archiveExpiredPipelinesMutex.Lock()
+	defer fake.archiveExpiredPipelinesMutex.Unlock()
+	fake.ArchiveExpiredPipelinesStub = stub
+}
+
+func (fake *FakePipelineLifecycle) ArchiveExpiredPipelinesReturns(result1 error) {
+	fake.archiveExpiredPipelinesMutex.Lock()
+	defer fake.archiveExpiredPipelinesMutex.Unlock()
+	fake.ArchiveExpiredPipelinesStub = nil
+	fake.archiveExpiredPipelinesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineLifecycle) ArchiveExpiredPipelinesReturnsOnCall(i int, result1 error) {
+	fake.archiveExpiredPipelinesMutex.Lock()
+	defer fake.archiveExpiredPipelinesMutex.Unlock()
+	fake.ArchiveExpiredPipelinesStub = nil
+	if fake.archiveExpiredPipelinesReturnsOnCall == nil {
+		fake.archiveExpiredPipelinesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.archiveExpiredPipelinesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipelineLifecycle) RemoveBuildEventsForDeletedPipelines() error {
 	fake.removeBuildEventsForDeletedPipelinesMutex.Lock()
 	ret, specificReturn := fake.removeBuildEventsForDeletedPipelinesReturnsOnCall[len(fake.removeBuildEventsForDeletedPipelinesArgsForCall)]
@@ -139,6 +202,8 @@ func (fake *FakePipelineLifecycle) RemoveBuildEventsForDeletedPipelinesReturnsOn
 }
 
 func (fake *FakePipelineLifecycle) Invocations() map[string][][]interface{} {
+	fake.archiveExpiredPipelinesMutex.RLock()
+	defer fake.archiveExpiredPipelinesMutex.RUnlock()
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.archiveAbandonedPipelinesMutex.RLock()
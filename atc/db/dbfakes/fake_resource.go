@@ -296,6 +296,16 @@ type FakeResource struct {
 	pipelineRefReturnsOnCall map[int]struct {
 		result1 atc.PipelineRef
 	}
+	PreserveVersionHistoryStub        func() error
+	preserveVersionHistoryMutex       sync.RWMutex
+	preserveVersionHistoryArgsForCall []struct {
+	}
+	preserveVersionHistoryReturns struct {
+		result1 error
+	}
+	preserveVersionHistoryReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PublicStub        func() bool
 	publicMutex       sync.RWMutex
 	publicArgsForCall []struct {
@@ -338,6 +348,18 @@ type FakeResource struct {
 	resourceConfigScopeIDReturnsOnCall map[int]struct {
 		result1 int
 	}
+	SetMetadataStub        func(string, string) error
+	setMetadataMutex       sync.RWMutex
+	setMetadataArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	setMetadataReturns struct {
+		result1 error
+	}
+	setMetadataReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPinCommentStub        func(string) error
 	setPinCommentMutex       sync.RWMutex
 	setPinCommentArgsForCall []struct {
@@ -1918,6 +1940,59 @@ func (fake *FakeResource) PipelineRefReturnsOnCall(i int, result1 atc.PipelineRe
 	}{result1}
 }
 
+func (fake *FakeResource) PreserveVersionHistory() error {
+	fake.preserveVersionHistoryMutex.Lock()
+	ret, specificReturn := fake.preserveVersionHistoryReturnsOnCall[len(fake.preserveVersionHistoryArgsForCall)]
+	fake.preserveVersionHistoryArgsForCall = append(fake.preserveVersionHistoryArgsForCall, struct {
+	}{})
+	stub := fake.PreserveVersionHistoryStub
+	fakeReturns := fake.preserveVersionHistoryReturns
+	fake.recordInvocation("PreserveVersionHistory", []interface{}{})
+	fake.preserveVersionHistoryMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeResource) PreserveVersionHistoryCallCount() int {
+	fake.preserveVersionHistoryMutex.RLock()
+	defer fake.preserveVersionHistoryMutex.RUnlock()
+	return len(fake.preserveVersionHistoryArgsForCall)
+}
+
+func (fake *FakeResource) PreserveVersionHistoryCalls(stub func() error) {
+	fake.preserveVersionHistoryMutex.Lock()
+	defer fake.preserveVersionHistoryMutex.Unlock()
+	fake.PreserveVersionHistoryStub = stub
+}
+
+func (fake *FakeResource) PreserveVersionHistoryReturns(result1 error) {
+	fake.preserveVersionHistoryMutex.Lock()
+	defer fake.preserveVersionHistoryMutex.Unlock()
+	fake.PreserveVersionHistoryStub = nil
+	fake.preserveVersionHistoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeResource) PreserveVersionHistoryReturnsOnCall(i int, result1 error) {
+	fake.preserveVersionHistoryMutex.Lock()
+	defer fake.preserveVersionHistoryMutex.Unlock()
+	fake.PreserveVersionHistoryStub = nil
+	if fake.preserveVersionHistoryReturnsOnCall == nil {
+		fake.preserveVersionHistoryReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.preserveVersionHistoryReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeResource) Public() bool {
 	fake.publicMutex.Lock()
 	ret, specificReturn := fake.publicReturnsOnCall[len(fake.publicArgsForCall)]
@@ -2133,6 +2208,68 @@ func (fake *FakeResource) ResourceConfigScopeIDReturnsOnCall(i int, result1 int)
 	}{result1}
 }
 
+func (fake *FakeResource) SetMetadata(arg1 string, arg2 string) error {
+	fake.setMetadataMutex.Lock()
+	ret, specificReturn := fake.setMetadataReturnsOnCall[len(fake.setMetadataArgsForCall)]
+	fake.setMetadataArgsForCall = append(fake.setMetadataArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetMetadataStub
+	fakeReturns := fake.setMetadataReturns
+	fake.recordInvocation("SetMetadata", []interface{}{arg1, arg2})
+	fake.setMetadataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeResource) SetMetadataCallCount() int {
+	fake.setMetadataMutex.RLock()
+	defer fake.setMetadataMutex.RUnlock()
+	return len(fake.setMetadataArgsForCall)
+}
+
+func (fake *FakeResource) SetMetadataCalls(stub func(string, string) error) {
+	fake.setMetadataMutex.Lock()
+	defer fake.setMetadataMutex.Unlock()
+	fake.SetMetadataStub = stub
+}
+
+func (fake *FakeResource) SetMetadataArgsForCall(i int) (string, string) {
+	fake.setMetadataMutex.RLock()
+	defer fake.setMetadataMutex.RUnlock()
+	argsForCall := fake.setMetadataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeResource) SetMetadataReturns(result1 error) {
+	fake.setMetadataMutex.Lock()
+	defer fake.setMetadataMutex.Unlock()
+	fake.SetMetadataStub = nil
+	fake.setMetadataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeResource) SetMetadataReturnsOnCall(i int, result1 error) {
+	fake.setMetadataMutex.Lock()
+	defer fake.setMetadataMutex.Unlock()
+	fake.SetMetadataStub = nil
+	if fake.setMetadataReturnsOnCall == nil {
+		fake.setMetadataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setMetadataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeResource) SetPinComment(arg1 string) error {
 	fake.setPinCommentMutex.Lock()
 	ret, specificReturn := fake.setPinCommentReturnsOnCall[len(fake.setPinCommentArgsForCall)]
@@ -2817,6 +2954,8 @@ func (fake *FakeResource) Invocations() map[string][][]interface{} {
 	defer fake.pipelineNameMutex.RUnlock()
 	fake.pipelineRefMutex.RLock()
 	defer fake.pipelineRefMutex.RUnlock()
+	fake.preserveVersionHistoryMutex.RLock()
+	defer fake.preserveVersionHistoryMutex.RUnlock()
 	fake.publicMutex.RLock()
 	defer fake.publicMutex.RUnlock()
 	fake.reloadMutex.RLock()
@@ -2825,6 +2964,8 @@ func (fake *FakeResource) Invocations() map[string][][]interface{} {
 	defer fake.resourceConfigIDMutex.RUnlock()
 	fake.resourceConfigScopeIDMutex.RLock()
 	defer fake.resourceConfigScopeIDMutex.RUnlock()
+	fake.setMetadataMutex.RLock()
+	defer fake.setMetadataMutex.RUnlock()
 	fake.setPinCommentMutex.RLock()
 	defer fake.setPinCommentMutex.RUnlock()
 	fake.setResourceConfigScopeMutex.RLock()
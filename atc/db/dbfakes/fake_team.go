@@ -99,6 +99,21 @@ type FakeTeam struct {
 		result1 db.Build
 		result2 error
 	}
+	DecryptVarReferenceStub        func(string) (string, bool, error)
+	decryptVarReferenceMutex       sync.RWMutex
+	decryptVarReferenceArgsForCall []struct {
+		arg1 string
+	}
+	decryptVarReferenceReturns struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	decryptVarReferenceReturnsOnCall map[int]struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
 	DeleteStub        func() error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
@@ -109,6 +124,19 @@ type FakeTeam struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	EncryptedVarReferenceStub        func(string) (string, error)
+	encryptedVarReferenceMutex       sync.RWMutex
+	encryptedVarReferenceArgsForCall []struct {
+		arg1 string
+	}
+	encryptedVarReferenceReturns struct {
+		result1 string
+		result2 error
+	}
+	encryptedVarReferenceReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	FindCheckContainersStub        func(lager.Logger, atc.PipelineRef, string, creds.Secrets, creds.VarSourcePool) ([]db.Container, map[int]time.Time, error)
 	findCheckContainersMutex       sync.RWMutex
 	findCheckContainersArgsForCall []struct {
@@ -253,6 +281,19 @@ type FakeTeam struct {
 		result1 bool
 		result2 error
 	}
+	JobsNamedStub        func(string) (db.Jobs, error)
+	jobsNamedMutex       sync.RWMutex
+	jobsNamedArgsForCall []struct {
+		arg1 string
+	}
+	jobsNamedReturns struct {
+		result1 db.Jobs
+		result2 error
+	}
+	jobsNamedReturnsOnCall map[int]struct {
+		result1 db.Jobs
+		result2 error
+	}
 	NameStub        func() string
 	nameMutex       sync.RWMutex
 	nameArgsForCall []struct {
@@ -828,6 +869,73 @@ func (fake *FakeTeam) CreateStartedBuildReturnsOnCall(i int, result1 db.Build, r
 	}{result1, result2}
 }
 
+func (fake *FakeTeam) DecryptVarReference(arg1 string) (string, bool, error) {
+	fake.decryptVarReferenceMutex.Lock()
+	ret, specificReturn := fake.decryptVarReferenceReturnsOnCall[len(fake.decryptVarReferenceArgsForCall)]
+	fake.decryptVarReferenceArgsForCall = append(fake.decryptVarReferenceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DecryptVarReferenceStub
+	fakeReturns := fake.decryptVarReferenceReturns
+	fake.recordInvocation("DecryptVarReference", []interface{}{arg1})
+	fake.decryptVarReferenceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeTeam) DecryptVarReferenceCallCount() int {
+	fake.decryptVarReferenceMutex.RLock()
+	defer fake.decryptVarReferenceMutex.RUnlock()
+	return len(fake.decryptVarReferenceArgsForCall)
+}
+
+func (fake *FakeTeam) DecryptVarReferenceCalls(stub func(string) (string, bool, error)) {
+	fake.decryptVarReferenceMutex.Lock()
+	defer fake.decryptVarReferenceMutex.Unlock()
+	fake.DecryptVarReferenceStub = stub
+}
+
+func (fake *FakeTeam) DecryptVarReferenceArgsForCall(i int) string {
+	fake.decryptVarReferenceMutex.RLock()
+	defer fake.decryptVarReferenceMutex.RUnlock()
+	argsForCall := fake.decryptVarReferenceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTeam) DecryptVarReferenceReturns(result1 string, result2 bool, result3 error) {
+	fake.decryptVarReferenceMutex.Lock()
+	defer fake.decryptVarReferenceMutex.Unlock()
+	fake.DecryptVarReferenceStub = nil
+	fake.decryptVarReferenceReturns = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeam) DecryptVarReferenceReturnsOnCall(i int, result1 string, result2 bool, result3 error) {
+	fake.decryptVarReferenceMutex.Lock()
+	defer fake.decryptVarReferenceMutex.Unlock()
+	fake.DecryptVarReferenceStub = nil
+	if fake.decryptVarReferenceReturnsOnCall == nil {
+		fake.decryptVarReferenceReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 bool
+			result3 error
+		})
+	}
+	fake.decryptVarReferenceReturnsOnCall[i] = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeTeam) Delete() error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
@@ -881,6 +989,70 @@ func (fake *FakeTeam) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeTeam) EncryptedVarReference(arg1 string) (string, error) {
+	fake.encryptedVarReferenceMutex.Lock()
+	ret, specificReturn := fake.encryptedVarReferenceReturnsOnCall[len(fake.encryptedVarReferenceArgsForCall)]
+	fake.encryptedVarReferenceArgsForCall = append(fake.encryptedVarReferenceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.EncryptedVarReferenceStub
+	fakeReturns := fake.encryptedVarReferenceReturns
+	fake.recordInvocation("EncryptedVarReference", []interface{}{arg1})
+	fake.encryptedVarReferenceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTeam) EncryptedVarReferenceCallCount() int {
+	fake.encryptedVarReferenceMutex.RLock()
+	defer fake.encryptedVarReferenceMutex.RUnlock()
+	return len(fake.encryptedVarReferenceArgsForCall)
+}
+
+func (fake *FakeTeam) EncryptedVarReferenceCalls(stub func(string) (string, error)) {
+	fake.encryptedVarReferenceMutex.Lock()
+	defer fake.encryptedVarReferenceMutex.Unlock()
+	fake.EncryptedVarReferenceStub = stub
+}
+
+func (fake *FakeTeam) EncryptedVarReferenceArgsForCall(i int) string {
+	fake.encryptedVarReferenceMutex.RLock()
+	defer fake.encryptedVarReferenceMutex.RUnlock()
+	argsForCall := fake.encryptedVarReferenceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTeam) EncryptedVarReferenceReturns(result1 string, result2 error) {
+	fake.encryptedVarReferenceMutex.Lock()
+	defer fake.encryptedVarReferenceMutex.Unlock()
+	fake.EncryptedVarReferenceStub = nil
+	fake.encryptedVarReferenceReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeam) EncryptedVarReferenceReturnsOnCall(i int, result1 string, result2 error) {
+	fake.encryptedVarReferenceMutex.Lock()
+	defer fake.encryptedVarReferenceMutex.Unlock()
+	fake.EncryptedVarReferenceStub = nil
+	if fake.encryptedVarReferenceReturnsOnCall == nil {
+		fake.encryptedVarReferenceReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.encryptedVarReferenceReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTeam) FindCheckContainers(arg1 lager.Logger, arg2 atc.PipelineRef, arg3 string, arg4 creds.Secrets, arg5 creds.VarSourcePool) ([]db.Container, map[int]time.Time, error) {
 	fake.findCheckContainersMutex.Lock()
 	ret, specificReturn := fake.findCheckContainersReturnsOnCall[len(fake.findCheckContainersArgsForCall)]
@@ -1533,6 +1705,70 @@ func (fake *FakeTeam) IsContainerWithinTeamReturnsOnCall(i int, result1 bool, re
 	}{result1, result2}
 }
 
+func (fake *FakeTeam) JobsNamed(arg1 string) (db.Jobs, error) {
+	fake.jobsNamedMutex.Lock()
+	ret, specificReturn := fake.jobsNamedReturnsOnCall[len(fake.jobsNamedArgsForCall)]
+	fake.jobsNamedArgsForCall = append(fake.jobsNamedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.JobsNamedStub
+	fakeReturns := fake.jobsNamedReturns
+	fake.recordInvocation("JobsNamed", []interface{}{arg1})
+	fake.jobsNamedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTeam) JobsNamedCallCount() int {
+	fake.jobsNamedMutex.RLock()
+	defer fake.jobsNamedMutex.RUnlock()
+	return len(fake.jobsNamedArgsForCall)
+}
+
+func (fake *FakeTeam) JobsNamedCalls(stub func(string) (db.Jobs, error)) {
+	fake.jobsNamedMutex.Lock()
+	defer fake.jobsNamedMutex.Unlock()
+	fake.JobsNamedStub = stub
+}
+
+func (fake *FakeTeam) JobsNamedArgsForCall(i int) string {
+	fake.jobsNamedMutex.RLock()
+	defer fake.jobsNamedMutex.RUnlock()
+	argsForCall := fake.jobsNamedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTeam) JobsNamedReturns(result1 db.Jobs, result2 error) {
+	fake.jobsNamedMutex.Lock()
+	defer fake.jobsNamedMutex.Unlock()
+	fake.JobsNamedStub = nil
+	fake.jobsNamedReturns = struct {
+		result1 db.Jobs
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeam) JobsNamedReturnsOnCall(i int, result1 db.Jobs, result2 error) {
+	fake.jobsNamedMutex.Lock()
+	defer fake.jobsNamedMutex.Unlock()
+	fake.JobsNamedStub = nil
+	if fake.jobsNamedReturnsOnCall == nil {
+		fake.jobsNamedReturnsOnCall = make(map[int]struct {
+			result1 db.Jobs
+			result2 error
+		})
+	}
+	fake.jobsNamedReturnsOnCall[i] = struct {
+		result1 db.Jobs
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTeam) Name() string {
 	fake.nameMutex.Lock()
 	ret, specificReturn := fake.nameReturnsOnCall[len(fake.nameArgsForCall)]
@@ -2277,6 +2513,8 @@ func (fake *FakeTeam) WorkersReturnsOnCall(i int, result1 []db.Worker, result2 e
 }
 
 func (fake *FakeTeam) Invocations() map[string][][]interface{} {
+	fake.decryptVarReferenceMutex.RLock()
+	defer fake.decryptVarReferenceMutex.RUnlock()
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.adminMutex.RLock()
@@ -2295,6 +2533,8 @@ func (fake *FakeTeam) Invocations() map[string][][]interface{} {
 	defer fake.createStartedBuildMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.encryptedVarReferenceMutex.RLock()
+	defer fake.encryptedVarReferenceMutex.RUnlock()
 	fake.findCheckContainersMutex.RLock()
 	defer fake.findCheckContainersMutex.RUnlock()
 	fake.findContainerByHandleMutex.RLock()
@@ -2315,6 +2555,8 @@ func (fake *FakeTeam) Invocations() map[string][][]interface{} {
 	defer fake.isCheckContainerMutex.RUnlock()
 	fake.isContainerWithinTeamMutex.RLock()
 	defer fake.isContainerWithinTeamMutex.RUnlock()
+	fake.jobsNamedMutex.RLock()
+	defer fake.jobsNamedMutex.RUnlock()
 	fake.nameMutex.RLock()
 	defer fake.nameMutex.RUnlock()
 	fake.orderPipelinesMutex.RLock()
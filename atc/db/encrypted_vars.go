@@ -0,0 +1,111 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/concourse/atc/db/encryption"
+	"github.com/concourse/concourse/vars"
+)
+
+// EncryptedVarSourceName is the var source of references to values which
+// have been encrypted with the ATC's encryption key, i.e.
+// ((encrypted:<team id>/<nonce>/<ciphertext>)).
+const EncryptedVarSourceName = "encrypted"
+
+// ErrEncryptionNotConfigured is returned when a value can not be encrypted
+// because the ATC has no encryption key.
+var ErrEncryptionNotConfigured = errors.New("no encryption key is configured")
+
+// EncryptedVarTeamMismatchError is returned when an encrypted var is used in
+// a pipeline of a different team than the one it was encrypted for.
+type EncryptedVarTeamMismatchError struct {
+	TeamID int
+}
+
+// Error returns a human-friendly error message.
+func (err EncryptedVarTeamMismatchError) Error() string {
+	return fmt.Sprintf("encrypted var was not encrypted for team %d", err.TeamID)
+}
+
+// encryptedVarAssociatedData binds the ciphertext of an encrypted var to the
+// team, so that the team id in the reference can not be altered.
+func encryptedVarAssociatedData(teamID int) []byte {
+	return []byte("team:" + strconv.Itoa(teamID))
+}
+
+func encryptedVarReference(strategy encryption.Strategy, teamID int, value string) (string, error) {
+	s, ok := strategy.(encryption.AssociatedDataStrategy)
+	if !ok {
+		return "", ErrEncryptionNotConfigured
+	}
+
+	ciphertext, nonce, err := s.EncryptWithAssociatedData([]byte(value), encryptedVarAssociatedData(teamID))
+	if err != nil {
+		if err == encryption.ErrAssociatedDataNotSupported {
+			return "", ErrEncryptionNotConfigured
+		}
+
+		return "", err
+	}
+
+	return fmt.Sprintf("((%s:%d/%s/%s))", EncryptedVarSourceName, teamID, *nonce, ciphertext), nil
+}
+
+// decryptEncryptedVar decrypts the path of an encrypted var reference,
+// refusing references which were encrypted for another team.
+func decryptEncryptedVar(strategy encryption.Strategy, teamID int, path string) (string, error) {
+	segments := strings.SplitN(path, "/", 3)
+	if len(segments) != 3 {
+		return "", fmt.Errorf("malformed encrypted var: %s", path)
+	}
+
+	refTeamID, err := strconv.Atoi(segments[0])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted var: %s", path)
+	}
+
+	if refTeamID != teamID {
+		return "", EncryptedVarTeamMismatchError{TeamID: teamID}
+	}
+
+	s, ok := strategy.(encryption.AssociatedDataStrategy)
+	if !ok {
+		return "", ErrEncryptionNotConfigured
+	}
+
+	plaintext, err := s.DecryptWithAssociatedData(segments[2], &segments[1], encryptedVarAssociatedData(teamID))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// encryptedVariables resolves references created by encryptedVarReference by
+// decrypting the value embedded in the reference, and forwards all other
+// references to the wrapped variables.
+type encryptedVariables struct {
+	strategy encryption.Strategy
+	teamID   int
+	next     vars.Variables
+}
+
+func (v encryptedVariables) Get(ref vars.Reference) (interface{}, bool, error) {
+	if ref.Source != EncryptedVarSourceName {
+		return v.next.Get(ref)
+	}
+
+	plaintext, err := decryptEncryptedVar(v.strategy, v.teamID, ref.Path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return plaintext, true, nil
+}
+
+func (v encryptedVariables) List() ([]vars.Reference, error) {
+	return v.next.List()
+}
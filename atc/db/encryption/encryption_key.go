@@ -18,12 +18,16 @@ func NewKey(a cipher.AEAD) *Key {
 }
 
 func (e Key) Encrypt(plaintext []byte) (string, *string, error) {
+	return e.EncryptWithAssociatedData(plaintext, nil)
+}
+
+func (e Key) EncryptWithAssociatedData(plaintext []byte, data []byte) (string, *string, error) {
 	nonce := make([]byte, 12)
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", nil, err
 	}
 
-	ciphertext := e.aesgcm.Seal(nil, nonce, plaintext, nil)
+	ciphertext := e.aesgcm.Seal(nil, nonce, plaintext, data)
 
 	noncense := hex.EncodeToString(nonce)
 
@@ -31,6 +35,10 @@ func (e Key) Encrypt(plaintext []byte) (string, *string, error) {
 }
 
 func (e Key) Decrypt(text string, n *string) ([]byte, error) {
+	return e.DecryptWithAssociatedData(text, n, nil)
+}
+
+func (e Key) DecryptWithAssociatedData(text string, n *string, data []byte) ([]byte, error) {
 	if n == nil {
 		return nil, ErrDataIsNotEncrypted
 	}
@@ -45,7 +53,7 @@ func (e Key) Decrypt(text string, n *string) ([]byte, error) {
 		return nil, err
 	}
 
-	plaintext, err := e.aesgcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := e.aesgcm.Open(nil, nonce, ciphertext, data)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +43,25 @@ var _ = Describe("Encryption Key", func() {
 			Expect(decryptedText).To(Equal(plaintext))
 		})
 
+		Context("when associated data is given", func() {
+			It("decrypts only with the same associated data", func() {
+				plaintext = []byte("exampleplaintext")
+
+				encryptedText, nonce, err := key.EncryptWithAssociatedData(plaintext, []byte("team:1"))
+				Expect(err).ToNot(HaveOccurred())
+
+				decryptedText, err := key.DecryptWithAssociatedData(encryptedText, nonce, []byte("team:1"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(decryptedText).To(Equal(plaintext))
+
+				_, err = key.DecryptWithAssociatedData(encryptedText, nonce, []byte("team:2"))
+				Expect(err).To(HaveOccurred())
+
+				_, err = key.Decrypt(encryptedText, nonce)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		Context("when encrypting empty text", func() {
 			It("does not error", func() {
 				By("encrypting the plaintext")
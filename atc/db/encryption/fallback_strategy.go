@@ -1,5 +1,11 @@
 package encryption
 
+import "errors"
+
+// ErrAssociatedDataNotSupported is returned when data is to be bound to a
+// ciphertext but the strategy can not do so, e.g. because no key is configured.
+var ErrAssociatedDataNotSupported = errors.New("encryption strategy does not support associated data")
+
 type FallbackStrategy struct {
 	main     Strategy
 	fallback Strategy
@@ -24,3 +30,29 @@ func (n *FallbackStrategy) Decrypt(text string, nonce *string) ([]byte, error) {
 	return n.fallback.Decrypt(text, nonce)
 }
 
+func (n *FallbackStrategy) EncryptWithAssociatedData(plaintext []byte, data []byte) (string, *string, error) {
+	main, ok := n.main.(AssociatedDataStrategy)
+	if !ok {
+		return "", nil, ErrAssociatedDataNotSupported
+	}
+
+	return main.EncryptWithAssociatedData(plaintext, data)
+}
+
+func (n *FallbackStrategy) DecryptWithAssociatedData(text string, nonce *string, data []byte) ([]byte, error) {
+	var err error = ErrAssociatedDataNotSupported
+	for _, strategy := range []Strategy{n.main, n.fallback} {
+		s, ok := strategy.(AssociatedDataStrategy)
+		if !ok {
+			continue
+		}
+
+		var plaintext []byte
+		plaintext, err = s.DecryptWithAssociatedData(text, nonce, data)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, err
+}
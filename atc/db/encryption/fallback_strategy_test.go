@@ -51,5 +51,14 @@ var _ = Describe("Encryption Key with Fallback", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
-})
 
+	Context("when the keys do not support associated data", func() {
+		It("returns an error", func() {
+			_, _, err := key.EncryptWithAssociatedData([]byte("plaintext"), []byte("data"))
+			Expect(err).To(Equal(encryption.ErrAssociatedDataNotSupported))
+
+			_, err = key.DecryptWithAssociatedData("ciphertext", nil, []byte("data"))
+			Expect(err).To(Equal(encryption.ErrAssociatedDataNotSupported))
+		})
+	})
+})
@@ -11,3 +11,10 @@ type Strategy interface {
 	Encrypt([]byte) (string, *string, error)
 	Decrypt(string, *string) ([]byte, error)
 }
+
+// AssociatedDataStrategy is implemented by strategies which can bind a
+// ciphertext to additional data. The same data must be given to decrypt it.
+type AssociatedDataStrategy interface {
+	EncryptWithAssociatedData(plaintext []byte, data []byte) (string, *string, error)
+	DecryptWithAssociatedData(text string, nonce *string, data []byte) ([]byte, error)
+}
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE pipelines
+    ADD COLUMN config_hash text;
+COMMIT;
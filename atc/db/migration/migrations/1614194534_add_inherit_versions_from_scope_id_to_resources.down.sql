@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE resources
+DROP COLUMN inherit_versions_from_scope_id;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE resources
+    ADD COLUMN inherit_versions_from_scope_id integer REFERENCES resource_config_scopes (id) ON DELETE SET NULL;
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+  CREATE TABLE pipeline_rollback_log (
+      id serial PRIMARY KEY,
+      pipeline_id integer REFERENCES pipelines(id) ON DELETE CASCADE NOT NULL,
+      build_id integer REFERENCES builds(id) ON DELETE SET NULL,
+      failed_config_version integer NOT NULL,
+      reason text NOT NULL,
+      rolled_back bool NOT NULL DEFAULT false,
+      created_at timestamp with time zone NOT NULL DEFAULT now()
+  );
+
+  CREATE INDEX pipeline_rollback_log_pipeline_id_idx ON pipeline_rollback_log (pipeline_id);
+COMMIT;
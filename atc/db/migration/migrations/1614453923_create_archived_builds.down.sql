@@ -0,0 +1,3 @@
+BEGIN;
+  DROP TABLE archived_builds;
+COMMIT;
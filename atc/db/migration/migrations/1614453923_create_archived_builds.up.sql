@@ -0,0 +1,14 @@
+BEGIN;
+  CREATE TABLE archived_builds (
+      id integer PRIMARY KEY,
+      pipeline_id integer REFERENCES pipelines(id) ON DELETE CASCADE NOT NULL,
+      job_name text NOT NULL,
+      name text NOT NULL,
+      status build_status NOT NULL,
+      start_time timestamp with time zone,
+      end_time timestamp with time zone,
+      archived_at timestamp with time zone NOT NULL DEFAULT now()
+  );
+
+  CREATE INDEX archived_builds_pipeline_id_idx ON archived_builds (pipeline_id);
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+  CREATE TABLE pipeline_notifications (
+      pipeline_id integer PRIMARY KEY REFERENCES pipelines(id) ON DELETE CASCADE,
+      on_failure text[] NOT NULL DEFAULT '{}',
+      on_success text[] NOT NULL DEFAULT '{}',
+      updated_at timestamp with time zone NOT NULL DEFAULT now()
+  );
+COMMIT;
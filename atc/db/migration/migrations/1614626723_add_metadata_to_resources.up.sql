@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE resources
+    ADD COLUMN metadata jsonb NOT NULL DEFAULT '{}';
+COMMIT;
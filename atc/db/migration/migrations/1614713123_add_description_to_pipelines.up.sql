@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE pipelines
+    ADD COLUMN description text NOT NULL DEFAULT '';
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+  CREATE TABLE pipeline_ownership (
+      pipeline_id integer PRIMARY KEY REFERENCES pipelines(id) ON DELETE CASCADE,
+      team text NOT NULL DEFAULT '',
+      contact text NOT NULL DEFAULT '',
+      updated_at timestamp with time zone NOT NULL DEFAULT now()
+  );
+COMMIT;
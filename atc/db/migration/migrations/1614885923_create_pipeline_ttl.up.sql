@@ -0,0 +1,7 @@
+BEGIN;
+  CREATE TABLE pipeline_ttl (
+      pipeline_id integer PRIMARY KEY REFERENCES pipelines(id) ON DELETE CASCADE,
+      archive_after interval NOT NULL,
+      updated_at timestamp with time zone NOT NULL DEFAULT now()
+  );
+COMMIT;
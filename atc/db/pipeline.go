@@ -1,7 +1,9 @@
 package db
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -11,6 +13,7 @@ import (
 	"code.cloudfoundry.org/lager"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
 	"github.com/concourse/concourse/atc"
@@ -47,10 +50,13 @@ type Pipeline interface {
 	VarSources() atc.VarSourceConfigs
 	Display() *atc.DisplayConfig
 	ConfigVersion() ConfigVersion
+	ConfigHash() string
 	Config() (atc.Config, error)
 	Public() bool
 	Paused() bool
 	Archived() bool
+	Frozen() bool
+	Description() string
 	LastUpdated() time.Time
 
 	CheckPaused() (bool, error)
@@ -92,11 +98,30 @@ type Pipeline interface {
 
 	Archive() error
 
+	UpdateDisplay(atc.DisplayConfig) error
+	SetFrozen(bool) error
+	SetDescription(string) error
+	RecordRollback(PipelineRollback) error
+	ResetBuildHistory() error
+
+	SetNotifications(atc.PipelineNotifications) error
+	ClearNotifications() error
+	Notifications() (atc.PipelineNotifications, bool, error)
+
+	SetOwnership(atc.PipelineOwnership) error
+	ClearOwnership() error
+	Ownership() (atc.PipelineOwnership, bool, error)
+
+	SetArchiveAfter(time.Duration) error
+	ClearArchiveAfter() error
+
 	Destroy() error
 
 	Variables(lager.Logger, creds.Secrets, creds.VarSourcePool) (vars.Variables, error)
 
 	SetParentIDs(jobID, buildID int) error
+
+	TriggerImmediateResourceChecks() error
 }
 
 type pipeline struct {
@@ -111,9 +136,12 @@ type pipeline struct {
 	varSources    atc.VarSourceConfigs
 	display       *atc.DisplayConfig
 	configVersion ConfigVersion
+	configHash    string
 	paused        bool
 	public        bool
 	archived      bool
+	frozen        bool
+	description   string
 	lastUpdated   time.Time
 
 	conn        Conn
@@ -123,6 +151,15 @@ type pipeline struct {
 // ConfigVersion is a sequence identifier used for compare-and-swap.
 type ConfigVersion int
 
+// PipelineRollback records a set_pipeline step's decision to roll back a
+// config which was found to be broken after it was saved.
+type PipelineRollback struct {
+	BuildID             int
+	FailedConfigVersion ConfigVersion
+	Reason              string
+	RolledBack          bool
+}
+
 var pipelinesQuery = psql.Select(`
 		p.id,
 		p.name,
@@ -139,7 +176,10 @@ var pipelinesQuery = psql.Select(`
 		p.last_updated,
 		p.parent_job_id,
 		p.parent_build_id,
-		p.instance_vars
+		p.instance_vars,
+		p.config_hash,
+		p.frozen,
+		p.description
 	`).
 	From("pipelines p").
 	LeftJoin("teams t ON p.team_id = t.id")
@@ -163,9 +203,12 @@ func (p *pipeline) Groups() atc.GroupConfigs       { return p.groups }
 func (p *pipeline) VarSources() atc.VarSourceConfigs { return p.varSources }
 func (p *pipeline) Display() *atc.DisplayConfig      { return p.display }
 func (p *pipeline) ConfigVersion() ConfigVersion     { return p.configVersion }
+func (p *pipeline) ConfigHash() string               { return p.configHash }
 func (p *pipeline) Public() bool                     { return p.public }
 func (p *pipeline) Paused() bool                     { return p.paused }
 func (p *pipeline) Archived() bool                   { return p.archived }
+func (p *pipeline) Frozen() bool                     { return p.frozen }
+func (p *pipeline) Description() string              { return p.description }
 func (p *pipeline) LastUpdated() time.Time           { return p.lastUpdated }
 
 // IMPORTANT: This method is broken with the new resource config versions changes
@@ -666,6 +709,7 @@ func (p *pipeline) archive(tx Tx) error {
 		Set("last_updated", sq.Expr("now()")).
 		Set("paused", true).
 		Set("version", 0).
+		Set("config_hash", nil).
 		Where(sq.Eq{
 			"id": p.id,
 		}).
@@ -713,6 +757,254 @@ func (p *pipeline) Expose() error {
 	return err
 }
 
+func (p *pipeline) UpdateDisplay(display atc.DisplayConfig) error {
+	displayPayload, err := json.Marshal(display)
+	if err != nil {
+		return err
+	}
+
+	_, err = psql.Update("pipelines").
+		Set("display", displayPayload).
+		Where(sq.Eq{
+			"id": p.id,
+		}).
+		RunWith(p.conn).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	p.display = &display
+
+	return nil
+}
+
+// SetFrozen marks whether the pipeline may only be configured by the build
+// that set it, rejecting manual updates through the API.
+func (p *pipeline) SetFrozen(frozen bool) error {
+	_, err := psql.Update("pipelines").
+		Set("frozen", frozen).
+		Where(sq.Eq{
+			"id": p.id,
+		}).
+		RunWith(p.conn).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	p.frozen = frozen
+
+	return nil
+}
+
+// SetDescription sets the human-readable description of the pipeline shown
+// when listing pipelines.
+func (p *pipeline) SetDescription(description string) error {
+	_, err := psql.Update("pipelines").
+		Set("description", description).
+		Where(sq.Eq{
+			"id": p.id,
+		}).
+		RunWith(p.conn).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	p.description = description
+
+	return nil
+}
+
+// RecordRollback appends the rollback decision to the pipeline's rollback
+// log.
+func (p *pipeline) RecordRollback(rollback PipelineRollback) error {
+	_, err := psql.Insert("pipeline_rollback_log").
+		Columns("pipeline_id", "build_id", "failed_config_version", "reason", "rolled_back").
+		Values(p.id, rollback.BuildID, rollback.FailedConfigVersion, rollback.Reason, rollback.RolledBack).
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+// SetNotifications replaces the recipients notified when builds of the
+// pipeline's jobs complete.
+func (p *pipeline) SetNotifications(notifications atc.PipelineNotifications) error {
+	_, err := psql.Insert("pipeline_notifications").
+		Columns("pipeline_id", "on_failure", "on_success").
+		Values(p.id, pq.Array(notifications.OnFailure), pq.Array(notifications.OnSuccess)).
+		Suffix("ON CONFLICT (pipeline_id) DO UPDATE SET on_failure = EXCLUDED.on_failure, on_success = EXCLUDED.on_success, updated_at = now()").
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+// ClearNotifications removes the recipients notified when builds of the
+// pipeline's jobs complete.
+func (p *pipeline) ClearNotifications() error {
+	_, err := psql.Delete("pipeline_notifications").
+		Where(sq.Eq{"pipeline_id": p.id}).
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+func (p *pipeline) Notifications() (atc.PipelineNotifications, bool, error) {
+	var notifications atc.PipelineNotifications
+
+	err := psql.Select("on_failure", "on_success").
+		From("pipeline_notifications").
+		Where(sq.Eq{"pipeline_id": p.id}).
+		RunWith(p.conn).
+		QueryRow().
+		Scan(pq.Array(&notifications.OnFailure), pq.Array(&notifications.OnSuccess))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return atc.PipelineNotifications{}, false, nil
+		}
+
+		return atc.PipelineNotifications{}, false, err
+	}
+
+	return notifications, true, nil
+}
+
+// SetOwnership replaces the team and contact responsible for the pipeline.
+func (p *pipeline) SetOwnership(ownership atc.PipelineOwnership) error {
+	_, err := psql.Insert("pipeline_ownership").
+		Columns("pipeline_id", "team", "contact").
+		Values(p.id, ownership.Team, ownership.Contact).
+		Suffix("ON CONFLICT (pipeline_id) DO UPDATE SET team = EXCLUDED.team, contact = EXCLUDED.contact, updated_at = now()").
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+// ClearOwnership removes the team and contact responsible for the pipeline.
+func (p *pipeline) ClearOwnership() error {
+	_, err := psql.Delete("pipeline_ownership").
+		Where(sq.Eq{"pipeline_id": p.id}).
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+func (p *pipeline) Ownership() (atc.PipelineOwnership, bool, error) {
+	var ownership atc.PipelineOwnership
+
+	err := psql.Select("team", "contact").
+		From("pipeline_ownership").
+		Where(sq.Eq{"pipeline_id": p.id}).
+		RunWith(p.conn).
+		QueryRow().
+		Scan(&ownership.Team, &ownership.Contact)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return atc.PipelineOwnership{}, false, nil
+		}
+
+		return atc.PipelineOwnership{}, false, err
+	}
+
+	return ownership, true, nil
+}
+
+// SetArchiveAfter makes the pipeline eligible for archiving once it has gone
+// the given duration without any builds. The countdown restarts every time it
+// is set.
+func (p *pipeline) SetArchiveAfter(archiveAfter time.Duration) error {
+	_, err := psql.Insert("pipeline_ttl").
+		Columns("pipeline_id", "archive_after").
+		Values(p.id, sq.Expr("make_interval(secs => ?)", archiveAfter.Seconds())).
+		Suffix("ON CONFLICT (pipeline_id) DO UPDATE SET archive_after = EXCLUDED.archive_after, updated_at = now()").
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+// ClearArchiveAfter makes the pipeline no longer eligible for archiving when
+// it goes without builds.
+func (p *pipeline) ClearArchiveAfter() error {
+	_, err := psql.Delete("pipeline_ttl").
+		Where(sq.Eq{"pipeline_id": p.id}).
+		RunWith(p.conn).
+		Exec()
+	return err
+}
+
+// ResetBuildHistory moves the completed builds of the pipeline's jobs into
+// archived_builds and restarts the build numbering of every job which has no
+// builds left. Builds which are still running, or have a rerun which is still
+// running, are kept.
+func (p *pipeline) ResetBuildHistory() error {
+	tx, err := p.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer Rollback(tx)
+
+	_, err = tx.Exec(`
+		DELETE FROM build_events
+		WHERE build_id IN (
+			SELECT b.id
+			FROM builds b
+			WHERE b.pipeline_id = $1
+			AND b.job_id IS NOT NULL
+			AND b.completed
+			AND NOT EXISTS (
+				SELECT 1
+				FROM builds r
+				WHERE r.rerun_of = b.id
+				AND NOT r.completed
+			)
+		)
+	`, p.id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		WITH archived AS (
+			DELETE FROM builds b
+			USING jobs j
+			WHERE j.id = b.job_id
+			AND b.pipeline_id = $1
+			AND b.completed
+			AND NOT EXISTS (
+				SELECT 1
+				FROM builds r
+				WHERE r.rerun_of = b.id
+				AND NOT r.completed
+			)
+			RETURNING b.id, j.name AS job_name, b.name, b.status, b.start_time, b.end_time
+		)
+		INSERT INTO archived_builds (id, pipeline_id, job_name, name, status, start_time, end_time)
+		SELECT id, $1, job_name, name, status, start_time, end_time
+		FROM archived
+	`, p.id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE jobs
+		SET build_number_seq = 0, first_logged_build_id = 0
+		WHERE pipeline_id = $1
+		AND NOT EXISTS (
+			SELECT 1
+			FROM builds b
+			WHERE b.job_id = jobs.id
+		)
+	`, p.id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (p *pipeline) Destroy() error {
 	tx, err := p.conn.Begin()
 	if err != nil {
@@ -1123,11 +1415,22 @@ func (p *pipeline) Variables(logger lager.Logger, globalSecrets creds.Secrets, v
 
 	// If there is no var_source from the pipeline, then just return the global
 	// vars.
-	if len(namedVarsMap) == 0 {
-		return globalVars, nil
+	var variables vars.Variables = globalVars
+	if len(namedVarsMap) > 0 {
+		variables = allVars
+	}
+
+	// a var_source of the same name takes precedence over the values
+	// encrypted by set_pipeline steps
+	if _, found := namedVarsMap[EncryptedVarSourceName]; !found {
+		variables = encryptedVariables{
+			strategy: p.conn.EncryptionStrategy(),
+			teamID:   p.teamID,
+			next:     variables,
+		}
 	}
 
-	return allVars, nil
+	return variables, nil
 }
 
 func (p *pipeline) SetParentIDs(jobID, buildID int) error {
@@ -1289,3 +1592,29 @@ func (p *pipeline) clearConfigForResourceTypesInPipeline(tx Tx) error {
 
 	return nil
 }
+
+// ConfigHash returns the hex-encoded SHA-256 of the marshaled config. It is
+// stored alongside the pipeline so that re-saving an identical config can be
+// detected without loading and comparing the existing config.
+func ConfigHash(config atc.Config) (string, error) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// TriggerImmediateResourceChecks resets the last check end time of every
+// active resource in the pipeline so that they are checked on the next scan,
+// regardless of their check interval.
+func (p *pipeline) TriggerImmediateResourceChecks() error {
+	_, err := psql.Update("resource_config_scopes").
+		Set("last_check_end_time", time.Unix(0, 0)).
+		Where(sq.Expr("id IN (SELECT resource_config_scope_id FROM resources WHERE pipeline_id = ? AND active)", p.id)).
+		RunWith(p.conn).
+		Exec()
+	return err
+}
@@ -12,6 +12,7 @@ import (
 
 type PipelineLifecycle interface {
 	ArchiveAbandonedPipelines() error
+	ArchiveExpiredPipelines() error
 	RemoveBuildEventsForDeletedPipelines() error
 }
 
@@ -69,6 +70,45 @@ func (pl *pipelineLifecycle) ArchiveAbandonedPipelines() error {
 	return nil
 }
 
+// ArchiveExpiredPipelines archives the pipelines configured with a ttl which
+// have not had a build created within it.
+func (pl *pipelineLifecycle) ArchiveExpiredPipelines() error {
+	tx, err := pl.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer Rollback(tx)
+
+	rows, err := pipelinesQuery.
+		Join("pipeline_ttl ttl ON ttl.pipeline_id = p.id").
+		Where(sq.Eq{"p.archived": false}).
+		Where(sq.Expr("ttl.updated_at < now() - ttl.archive_after")).
+		Where(sq.Expr(`NOT EXISTS (
+			SELECT 1 FROM builds b
+			WHERE b.pipeline_id = p.id
+			AND b.create_time > now() - ttl.archive_after
+		)`)).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	err = archivePipelines(tx, pl.conn, pl.lockFactory, rows)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func archivePipelines(tx Tx, conn Conn, lockFactory lock.LockFactory, rows *sql.Rows) error {
 	var toArchive []pipeline
 	for rows.Next() {
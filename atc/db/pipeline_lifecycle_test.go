@@ -2,6 +2,7 @@ package db_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/concourse/concourse/atc"
 	"github.com/concourse/concourse/atc/db"
@@ -84,6 +85,70 @@ var _ = Describe("PipelineLifecycle", func() {
 		})
 	})
 
+	Describe("ArchiveExpiredPipelines", func() {
+		JustBeforeEach(func() {
+			err = pl.ArchiveExpiredPipelines()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the pipeline does not have a ttl", func() {
+			It("does not archive the pipeline", func() {
+				defaultPipeline.Reload()
+				Expect(defaultPipeline.Archived()).To(BeFalse())
+			})
+		})
+
+		Context("when the pipeline has a ttl", func() {
+			BeforeEach(func() {
+				err := defaultPipeline.SetArchiveAfter(time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the ttl was set within the duration", func() {
+				It("does not archive the pipeline", func() {
+					defaultPipeline.Reload()
+					Expect(defaultPipeline.Archived()).To(BeFalse())
+				})
+			})
+
+			Context("when the ttl was set before the duration", func() {
+				BeforeEach(func() {
+					_, err := dbConn.Exec(`UPDATE pipeline_ttl SET updated_at = now() - interval '2 hours' WHERE pipeline_id = $1`, defaultPipeline.ID())
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("archives the pipeline", func() {
+					defaultPipeline.Reload()
+					Expect(defaultPipeline.Archived()).To(BeTrue())
+				})
+
+				Context("when the ttl has been cleared", func() {
+					BeforeEach(func() {
+						err := defaultPipeline.ClearArchiveAfter()
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("does not archive the pipeline", func() {
+						defaultPipeline.Reload()
+						Expect(defaultPipeline.Archived()).To(BeFalse())
+					})
+				})
+
+				Context("when the pipeline had a build within the duration", func() {
+					BeforeEach(func() {
+						_, err := defaultJob.CreateBuild(defaultBuildCreatedBy)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("does not archive the pipeline", func() {
+						defaultPipeline.Reload()
+						Expect(defaultPipeline.Archived()).To(BeFalse())
+					})
+				})
+			})
+		})
+	})
+
 	Describe("RemoveBuildEventsForDeletedPipelines", func() {
 		var (
 			pipeline1 db.Pipeline
@@ -258,6 +258,10 @@ var _ = Describe("Pipeline", func() {
 			Expect(version).To(Equal(db.ConfigVersion(0)))
 		})
 
+		It("clears the config hash", func() {
+			Expect(pipeline.ConfigHash()).To(BeEmpty())
+		})
+
 		It("removes the config of each job", func() {
 			jobs, err := pipeline.Jobs()
 			Expect(err).ToNot(HaveOccurred())
@@ -2155,6 +2159,232 @@ var _ = Describe("Pipeline", func() {
 		})
 	})
 
+	Describe("TriggerImmediateResourceChecks", func() {
+		var scenario *dbtest.Scenario
+
+		BeforeEach(func() {
+			scenario = dbtest.Setup(
+				builder.WithPipeline(atc.Config{
+					Resources: atc.ResourceConfigs{
+						{
+							Name:   "some-resource",
+							Type:   dbtest.BaseResourceType,
+							Source: atc.Source{"some": "source"},
+						},
+					},
+				}),
+				builder.WithResourceVersions("some-resource", atc.Version{"some": "version"}),
+			)
+
+			Expect(scenario.Resource("some-resource").LastCheckEndTime()).ToNot(BeTemporally("==", time.Unix(0, 0)))
+		})
+
+		It("resets the last check end time of the resources", func() {
+			Expect(scenario.Pipeline.TriggerImmediateResourceChecks()).To(Succeed())
+			Expect(scenario.Resource("some-resource").LastCheckEndTime()).To(BeTemporally("==", time.Unix(0, 0)))
+		})
+	})
+
+	Describe("UpdateDisplay", func() {
+		It("updates the display config of the pipeline", func() {
+			display := atc.DisplayConfig{BackgroundImage: "https://example.com/image.png"}
+			Expect(pipeline.UpdateDisplay(display)).To(Succeed())
+			Expect(pipeline.Display()).To(Equal(&display))
+
+			reloaded, err := pipeline.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reloaded).To(BeTrue())
+			Expect(pipeline.Display()).To(Equal(&display))
+		})
+	})
+
+	Describe("SetFrozen", func() {
+		It("is not frozen by default", func() {
+			Expect(pipeline.Frozen()).To(BeFalse())
+		})
+
+		It("freezes and unfreezes the pipeline", func() {
+			Expect(pipeline.SetFrozen(true)).To(Succeed())
+			Expect(pipeline.Frozen()).To(BeTrue())
+
+			reloaded, err := pipeline.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reloaded).To(BeTrue())
+			Expect(pipeline.Frozen()).To(BeTrue())
+
+			Expect(pipeline.SetFrozen(false)).To(Succeed())
+
+			_, err = pipeline.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pipeline.Frozen()).To(BeFalse())
+		})
+	})
+
+	Describe("ResetBuildHistory", func() {
+		var (
+			job           db.Job
+			finishedBuild db.Build
+			runningBuild  db.Build
+		)
+
+		BeforeEach(func() {
+			var found bool
+			var err error
+			job, found, err = pipeline.Job("job-name")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			finishedBuild, err = job.CreateBuild(defaultBuildCreatedBy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(finishedBuild.Finish(db.BuildStatusSucceeded)).To(Succeed())
+
+			otherJob, found, err := pipeline.Job("some-other-job")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			runningBuild, err = otherJob.CreateBuild(defaultBuildCreatedBy)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pipeline.ResetBuildHistory()).To(Succeed())
+		})
+
+		It("archives the completed builds", func() {
+			builds, _, err := pipeline.Builds(db.Page{Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].ID()).To(Equal(runningBuild.ID()))
+
+			var jobName, status string
+			err = dbConn.QueryRow("SELECT job_name, status FROM archived_builds WHERE id = $1", finishedBuild.ID()).Scan(&jobName, &status)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(jobName).To(Equal("job-name"))
+			Expect(status).To(Equal("succeeded"))
+		})
+
+		It("restarts the build numbers of jobs without builds", func() {
+			build, err := job.CreateBuild(defaultBuildCreatedBy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(build.Name()).To(Equal("1"))
+		})
+	})
+
+	Describe("RecordRollback", func() {
+		It("appends the decision to the rollback log", func() {
+			build, err := pipeline.CreateOneOffBuild()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pipeline.RecordRollback(db.PipelineRollback{
+				BuildID:             build.ID(),
+				FailedConfigVersion: pipeline.ConfigVersion(),
+				Reason:              "some-reason",
+				RolledBack:          true,
+			})).To(Succeed())
+
+			var reason string
+			var rolledBack bool
+			err = dbConn.QueryRow("SELECT reason, rolled_back FROM pipeline_rollback_log WHERE pipeline_id = $1", pipeline.ID()).Scan(&reason, &rolledBack)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reason).To(Equal("some-reason"))
+			Expect(rolledBack).To(BeTrue())
+		})
+	})
+
+	Describe("Notifications", func() {
+		It("returns not found until notifications are set", func() {
+			_, found, err := pipeline.Notifications()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("replaces the notifications when set again", func() {
+			Expect(pipeline.SetNotifications(atc.PipelineNotifications{
+				OnFailure: []string{"email:ops@example.com"},
+			})).To(Succeed())
+
+			Expect(pipeline.SetNotifications(atc.PipelineNotifications{
+				OnFailure: []string{"email:dev@example.com"},
+				OnSuccess: []string{"webhook:https://example.com/hook"},
+			})).To(Succeed())
+
+			notifications, found, err := pipeline.Notifications()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(notifications).To(Equal(atc.PipelineNotifications{
+				OnFailure: []string{"email:dev@example.com"},
+				OnSuccess: []string{"webhook:https://example.com/hook"},
+			}))
+		})
+
+		It("returns not found once the notifications are cleared", func() {
+			Expect(pipeline.SetNotifications(atc.PipelineNotifications{
+				OnFailure: []string{"email:ops@example.com"},
+			})).To(Succeed())
+
+			Expect(pipeline.ClearNotifications()).To(Succeed())
+
+			_, found, err := pipeline.Notifications()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("Ownership", func() {
+		It("returns not found until ownership is set", func() {
+			_, found, err := pipeline.Ownership()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("replaces the ownership when set again", func() {
+			Expect(pipeline.SetOwnership(atc.PipelineOwnership{
+				Team:    "platform-eng",
+				Contact: "platform@example.com",
+			})).To(Succeed())
+
+			Expect(pipeline.SetOwnership(atc.PipelineOwnership{
+				Team:    "api",
+				Contact: "api@example.com",
+			})).To(Succeed())
+
+			ownership, found, err := pipeline.Ownership()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(ownership).To(Equal(atc.PipelineOwnership{
+				Team:    "api",
+				Contact: "api@example.com",
+			}))
+		})
+
+		It("returns not found once the ownership is cleared", func() {
+			Expect(pipeline.SetOwnership(atc.PipelineOwnership{
+				Team:    "platform-eng",
+				Contact: "platform@example.com",
+			})).To(Succeed())
+
+			Expect(pipeline.ClearOwnership()).To(Succeed())
+
+			_, found, err := pipeline.Ownership()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("SetDescription", func() {
+		It("has no description by default", func() {
+			Expect(pipeline.Description()).To(BeEmpty())
+		})
+
+		It("sets the description", func() {
+			Expect(pipeline.SetDescription("Deploys the API service")).To(Succeed())
+			Expect(pipeline.Description()).To(Equal("Deploys the API service"))
+
+			reloaded, err := pipeline.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reloaded).To(BeTrue())
+			Expect(pipeline.Description()).To(Equal("Deploys the API service"))
+		})
+	})
+
 	Context("Config", func() {
 		It("should return config correctly", func() {
 			Expect(pipeline.Config()).To(Equal(pipelineConfig))
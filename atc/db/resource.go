@@ -43,6 +43,7 @@ type Resource interface {
 	APIPinnedVersion() atc.Version
 	PinComment() string
 	SetPinComment(string) error
+	SetMetadata(key string, value string) error
 	ResourceConfigID() int
 	ResourceConfigScopeID() int
 	Icon() string
@@ -64,6 +65,7 @@ type Resource interface {
 	UnpinVersion() error
 
 	SetResourceConfigScope(ResourceConfigScope) error
+	PreserveVersionHistory() error
 
 	CheckPlan(atc.Version, time.Duration, ResourceTypes, atc.Source) atc.CheckPlan
 	CreateBuild(context.Context, bool, atc.Plan) (Build, bool, error)
@@ -245,6 +247,11 @@ func (r *resource) setResourceConfigScopeInTransaction(tx Tx, scope ResourceConf
 	}
 
 	if rowsAffected > 0 {
+		err = inheritVersionHistory(tx, r.id, scope.ID())
+		if err != nil {
+			return err
+		}
+
 		err = requestScheduleForJobsUsingResource(tx, r.id)
 		if err != nil {
 			return err
@@ -254,6 +261,56 @@ func (r *resource) setResourceConfigScopeInTransaction(tx Tx, scope ResourceConf
 	return nil
 }
 
+// PreserveVersionHistory marks the versions of the resource's current scope
+// to be copied into the next scope set on the resource, e.g. once its source
+// has changed and it has been checked again.
+func (r *resource) PreserveVersionHistory() error {
+	_, err := psql.Update("resources").
+		Set("inherit_versions_from_scope_id", sq.Expr("resource_config_scope_id")).
+		Where(sq.Eq{"id": r.id}).
+		Where(sq.NotEq{"resource_config_scope_id": nil}).
+		RunWith(r.conn).
+		Exec()
+	return err
+}
+
+func inheritVersionHistory(tx Tx, resourceID int, scopeID int) error {
+	var fromScopeID sql.NullInt64
+	err := psql.Select("inherit_versions_from_scope_id").
+		From("resources").
+		Where(sq.Eq{"id": resourceID}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&fromScopeID)
+	if err != nil {
+		return err
+	}
+
+	if !fromScopeID.Valid {
+		return nil
+	}
+
+	if int(fromScopeID.Int64) != scopeID {
+		_, err = tx.Exec(`
+			INSERT INTO resource_config_versions (resource_config_scope_id, version, version_md5, metadata, check_order, span_context)
+			SELECT $1, version, version_md5, metadata, check_order, span_context
+			FROM resource_config_versions
+			WHERE resource_config_scope_id = $2
+			ON CONFLICT (resource_config_scope_id, version_md5) DO NOTHING
+		`, scopeID, fromScopeID.Int64)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = psql.Update("resources").
+		Set("inherit_versions_from_scope_id", nil).
+		Where(sq.Eq{"id": resourceID}).
+		RunWith(tx).
+		Exec()
+	return err
+}
+
 func (r *resource) CheckPlan(from atc.Version, interval time.Duration, resourceTypes ResourceTypes, sourceDefaults atc.Source) atc.CheckPlan {
 	return atc.CheckPlan{
 		Name:    r.Name(),
@@ -426,6 +483,22 @@ func (r *resource) SetPinComment(comment string) error {
 	return err
 }
 
+// SetMetadata records the metadata key on the resource, replacing any earlier
+// value of the same key.
+func (r *resource) SetMetadata(key string, value string) error {
+	_, err := psql.Update("resources").
+		Set("metadata", sq.Expr(
+			"metadata || jsonb_build_object(?::text, ?::text)",
+			key,
+			value,
+		)).
+		Where(sq.Eq{"id": r.id}).
+		RunWith(r.conn).
+		Exec()
+
+	return err
+}
+
 func (r *resource) CurrentPinnedVersion() atc.Version {
 	if r.configPinnedVersion != nil {
 		return r.configPinnedVersion
@@ -419,6 +419,47 @@ var _ = Describe("Resource", func() {
 			Expect(job.ScheduleRequestedTime()).Should(BeTemporally(">", requestedSchedule))
 			Expect(otherJob.ScheduleRequestedTime()).Should(Equal(otherRequestedSchedule))
 		})
+
+		Context("when the version history is preserved", func() {
+			var newScope db.ResourceConfigScope
+
+			BeforeEach(func() {
+				Expect(resource.SetResourceConfigScope(scope)).To(Succeed())
+				Expect(scope.SaveVersions(nil, []atc.Version{{"ref": "v1"}, {"ref": "v2"}})).To(Succeed())
+
+				_, err := resource.Reload()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(resource.PreserveVersionHistory()).To(Succeed())
+
+				newConfig, err := resourceConfigFactory.FindOrCreateResourceConfig(resource.Type(), atc.Source{"some": "other-branch"}, atc.VersionedResourceTypes{})
+				Expect(err).ToNot(HaveOccurred())
+
+				newScope, err = newConfig.FindOrCreateScope(resource)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("copies the versions into the next scope", func() {
+				Expect(resource.SetResourceConfigScope(newScope)).To(Succeed())
+
+				latest, found, err := newScope.LatestVersion()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(latest.Version()).To(Equal(db.Version{"ref": "v2"}))
+			})
+
+			It("only copies them once", func() {
+				Expect(resource.SetResourceConfigScope(newScope)).To(Succeed())
+				Expect(resource.SetResourceConfigScope(scope)).To(Succeed())
+				Expect(scope.SaveVersions(nil, []atc.Version{{"ref": "v3"}})).To(Succeed())
+				Expect(resource.SetResourceConfigScope(newScope)).To(Succeed())
+
+				latest, found, err := newScope.LatestVersion()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(latest.Version()).To(Equal(db.Version{"ref": "v2"}))
+			})
+		})
 	})
 
 	Describe("CheckPlan", func() {
@@ -1218,4 +1259,33 @@ var _ = Describe("Resource", func() {
 			})
 		})
 	})
+
+	Describe("SetMetadata", func() {
+		var resource db.Resource
+
+		BeforeEach(func() {
+			var found bool
+			var err error
+			resource, found, err = pipeline.Resource("some-resource")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			err = resource.SetMetadata("last_set_by_build", "1")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = resource.SetMetadata("some-key", "some-value")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = resource.SetMetadata("last_set_by_build", "2")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("records the keys, replacing earlier values", func() {
+			var metadata []byte
+			err := dbConn.QueryRow(`SELECT metadata FROM resources WHERE id = $1`, resource.ID()).Scan(&metadata)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(metadata).To(MatchJSON(`{"last_set_by_build": "2", "some-key": "some-value"}`))
+		})
+	})
 })
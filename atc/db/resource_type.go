@@ -388,6 +388,10 @@ func scanResourceType(t *resourceType, row scannable) error {
 	t.tags = config.Tags
 	t.checkEvery = config.CheckEvery
 
+	if config.Version != nil {
+		t.version = config.Version
+	}
+
 	if rcsID.Valid {
 		t.resourceConfigScopeID, err = strconv.Atoi(rcsID.String)
 		if err != nil {
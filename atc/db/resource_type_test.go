@@ -452,6 +452,25 @@ var _ = Describe("ResourceType", func() {
 			It("returns the version", func() {
 				Expect(scenario.ResourceType("some-type").Version()).To(Equal(atc.Version{"version": "2"}))
 			})
+
+			Context("when the config declares a version", func() {
+				BeforeEach(func() {
+					scenario.Run(builder.WithPipeline(atc.Config{
+						ResourceTypes: atc.ResourceTypes{
+							{
+								Name:    "some-type",
+								Type:    "some-base-resource-type",
+								Source:  atc.Source{"some": "repository"},
+								Version: atc.Version{"version": "1"},
+							},
+						},
+					}))
+				})
+
+				It("returns the declared version", func() {
+					Expect(scenario.ResourceType("some-type").Version()).To(Equal(atc.Version{"version": "1"}))
+				})
+			})
 		})
 	})
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -49,9 +50,13 @@ type Team interface {
 	) (Pipeline, bool, error)
 	RenamePipeline(oldName string, newName string) (bool, error)
 
+	EncryptedVarReference(value string) (string, error)
+	DecryptVarReference(reference string) (string, bool, error)
+
 	Pipeline(pipelineRef atc.PipelineRef) (Pipeline, bool, error)
 	Pipelines() ([]Pipeline, error)
 	PublicPipelines() ([]Pipeline, error)
+	JobsNamed(name string) (Jobs, error)
 	OrderPipelines([]string) error
 
 	CreateOneOffBuild() (Build, error)
@@ -414,6 +419,11 @@ func savePipeline(
 		return 0, false, err
 	}
 
+	configHash, err := ConfigHash(config)
+	if err != nil {
+		return 0, false, err
+	}
+
 	var pipelineID int
 	if !existingConfig {
 		values := map[string]interface{}{
@@ -429,6 +439,7 @@ func savePipeline(
 			"parent_job_id":   jobID,
 			"parent_build_id": buildID,
 			"instance_vars":   instanceVars,
+			"config_hash":     configHash,
 		}
 		var ordering sql.NullInt64
 		err := psql.Select("max(ordering)").
@@ -468,6 +479,7 @@ func savePipeline(
 			Set("last_updated", sq.Expr("now()")).
 			Set("parent_job_id", jobID).
 			Set("parent_build_id", buildID).
+			Set("config_hash", configHash).
 			Where(sq.And{
 				pipelineRefWhereClause,
 				sq.Eq{"version": from},
@@ -569,6 +581,10 @@ func (t *team) SavePipeline(
 	from ConfigVersion,
 	initiallyPaused bool,
 ) (Pipeline, bool, error) {
+	if atc.ReadOnlyMode {
+		return nil, false, ErrReadOnlyMode
+	}
+
 	tx, err := t.conn.Begin()
 	if err != nil {
 		return nil, false, err
@@ -603,6 +619,32 @@ func (t *team) SavePipeline(
 	return pipeline, isNewPipeline, nil
 }
 
+// EncryptedVarReference encrypts the value with the ATC's encryption key and
+// returns a var reference which resolves to the value when used in one of the
+// team's pipelines.
+func (t *team) EncryptedVarReference(value string) (string, error) {
+	return encryptedVarReference(t.conn.EncryptionStrategy(), t.id, value)
+}
+
+// DecryptVarReference returns the value of a reference returned by
+// EncryptedVarReference. It returns false if the string is not an encrypted
+// var reference.
+func (t *team) DecryptVarReference(reference string) (string, bool, error) {
+	prefix := "((" + EncryptedVarSourceName + ":"
+	if !strings.HasPrefix(reference, prefix) || !strings.HasSuffix(reference, "))") {
+		return "", false, nil
+	}
+
+	path := strings.TrimSuffix(strings.TrimPrefix(reference, prefix), "))")
+
+	value, err := decryptEncryptedVar(t.conn.EncryptionStrategy(), t.id, path)
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
 func (t *team) RenamePipeline(oldName, newName string) (bool, error) {
 	result, err := psql.Update("pipelines").
 		Set("name", newName).
@@ -676,6 +718,25 @@ func (t *team) Pipelines() ([]Pipeline, error) {
 	return pipelines, nil
 }
 
+// JobsNamed returns the active jobs with the given name across all of the
+// team's pipelines.
+func (t *team) JobsNamed(name string) (Jobs, error) {
+	rows, err := jobsQuery.
+		Where(sq.Eq{
+			"p.team_id": t.id,
+			"j.name":    name,
+			"j.active":  true,
+		}).
+		OrderBy("j.id ASC").
+		RunWith(t.conn).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanJobs(t.conn, t.lockFactory, rows)
+}
+
 func (t *team) PublicPipelines() ([]Pipeline, error) {
 	rows, err := pipelinesQuery.
 		Where(sq.Eq{
@@ -1305,8 +1366,9 @@ func scanPipeline(p *pipeline, scan scannable) error {
 		parentJobID   sql.NullInt64
 		parentBuildID sql.NullInt64
 		instanceVars  sql.NullString
+		configHash    sql.NullString
 	)
-	err := scan.Scan(&p.id, &p.name, &groups, &varSources, &display, &nonce, &p.configVersion, &p.teamID, &p.teamName, &p.paused, &p.public, &p.archived, &lastUpdated, &parentJobID, &parentBuildID, &instanceVars)
+	err := scan.Scan(&p.id, &p.name, &groups, &varSources, &display, &nonce, &p.configVersion, &p.teamID, &p.teamName, &p.paused, &p.public, &p.archived, &lastUpdated, &parentJobID, &parentBuildID, &instanceVars, &configHash, &p.frozen, &p.description)
 	if err != nil {
 		return err
 	}
@@ -1314,6 +1376,7 @@ func scanPipeline(p *pipeline, scan scannable) error {
 	p.lastUpdated = lastUpdated.Time
 	p.parentJobID = int(parentJobID.Int64)
 	p.parentBuildID = int(parentBuildID.Int64)
+	p.configHash = configHash.String
 
 	if groups.Valid {
 		var pipelineGroups atc.GroupConfigs
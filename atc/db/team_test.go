@@ -1005,6 +1005,52 @@ var _ = Describe("Team", func() {
 		})
 	})
 
+	Describe("JobsNamed", func() {
+		BeforeEach(func() {
+			_, _, err := team.SavePipeline(atc.PipelineRef{Name: "fake-pipeline"}, atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "job-name"},
+					{Name: "other-job-name"},
+				},
+			}, db.ConfigVersion(0), false)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = team.SavePipeline(atc.PipelineRef{Name: "fake-pipeline", InstanceVars: atc.InstanceVars{"branch": "feature/foo"}}, atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "job-name"},
+				},
+			}, db.ConfigVersion(0), false)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = otherTeam.SavePipeline(atc.PipelineRef{Name: "fake-pipeline"}, atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "job-name"},
+				},
+			}, db.ConfigVersion(0), false)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns the team's jobs with the name across all pipelines", func() {
+			jobs, err := team.JobsNamed("job-name")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(jobs).To(HaveLen(2))
+
+			Expect(jobs[0].Name()).To(Equal("job-name"))
+			Expect(jobs[0].PipelineRef()).To(Equal(atc.PipelineRef{Name: "fake-pipeline"}))
+			Expect(jobs[0].TeamID()).To(Equal(team.ID()))
+
+			Expect(jobs[1].Name()).To(Equal("job-name"))
+			Expect(jobs[1].PipelineRef()).To(Equal(atc.PipelineRef{Name: "fake-pipeline", InstanceVars: atc.InstanceVars{"branch": "feature/foo"}}))
+			Expect(jobs[1].TeamID()).To(Equal(team.ID()))
+		})
+
+		It("returns no jobs when none have the name", func() {
+			jobs, err := team.JobsNamed("missing-job")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(jobs).To(BeEmpty())
+		})
+	})
+
 	Describe("PublicPipelines", func() {
 		var (
 			pipelines []db.Pipeline
@@ -1817,6 +1863,34 @@ var _ = Describe("Team", func() {
 			Expect(created).To(BeTrue())
 		})
 
+		Context("when the ATC is in read-only mode", func() {
+			BeforeEach(func() {
+				atc.ReadOnlyMode = true
+			})
+
+			AfterEach(func() {
+				atc.ReadOnlyMode = false
+			})
+
+			It("does not save the pipeline", func() {
+				_, _, err := team.SavePipeline(pipelineRef, config, 0, false)
+				Expect(err).To(Equal(db.ErrReadOnlyMode))
+
+				_, found, err := team.Pipeline(pipelineRef)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		It("stores the config hash", func() {
+			pipeline, _, err := team.SavePipeline(pipelineRef, config, 0, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			expectedHash, err := db.ConfigHash(config)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pipeline.ConfigHash()).To(Equal(expectedHash))
+		})
+
 		It("caches the team id", func() {
 			_, _, err := team.SavePipeline(pipelineRef, config, 0, false)
 			Expect(err).ToNot(HaveOccurred())
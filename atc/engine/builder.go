@@ -25,6 +25,7 @@ type CoreStepFactory interface {
 	CheckStep(atc.Plan, exec.StepMetadata, db.ContainerMetadata, DelegateFactory) exec.Step
 	SetPipelineStep(atc.Plan, exec.StepMetadata, DelegateFactory) exec.Step
 	LoadVarStep(atc.Plan, exec.StepMetadata, DelegateFactory) exec.Step
+	ValidatePipelineStep(atc.Plan, exec.StepMetadata, DelegateFactory) exec.Step
 	ArtifactInputStep(atc.Plan, db.Build) exec.Step
 	ArtifactOutputStep(atc.Plan, db.Build) exec.Step
 }
@@ -140,6 +141,10 @@ func (factory *stepperFactory) buildStep(build db.Build, plan atc.Plan) exec.Ste
 		return factory.buildLoadVarStep(build, plan)
 	}
 
+	if plan.ValidatePipeline != nil {
+		return factory.buildValidatePipelineStep(build, plan)
+	}
+
 	if plan.Check != nil {
 		return factory.buildCheckStep(build, plan)
 	}
@@ -405,6 +410,21 @@ func (factory *stepperFactory) buildLoadVarStep(build db.Build, plan atc.Plan) e
 	)
 }
 
+func (factory *stepperFactory) buildValidatePipelineStep(build db.Build, plan atc.Plan) exec.Step {
+
+	stepMetadata := factory.stepMetadata(
+		build,
+		factory.externalURL,
+		false,
+	)
+
+	return factory.coreFactory.ValidatePipelineStep(
+		plan,
+		stepMetadata,
+		factory.buildDelegateFactory(build, plan),
+	)
+}
+
 func (factory *stepperFactory) buildArtifactInputStep(build db.Build, plan atc.Plan) exec.Step {
 	return factory.coreFactory.ArtifactInputStep(
 		plan,
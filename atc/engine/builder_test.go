@@ -589,6 +589,21 @@ var _ = Describe("Builder", func() {
 						})
 					})
 
+					Context("that contains a validate_pipeline step", func() {
+						BeforeEach(func() {
+							expectedPlan = planFactory.NewPlan(atc.ValidatePipelinePlan{
+								Name: "some-pipeline",
+								File: "some-input/pipeline.yml",
+							})
+						})
+
+						It("constructs validate_pipeline correctly", func() {
+							plan, stepMetadata, _ := fakeCoreStepFactory.ValidatePipelineStepArgsForCall(0)
+							Expect(plan).To(Equal(expectedPlan))
+							Expect(stepMetadata).To(Equal(expectedMetadataWithoutCreatedBy))
+						})
+					})
+
 					Context("that contains a check step", func() {
 						BeforeEach(func() {
 							expectedPlan = planFactory.NewPlan(atc.CheckPlan{
@@ -15,6 +15,7 @@ import (
 	"github.com/concourse/concourse/atc/event"
 	"github.com/concourse/concourse/atc/exec"
 	"github.com/concourse/concourse/atc/metric"
+	"github.com/concourse/concourse/atc/notifications"
 	"github.com/concourse/concourse/atc/util"
 	"github.com/concourse/concourse/tracing"
 )
@@ -37,6 +38,7 @@ func NewEngine(
 	stepperFactory StepperFactory,
 	secrets creds.Secrets,
 	varSourcePool creds.VarSourcePool,
+	notifier notifications.Notifier,
 ) Engine {
 	return &engine{
 		stepperFactory: stepperFactory,
@@ -46,6 +48,7 @@ func NewEngine(
 
 		globalSecrets: secrets,
 		varSourcePool: varSourcePool,
+		notifier:      notifier,
 	}
 }
 
@@ -57,6 +60,7 @@ type engine struct {
 
 	globalSecrets creds.Secrets
 	varSourcePool creds.VarSourcePool
+	notifier      notifications.Notifier
 }
 
 func (engine *engine) Drain(ctx context.Context) {
@@ -78,6 +82,7 @@ func (engine *engine) NewBuild(build db.Build) Runnable {
 		engine.stepperFactory,
 		engine.globalSecrets,
 		engine.varSourcePool,
+		engine.notifier,
 		engine.release,
 		engine.trackedStates,
 		engine.waitGroup,
@@ -89,6 +94,7 @@ func NewBuild(
 	builder StepperFactory,
 	globalSecrets creds.Secrets,
 	varSourcePool creds.VarSourcePool,
+	notifier notifications.Notifier,
 	release chan bool,
 	trackedStates *sync.Map,
 	waitGroup *sync.WaitGroup,
@@ -99,6 +105,7 @@ func NewBuild(
 
 		globalSecrets: globalSecrets,
 		varSourcePool: varSourcePool,
+		notifier:      notifier,
 
 		release:       release,
 		trackedStates: trackedStates,
@@ -112,6 +119,7 @@ type engineBuild struct {
 
 	globalSecrets creds.Secrets
 	varSourcePool creds.VarSourcePool
+	notifier      notifications.Notifier
 
 	release       chan bool
 	trackedStates *sync.Map
@@ -234,6 +242,21 @@ func (b *engineBuild) Run(ctx context.Context) {
 			return
 		}
 
+		if runErr == nil && succeeded {
+			err := state.FinalizeAll(lagerctx.NewContext(context.Background(), logger))
+			if err != nil {
+				logger.Error("failed-to-finalize-steps", err)
+				runErr = err
+			}
+		}
+
+		if runErr != nil || !succeeded {
+			err := state.AbortAll(lagerctx.NewContext(context.Background(), logger))
+			if err != nil {
+				logger.Error("failed-to-abort-steps", err)
+			}
+		}
+
 		b.finish(logger.Session("finish"), runErr, succeeded)
 	}
 }
@@ -273,6 +296,54 @@ func (b *engineBuild) finish(logger lager.Logger, err error, succeeded bool) {
 func (b *engineBuild) saveStatus(logger lager.Logger, status atc.BuildStatus) {
 	if err := b.build.Finish(db.BuildStatus(status)); err != nil {
 		logger.Error("failed-to-finish-build", err)
+		return
+	}
+
+	b.notify(logger, status)
+}
+
+// notify dispatches the notifications configured for the build's pipeline by
+// a set_pipeline step. The notifier is expected to send them in the
+// background; failing to notify a recipient does not affect the build.
+func (b *engineBuild) notify(logger lager.Logger, status atc.BuildStatus) {
+	if b.notifier == nil || b.build.JobID() == 0 {
+		return
+	}
+
+	pipeline, found, err := b.build.Pipeline()
+	if err != nil {
+		logger.Error("failed-to-find-pipeline", err)
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	config, found, err := pipeline.Notifications()
+	if err != nil {
+		logger.Error("failed-to-get-notifications", err)
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	notification := notifications.Notification{
+		TeamName:     b.build.TeamName(),
+		PipelineName: b.build.PipelineName(),
+		JobName:      b.build.JobName(),
+		BuildID:      b.build.ID(),
+		BuildName:    b.build.Name(),
+		Status:       status,
+	}
+
+	for _, recipient := range config.Recipients(status) {
+		err := b.notifier.Notify(context.Background(), recipient, notification)
+		if err != nil {
+			logger.Error("failed-to-notify", err, lager.Data{"recipient": recipient})
+		}
 	}
 }
 
@@ -20,6 +20,8 @@ import (
 	"github.com/concourse/concourse/atc/event"
 	"github.com/concourse/concourse/atc/exec"
 	"github.com/concourse/concourse/atc/exec/execfakes"
+	"github.com/concourse/concourse/atc/notifications"
+	"github.com/concourse/concourse/atc/notifications/notificationsfakes"
 	"github.com/concourse/concourse/vars"
 
 	. "github.com/onsi/ginkgo"
@@ -33,6 +35,7 @@ var _ = Describe("Engine", func() {
 
 		fakeGlobalCreds   *credsfakes.FakeSecrets
 		fakeVarSourcePool *credsfakes.FakeVarSourcePool
+		fakeBuildNotifier *notificationsfakes.FakeNotifier
 	)
 
 	BeforeEach(func() {
@@ -43,6 +46,7 @@ var _ = Describe("Engine", func() {
 
 		fakeGlobalCreds = new(credsfakes.FakeSecrets)
 		fakeVarSourcePool = new(credsfakes.FakeVarSourcePool)
+		fakeBuildNotifier = new(notificationsfakes.FakeNotifier)
 	})
 
 	Describe("NewBuild", func() {
@@ -52,7 +56,7 @@ var _ = Describe("Engine", func() {
 		)
 
 		BeforeEach(func() {
-			engine = NewEngine(fakeStepperFactory, fakeGlobalCreds, fakeVarSourcePool)
+			engine = NewEngine(fakeStepperFactory, fakeGlobalCreds, fakeVarSourcePool, fakeBuildNotifier)
 		})
 
 		JustBeforeEach(func() {
@@ -82,6 +86,7 @@ var _ = Describe("Engine", func() {
 				fakeStepperFactory,
 				fakeGlobalCreds,
 				fakeVarSourcePool,
+				fakeBuildNotifier,
 				release,
 				trackedStates,
 				waitGroup,
@@ -240,6 +245,52 @@ var _ = Describe("Engine", func() {
 										fakeStep.RunReturns(true, nil)
 									})
 
+									Context("when a step registered an abortable", func() {
+										var fakeAbortable *execfakes.FakeAbortable
+
+										BeforeEach(func() {
+											fakeAbortable = new(execfakes.FakeAbortable)
+											fakeStep.RunStub = func(ctx context.Context, state exec.RunState) (bool, error) {
+												state.RegisterAbortable(fakeAbortable)
+												return true, nil
+											}
+										})
+
+										It("does not abort it", func() {
+											waitGroup.Wait()
+											Expect(fakeAbortable.AbortCallCount()).To(Equal(0))
+										})
+									})
+
+									Context("when a step registered a finalizer", func() {
+										var fakeFinalizer *execfakes.FakeFinalizer
+
+										BeforeEach(func() {
+											fakeFinalizer = new(execfakes.FakeFinalizer)
+											fakeStep.RunStub = func(ctx context.Context, state exec.RunState) (bool, error) {
+												state.RegisterFinalizer(fakeFinalizer)
+												return true, nil
+											}
+										})
+
+										It("finalizes it", func() {
+											waitGroup.Wait()
+											Expect(fakeFinalizer.FinalizeCallCount()).To(Equal(1))
+										})
+
+										Context("when finalizing fails", func() {
+											BeforeEach(func() {
+												fakeFinalizer.FinalizeReturns(errors.New("nope"))
+											})
+
+											It("errors the build", func() {
+												waitGroup.Wait()
+												Expect(fakeBuild.FinishCallCount()).To(Equal(1))
+												Expect(fakeBuild.FinishArgsForCall(0)).To(Equal(db.BuildStatusErrored))
+											})
+										})
+									})
+
 									It("finishes the build", func() {
 										waitGroup.Wait()
 										Expect(fakeBuild.FinishCallCount()).To(Equal(1))
@@ -257,6 +308,84 @@ var _ = Describe("Engine", func() {
 										Expect(fakeBuild.FinishCallCount()).To(Equal(1))
 										Expect(fakeBuild.FinishArgsForCall(0)).To(Equal(db.BuildStatusFailed))
 									})
+
+									It("does not notify anyone for one-off builds", func() {
+										waitGroup.Wait()
+										Expect(fakeBuild.PipelineCallCount()).To(Equal(0))
+										Expect(fakeBuildNotifier.NotifyCallCount()).To(Equal(0))
+									})
+
+									Context("when the build's pipeline has notifications", func() {
+										BeforeEach(func() {
+											fakeBuild.JobIDReturns(12)
+											fakeBuild.JobNameReturns("some-job")
+											fakeBuild.NameReturns("3")
+											fakeBuild.TeamNameReturns("some-team")
+											fakeBuild.PipelineNameReturns("some-pipeline")
+
+											fakePipeline := new(dbfakes.FakePipeline)
+											fakePipeline.NotificationsReturns(atc.PipelineNotifications{
+												OnFailure: []string{"email:ops@example.com", "webhook:https://example.com/hook"},
+												OnSuccess: []string{"email:dev@example.com"},
+											}, true, nil)
+											fakeBuild.PipelineReturns(fakePipeline, true, nil)
+
+											fakeBuildNotifier.NotifyReturnsOnCall(0, errors.New("smtp down"))
+										})
+
+										It("notifies every on_failure recipient", func() {
+											waitGroup.Wait()
+											Expect(fakeBuildNotifier.NotifyCallCount()).To(Equal(2))
+
+											_, recipient, notification := fakeBuildNotifier.NotifyArgsForCall(0)
+											Expect(recipient).To(Equal("email:ops@example.com"))
+											Expect(notification).To(Equal(notifications.Notification{
+												TeamName:     "some-team",
+												PipelineName: "some-pipeline",
+												JobName:      "some-job",
+												BuildID:      128,
+												BuildName:    "3",
+												Status:       atc.StatusFailed,
+											}))
+
+											_, recipient, _ = fakeBuildNotifier.NotifyArgsForCall(1)
+											Expect(recipient).To(Equal("webhook:https://example.com/hook"))
+										})
+									})
+
+									Context("when a step registered an abortable", func() {
+										var fakeAbortable *execfakes.FakeAbortable
+
+										BeforeEach(func() {
+											fakeAbortable = new(execfakes.FakeAbortable)
+											fakeStep.RunStub = func(ctx context.Context, state exec.RunState) (bool, error) {
+												state.RegisterAbortable(fakeAbortable)
+												return false, nil
+											}
+										})
+
+										It("aborts it", func() {
+											waitGroup.Wait()
+											Expect(fakeAbortable.AbortCallCount()).To(Equal(1))
+										})
+									})
+
+									Context("when a step registered a finalizer", func() {
+										var fakeFinalizer *execfakes.FakeFinalizer
+
+										BeforeEach(func() {
+											fakeFinalizer = new(execfakes.FakeFinalizer)
+											fakeStep.RunStub = func(ctx context.Context, state exec.RunState) (bool, error) {
+												state.RegisterFinalizer(fakeFinalizer)
+												return false, nil
+											}
+										})
+
+										It("does not finalize it", func() {
+											waitGroup.Wait()
+											Expect(fakeFinalizer.FinalizeCallCount()).To(Equal(0))
+										})
+									})
 								})
 
 								Context("when the build finishes with error", func() {
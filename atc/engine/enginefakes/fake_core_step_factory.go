@@ -117,6 +117,19 @@ type FakeCoreStepFactory struct {
 	taskStepReturnsOnCall map[int]struct {
 		result1 exec.Step
 	}
+	ValidatePipelineStepStub        func(atc.Plan, exec.StepMetadata, engine.DelegateFactory) exec.Step
+	validatePipelineStepMutex       sync.RWMutex
+	validatePipelineStepArgsForCall []struct {
+		arg1 atc.Plan
+		arg2 exec.StepMetadata
+		arg3 engine.DelegateFactory
+	}
+	validatePipelineStepReturns struct {
+		result1 exec.Step
+	}
+	validatePipelineStepReturnsOnCall map[int]struct {
+		result1 exec.Step
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -627,6 +640,69 @@ func (fake *FakeCoreStepFactory) TaskStepReturnsOnCall(i int, result1 exec.Step)
 	}{result1}
 }
 
+func (fake *FakeCoreStepFactory) ValidatePipelineStep(arg1 atc.Plan, arg2 exec.StepMetadata, arg3 engine.DelegateFactory) exec.Step {
+	fake.validatePipelineStepMutex.Lock()
+	ret, specificReturn := fake.validatePipelineStepReturnsOnCall[len(fake.validatePipelineStepArgsForCall)]
+	fake.validatePipelineStepArgsForCall = append(fake.validatePipelineStepArgsForCall, struct {
+		arg1 atc.Plan
+		arg2 exec.StepMetadata
+		arg3 engine.DelegateFactory
+	}{arg1, arg2, arg3})
+	stub := fake.ValidatePipelineStepStub
+	fakeReturns := fake.validatePipelineStepReturns
+	fake.recordInvocation("ValidatePipelineStep", []interface{}{arg1, arg2, arg3})
+	fake.validatePipelineStepMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCoreStepFactory) ValidatePipelineStepCallCount() int {
+	fake.validatePipelineStepMutex.RLock()
+	defer fake.validatePipelineStepMutex.RUnlock()
+	return len(fake.validatePipelineStepArgsForCall)
+}
+
+func (fake *FakeCoreStepFactory) ValidatePipelineStepCalls(stub func(atc.Plan, exec.StepMetadata, engine.DelegateFactory) exec.Step) {
+	fake.validatePipelineStepMutex.Lock()
+	defer fake.validatePipelineStepMutex.Unlock()
+	fake.ValidatePipelineStepStub = stub
+}
+
+func (fake *FakeCoreStepFactory) ValidatePipelineStepArgsForCall(i int) (atc.Plan, exec.StepMetadata, engine.DelegateFactory) {
+	fake.validatePipelineStepMutex.RLock()
+	defer fake.validatePipelineStepMutex.RUnlock()
+	argsForCall := fake.validatePipelineStepArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCoreStepFactory) ValidatePipelineStepReturns(result1 exec.Step) {
+	fake.validatePipelineStepMutex.Lock()
+	defer fake.validatePipelineStepMutex.Unlock()
+	fake.ValidatePipelineStepStub = nil
+	fake.validatePipelineStepReturns = struct {
+		result1 exec.Step
+	}{result1}
+}
+
+func (fake *FakeCoreStepFactory) ValidatePipelineStepReturnsOnCall(i int, result1 exec.Step) {
+	fake.validatePipelineStepMutex.Lock()
+	defer fake.validatePipelineStepMutex.Unlock()
+	fake.ValidatePipelineStepStub = nil
+	if fake.validatePipelineStepReturnsOnCall == nil {
+		fake.validatePipelineStepReturnsOnCall = make(map[int]struct {
+			result1 exec.Step
+		})
+	}
+	fake.validatePipelineStepReturnsOnCall[i] = struct {
+		result1 exec.Step
+	}{result1}
+}
+
 func (fake *FakeCoreStepFactory) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -646,6 +722,8 @@ func (fake *FakeCoreStepFactory) Invocations() map[string][][]interface{} {
 	defer fake.setPipelineStepMutex.RUnlock()
 	fake.taskStepMutex.RLock()
 	defer fake.taskStepMutex.RUnlock()
+	fake.validatePipelineStepMutex.RLock()
+	defer fake.validatePipelineStepMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
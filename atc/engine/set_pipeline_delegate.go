@@ -7,6 +7,8 @@ import (
 	"github.com/concourse/concourse/atc/db"
 	"github.com/concourse/concourse/atc/event"
 	"github.com/concourse/concourse/atc/exec"
+	"github.com/concourse/concourse/atc/policy"
+	"github.com/concourse/concourse/atc/worker"
 )
 
 func NewSetPipelineStepDelegate(
@@ -14,15 +16,19 @@ func NewSetPipelineStepDelegate(
 	planID atc.PlanID,
 	state exec.RunState,
 	clock clock.Clock,
+	policyChecker policy.Checker,
+	artifactSourcer worker.ArtifactSourcer,
 ) *setPipelineStepDelegate {
 	return &setPipelineStepDelegate{
 		buildStepDelegate{
-			build:  build,
-			planID: planID,
-			clock:  clock,
-			state:  state,
-			stdout: nil,
-			stderr: nil,
+			build:           build,
+			planID:          planID,
+			clock:           clock,
+			state:           state,
+			stdout:          nil,
+			stderr:          nil,
+			policyChecker:   policyChecker,
+			artifactSourcer: artifactSourcer,
 		},
 	}
 }
@@ -45,3 +51,48 @@ func (delegate *setPipelineStepDelegate) SetPipelineChanged(logger lager.Logger,
 
 	logger.Debug("set pipeline changed")
 }
+
+func (delegate *setPipelineStepDelegate) SetPipelineProgress(logger lager.Logger, message string) {
+	err := delegate.build.SaveEvent(event.SetPipelineProgress{
+		Origin: event.Origin{
+			ID: event.OriginID(delegate.planID),
+		},
+		Time:    delegate.clock.Now().Unix(),
+		Message: message,
+	})
+	if err != nil {
+		logger.Error("failed-to-save-set-pipeline-progress-event", err)
+		return
+	}
+
+	logger.Debug("set pipeline progress", lager.Data{"message": message})
+}
+
+func (delegate *setPipelineStepDelegate) VarFileFetched(logger lager.Logger, fetch exec.VarFileFetch) {
+	err := delegate.build.SaveEvent(event.VarFileFetched{
+		Origin: event.Origin{
+			ID: event.OriginID(delegate.planID),
+		},
+		Time:       delegate.clock.Now().Unix(),
+		Path:       fetch.Path,
+		DigestHex:  fetch.DigestHex,
+		SizeBytes:  fetch.SizeBytes,
+		DurationMs: fetch.Duration.Milliseconds(),
+	})
+	if err != nil {
+		logger.Error("failed-to-save-var-file-fetched-event", err)
+		return
+	}
+
+	logger.Debug("var file fetched", lager.Data{"path": fetch.Path, "digest": fetch.DigestHex})
+}
+
+func (delegate *setPipelineStepDelegate) SetBuildAnnotation(logger lager.Logger, key string, value string) {
+	err := delegate.build.SetAnnotation(key, value)
+	if err != nil {
+		logger.Error("failed-to-set-build-annotation", err, lager.Data{"key": key})
+		return
+	}
+
+	logger.Debug("set build annotation", lager.Data{"key": key, "value": value})
+}
@@ -12,14 +12,18 @@ import (
 	"github.com/concourse/concourse/atc/engine"
 	"github.com/concourse/concourse/atc/event"
 	"github.com/concourse/concourse/atc/exec"
+	"github.com/concourse/concourse/atc/policy/policyfakes"
+	"github.com/concourse/concourse/atc/worker/workerfakes"
 	"github.com/concourse/concourse/vars"
 )
 
 var _ = Describe("SetPipelineStepDelegate", func() {
 	var (
-		logger    *lagertest.TestLogger
-		fakeBuild *dbfakes.FakeBuild
-		fakeClock *fakeclock.FakeClock
+		logger              *lagertest.TestLogger
+		fakeBuild           *dbfakes.FakeBuild
+		fakeClock           *fakeclock.FakeClock
+		fakePolicyChecker   *policyfakes.FakeChecker
+		fakeArtifactSourcer *workerfakes.FakeArtifactSourcer
 
 		state exec.RunState
 
@@ -32,13 +36,15 @@ var _ = Describe("SetPipelineStepDelegate", func() {
 
 		fakeBuild = new(dbfakes.FakeBuild)
 		fakeClock = fakeclock.NewFakeClock(now)
+		fakePolicyChecker = new(policyfakes.FakeChecker)
+		fakeArtifactSourcer = new(workerfakes.FakeArtifactSourcer)
 		credVars := vars.StaticVariables{
 			"source-param": "super-secret-source",
 			"git-key":      "{\n123\n456\n789\n}\n",
 		}
 		state = exec.NewRunState(noopStepper, credVars, true)
 
-		delegate = engine.NewSetPipelineStepDelegate(fakeBuild, "some-plan-id", state, fakeClock)
+		delegate = engine.NewSetPipelineStepDelegate(fakeBuild, "some-plan-id", state, fakeClock, fakePolicyChecker, fakeArtifactSourcer)
 	})
 
 	Describe("SetPipelineChanged", func() {
@@ -54,4 +60,54 @@ var _ = Describe("SetPipelineStepDelegate", func() {
 			}))
 		})
 	})
+	Describe("SetPipelineProgress", func() {
+		JustBeforeEach(func() {
+			delegate.SetPipelineProgress(logger, "validating config")
+		})
+
+		It("saves an event", func() {
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveEventArgsForCall(0)).To(Equal(event.SetPipelineProgress{
+				Origin:  event.Origin{ID: event.OriginID("some-plan-id")},
+				Time:    now.Unix(),
+				Message: "validating config",
+			}))
+		})
+	})
+
+	Describe("VarFileFetched", func() {
+		JustBeforeEach(func() {
+			delegate.VarFileFetched(logger, exec.VarFileFetch{
+				Path:      "some-resource/vars.yml",
+				DigestHex: "some-digest",
+				SizeBytes: 512,
+				Duration:  1500 * time.Millisecond,
+			})
+		})
+
+		It("saves an event", func() {
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveEventArgsForCall(0)).To(Equal(event.VarFileFetched{
+				Origin:     event.Origin{ID: event.OriginID("some-plan-id")},
+				Time:       now.Unix(),
+				Path:       "some-resource/vars.yml",
+				DigestHex:  "some-digest",
+				SizeBytes:  512,
+				DurationMs: 1500,
+			}))
+		})
+	})
+
+	Describe("SetBuildAnnotation", func() {
+		JustBeforeEach(func() {
+			delegate.SetBuildAnnotation(logger, "set_pipeline_version", "3")
+		})
+
+		It("sets the annotation on the build", func() {
+			Expect(fakeBuild.SetAnnotationCallCount()).To(Equal(1))
+			key, value := fakeBuild.SetAnnotationArgsForCall(0)
+			Expect(key).To(Equal("set_pipeline_version"))
+			Expect(value).To(Equal("3"))
+		})
+	})
 })
@@ -8,6 +8,7 @@ import (
 
 	"github.com/concourse/concourse/atc"
 	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/atc/eventbus"
 	"github.com/concourse/concourse/atc/exec"
 	"github.com/concourse/concourse/atc/resource"
 	"github.com/concourse/concourse/atc/worker"
@@ -25,6 +26,13 @@ type coreStepFactory struct {
 	defaultLimits         atc.ContainerLimits
 	strategy              worker.ContainerPlacementStrategy
 	defaultCheckTimeout   time.Duration
+	maxVarFiles           int
+	setPipelineFileCache  *exec.SetPipelineFileCache
+	setPipelineLimiter    *exec.SetPipelineLimiter
+	configMapFetcher      exec.ConfigMapFetcher
+	vaultSecretReader     exec.VaultSecretReader
+	eventPublisher        eventbus.Publisher
+	credentialInvalidator exec.CredentialCacheInvalidator
 }
 
 func NewCoreStepFactory(
@@ -39,6 +47,13 @@ func NewCoreStepFactory(
 	defaultLimits atc.ContainerLimits,
 	strategy worker.ContainerPlacementStrategy,
 	defaultCheckTimeout time.Duration,
+	maxVarFiles int,
+	setPipelineFileCache *exec.SetPipelineFileCache,
+	setPipelineLimiter *exec.SetPipelineLimiter,
+	configMapFetcher exec.ConfigMapFetcher,
+	vaultSecretReader exec.VaultSecretReader,
+	eventPublisher eventbus.Publisher,
+	credentialInvalidator exec.CredentialCacheInvalidator,
 ) CoreStepFactory {
 	return &coreStepFactory{
 		pool:                  pool,
@@ -52,6 +67,13 @@ func NewCoreStepFactory(
 		defaultLimits:         defaultLimits,
 		strategy:              strategy,
 		defaultCheckTimeout:   defaultCheckTimeout,
+		maxVarFiles:           maxVarFiles,
+		setPipelineFileCache:  setPipelineFileCache,
+		setPipelineLimiter:    setPipelineLimiter,
+		configMapFetcher:      configMapFetcher,
+		vaultSecretReader:     vaultSecretReader,
+		eventPublisher:        eventPublisher,
+		credentialInvalidator: credentialInvalidator,
 	}
 }
 
@@ -181,6 +203,15 @@ func (factory *coreStepFactory) SetPipelineStep(
 		factory.buildFactory,
 		factory.artifactStreamer,
 		delegateFactory.policyChecker,
+		factory.maxVarFiles,
+		factory.setPipelineFileCache,
+		factory.setPipelineLimiter,
+		factory.configMapFetcher,
+		exec.NewWorkerPipelineLinter(factory.pool, factory.strategy),
+		exec.NewWorkerArtifactFileWriter(factory.pool),
+		factory.vaultSecretReader,
+		factory.eventPublisher,
+		factory.credentialInvalidator,
 	)
 
 	spStep = exec.LogError(spStep, delegateFactory)
@@ -210,6 +241,29 @@ func (factory *coreStepFactory) LoadVarStep(
 	return loadVarStep
 }
 
+func (factory *coreStepFactory) ValidatePipelineStep(
+	plan atc.Plan,
+	stepMetadata exec.StepMetadata,
+	delegateFactory DelegateFactory,
+) exec.Step {
+	vpStep := exec.NewValidatePipelineStep(
+		plan.ID,
+		*plan.ValidatePipeline,
+		stepMetadata,
+		delegateFactory,
+		factory.artifactStreamer,
+		factory.maxVarFiles,
+		factory.setPipelineFileCache,
+		factory.configMapFetcher,
+	)
+
+	vpStep = exec.LogError(vpStep, delegateFactory)
+	if atc.EnableBuildRerunWhenWorkerDisappears {
+		vpStep = exec.RetryError(vpStep, delegateFactory)
+	}
+	return vpStep
+}
+
 func (factory *coreStepFactory) ArtifactInputStep(
 	plan atc.Plan,
 	build db.Build,
@@ -2,6 +2,7 @@ package atc
 
 import (
 	"fmt"
+	"strings"
 )
 
 type MalformedConfigError struct {
@@ -24,3 +25,11 @@ func (err MalformedStepError) Error() string {
 func (err MalformedStepError) Unwrap() error {
 	return err.Err
 }
+
+type DuplicateStepNameError struct {
+	Names []string
+}
+
+func (err DuplicateStepNameError) Error() string {
+	return fmt.Sprintf("duplicate step names: %s", strings.Join(err.Names, ", "))
+}
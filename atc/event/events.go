@@ -190,6 +190,27 @@ type SetPipelineChanged struct {
 func (SetPipelineChanged) EventType() atc.EventType  { return EventTypeSetPipelineChanged }
 func (SetPipelineChanged) Version() atc.EventVersion { return "1.0" }
 
+type SetPipelineProgress struct {
+	Origin  Origin `json:"origin"`
+	Time    int64  `json:"time"`
+	Message string `json:"message"`
+}
+
+func (SetPipelineProgress) EventType() atc.EventType  { return EventTypeSetPipelineProgress }
+func (SetPipelineProgress) Version() atc.EventVersion { return "1.0" }
+
+type VarFileFetched struct {
+	Origin     Origin `json:"origin"`
+	Time       int64  `json:"time"`
+	Path       string `json:"path"`
+	DigestHex  string `json:"digest_hex"`
+	SizeBytes  int64  `json:"size_bytes"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func (VarFileFetched) EventType() atc.EventType  { return EventTypeVarFileFetched }
+func (VarFileFetched) Version() atc.EventVersion { return "1.0" }
+
 type Initialize struct {
 	Origin Origin `json:"origin"`
 	Time   int64  `json:"time,omitempty"`
@@ -44,6 +44,8 @@ func init() {
 	RegisterEvent(StartPut{})
 	RegisterEvent(FinishPut{})
 	RegisterEvent(SetPipelineChanged{})
+	RegisterEvent(SetPipelineProgress{})
+	RegisterEvent(VarFileFetched{})
 	RegisterEvent(Status{})
 	RegisterEvent(SelectedWorker{})
 	RegisterEvent(Log{})
@@ -39,7 +39,11 @@ const (
 	// finished putting something
 	EventTypeFinishPut atc.EventType = "finish-put"
 
-	EventTypeSetPipelineChanged atc.EventType = "set-pipeline-changed"
+	EventTypeSetPipelineChanged  atc.EventType = "set-pipeline-changed"
+	EventTypeSetPipelineProgress atc.EventType = "set-pipeline-progress"
+
+	// fetched a var file for a set_pipeline step
+	EventTypeVarFileFetched atc.EventType = "var-file-fetched"
 
 	// initialize step
 	EventTypeInitialize atc.EventType = "initialize"
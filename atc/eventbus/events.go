@@ -0,0 +1,14 @@
+package eventbus
+
+import "github.com/concourse/concourse/atc"
+
+// PipelineUpdated is the data of a concourse.pipeline.updated event.
+type PipelineUpdated struct {
+	Team          string                 `json:"team"`
+	Pipeline      string                 `json:"pipeline"`
+	InstanceVars  atc.InstanceVars       `json:"instance_vars,omitempty"`
+	ConfigVersion int                    `json:"config_version"`
+	Created       bool                   `json:"created"`
+	Diff          atc.DiffConfigResponse `json:"diff"`
+	BuildID       int                    `json:"build_id"`
+}
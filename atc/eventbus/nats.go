@@ -0,0 +1,108 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// natsTimeout bounds how long publishing to NATS may take when the context
+// has no deadline.
+const natsTimeout = 10 * time.Second
+
+type natsConnectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+}
+
+// natsTransport publishes each event over its own connection using the NATS
+// client protocol, waiting for the server to acknowledge a PING so that
+// errors, e.g. authorization failures, are reported.
+type natsTransport struct {
+	url *url.URL
+}
+
+func (t natsTransport) send(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	subject := strings.Trim(t.url.Path, "/")
+	if subject == "" {
+		subject = event.Type
+	}
+
+	host := t.url.Host
+	if t.url.Port() == "" {
+		host = net.JoinHostPort(t.url.Hostname(), "4222")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(natsTimeout)
+	}
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+
+	info, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("read nats info: %w", err)
+	}
+
+	if !strings.HasPrefix(info, "INFO ") {
+		return fmt.Errorf("unexpected nats greeting: %s", strings.TrimSpace(info))
+	}
+
+	options := natsConnectOptions{Name: "concourse"}
+	if t.url.User != nil {
+		options.User = t.url.User.Username()
+		options.Pass, _ = t.url.User.Password()
+	}
+
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(conn, "CONNECT %s\r\nPUB %s %d\r\n%s\r\nPING\r\n", connect, subject, len(payload), payload)
+	if err != nil {
+		return err
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("read nats response: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+// TypePipelineUpdated is the type of the event published when a set_pipeline
+// step saves a pipeline.
+const TypePipelineUpdated = "concourse.pipeline.updated"
+
+// Event is a CloudEvents 1.0 event in its structured JSON format.
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+//go:generate counterfeiter . Publisher
+
+// Publisher publishes events to a message broker.
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{}) error
+}
+
+// NewPublisher returns a Publisher for the broker at the URL. http:// and
+// https:// URLs are posted to, while nats:// URLs publish to the subject given
+// by the URL's path, or to the event type if it has none. Events are sourced
+// from the external URL.
+func NewPublisher(brokerURL *url.URL, externalURL string, httpClient *http.Client) (Publisher, error) {
+	var transport transport
+	switch brokerURL.Scheme {
+	case "http", "https":
+		transport = httpTransport{url: brokerURL.String(), client: httpClient}
+	case "nats":
+		transport = natsTransport{url: brokerURL}
+	default:
+		return nil, fmt.Errorf("unsupported event bus scheme: %s", brokerURL.Scheme)
+	}
+
+	return publisher{
+		source:    externalURL,
+		transport: transport,
+	}, nil
+}
+
+type transport interface {
+	send(ctx context.Context, event Event) error
+}
+
+type publisher struct {
+	source    string
+	transport transport
+}
+
+func (p publisher) Publish(ctx context.Context, eventType string, data interface{}) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	return p.transport.send(ctx, Event{
+		SpecVersion:     "1.0",
+		ID:              id.String(),
+		Source:          p.source,
+		Type:            eventType,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	})
+}
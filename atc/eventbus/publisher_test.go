@@ -0,0 +1,183 @@
+package eventbus_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/concourse/concourse/atc/eventbus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Publisher", func() {
+	var data = map[string]interface{}{"pipeline": "some-pipeline"}
+
+	Describe("publishing over http", func() {
+		var (
+			server      *httptest.Server
+			status      int
+			contentType string
+			received    []byte
+			publisher   eventbus.Publisher
+		)
+
+		BeforeEach(func() {
+			status = http.StatusAccepted
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				received, _ = ioutil.ReadAll(r.Body)
+				w.WriteHeader(status)
+			}))
+
+			brokerURL, err := url.Parse(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			publisher, err = eventbus.NewPublisher(brokerURL, "https://ci.example.com", server.Client())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("posts a structured cloudevent", func() {
+			Expect(publisher.Publish(context.Background(), eventbus.TypePipelineUpdated, data)).To(Succeed())
+			Expect(contentType).To(Equal("application/cloudevents+json"))
+
+			var event eventbus.Event
+			Expect(json.Unmarshal(received, &event)).To(Succeed())
+			Expect(event.SpecVersion).To(Equal("1.0"))
+			Expect(event.ID).ToNot(BeEmpty())
+			Expect(event.Source).To(Equal("https://ci.example.com"))
+			Expect(event.Type).To(Equal("concourse.pipeline.updated"))
+			Expect(event.DataContentType).To(Equal("application/json"))
+			Expect(event.Data).To(Equal(data))
+		})
+
+		It("returns an error when the endpoint fails", func() {
+			status = http.StatusBadGateway
+			Expect(publisher.Publish(context.Background(), eventbus.TypePipelineUpdated, data)).To(MatchError("unexpected response from event bus: 502 Bad Gateway"))
+		})
+	})
+
+	Describe("publishing over nats", func() {
+		var (
+			listener  net.Listener
+			reply     string
+			published chan string
+		)
+
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+
+			reply = "PONG"
+			published = make(chan string, 1)
+		})
+
+		AfterEach(func() {
+			listener.Close()
+		})
+
+		publish := func(path string) error {
+			serverReply := reply
+
+			go func() {
+				defer GinkgoRecover()
+
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				defer conn.Close()
+
+				fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+
+				reader := bufio.NewReader(conn)
+
+				var lines []string
+				for {
+					line, err := reader.ReadString('\n')
+					if err == io.EOF {
+						return
+					}
+					Expect(err).ToNot(HaveOccurred())
+
+					line = strings.TrimSpace(line)
+					if line == "PING" {
+						break
+					}
+
+					lines = append(lines, line)
+				}
+
+				fmt.Fprintf(conn, "%s\r\n", serverReply)
+				published <- strings.Join(lines, "\n")
+			}()
+
+			brokerURL, err := url.Parse("nats://user:pass@" + listener.Addr().String() + path)
+			Expect(err).ToNot(HaveOccurred())
+
+			publisher, err := eventbus.NewPublisher(brokerURL, "https://ci.example.com", http.DefaultClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			return publisher.Publish(context.Background(), eventbus.TypePipelineUpdated, data)
+		}
+
+		It("publishes the event to the subject of the url", func() {
+			Expect(publish("/some.subject")).To(Succeed())
+
+			var lines []string
+			Eventually(published).Should(Receive(WithTransform(func(s string) []string {
+				lines = strings.Split(s, "\n")
+				return lines
+			}, HaveLen(3))))
+
+			Expect(lines[0]).To(HavePrefix("CONNECT "))
+
+			var options map[string]interface{}
+			Expect(json.Unmarshal([]byte(strings.TrimPrefix(lines[0], "CONNECT ")), &options)).To(Succeed())
+			Expect(options["user"]).To(Equal("user"))
+			Expect(options["pass"]).To(Equal("pass"))
+
+			Expect(lines[1]).To(Equal(fmt.Sprintf("PUB some.subject %d", len(lines[2]))))
+
+			var event eventbus.Event
+			Expect(json.Unmarshal([]byte(lines[2]), &event)).To(Succeed())
+			Expect(event.Type).To(Equal("concourse.pipeline.updated"))
+		})
+
+		It("defaults the subject to the event type", func() {
+			Expect(publish("")).To(Succeed())
+
+			var message string
+			Eventually(published).Should(Receive(&message))
+			Expect(strings.Split(message, "\n")[1]).To(HavePrefix("PUB concourse.pipeline.updated "))
+		})
+
+		It("returns errors reported by the server", func() {
+			reply = "-ERR 'Authorization Violation'"
+			Expect(publish("")).To(MatchError("nats: 'Authorization Violation'"))
+		})
+	})
+
+	It("rejects unsupported schemes", func() {
+		brokerURL, err := url.Parse("amqp://localhost")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = eventbus.NewPublisher(brokerURL, "https://ci.example.com", http.DefaultClient)
+		Expect(err).To(MatchError("unsupported event bus scheme: amqp"))
+	})
+})
@@ -46,4 +46,7 @@ type SetPipelineStepDelegateFactory interface {
 type SetPipelineStepDelegate interface {
 	BuildStepDelegate
 	SetPipelineChanged(lager.Logger, bool)
+	SetPipelineProgress(lager.Logger, string)
+	VarFileFetched(lager.Logger, VarFileFetch)
+	SetBuildAnnotation(lager.Logger, string, string)
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/concourse/concourse/atc"
 	"github.com/concourse/concourse/atc/exec"
+	"github.com/concourse/concourse/atc/exec/execfakes"
 	"github.com/concourse/concourse/atc/policy"
 	"github.com/concourse/concourse/atc/policy/policyfakes"
 	"github.com/concourse/concourse/atc/util"
@@ -29,6 +30,8 @@ var (
 	testLogger = lagertest.NewTestLogger("test")
 
 	fakePolicyAgentFactory *policyfakes.FakeAgentFactory
+
+	fakeSetPipelineHook *execfakes.FakeSetPipelineHook
 )
 
 var _ = BeforeSuite(func() {
@@ -38,6 +41,9 @@ var _ = BeforeSuite(func() {
 
 	policy.RegisterAgent(fakePolicyAgentFactory)
 
+	fakeSetPipelineHook = new(execfakes.FakeSetPipelineHook)
+	exec.RegisterSetPipelineHook(fakeSetPipelineHook)
+
 	atc.EnablePipelineInstances = true
 })
 
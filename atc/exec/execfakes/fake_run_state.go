@@ -12,6 +12,17 @@ import (
 )
 
 type FakeRunState struct {
+	AbortAllStub        func(context.Context) error
+	abortAllMutex       sync.RWMutex
+	abortAllArgsForCall []struct {
+		arg1 context.Context
+	}
+	abortAllReturns struct {
+		result1 error
+	}
+	abortAllReturnsOnCall map[int]struct {
+		result1 error
+	}
 	AddLocalVarStub        func(string, interface{}, bool)
 	addLocalVarMutex       sync.RWMutex
 	addLocalVarArgsForCall []struct {
@@ -29,6 +40,17 @@ type FakeRunState struct {
 	artifactRepositoryReturnsOnCall map[int]struct {
 		result1 *build.Repository
 	}
+	FinalizeAllStub        func(context.Context) error
+	finalizeAllMutex       sync.RWMutex
+	finalizeAllArgsForCall []struct {
+		arg1 context.Context
+	}
+	finalizeAllReturns struct {
+		result1 error
+	}
+	finalizeAllReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetStub        func(vars.Reference) (interface{}, bool, error)
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
@@ -91,6 +113,16 @@ type FakeRunState struct {
 	redactionEnabledReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	RegisterAbortableStub        func(exec.Abortable)
+	registerAbortableMutex       sync.RWMutex
+	registerAbortableArgsForCall []struct {
+		arg1 exec.Abortable
+	}
+	RegisterFinalizerStub        func(exec.Finalizer)
+	registerFinalizerMutex       sync.RWMutex
+	registerFinalizerArgsForCall []struct {
+		arg1 exec.Finalizer
+	}
 	ResultStub        func(atc.PlanID, interface{}) bool
 	resultMutex       sync.RWMutex
 	resultArgsForCall []struct {
@@ -127,6 +159,67 @@ type FakeRunState struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeRunState) AbortAll(arg1 context.Context) error {
+	fake.abortAllMutex.Lock()
+	ret, specificReturn := fake.abortAllReturnsOnCall[len(fake.abortAllArgsForCall)]
+	fake.abortAllArgsForCall = append(fake.abortAllArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AbortAllStub
+	fakeReturns := fake.abortAllReturns
+	fake.recordInvocation("AbortAll", []interface{}{arg1})
+	fake.abortAllMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRunState) AbortAllCallCount() int {
+	fake.abortAllMutex.RLock()
+	defer fake.abortAllMutex.RUnlock()
+	return len(fake.abortAllArgsForCall)
+}
+
+func (fake *FakeRunState) AbortAllCalls(stub func(context.Context) error) {
+	fake.abortAllMutex.Lock()
+	defer fake.abortAllMutex.Unlock()
+	fake.AbortAllStub = stub
+}
+
+func (fake *FakeRunState) AbortAllArgsForCall(i int) context.Context {
+	fake.abortAllMutex.RLock()
+	defer fake.abortAllMutex.RUnlock()
+	argsForCall := fake.abortAllArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRunState) AbortAllReturns(result1 error) {
+	fake.abortAllMutex.Lock()
+	defer fake.abortAllMutex.Unlock()
+	fake.AbortAllStub = nil
+	fake.abortAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRunState) AbortAllReturnsOnCall(i int, result1 error) {
+	fake.abortAllMutex.Lock()
+	defer fake.abortAllMutex.Unlock()
+	fake.AbortAllStub = nil
+	if fake.abortAllReturnsOnCall == nil {
+		fake.abortAllReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.abortAllReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRunState) AddLocalVar(arg1 string, arg2 interface{}, arg3 bool) {
 	fake.addLocalVarMutex.Lock()
 	fake.addLocalVarArgsForCall = append(fake.addLocalVarArgsForCall, struct {
@@ -214,6 +307,67 @@ func (fake *FakeRunState) ArtifactRepositoryReturnsOnCall(i int, result1 *build.
 	}{result1}
 }
 
+func (fake *FakeRunState) FinalizeAll(arg1 context.Context) error {
+	fake.finalizeAllMutex.Lock()
+	ret, specificReturn := fake.finalizeAllReturnsOnCall[len(fake.finalizeAllArgsForCall)]
+	fake.finalizeAllArgsForCall = append(fake.finalizeAllArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.FinalizeAllStub
+	fakeReturns := fake.finalizeAllReturns
+	fake.recordInvocation("FinalizeAll", []interface{}{arg1})
+	fake.finalizeAllMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRunState) FinalizeAllCallCount() int {
+	fake.finalizeAllMutex.RLock()
+	defer fake.finalizeAllMutex.RUnlock()
+	return len(fake.finalizeAllArgsForCall)
+}
+
+func (fake *FakeRunState) FinalizeAllCalls(stub func(context.Context) error) {
+	fake.finalizeAllMutex.Lock()
+	defer fake.finalizeAllMutex.Unlock()
+	fake.FinalizeAllStub = stub
+}
+
+func (fake *FakeRunState) FinalizeAllArgsForCall(i int) context.Context {
+	fake.finalizeAllMutex.RLock()
+	defer fake.finalizeAllMutex.RUnlock()
+	argsForCall := fake.finalizeAllArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRunState) FinalizeAllReturns(result1 error) {
+	fake.finalizeAllMutex.Lock()
+	defer fake.finalizeAllMutex.Unlock()
+	fake.FinalizeAllStub = nil
+	fake.finalizeAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRunState) FinalizeAllReturnsOnCall(i int, result1 error) {
+	fake.finalizeAllMutex.Lock()
+	defer fake.finalizeAllMutex.Unlock()
+	fake.FinalizeAllStub = nil
+	if fake.finalizeAllReturnsOnCall == nil {
+		fake.finalizeAllReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.finalizeAllReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRunState) Get(arg1 vars.Reference) (interface{}, bool, error) {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
@@ -528,6 +682,70 @@ func (fake *FakeRunState) RedactionEnabledReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeRunState) RegisterAbortable(arg1 exec.Abortable) {
+	fake.registerAbortableMutex.Lock()
+	fake.registerAbortableArgsForCall = append(fake.registerAbortableArgsForCall, struct {
+		arg1 exec.Abortable
+	}{arg1})
+	stub := fake.RegisterAbortableStub
+	fake.recordInvocation("RegisterAbortable", []interface{}{arg1})
+	fake.registerAbortableMutex.Unlock()
+	if stub != nil {
+		fake.RegisterAbortableStub(arg1)
+	}
+}
+
+func (fake *FakeRunState) RegisterAbortableCallCount() int {
+	fake.registerAbortableMutex.RLock()
+	defer fake.registerAbortableMutex.RUnlock()
+	return len(fake.registerAbortableArgsForCall)
+}
+
+func (fake *FakeRunState) RegisterAbortableCalls(stub func(exec.Abortable)) {
+	fake.registerAbortableMutex.Lock()
+	defer fake.registerAbortableMutex.Unlock()
+	fake.RegisterAbortableStub = stub
+}
+
+func (fake *FakeRunState) RegisterAbortableArgsForCall(i int) exec.Abortable {
+	fake.registerAbortableMutex.RLock()
+	defer fake.registerAbortableMutex.RUnlock()
+	argsForCall := fake.registerAbortableArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRunState) RegisterFinalizer(arg1 exec.Finalizer) {
+	fake.registerFinalizerMutex.Lock()
+	fake.registerFinalizerArgsForCall = append(fake.registerFinalizerArgsForCall, struct {
+		arg1 exec.Finalizer
+	}{arg1})
+	stub := fake.RegisterFinalizerStub
+	fake.recordInvocation("RegisterFinalizer", []interface{}{arg1})
+	fake.registerFinalizerMutex.Unlock()
+	if stub != nil {
+		fake.RegisterFinalizerStub(arg1)
+	}
+}
+
+func (fake *FakeRunState) RegisterFinalizerCallCount() int {
+	fake.registerFinalizerMutex.RLock()
+	defer fake.registerFinalizerMutex.RUnlock()
+	return len(fake.registerFinalizerArgsForCall)
+}
+
+func (fake *FakeRunState) RegisterFinalizerCalls(stub func(exec.Finalizer)) {
+	fake.registerFinalizerMutex.Lock()
+	defer fake.registerFinalizerMutex.Unlock()
+	fake.RegisterFinalizerStub = stub
+}
+
+func (fake *FakeRunState) RegisterFinalizerArgsForCall(i int) exec.Finalizer {
+	fake.registerFinalizerMutex.RLock()
+	defer fake.registerFinalizerMutex.RUnlock()
+	argsForCall := fake.registerFinalizerArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeRunState) Result(arg1 atc.PlanID, arg2 interface{}) bool {
 	fake.resultMutex.Lock()
 	ret, specificReturn := fake.resultReturnsOnCall[len(fake.resultArgsForCall)]
@@ -689,8 +907,12 @@ func (fake *FakeRunState) StoreResultArgsForCall(i int) (atc.PlanID, interface{}
 }
 
 func (fake *FakeRunState) Invocations() map[string][][]interface{} {
+	fake.finalizeAllMutex.RLock()
+	defer fake.finalizeAllMutex.RUnlock()
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.abortAllMutex.RLock()
+	defer fake.abortAllMutex.RUnlock()
 	fake.addLocalVarMutex.RLock()
 	defer fake.addLocalVarMutex.RUnlock()
 	fake.artifactRepositoryMutex.RLock()
@@ -707,6 +929,10 @@ func (fake *FakeRunState) Invocations() map[string][][]interface{} {
 	defer fake.parentMutex.RUnlock()
 	fake.redactionEnabledMutex.RLock()
 	defer fake.redactionEnabledMutex.RUnlock()
+	fake.registerAbortableMutex.RLock()
+	defer fake.registerAbortableMutex.RUnlock()
+	fake.registerFinalizerMutex.RLock()
+	defer fake.registerFinalizerMutex.RUnlock()
 	fake.resultMutex.RLock()
 	defer fake.resultMutex.RUnlock()
 	fake.runMutex.RLock()
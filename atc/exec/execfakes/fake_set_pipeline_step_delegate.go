@@ -54,12 +54,25 @@ type FakeSetPipelineStepDelegate struct {
 		arg1 lager.Logger
 		arg2 string
 	}
+	SetBuildAnnotationStub        func(lager.Logger, string, string)
+	setBuildAnnotationMutex       sync.RWMutex
+	setBuildAnnotationArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 string
+		arg3 string
+	}
 	SetPipelineChangedStub        func(lager.Logger, bool)
 	setPipelineChangedMutex       sync.RWMutex
 	setPipelineChangedArgsForCall []struct {
 		arg1 lager.Logger
 		arg2 bool
 	}
+	SetPipelineProgressStub        func(lager.Logger, string)
+	setPipelineProgressMutex       sync.RWMutex
+	setPipelineProgressArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 string
+	}
 	StartSpanStub        func(context.Context, string, tracing.Attrs) (context.Context, trace.Span)
 	startSpanMutex       sync.RWMutex
 	startSpanArgsForCall []struct {
@@ -100,6 +113,12 @@ type FakeSetPipelineStepDelegate struct {
 	stdoutReturnsOnCall map[int]struct {
 		result1 io.Writer
 	}
+	VarFileFetchedStub        func(lager.Logger, exec.VarFileFetch)
+	varFileFetchedMutex       sync.RWMutex
+	varFileFetchedArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 exec.VarFileFetch
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -302,6 +321,40 @@ func (fake *FakeSetPipelineStepDelegate) SelectedWorkerArgsForCall(i int) (lager
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeSetPipelineStepDelegate) SetBuildAnnotation(arg1 lager.Logger, arg2 string, arg3 string) {
+	fake.setBuildAnnotationMutex.Lock()
+	fake.setBuildAnnotationArgsForCall = append(fake.setBuildAnnotationArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.SetBuildAnnotationStub
+	fake.recordInvocation("SetBuildAnnotation", []interface{}{arg1, arg2, arg3})
+	fake.setBuildAnnotationMutex.Unlock()
+	if stub != nil {
+		fake.SetBuildAnnotationStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeSetPipelineStepDelegate) SetBuildAnnotationCallCount() int {
+	fake.setBuildAnnotationMutex.RLock()
+	defer fake.setBuildAnnotationMutex.RUnlock()
+	return len(fake.setBuildAnnotationArgsForCall)
+}
+
+func (fake *FakeSetPipelineStepDelegate) SetBuildAnnotationCalls(stub func(lager.Logger, string, string)) {
+	fake.setBuildAnnotationMutex.Lock()
+	defer fake.setBuildAnnotationMutex.Unlock()
+	fake.SetBuildAnnotationStub = stub
+}
+
+func (fake *FakeSetPipelineStepDelegate) SetBuildAnnotationArgsForCall(i int) (lager.Logger, string, string) {
+	fake.setBuildAnnotationMutex.RLock()
+	defer fake.setBuildAnnotationMutex.RUnlock()
+	argsForCall := fake.setBuildAnnotationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
 func (fake *FakeSetPipelineStepDelegate) SetPipelineChanged(arg1 lager.Logger, arg2 bool) {
 	fake.setPipelineChangedMutex.Lock()
 	fake.setPipelineChangedArgsForCall = append(fake.setPipelineChangedArgsForCall, struct {
@@ -335,6 +388,39 @@ func (fake *FakeSetPipelineStepDelegate) SetPipelineChangedArgsForCall(i int) (l
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeSetPipelineStepDelegate) SetPipelineProgress(arg1 lager.Logger, arg2 string) {
+	fake.setPipelineProgressMutex.Lock()
+	fake.setPipelineProgressArgsForCall = append(fake.setPipelineProgressArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetPipelineProgressStub
+	fake.recordInvocation("SetPipelineProgress", []interface{}{arg1, arg2})
+	fake.setPipelineProgressMutex.Unlock()
+	if stub != nil {
+		fake.SetPipelineProgressStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeSetPipelineStepDelegate) SetPipelineProgressCallCount() int {
+	fake.setPipelineProgressMutex.RLock()
+	defer fake.setPipelineProgressMutex.RUnlock()
+	return len(fake.setPipelineProgressArgsForCall)
+}
+
+func (fake *FakeSetPipelineStepDelegate) SetPipelineProgressCalls(stub func(lager.Logger, string)) {
+	fake.setPipelineProgressMutex.Lock()
+	defer fake.setPipelineProgressMutex.Unlock()
+	fake.SetPipelineProgressStub = stub
+}
+
+func (fake *FakeSetPipelineStepDelegate) SetPipelineProgressArgsForCall(i int) (lager.Logger, string) {
+	fake.setPipelineProgressMutex.RLock()
+	defer fake.setPipelineProgressMutex.RUnlock()
+	argsForCall := fake.setPipelineProgressArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeSetPipelineStepDelegate) StartSpan(arg1 context.Context, arg2 string, arg3 tracing.Attrs) (context.Context, trace.Span) {
 	fake.startSpanMutex.Lock()
 	ret, specificReturn := fake.startSpanReturnsOnCall[len(fake.startSpanArgsForCall)]
@@ -539,6 +625,39 @@ func (fake *FakeSetPipelineStepDelegate) StdoutReturnsOnCall(i int, result1 io.W
 	}{result1}
 }
 
+func (fake *FakeSetPipelineStepDelegate) VarFileFetched(arg1 lager.Logger, arg2 exec.VarFileFetch) {
+	fake.varFileFetchedMutex.Lock()
+	fake.varFileFetchedArgsForCall = append(fake.varFileFetchedArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 exec.VarFileFetch
+	}{arg1, arg2})
+	stub := fake.VarFileFetchedStub
+	fake.recordInvocation("VarFileFetched", []interface{}{arg1, arg2})
+	fake.varFileFetchedMutex.Unlock()
+	if stub != nil {
+		fake.VarFileFetchedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeSetPipelineStepDelegate) VarFileFetchedCallCount() int {
+	fake.varFileFetchedMutex.RLock()
+	defer fake.varFileFetchedMutex.RUnlock()
+	return len(fake.varFileFetchedArgsForCall)
+}
+
+func (fake *FakeSetPipelineStepDelegate) VarFileFetchedCalls(stub func(lager.Logger, exec.VarFileFetch)) {
+	fake.varFileFetchedMutex.Lock()
+	defer fake.varFileFetchedMutex.Unlock()
+	fake.VarFileFetchedStub = stub
+}
+
+func (fake *FakeSetPipelineStepDelegate) VarFileFetchedArgsForCall(i int) (lager.Logger, exec.VarFileFetch) {
+	fake.varFileFetchedMutex.RLock()
+	defer fake.varFileFetchedMutex.RUnlock()
+	argsForCall := fake.varFileFetchedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeSetPipelineStepDelegate) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -552,8 +671,12 @@ func (fake *FakeSetPipelineStepDelegate) Invocations() map[string][][]interface{
 	defer fake.initializingMutex.RUnlock()
 	fake.selectedWorkerMutex.RLock()
 	defer fake.selectedWorkerMutex.RUnlock()
+	fake.setBuildAnnotationMutex.RLock()
+	defer fake.setBuildAnnotationMutex.RUnlock()
 	fake.setPipelineChangedMutex.RLock()
 	defer fake.setPipelineChangedMutex.RUnlock()
+	fake.setPipelineProgressMutex.RLock()
+	defer fake.setPipelineProgressMutex.RUnlock()
 	fake.startSpanMutex.RLock()
 	defer fake.startSpanMutex.RUnlock()
 	fake.startingMutex.RLock()
@@ -562,6 +685,8 @@ func (fake *FakeSetPipelineStepDelegate) Invocations() map[string][][]interface{
 	defer fake.stderrMutex.RUnlock()
 	fake.stdoutMutex.RLock()
 	defer fake.stdoutMutex.RUnlock()
+	fake.varFileFetchedMutex.RLock()
+	defer fake.varFileFetchedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,45 @@
+package exec
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// AllowLoopbackAddresses lets set_pipeline steps make requests to the test
+// servers, which listen on the loopback interface. It returns a func which
+// blocks them again.
+func AllowLoopbackAddresses() func() {
+	setPipelineAddressBlocked = func(ip net.IP) bool {
+		return !ip.IsLoopback() && addressBlocked(ip)
+	}
+
+	return func() {
+		setPipelineAddressBlocked = addressBlocked
+	}
+}
+
+// TrustTLSServer lets set_pipeline steps make requests to the test server,
+// which uses a self-signed certificate. It returns a func which no longer
+// trusts it.
+func TrustTLSServer(server *httptest.Server) func() {
+	transport := setPipelineHTTPClient.Transport.(*http.Transport)
+	transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	return func() {
+		transport.TLSClientConfig = nil
+		transport.CloseIdleConnections()
+	}
+}
+
+// SetDefaultSetPipelineTimeout changes the deadline of set_pipeline steps
+// which do not configure a timeout. It returns a func which restores it.
+func SetDefaultSetPipelineTimeout(timeout time.Duration) func() {
+	original := defaultSetPipelineTimeout
+	defaultSetPipelineTimeout = timeout
+
+	return func() {
+		defaultSetPipelineTimeout = original
+	}
+}
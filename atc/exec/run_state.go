@@ -18,9 +18,22 @@ type runState struct {
 	artifacts *build.Repository
 	results   *sync.Map
 
+	abortables *abortables
+	finalizers *finalizers
+
 	parent RunState
 }
 
+type abortables struct {
+	lock  sync.Mutex
+	steps []Abortable
+}
+
+type finalizers struct {
+	lock  sync.Mutex
+	steps []Finalizer
+}
+
 type Stepper func(atc.Plan) Step
 
 func NewRunState(
@@ -35,6 +48,9 @@ func NewRunState(
 
 		artifacts: build.NewRepository(),
 		results:   &sync.Map{},
+
+		abortables: &abortables{},
+		finalizers: &finalizers{},
 	}
 }
 
@@ -95,3 +111,55 @@ func (state *runState) RedactionEnabled() bool {
 func (state *runState) Run(ctx context.Context, plan atc.Plan) (bool, error) {
 	return state.stepper(plan).Run(ctx, state)
 }
+
+func (state *runState) RegisterAbortable(abortable Abortable) {
+	state.abortables.lock.Lock()
+	defer state.abortables.lock.Unlock()
+
+	state.abortables.steps = append(state.abortables.steps, abortable)
+}
+
+// AbortAll aborts every registered Abortable in the reverse order of
+// registration, returning the first error encountered.
+func (state *runState) AbortAll(ctx context.Context) error {
+	state.abortables.lock.Lock()
+	defer state.abortables.lock.Unlock()
+
+	var firstErr error
+	for i := len(state.abortables.steps) - 1; i >= 0; i-- {
+		err := state.abortables.steps[i].Abort(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	state.abortables.steps = nil
+
+	return firstErr
+}
+
+func (state *runState) RegisterFinalizer(finalizer Finalizer) {
+	state.finalizers.lock.Lock()
+	defer state.finalizers.lock.Unlock()
+
+	state.finalizers.steps = append(state.finalizers.steps, finalizer)
+}
+
+// FinalizeAll finalizes every registered Finalizer in the order of
+// registration, returning the first error encountered.
+func (state *runState) FinalizeAll(ctx context.Context) error {
+	state.finalizers.lock.Lock()
+	defer state.finalizers.lock.Unlock()
+
+	var firstErr error
+	for _, finalizer := range state.finalizers.steps {
+		err := finalizer.Finalize(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	state.finalizers.steps = nil
+
+	return firstErr
+}
@@ -372,4 +372,103 @@ var _ = Describe("RunState", func() {
 			})
 		})
 	})
+
+	Describe("AbortAll", func() {
+		var (
+			first  *execfakes.FakeAbortable
+			second *execfakes.FakeAbortable
+			order  []string
+		)
+
+		BeforeEach(func() {
+			order = nil
+
+			first = new(execfakes.FakeAbortable)
+			first.AbortStub = func(context.Context) error {
+				order = append(order, "first")
+				return nil
+			}
+
+			second = new(execfakes.FakeAbortable)
+			second.AbortStub = func(context.Context) error {
+				order = append(order, "second")
+				return nil
+			}
+
+			state.RegisterAbortable(first)
+			state.NewLocalScope().RegisterAbortable(second)
+		})
+
+		It("aborts everything registered in any scope in reverse order", func() {
+			Expect(state.AbortAll(context.Background())).To(Succeed())
+			Expect(order).To(Equal([]string{"second", "first"}))
+		})
+
+		It("only aborts each abortable once", func() {
+			Expect(state.AbortAll(context.Background())).To(Succeed())
+			Expect(state.AbortAll(context.Background())).To(Succeed())
+			Expect(first.AbortCallCount()).To(Equal(1))
+		})
+
+		Context("when an abortable fails", func() {
+			BeforeEach(func() {
+				second.AbortStub = nil
+				second.AbortReturns(errors.New("nope"))
+			})
+
+			It("still aborts the rest and returns the error", func() {
+				Expect(state.AbortAll(context.Background())).To(MatchError("nope"))
+				Expect(first.AbortCallCount()).To(Equal(1))
+			})
+		})
+	})
+	Describe("FinalizeAll", func() {
+		var (
+			first  *execfakes.FakeFinalizer
+			second *execfakes.FakeFinalizer
+			order  []string
+		)
+
+		BeforeEach(func() {
+			order = nil
+
+			first = new(execfakes.FakeFinalizer)
+			first.FinalizeStub = func(context.Context) error {
+				order = append(order, "first")
+				return nil
+			}
+
+			second = new(execfakes.FakeFinalizer)
+			second.FinalizeStub = func(context.Context) error {
+				order = append(order, "second")
+				return nil
+			}
+
+			state.RegisterFinalizer(first)
+			state.NewLocalScope().RegisterFinalizer(second)
+		})
+
+		It("finalizes everything registered in any scope in order", func() {
+			Expect(state.FinalizeAll(context.Background())).To(Succeed())
+			Expect(order).To(Equal([]string{"first", "second"}))
+		})
+
+		It("only finalizes each finalizer once", func() {
+			Expect(state.FinalizeAll(context.Background())).To(Succeed())
+			Expect(state.FinalizeAll(context.Background())).To(Succeed())
+			Expect(first.FinalizeCallCount()).To(Equal(1))
+		})
+
+		Context("when a finalizer fails", func() {
+			BeforeEach(func() {
+				first.FinalizeStub = nil
+				first.FinalizeReturns(errors.New("nope"))
+			})
+
+			It("still finalizes the rest and returns the error", func() {
+				Expect(state.FinalizeAll(context.Background())).To(MatchError("nope"))
+				Expect(second.FinalizeCallCount()).To(Equal(1))
+			})
+		})
+	})
 })
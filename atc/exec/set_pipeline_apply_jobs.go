@@ -0,0 +1,55 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// applyJobs returns the existing config with only the named jobs taken from
+// the new config. A named job which is not in the new config is removed.
+// Resources and resource types which the existing config does not have yet
+// are added so that the applied jobs can use them; all other parts of the
+// existing config are left unchanged.
+func applyJobs(existing atc.Config, config atc.Config, jobNames []string) (atc.Config, error) {
+	partial := atc.Config{}
+	removed := map[string]bool{}
+
+	for _, name := range jobNames {
+		job, found := config.Jobs.Lookup(name)
+		if found {
+			partial.Jobs = append(partial.Jobs, job)
+			continue
+		}
+
+		if _, found := existing.Jobs.Lookup(name); !found {
+			return atc.Config{}, fmt.Errorf("job to apply not found: %s", name)
+		}
+
+		removed[name] = true
+	}
+
+	for _, resource := range config.Resources {
+		if _, found := existing.Resources.Lookup(resource.Name); !found {
+			partial.Resources = append(partial.Resources, resource)
+		}
+	}
+
+	for _, resourceType := range config.ResourceTypes {
+		if _, found := existing.ResourceTypes.Lookup(resourceType.Name); !found {
+			partial.ResourceTypes = append(partial.ResourceTypes, resourceType)
+		}
+	}
+
+	merged := extendConfig(existing, partial)
+
+	var jobs atc.JobConfigs
+	for _, job := range merged.Jobs {
+		if !removed[job.Name] {
+			jobs = append(jobs, job)
+		}
+	}
+	merged.Jobs = jobs
+
+	return merged, nil
+}
@@ -0,0 +1,49 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// applyResources returns the existing config with only the named resources
+// taken from the new config. A named resource which is not in the new config
+// is removed. Resource types which the existing config does not have yet are
+// added so that the applied resources can use them; all other parts of the
+// existing config are left unchanged.
+func applyResources(existing atc.Config, config atc.Config, resourceNames []string) (atc.Config, error) {
+	partial := atc.Config{}
+	removed := map[string]bool{}
+
+	for _, name := range resourceNames {
+		resource, found := config.Resources.Lookup(name)
+		if found {
+			partial.Resources = append(partial.Resources, resource)
+			continue
+		}
+
+		if _, found := existing.Resources.Lookup(name); !found {
+			return atc.Config{}, fmt.Errorf("resource to apply not found: %s", name)
+		}
+
+		removed[name] = true
+	}
+
+	for _, resourceType := range config.ResourceTypes {
+		if _, found := existing.ResourceTypes.Lookup(resourceType.Name); !found {
+			partial.ResourceTypes = append(partial.ResourceTypes, resourceType)
+		}
+	}
+
+	merged := extendConfig(existing, partial)
+
+	var resources atc.ResourceConfigs
+	for _, resource := range merged.Resources {
+		if !removed[resource.Name] {
+			resources = append(resources, resource)
+		}
+	}
+	merged.Resources = resources
+
+	return merged, nil
+}
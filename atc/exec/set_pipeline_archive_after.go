@@ -0,0 +1,35 @@
+package exec
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseArchiveAfter parses the archive_after duration. On top of the units
+// understood by time.ParseDuration it accepts a whole number of days, e.g.
+// "30d", since pipelines are usually left unused for far longer than hours.
+func parseArchiveAfter(value string) (time.Duration, error) {
+	var archiveAfter time.Duration
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+
+		archiveAfter = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		archiveAfter, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if archiveAfter <= 0 {
+		return 0, errors.New("must be positive")
+	}
+
+	return archiveAfter, nil
+}
@@ -0,0 +1,50 @@
+package exec
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/concourse/concourse/atc/exec/build"
+)
+
+// AutoConfigFile is the `file` of a set_pipeline step which selects the
+// build's only YAML artifact as the pipeline config.
+const AutoConfigFile = "auto"
+
+// AutoConfigFileError is returned when `file: auto` does not match exactly one
+// artifact.
+type AutoConfigFileError struct {
+	Candidates []string
+}
+
+// Error returns a human-friendly error message.
+func (err AutoConfigFileError) Error() string {
+	if len(err.Candidates) == 0 {
+		return "file: auto found no artifact with a .yml or .yaml extension"
+	}
+
+	return fmt.Sprintf("file: auto matched multiple artifacts: %s", strings.Join(err.Candidates, ", "))
+}
+
+// resolveAutoConfigFile returns the path of the config selected by
+// `file: auto`, i.e. the only artifact whose name has a .yml or .yaml
+// extension. The artifact is expected to contain a file of the same name, as
+// produced by e.g. a task output named pipeline.yml.
+func resolveAutoConfigFile(repo *build.Repository) (string, error) {
+	var candidates []string
+	for name := range repo.AsMap() {
+		switch filepath.Ext(string(name)) {
+		case ".yml", ".yaml":
+			candidates = append(candidates, string(name))
+		}
+	}
+
+	if len(candidates) != 1 {
+		sort.Strings(candidates)
+		return "", AutoConfigFileError{Candidates: candidates}
+	}
+
+	return candidates[0] + "/" + candidates[0], nil
+}
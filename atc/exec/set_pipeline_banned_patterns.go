@@ -0,0 +1,50 @@
+package exec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BannedPatternMatch is a line of a pipeline config containing one of the
+// step's banned patterns.
+type BannedPatternMatch struct {
+	Pattern string
+	Line    int
+}
+
+// BannedPatternsError is returned when a pipeline config contains any of the
+// step's banned patterns.
+type BannedPatternsError struct {
+	File    string
+	Matches []BannedPatternMatch
+}
+
+// Error returns a human-friendly error message.
+func (err BannedPatternsError) Error() string {
+	var msg strings.Builder
+
+	fmt.Fprintf(&msg, "%s contains banned patterns:", err.File)
+	for _, match := range err.Matches {
+		fmt.Fprintf(&msg, "\n- line %d: %s", match.Line, match.Pattern)
+	}
+
+	return msg.String()
+}
+
+// findBannedPatterns returns every line of the config containing one of the
+// patterns, compared case-insensitively.
+func findBannedPatterns(config []byte, patterns []string) []BannedPatternMatch {
+	var matches []BannedPatternMatch
+
+	for i, text := range strings.Split(strings.ToLower(string(config)), "\n") {
+		line := i + 1
+
+		for _, pattern := range patterns {
+			if pattern != "" && strings.Contains(text, strings.ToLower(pattern)) {
+				matches = append(matches, BannedPatternMatch{Pattern: pattern, Line: line})
+			}
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,140 @@
+package exec
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/concourse/concourse/atc/runtime"
+)
+
+// SetPipelineFileCache is an in-memory cache of the files fetched by
+// set_pipeline steps. Entries are keyed by the artifact, the version of the
+// resource it holds and the path they were fetched from, and each team has its
+// own LRU so that one team cannot evict another team's entries.
+type SetPipelineFileCache struct {
+	clock clock.Clock
+	ttl   time.Duration
+	size  int
+
+	lock  sync.Mutex
+	teams map[int]*fileLRU
+}
+
+type fileLRU struct {
+	entries map[fileCacheKey]*list.Element
+	order   *list.List
+}
+
+type fileCacheKey struct {
+	artifactID      string
+	artifactVersion string
+	path            string
+}
+
+type fileCacheEntry struct {
+	key       fileCacheKey
+	content   []byte
+	expiresAt time.Time
+}
+
+// NewSetPipelineFileCache returns a cache holding up to size entries per team,
+// each for at most ttl.
+func NewSetPipelineFileCache(clock clock.Clock, ttl time.Duration, size int) *SetPipelineFileCache {
+	return &SetPipelineFileCache{
+		clock: clock,
+		ttl:   ttl,
+		size:  size,
+		teams: map[int]*fileLRU{},
+	}
+}
+
+// Get returns the cached content of the file, if present and not expired.
+func (c *SetPipelineFileCache) Get(teamID int, artifactID string, artifactVersion string, path string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	lru, found := c.teams[teamID]
+	if !found {
+		return nil, false
+	}
+
+	key := fileCacheKey{artifactID: artifactID, artifactVersion: artifactVersion, path: path}
+
+	elem, found := lru.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := elem.Value.(*fileCacheEntry)
+	if !c.clock.Now().Before(entry.expiresAt) {
+		lru.order.Remove(elem)
+		delete(lru.entries, key)
+		return nil, false
+	}
+
+	lru.order.MoveToFront(elem)
+
+	return entry.content, true
+}
+
+// Set stores the content of the file, evicting the team's least recently
+// used entry if the team's cache is full.
+func (c *SetPipelineFileCache) Set(teamID int, artifactID string, artifactVersion string, path string, content []byte) {
+	if c.size <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	lru, found := c.teams[teamID]
+	if !found {
+		lru = &fileLRU{
+			entries: map[fileCacheKey]*list.Element{},
+			order:   list.New(),
+		}
+		c.teams[teamID] = lru
+	}
+
+	key := fileCacheKey{artifactID: artifactID, artifactVersion: artifactVersion, path: path}
+	expiresAt := c.clock.Now().Add(c.ttl)
+
+	if elem, found := lru.entries[key]; found {
+		entry := elem.Value.(*fileCacheEntry)
+		entry.content = content
+		entry.expiresAt = expiresAt
+		lru.order.MoveToFront(elem)
+		return
+	}
+
+	lru.entries[key] = lru.order.PushFront(&fileCacheEntry{
+		key:       key,
+		content:   content,
+		expiresAt: expiresAt,
+	})
+
+	for lru.order.Len() > c.size {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.entries, oldest.Value.(*fileCacheEntry).key)
+	}
+}
+
+// artifactVersion returns the version of the resource held by the artifact,
+// or an empty string if the artifact does not hold one, e.g. a task output.
+func artifactVersion(art runtime.Artifact) string {
+	versioned, ok := art.(runtime.VersionedArtifact)
+	if !ok || versioned.Version() == nil {
+		return ""
+	}
+
+	payload, err := json.Marshal(versioned.Version())
+	if err != nil {
+		return ""
+	}
+
+	return string(payload)
+}
@@ -0,0 +1,101 @@
+package exec_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/concourse/concourse/atc/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetPipelineFileCache", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		cache     *exec.SetPipelineFileCache
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		cache = exec.NewSetPipelineFileCache(fakeClock, time.Minute, 2)
+	})
+
+	It("returns cached files", func() {
+		cache.Set(1, "some-artifact", "", "pipeline.yml", []byte("some-content"))
+
+		content, found := cache.Get(1, "some-artifact", "", "pipeline.yml")
+		Expect(found).To(BeTrue())
+		Expect(content).To(Equal([]byte("some-content")))
+	})
+
+	It("keys files by artifact and path", func() {
+		cache.Set(1, "some-artifact", "", "pipeline.yml", []byte("some-content"))
+
+		_, found := cache.Get(1, "other-artifact", "", "pipeline.yml")
+		Expect(found).To(BeFalse())
+
+		_, found = cache.Get(1, "some-artifact", "", "vars.yml")
+		Expect(found).To(BeFalse())
+	})
+
+	It("keys files by artifact version", func() {
+		cache.Set(1, "some-artifact", `{"ref":"v1"}`, "vars.yml", []byte("some-content"))
+
+		content, found := cache.Get(1, "some-artifact", `{"ref":"v1"}`, "vars.yml")
+		Expect(found).To(BeTrue())
+		Expect(content).To(Equal([]byte("some-content")))
+
+		_, found = cache.Get(1, "some-artifact", `{"ref":"v2"}`, "vars.yml")
+		Expect(found).To(BeFalse())
+	})
+
+	It("scopes files by team", func() {
+		cache.Set(1, "some-artifact", "", "pipeline.yml", []byte("some-content"))
+
+		_, found := cache.Get(2, "some-artifact", "", "pipeline.yml")
+		Expect(found).To(BeFalse())
+	})
+
+	It("expires files after the ttl", func() {
+		cache.Set(1, "some-artifact", "", "pipeline.yml", []byte("some-content"))
+
+		fakeClock.Increment(time.Minute)
+
+		_, found := cache.Get(1, "some-artifact", "", "pipeline.yml")
+		Expect(found).To(BeFalse())
+	})
+
+	It("evicts the least recently used file of the team when full", func() {
+		cache.Set(1, "some-artifact", "", "a.yml", []byte("a"))
+		cache.Set(1, "some-artifact", "", "b.yml", []byte("b"))
+		cache.Set(2, "some-artifact", "", "c.yml", []byte("c"))
+
+		_, found := cache.Get(1, "some-artifact", "", "a.yml")
+		Expect(found).To(BeTrue())
+
+		cache.Set(1, "some-artifact", "", "d.yml", []byte("d"))
+
+		_, found = cache.Get(1, "some-artifact", "", "b.yml")
+		Expect(found).To(BeFalse())
+
+		_, found = cache.Get(1, "some-artifact", "", "a.yml")
+		Expect(found).To(BeTrue())
+
+		_, found = cache.Get(2, "some-artifact", "", "c.yml")
+		Expect(found).To(BeTrue())
+	})
+
+	Context("when the ttl is zero", func() {
+		BeforeEach(func() {
+			cache = exec.NewSetPipelineFileCache(fakeClock, 0, 2)
+		})
+
+		It("does not cache files", func() {
+			cache.Set(1, "some-artifact", "", "pipeline.yml", []byte("some-content"))
+
+			_, found := cache.Get(1, "some-artifact", "", "pipeline.yml")
+			Expect(found).To(BeFalse())
+		})
+	})
+})
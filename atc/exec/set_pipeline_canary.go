@@ -0,0 +1,175 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc/db"
+)
+
+const canaryPollingInterval = 10 * time.Second
+
+// CanaryJobFailedError is returned when a canary job's first build after the
+// pipeline was set does not succeed.
+type CanaryJobFailedError struct {
+	Job    string
+	Status db.BuildStatus
+}
+
+// Error returns a human-friendly error message.
+func (err CanaryJobFailedError) Error() string {
+	return fmt.Sprintf("canary job %s %s", err.Job, err.Status)
+}
+
+// rollOutCanaryJobs pauses every job of the pipeline other than the canary
+// jobs, waits for each canary job to complete a new build successfully, and
+// then returns the jobs to how they were. Jobs which were paused beforehand
+// stay paused. If a canary build does not succeed, the remaining jobs are
+// left paused; on any other error they are unpaused again.
+func rollOutCanaryJobs(ctx context.Context, logger lager.Logger, pipeline db.Pipeline, canaryJobs []string, stdout io.Writer) (err error) {
+	jobs, err := pipeline.Jobs()
+	if err != nil {
+		return err
+	}
+
+	isCanary := map[string]bool{}
+	for _, name := range canaryJobs {
+		isCanary[name] = true
+	}
+
+	canaries := map[string]db.Job{}
+	lastBuildIDs := map[string]int{}
+	var remaining db.Jobs
+	for _, job := range jobs {
+		if !isCanary[job.Name()] {
+			remaining = append(remaining, job)
+			continue
+		}
+
+		finished, _, err := job.FinishedAndNextBuild()
+		if err != nil {
+			return err
+		}
+
+		if finished != nil {
+			lastBuildIDs[job.Name()] = finished.ID()
+		}
+
+		canaries[job.Name()] = job
+	}
+
+	for _, name := range canaryJobs {
+		if _, found := canaries[name]; !found {
+			return fmt.Errorf("canary job %s not found", name)
+		}
+	}
+
+	// only the jobs whose state is changed here are restored, so that jobs
+	// paused on purpose are never unpaused
+	var pausedJobs, unpausedCanaries db.Jobs
+	defer func() {
+		restoreErr := restoreCanaryRollOut(logger, pausedJobs, unpausedCanaries, err)
+		if err == nil {
+			err = restoreErr
+		}
+	}()
+
+	for _, job := range remaining {
+		if job.Paused() {
+			continue
+		}
+
+		err := job.Pause()
+		if err != nil {
+			return err
+		}
+
+		pausedJobs = append(pausedJobs, job)
+	}
+
+	for _, name := range canaryJobs {
+		if !canaries[name].Paused() {
+			continue
+		}
+
+		err := canaries[name].Unpause()
+		if err != nil {
+			return err
+		}
+
+		unpausedCanaries = append(unpausedCanaries, canaries[name])
+	}
+
+	fmt.Fprintf(stdout, "waiting for canary jobs: %v\n", canaryJobs)
+
+	pending := append([]string{}, canaryJobs...)
+	for {
+		var stillPending []string
+		for _, name := range pending {
+			finished, _, err := canaries[name].FinishedAndNextBuild()
+			if err != nil {
+				return err
+			}
+
+			if finished == nil || finished.ID() <= lastBuildIDs[name] {
+				stillPending = append(stillPending, name)
+				continue
+			}
+
+			if finished.Status() != db.BuildStatusSucceeded {
+				return CanaryJobFailedError{Job: name, Status: finished.Status()}
+			}
+
+			logger.Debug("canary-job-succeeded", lager.Data{"job": name, "build": finished.ID()})
+			fmt.Fprintf(stdout, "canary job %s succeeded\n", name)
+		}
+
+		pending = stillPending
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-time.After(canaryPollingInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// restoreCanaryRollOut pauses the canary jobs which were unpaused for the
+// roll out and unpauses the jobs which were paused for it, unless a canary
+// failed. It does not depend on the step's context, so the jobs are restored
+// even when the step timed out or the build was aborted.
+func restoreCanaryRollOut(logger lager.Logger, pausedJobs db.Jobs, unpausedCanaries db.Jobs, rollOutErr error) error {
+	var firstErr error
+
+	for _, job := range unpausedCanaries {
+		err := job.Pause()
+		if err != nil {
+			logger.Error("failed-to-pause-canary-job", err, lager.Data{"job": job.Name()})
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if _, failed := rollOutErr.(CanaryJobFailedError); failed {
+		return firstErr
+	}
+
+	for _, job := range pausedJobs {
+		err := job.Unpause()
+		if err != nil {
+			logger.Error("failed-to-unpause-job", err, lager.Data{"job": job.Name()})
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,35 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// jobNameCollisions returns a warning for each job, in the config being set
+// or in any other pipeline of the team, whose name is the same as the
+// pipeline's. Such names make `passed:` constraints ambiguous to read.
+func jobNameCollisions(team db.Team, pipelineRef atc.PipelineRef, config atc.Config) ([]string, error) {
+	var warnings []string
+
+	if _, found := config.Jobs.Lookup(pipelineRef.Name); found {
+		warnings = append(warnings, fmt.Sprintf("pipeline '%s' has a job with the same name", pipelineRef.Name))
+	}
+
+	jobs, err := team.JobsNamed(pipelineRef.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		otherRef := job.PipelineRef()
+		if otherRef.String() == pipelineRef.String() {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf("pipeline '%s' has the same name as job '%s' in pipeline '%s'", pipelineRef.Name, job.Name(), otherRef.String()))
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,72 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapSourcePrefix marks a set_pipeline file path as referring to a key
+// of a Kubernetes ConfigMap, i.e. configmap:<name>/<key>, rather than a file
+// in an artifact.
+const ConfigMapSourcePrefix = "configmap:"
+
+//go:generate counterfeiter . ConfigMapFetcher
+
+// ConfigMapFetcher fetches files for set_pipeline steps from Kubernetes
+// ConfigMaps belonging to a team.
+type ConfigMapFetcher interface {
+	FetchConfigMapFile(ctx context.Context, teamName string, name string, key string) ([]byte, error)
+}
+
+// ConfigMapKeyNotFoundError is returned when a ConfigMap does not contain the
+// requested key.
+type ConfigMapKeyNotFoundError struct {
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// Error returns a human-friendly error message.
+func (err ConfigMapKeyNotFoundError) Error() string {
+	return fmt.Sprintf("key '%s' not found in configmap '%s/%s'", err.Key, err.Namespace, err.Name)
+}
+
+type kubernetesConfigMapFetcher struct {
+	client          kubernetes.Interface
+	namespacePrefix string
+}
+
+// NewKubernetesConfigMapFetcher returns a ConfigMapFetcher which looks up
+// ConfigMaps in the namespace named by the prefix followed by the team name.
+func NewKubernetesConfigMapFetcher(client kubernetes.Interface, namespacePrefix string) ConfigMapFetcher {
+	return kubernetesConfigMapFetcher{
+		client:          client,
+		namespacePrefix: namespacePrefix,
+	}
+}
+
+func (fetcher kubernetesConfigMapFetcher) FetchConfigMapFile(ctx context.Context, teamName string, name string, key string) ([]byte, error) {
+	namespace := fetcher.namespacePrefix + teamName
+
+	configMap, err := fetcher.client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if data, found := configMap.Data[key]; found {
+		return []byte(data), nil
+	}
+
+	if data, found := configMap.BinaryData[key]; found {
+		return data, nil
+	}
+
+	return nil, ConfigMapKeyNotFoundError{
+		Namespace: namespace,
+		Name:      name,
+		Key:       key,
+	}
+}
@@ -0,0 +1,64 @@
+package exec_test
+
+import (
+	"context"
+
+	"github.com/concourse/concourse/atc/exec"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KubernetesConfigMapFetcher", func() {
+	var (
+		fakeClientset *fake.Clientset
+		fetcher       exec.ConfigMapFetcher
+	)
+
+	BeforeEach(func() {
+		fakeClientset = fake.NewSimpleClientset()
+		fetcher = exec.NewKubernetesConfigMapFetcher(fakeClientset, "prefix-")
+
+		_, err := fakeClientset.CoreV1().ConfigMaps("prefix-some-team").Create(context.TODO(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "some-configmap",
+			},
+			Data: map[string]string{
+				"pipeline.yml": "some-content",
+			},
+			BinaryData: map[string][]byte{
+				"vars.yml": []byte("some-binary-content"),
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns the data under the key", func() {
+		content, err := fetcher.FetchConfigMapFile(context.TODO(), "some-team", "some-configmap", "pipeline.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal([]byte("some-content")))
+	})
+
+	It("returns the binary data under the key", func() {
+		content, err := fetcher.FetchConfigMapFile(context.TODO(), "some-team", "some-configmap", "vars.yml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal([]byte("some-binary-content")))
+	})
+
+	It("errors when the key is missing", func() {
+		_, err := fetcher.FetchConfigMapFile(context.TODO(), "some-team", "some-configmap", "missing.yml")
+		Expect(err).To(Equal(exec.ConfigMapKeyNotFoundError{
+			Namespace: "prefix-some-team",
+			Name:      "some-configmap",
+			Key:       "missing.yml",
+		}))
+	})
+
+	It("does not look in other teams' namespaces", func() {
+		_, err := fetcher.FetchConfigMapFile(context.TODO(), "other-team", "some-configmap", "pipeline.yml")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,39 @@
+package exec
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// TeamNotFoundError is returned when the team a set_pipeline step refers to
+// does not exist.
+type TeamNotFoundError struct {
+	Name string
+}
+
+// Error returns a human-friendly error message.
+func (err TeamNotFoundError) Error() string {
+	return fmt.Sprintf("team %s not found", err.Name)
+}
+
+// createMissingTeam creates the target team of a set_pipeline step with
+// `create_team_if_missing`. Only builds of the main team may create teams.
+// The team is created without any auth, leaving it to the main team to grant
+// access.
+func (step *SetPipelineStep) createMissingTeam(currentTeam db.Team, name string, stdout io.Writer) (db.Team, error) {
+	if currentTeam.Name() != atc.DefaultTeamName {
+		return nil, fmt.Errorf("team %s does not have permission to create team %s", currentTeam.Name(), name)
+	}
+
+	team, err := step.teamFactory.CreateTeam(atc.Team{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("create team %s: %w", name, err)
+	}
+
+	fmt.Fprintf(stdout, "created team %s\n", name)
+
+	return team, nil
+}
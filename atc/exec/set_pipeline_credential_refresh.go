@@ -0,0 +1,76 @@
+package exec
+
+import (
+	"encoding/json"
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/vars"
+)
+
+//go:generate counterfeiter . CredentialCacheInvalidator
+
+// CredentialCacheInvalidator removes the secrets a var of a pipeline is looked
+// up from from the ATC's credential cache, so that they are fetched afresh
+// from the credential manager the next time they are used.
+type CredentialCacheInvalidator interface {
+	InvalidateVariable(teamName string, pipelineName string, ref vars.Reference) error
+}
+
+// credentialReferences returns the vars referenced by the config which are
+// looked up in the ATC's credential manager, i.e. those without a var source.
+func credentialReferences(config atc.Config) ([]vars.Reference, error) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+
+	var refs []vars.Reference
+	for _, name := range vars.NewTemplate(payload).ExtraVarNames() {
+		ref, err := vars.ParseReference(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if ref.Source != "" || seen[ref.Path] {
+			continue
+		}
+
+		seen[ref.Path] = true
+		refs = append(refs, ref)
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Path < refs[j].Path
+	})
+
+	return refs, nil
+}
+
+// refreshCredentials invalidates the cached secrets of every var of the
+// config, so that the pipeline's next builds use freshly rotated credentials.
+func (step *SetPipelineStep) refreshCredentials(logger lager.Logger, teamName string, pipelineName string, config atc.Config) error {
+	if step.credentialCacheInvalidator == nil {
+		// secrets are not cached, so they are always fetched afresh
+		return nil
+	}
+
+	refs, err := credentialReferences(config)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		err := step.credentialCacheInvalidator.InvalidateVariable(teamName, pipelineName, ref)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Debug("invalidated-credentials", lager.Data{"count": len(refs)})
+
+	return nil
+}
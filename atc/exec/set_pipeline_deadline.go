@@ -0,0 +1,72 @@
+package exec
+
+import (
+	"sync"
+	"time"
+)
+
+// setPipelineDeadline cancels a set_pipeline step once it has run for its
+// timeout. Time spent waiting after the pipeline has been saved is excluded,
+// as those waits are bounded by their own settings rather than by the step's
+// timeout.
+type setPipelineDeadline struct {
+	lock      sync.Mutex
+	timer     *time.Timer
+	remaining time.Duration
+	started   time.Time
+	expired   bool
+}
+
+// newSetPipelineDeadline returns a deadline which calls cancel once the
+// timeout passes. A zero timeout never passes.
+func newSetPipelineDeadline(timeout time.Duration, cancel func()) *setPipelineDeadline {
+	deadline := &setPipelineDeadline{
+		remaining: timeout,
+		started:   time.Now(),
+	}
+
+	if timeout > 0 {
+		deadline.timer = time.AfterFunc(timeout, cancel)
+	}
+
+	return deadline
+}
+
+// Exclude runs the given function without counting the time it takes towards
+// the deadline. If the deadline has already passed it has no effect.
+func (deadline *setPipelineDeadline) Exclude(f func() error) error {
+	if deadline.timer == nil {
+		return f()
+	}
+
+	deadline.lock.Lock()
+	if !deadline.timer.Stop() {
+		deadline.expired = true
+	}
+	deadline.remaining -= time.Since(deadline.started)
+	deadline.lock.Unlock()
+
+	defer func() {
+		deadline.lock.Lock()
+		defer deadline.lock.Unlock()
+
+		if !deadline.expired {
+			deadline.started = time.Now()
+			deadline.timer.Reset(deadline.remaining)
+		}
+	}()
+
+	return f()
+}
+
+// Stop stops the deadline, returning false if it had already passed.
+func (deadline *setPipelineDeadline) Stop() bool {
+	if deadline.timer == nil {
+		return true
+	}
+
+	deadline.lock.Lock()
+	defer deadline.lock.Unlock()
+
+	return deadline.timer.Stop()
+}
@@ -0,0 +1,67 @@
+package exec
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setPipelineDeadline", func() {
+	var (
+		deadline  *setPipelineDeadline
+		cancelled chan struct{}
+	)
+
+	BeforeEach(func() {
+		cancelled = make(chan struct{})
+		deadline = newSetPipelineDeadline(100*time.Millisecond, func() { close(cancelled) })
+	})
+
+	It("cancels once the timeout passes", func() {
+		Eventually(cancelled).Should(BeClosed())
+		Expect(deadline.Stop()).To(BeFalse())
+	})
+
+	It("does not count excluded time towards the timeout", func() {
+		err := deadline.Exclude(func() error {
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cancelled).ToNot(BeClosed())
+
+		Eventually(cancelled).Should(BeClosed())
+	})
+
+	It("can be stopped before the timeout passes", func() {
+		Expect(deadline.Stop()).To(BeTrue())
+		Consistently(cancelled, 200*time.Millisecond).ShouldNot(BeClosed())
+	})
+
+	Context("when there is no timeout", func() {
+		BeforeEach(func() {
+			deadline.Stop()
+
+			cancelled = make(chan struct{})
+			deadline = newSetPipelineDeadline(0, func() { close(cancelled) })
+		})
+
+		It("never cancels", func() {
+			Expect(deadline.Exclude(func() error { return nil })).To(Succeed())
+			Consistently(cancelled, 200*time.Millisecond).ShouldNot(BeClosed())
+			Expect(deadline.Stop()).To(BeTrue())
+		})
+	})
+
+	Context("when the timeout has already passed", func() {
+		BeforeEach(func() {
+			Eventually(cancelled).Should(BeClosed())
+		})
+
+		It("does not start it again", func() {
+			Expect(deadline.Exclude(func() error { return nil })).To(Succeed())
+			Expect(deadline.Stop()).To(BeFalse())
+		})
+	})
+})
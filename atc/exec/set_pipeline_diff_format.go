@@ -0,0 +1,28 @@
+package exec
+
+import (
+	"io"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// The formats a set_pipeline step may print the config diff in. The unified
+// format is used when none is configured.
+const (
+	DiffFormatUnified    = "unified"
+	DiffFormatSideBySide = "sidebyside"
+	DiffFormatSummary    = "summary"
+)
+
+// diffConfigs prints the difference between the existing and new config in
+// the given format, returning whether there is any difference.
+func diffConfigs(out io.Writer, format string, existing atc.Config, config atc.Config) bool {
+	switch format {
+	case DiffFormatSummary:
+		return existing.SummaryDiff(out, config)
+	case DiffFormatSideBySide:
+		return existing.SideBySideDiff(out, config)
+	default:
+		return existing.Diff(out, config)
+	}
+}
@@ -0,0 +1,164 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/atc/exec/build"
+	"github.com/concourse/concourse/atc/runtime"
+	"github.com/concourse/concourse/atc/worker"
+)
+
+// ArtifactFileSpec describes an artifact holding a single file.
+type ArtifactFileSpec struct {
+	TeamID  int
+	BuildID int
+	Name    string
+	Path    string
+	Content []byte
+}
+
+//go:generate counterfeiter . ArtifactFileWriter
+
+// ArtifactFileWriter creates an artifact holding a single file, which later
+// steps of the build can use as an input.
+type ArtifactFileWriter interface {
+	WriteArtifactFile(ctx context.Context, spec ArtifactFileSpec) (runtime.Artifact, error)
+}
+
+type workerArtifactFileWriter struct {
+	pool worker.Pool
+}
+
+// NewWorkerArtifactFileWriter returns an ArtifactFileWriter which writes the
+// file to a new volume on one of the team's workers.
+func NewWorkerArtifactFileWriter(pool worker.Pool) ArtifactFileWriter {
+	return workerArtifactFileWriter{
+		pool: pool,
+	}
+}
+
+func (writer workerArtifactFileWriter) WriteArtifactFile(ctx context.Context, spec ArtifactFileSpec) (runtime.Artifact, error) {
+	logger := lagerctx.FromContext(ctx).Session("write-artifact-file", lager.Data{"name": spec.Name, "path": spec.Path})
+
+	volume, err := writer.pool.CreateVolume(
+		logger,
+		worker.VolumeSpec{Strategy: baggageclaim.EmptyStrategy{}},
+		worker.WorkerSpec{
+			Platform: "linux",
+			TeamID:   spec.TeamID,
+		},
+		db.VolumeTypeArtifact,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = volume.InitializeArtifact(spec.Name, spec.BuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	tarStream, err := tarGzFile(spec.Path, spec.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = volume.StreamIn(ctx, ".", baggageclaim.GzipEncoding, tarStream)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.TaskArtifact{VolumeHandle: volume.Handle()}, nil
+}
+
+func tarGzFile(path string, content []byte) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+
+	gzWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name: path,
+		Mode: 0644,
+		Size: int64(len(content)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tarWriter.Write(content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// splitDiffOutput splits the diff_output of a set_pipeline step into the name
+// of the artifact and the path of the file within it.
+func splitDiffOutput(diffOutput string) (build.ArtifactName, string, error) {
+	segs := strings.SplitN(diffOutput, "/", 2)
+	if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+		return "", "", fmt.Errorf("invalid diff_output: %s: must be of the form <artifact>/<path>", diffOutput)
+	}
+
+	return build.ArtifactName(segs[0]), segs[1], nil
+}
+
+// writeDiffOutput writes the diff to the step's diff_output and registers the
+// artifact holding it, replacing any artifact of the same name.
+func (step *SetPipelineStep) writeDiffOutput(ctx context.Context, state RunState, diff []byte) error {
+	name, path, err := splitDiffOutput(step.plan.DiffOutput)
+	if err != nil {
+		return err
+	}
+
+	err = step.writeArtifactFile(ctx, state, name, path, diff)
+	if err != nil {
+		return fmt.Errorf("write diff output: %w", err)
+	}
+
+	return nil
+}
+
+// writeArtifactFile writes the file to a new artifact and registers it under
+// the name, replacing any artifact of the same name.
+func (step *SetPipelineStep) writeArtifactFile(ctx context.Context, state RunState, name build.ArtifactName, path string, content []byte) error {
+	if step.artifactWriter == nil {
+		return fmt.Errorf("writing files to artifacts is not supported")
+	}
+
+	artifact, err := step.artifactWriter.WriteArtifactFile(ctx, ArtifactFileSpec{
+		TeamID:  step.metadata.TeamID,
+		BuildID: step.metadata.BuildID,
+		Name:    string(name),
+		Path:    path,
+		Content: content,
+	})
+	if err != nil {
+		return err
+	}
+
+	state.ArtifactRepository().RegisterArtifact(name, artifact)
+
+	return nil
+}
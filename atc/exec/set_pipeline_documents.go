@@ -0,0 +1,30 @@
+package exec
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// documentSeparator matches the `---` lines between the documents of a YAML
+// stream.
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*(#.*)?$`)
+
+// mergeDocuments merges the documents of a multi-document config file in the
+// same way as strategic_merge_files, each document being applied on top of
+// the ones before it. A config with a single document is returned as-is.
+func mergeDocuments(config []byte) ([]byte, error) {
+	var documents [][]byte
+	for _, document := range documentSeparator.Split(string(config), -1) {
+		if len(bytes.TrimSpace([]byte(document))) == 0 {
+			continue
+		}
+
+		documents = append(documents, []byte(document))
+	}
+
+	if len(documents) < 2 {
+		return config, nil
+	}
+
+	return strategicMerge(documents[0], documents[1:])
+}
@@ -0,0 +1,200 @@
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// encryptFieldsSegment is one segment of an encrypt_fields pattern, i.e. a
+// key, a [*] wildcard matching every item of a list, or a [N] list index.
+type encryptFieldsSegment struct {
+	key      string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// parseEncryptFieldsPattern parses a pattern such as
+// resources[*].source.password into its segments.
+func parseEncryptFieldsPattern(pattern string) ([]encryptFieldsSegment, error) {
+	var segments []encryptFieldsSegment
+
+	for _, part := range strings.Split(strings.TrimPrefix(pattern, "$."), ".") {
+		key := part
+		if i := strings.Index(part, "["); i != -1 {
+			key = part[:i]
+		}
+
+		if key != "" {
+			segments = append(segments, encryptFieldsSegment{key: key})
+		} else if part == "" || !strings.HasPrefix(part, "[") {
+			return nil, fmt.Errorf("empty key in %q", pattern)
+		}
+
+		rest := part[len(key):]
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if !strings.HasPrefix(rest, "[") || end == -1 {
+				return nil, fmt.Errorf("malformed index in %q", pattern)
+			}
+
+			index := rest[1:end]
+			if index == "*" {
+				segments = append(segments, encryptFieldsSegment{wildcard: true, isIndex: true})
+			} else {
+				n, err := strconv.Atoi(index)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid index %q in %q", index, pattern)
+				}
+
+				segments = append(segments, encryptFieldsSegment{index: n, isIndex: true})
+			}
+
+			rest = rest[end+1:]
+		}
+	}
+
+	return segments, nil
+}
+
+// fieldEncrypter encrypts values into var references, and decrypts the
+// references it created.
+type fieldEncrypter interface {
+	EncryptedVarReference(value string) (string, error)
+	DecryptVarReference(reference string) (string, bool, error)
+}
+
+// encryptFields replaces every string value of the config matching one of the
+// patterns with an encrypted var reference. Values which already contain a
+// var reference are left alone.
+//
+// Encrypting the same value twice never yields the same reference, so when
+// the existing config has a reference to the same value at the same place it
+// is kept, which keeps unchanged values from showing up in the diff.
+func encryptFields(config atc.Config, existing atc.Config, patterns []string, encrypter fieldEncrypter) (atc.Config, error) {
+	tree, err := configTree(config)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	existingTree, err := configTree(existing)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	for _, pattern := range patterns {
+		segments, err := parseEncryptFieldsPattern(pattern)
+		if err != nil {
+			return atc.Config{}, err
+		}
+
+		tree, err = encryptMatching(tree, existingTree, segments, encrypter)
+		if err != nil {
+			return atc.Config{}, fmt.Errorf("encrypt %s: %w", pattern, err)
+		}
+	}
+
+	payload, err := json.Marshal(tree)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	var encrypted atc.Config
+	err = json.Unmarshal(payload, &encrypted)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	return encrypted, nil
+}
+
+func configTree(config atc.Config) (interface{}, error) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree interface{}
+	err = json.Unmarshal(payload, &tree)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+func encryptMatching(node interface{}, existing interface{}, segments []encryptFieldsSegment, encrypter fieldEncrypter) (interface{}, error) {
+	if len(segments) == 0 {
+		value, ok := node.(string)
+		if !ok || strings.Contains(value, "((") {
+			return node, nil
+		}
+
+		if reference, ok := existing.(string); ok {
+			existingValue, found, err := encrypter.DecryptVarReference(reference)
+			if err != nil {
+				return nil, err
+			}
+
+			if found && existingValue == value {
+				return reference, nil
+			}
+		}
+
+		return encrypter.EncryptedVarReference(value)
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment.isIndex {
+		list, ok := node.([]interface{})
+		if !ok {
+			return node, nil
+		}
+
+		existingList, _ := existing.([]interface{})
+
+		for i := range list {
+			if !segment.wildcard && i != segment.index {
+				continue
+			}
+
+			var existingItem interface{}
+			if i < len(existingList) {
+				existingItem = existingList[i]
+			}
+
+			var err error
+			list[i], err = encryptMatching(list[i], existingItem, rest, encrypter)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return list, nil
+	}
+
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return node, nil
+	}
+
+	child, found := object[segment.key]
+	if !found {
+		return node, nil
+	}
+
+	existingObject, _ := existing.(map[string]interface{})
+
+	var err error
+	object[segment.key], err = encryptMatching(child, existingObject[segment.key], rest, encrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
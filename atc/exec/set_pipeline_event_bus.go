@@ -0,0 +1,43 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/atc/eventbus"
+)
+
+// eventBusTimeout bounds how long publishing the pipeline updated event may
+// take, so that an unreachable broker does not hold up the build.
+const eventBusTimeout = 30 * time.Second
+
+// publishPipelineUpdated publishes a concourse.pipeline.updated event for the
+// saved pipeline to the event bus.
+func (step *SetPipelineStep) publishPipelineUpdated(ctx context.Context, team db.Team, pipeline db.Pipeline, created bool, existingConfig atc.Config, atcConfig atc.Config) error {
+	if step.eventPublisher == nil {
+		return errors.New("no event bus is configured")
+	}
+
+	// the display override is applied after saving, so it is part of the
+	// change even though it is not part of the saved config
+	diffConfig := atcConfig
+	if step.plan.Display != nil {
+		diffConfig.Display = step.plan.Display
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, eventBusTimeout)
+	defer cancel()
+
+	return step.eventPublisher.Publish(ctx, eventbus.TypePipelineUpdated, eventbus.PipelineUpdated{
+		Team:          team.Name(),
+		Pipeline:      pipeline.Name(),
+		InstanceVars:  pipeline.InstanceVars(),
+		ConfigVersion: int(pipeline.ConfigVersion()),
+		Created:       created,
+		Diff:          existingConfig.DiffSummary(diffConfig),
+		BuildID:       step.metadata.BuildID,
+	})
+}
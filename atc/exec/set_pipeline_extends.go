@@ -0,0 +1,67 @@
+package exec
+
+import (
+	"github.com/concourse/concourse/atc"
+)
+
+// extendConfig merges the config on top of the base config. Jobs, resources
+// and resource types are unioned, with those in the config replacing those of
+// the same name in the base. Groups, var sources and display are taken from
+// the config if it specifies them, and from the base otherwise.
+func extendConfig(base atc.Config, config atc.Config) atc.Config {
+	merged := atc.Config{
+		Groups:     base.Groups,
+		VarSources: base.VarSources,
+		Display:    base.Display,
+	}
+
+	if len(config.Groups) > 0 {
+		merged.Groups = config.Groups
+	}
+
+	if len(config.VarSources) > 0 {
+		merged.VarSources = config.VarSources
+	}
+
+	if config.Display != nil {
+		merged.Display = config.Display
+	}
+
+	for _, job := range base.Jobs {
+		if override, found := config.Jobs.Lookup(job.Name); found {
+			job = override
+		}
+		merged.Jobs = append(merged.Jobs, job)
+	}
+	for _, job := range config.Jobs {
+		if _, found := base.Jobs.Lookup(job.Name); !found {
+			merged.Jobs = append(merged.Jobs, job)
+		}
+	}
+
+	for _, resource := range base.Resources {
+		if override, found := config.Resources.Lookup(resource.Name); found {
+			resource = override
+		}
+		merged.Resources = append(merged.Resources, resource)
+	}
+	for _, resource := range config.Resources {
+		if _, found := base.Resources.Lookup(resource.Name); !found {
+			merged.Resources = append(merged.Resources, resource)
+		}
+	}
+
+	for _, resourceType := range base.ResourceTypes {
+		if override, found := config.ResourceTypes.Lookup(resourceType.Name); found {
+			resourceType = override
+		}
+		merged.ResourceTypes = append(merged.ResourceTypes, resourceType)
+	}
+	for _, resourceType := range config.ResourceTypes {
+		if _, found := base.ResourceTypes.Lookup(resourceType.Name); !found {
+			merged.ResourceTypes = append(merged.ResourceTypes, resourceType)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,58 @@
+package exec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// jobFeatures returns the features behind feature flags which the job uses.
+func jobFeatures(job atc.JobConfig) []string {
+	used := map[string]bool{}
+
+	_ = job.StepConfig().Visit(atc.StepRecursor{
+		OnAcross: func(*atc.AcrossStep) error {
+			used[atc.FeatureAcrossStep] = true
+			return nil
+		},
+		OnSetPipeline: func(step *atc.SetPipelineStep) error {
+			if len(step.InstanceVars) > 0 {
+				used[atc.FeaturePipelineInstances] = true
+			}
+			return nil
+		},
+	})
+
+	var features []string
+	for feature := range used {
+		features = append(features, feature)
+	}
+
+	sort.Strings(features)
+
+	return features
+}
+
+// checkFeatureFlags returns an error naming each feature which the config
+// uses but which is disabled on this ATC. Features which are not behind a
+// feature flag are always available.
+func checkFeatureFlags(config atc.Config) error {
+	enabled := atc.EnabledFeatures()
+
+	var disabled []string
+	for _, job := range config.Jobs {
+		for _, feature := range jobFeatures(job) {
+			if !enabled[feature] {
+				disabled = append(disabled, fmt.Sprintf("%s (job '%s')", feature, job.Name))
+			}
+		}
+	}
+
+	if len(disabled) > 0 {
+		return fmt.Errorf("pipeline uses features which are disabled on this ATC: %s", strings.Join(disabled, ", "))
+	}
+
+	return nil
+}
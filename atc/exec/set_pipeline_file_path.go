@@ -0,0 +1,55 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/concourse/concourse/vars"
+)
+
+// expandFilePath replaces `$VAR` and `${VAR}` references in the path with the
+// build's metadata (e.g. `$BUILD_PIPELINE_NAME`) or the build's local vars,
+// such as those set by a `load_var` step. Use `$$` for a literal `$`.
+func expandFilePath(path string, metadata StepMetadata, variables vars.Variables) (string, error) {
+	if !strings.Contains(path, "$") {
+		return path, nil
+	}
+
+	env := map[string]string{}
+	for _, kv := range metadata.Env() {
+		segs := strings.SplitN(kv, "=", 2)
+		env[segs[0]] = segs[1]
+	}
+
+	var lookupErr error
+	expanded := os.Expand(path, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+
+		if value, found := env[name]; found {
+			return value
+		}
+
+		value, found, err := variables.Get(vars.Reference{Source: ".", Path: name})
+		if err != nil {
+			lookupErr = err
+			return ""
+		}
+
+		if !found {
+			if lookupErr == nil {
+				lookupErr = fmt.Errorf("undefined variable in file path: $%s", name)
+			}
+			return ""
+		}
+
+		return fmt.Sprint(value)
+	})
+	if lookupErr != nil {
+		return "", lookupErr
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,151 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/vars"
+)
+
+const (
+	defaultGitHubAPIURL          = "https://api.github.com"
+	defaultGitHubDeploymentEnv   = "production"
+	gitHubDeploymentTask         = "deploy:concourse-pipeline"
+	gitHubDeploymentAcceptHeader = "application/vnd.github.v3+json"
+)
+
+type gitHubDeploymentRequest struct {
+	Ref              string                  `json:"ref"`
+	Task             string                  `json:"task"`
+	Environment      string                  `json:"environment"`
+	Description      string                  `json:"description"`
+	AutoMerge        bool                    `json:"auto_merge"`
+	RequiredContexts []string                `json:"required_contexts"`
+	Payload          gitHubDeploymentPayload `json:"payload"`
+}
+
+type gitHubDeploymentPayload struct {
+	Team          string           `json:"team"`
+	Pipeline      string           `json:"pipeline"`
+	ConfigVersion db.ConfigVersion `json:"config_version"`
+	BuildID       int              `json:"build_id"`
+}
+
+// gitHubDeployer creates deployments in a GitHub repository.
+type gitHubDeployer struct {
+	apiURL string
+	token  string
+}
+
+// newGitHubDeployer returns a deployer for the configured repository,
+// authenticating with the token held by its token var.
+func newGitHubDeployer(state RunState, config atc.GitHubDeployment) (gitHubDeployer, error) {
+	token, found, err := state.Get(vars.Reference{Source: ".", Path: config.TokenVar})
+	if err != nil {
+		return gitHubDeployer{}, err
+	}
+
+	if !found {
+		return gitHubDeployer{}, fmt.Errorf("undefined token var: %s", config.TokenVar)
+	}
+
+	apiURL := config.APIURL
+	if apiURL == "" {
+		apiURL = defaultGitHubAPIURL
+	}
+
+	return gitHubDeployer{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  fmt.Sprint(token),
+	}, nil
+}
+
+// createDeployment creates a deployment of the pipeline in the configured
+// environment. The deployment is made on the repository's default branch
+// unless a ref is configured. Commit statuses are not required to pass, as
+// the pipeline has already been set.
+func (d gitHubDeployer) createDeployment(ctx context.Context, config atc.GitHubDeployment, payload gitHubDeploymentPayload) error {
+	ref := config.Ref
+	if ref == "" {
+		var err error
+		ref, err = d.defaultBranch(ctx, config.Owner, config.Repo)
+		if err != nil {
+			return err
+		}
+	}
+
+	environment := config.Environment
+	if environment == "" {
+		environment = defaultGitHubDeploymentEnv
+	}
+
+	body, err := json.Marshal(gitHubDeploymentRequest{
+		Ref:              ref,
+		Task:             gitHubDeploymentTask,
+		Environment:      environment,
+		Description:      fmt.Sprintf("set pipeline %s of team %s (config version %d)", payload.Pipeline, payload.Team, payload.ConfigVersion),
+		RequiredContexts: []string{},
+		Payload:          payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/deployments", config.Owner, config.Repo), body)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected response from github: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (d gitHubDeployer) defaultBranch(ctx context.Context, owner string, repo string) (string, error) {
+	resp, err := d.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s", owner, repo), nil)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from github: %s", resp.Status)
+	}
+
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&repository)
+	if err != nil {
+		return "", err
+	}
+
+	return repository.DefaultBranch, nil
+}
+
+func (d gitHubDeployer) do(ctx context.Context, method string, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, d.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", gitHubDeploymentAcceptHeader)
+	req.Header.Set("Authorization", "token "+d.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return setPipelineHTTPClient.Do(req.WithContext(ctx))
+}
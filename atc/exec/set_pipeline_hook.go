@@ -0,0 +1,27 @@
+package exec
+
+import (
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+//go:generate counterfeiter . SetPipelineHook
+
+// SetPipelineHook allows integrations (e.g. policy-as-code tooling) to take
+// part in every set_pipeline step without modifying the step itself.
+type SetPipelineHook interface {
+	// BeforeSave is called with the validated config before it is saved. Any
+	// error aborts the step without saving the pipeline.
+	BeforeSave(config atc.Config) error
+
+	// AfterSave is called with the saved config and the resulting pipeline.
+	AfterSave(config atc.Config, pipeline db.Pipeline) error
+}
+
+var setPipelineHooks []SetPipelineHook
+
+// RegisterSetPipelineHook adds a hook to be called by every set_pipeline step.
+// Hooks are called in the order they were registered.
+func RegisterSetPipelineHook(hook SetPipelineHook) {
+	setPipelineHooks = append(setPipelineHooks, hook)
+}
@@ -0,0 +1,101 @@
+package exec
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// blockedNetworks are the networks which set_pipeline steps may not make
+// requests to: loopback, private, link-local (including cloud metadata
+// services), carrier-grade NAT and unspecified addresses.
+var blockedNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks
+}
+
+// AddressNotAllowedError is returned when a set_pipeline step would make a
+// request to an address inside the ATC's network.
+type AddressNotAllowedError struct {
+	Address string
+}
+
+// Error returns a human-friendly error message.
+func (err AddressNotAllowedError) Error() string {
+	return fmt.Sprintf("requests to %s are not allowed", err.Address)
+}
+
+// addressBlocked returns whether the IP is in one of the blocked networks.
+func addressBlocked(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setPipelineAddressBlocked decides whether set_pipeline steps may connect to
+// an address. It is a variable so that tests can reach servers listening on
+// the loopback interface.
+var setPipelineAddressBlocked = addressBlocked
+
+// setPipelineHTTPClient is used for the requests made by set_pipeline steps
+// to services outside of Concourse which are named by the pipeline. The
+// address is checked when connecting, after the host has been resolved and on
+// every redirect, so that steps can not use the ATC to reach services inside
+// its network.
+var setPipelineHTTPClient = &http.Client{
+	Timeout: 5 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+
+				ip := net.ParseIP(host)
+				if ip == nil || setPipelineAddressBlocked(ip) {
+					return AddressNotAllowedError{Address: host}
+				}
+
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
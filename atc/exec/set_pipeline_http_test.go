@@ -0,0 +1,53 @@
+package exec
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setPipelineHTTPClient", func() {
+	DescribeTable("blocked addresses",
+		func(address string, blocked bool) {
+			Expect(addressBlocked(net.ParseIP(address))).To(Equal(blocked))
+		},
+		Entry("loopback", "127.0.0.1", true),
+		Entry("ipv6 loopback", "::1", true),
+		Entry("ipv4-mapped loopback", "::ffff:127.0.0.1", true),
+		Entry("private", "10.1.2.3", true),
+		Entry("private", "172.16.0.1", true),
+		Entry("private", "192.168.1.1", true),
+		Entry("ipv6 unique local", "fd00::1", true),
+		Entry("cloud metadata", "169.254.169.254", true),
+		Entry("unspecified", "0.0.0.0", true),
+		Entry("public", "140.82.112.3", false),
+		Entry("ipv6 public", "2606:4700::1111", false),
+	)
+
+	It("refuses to connect to blocked addresses", func() {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+		}))
+		defer server.Close()
+
+		_, err := setPipelineHTTPClient.Get(server.URL)
+		Expect(err).To(MatchError(ContainSubstring("requests to 127.0.0.1 are not allowed")))
+		Expect(requests).To(Equal(0))
+	})
+
+	It("refuses to follow redirects to blocked addresses", func() {
+		restore := AllowLoopbackAddresses()
+		defer restore()
+
+		server := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest/meta-data", http.StatusFound))
+		defer server.Close()
+
+		_, err := setPipelineHTTPClient.Get(server.URL)
+		Expect(err).To(MatchError(ContainSubstring("requests to 169.254.169.254 are not allowed")))
+	})
+})
@@ -0,0 +1,68 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// imageResourceTypeWarnings returns a warning for each task of the config
+// whose image_resource is of a type which can not be used yet: either a
+// pipeline resource type which has no version, or a type which is neither a
+// pipeline resource type nor provided by any of the team's workers. Tasks
+// whose config is loaded from a file are not checked.
+func imageResourceTypeWarnings(config atc.Config, pipeline db.Pipeline, team db.Team) ([]string, error) {
+	resourceTypes, err := pipeline.ResourceTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := team.Workers()
+	if err != nil {
+		return nil, err
+	}
+
+	pipelineTypes := map[string]db.ResourceType{}
+	for _, resourceType := range resourceTypes {
+		pipelineTypes[resourceType.Name()] = resourceType
+	}
+
+	baseTypes := map[string]bool{}
+	for _, worker := range workers {
+		for _, resourceType := range worker.ResourceTypes() {
+			baseTypes[resourceType.Type] = true
+		}
+	}
+
+	var warnings []string
+	for _, job := range config.Jobs {
+		_ = job.StepConfig().Visit(atc.StepRecursor{
+			OnTask: func(step *atc.TaskStep) error {
+				if step.Config == nil || step.Config.ImageResource == nil {
+					return nil
+				}
+
+				imageType := step.Config.ImageResource.Type
+
+				resourceType, found := pipelineTypes[imageType]
+				switch {
+				case found && resourceType.Version() == nil:
+					warnings = append(warnings, fmt.Sprintf(
+						"image_resource of task '%s' in job '%s' uses resource type '%s' which has no version yet",
+						step.Name, job.Name, imageType,
+					))
+				case !found && !baseTypes[imageType]:
+					warnings = append(warnings, fmt.Sprintf(
+						"image_resource of task '%s' in job '%s' uses resource type '%s' which is not provided by any worker",
+						step.Name, job.Name, imageType,
+					))
+				}
+
+				return nil
+			},
+		})
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,310 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/creds"
+	"github.com/concourse/concourse/vars"
+)
+
+// imageTagCheckedResourceTypes are the resource types whose `source` names an
+// image in a Docker registry by its `repository` and `tag`.
+var imageTagCheckedResourceTypes = map[string]bool{
+	"docker-image":   true,
+	"registry-image": true,
+}
+
+const (
+	defaultImageRegistry = "registry-1.docker.io"
+	defaultImageTag      = "latest"
+
+	registryManifestAcceptHeader = "application/vnd.docker.distribution.manifest.v2+json, " +
+		"application/vnd.docker.distribution.manifest.list.v2+json, " +
+		"application/vnd.oci.image.manifest.v1+json, " +
+		"application/vnd.oci.image.index.v1+json"
+)
+
+// taskImage is the source of the image a task runs in.
+type taskImage struct {
+	job    string
+	task   string
+	source atc.Source
+}
+
+// taskImages returns the images of the config's tasks which come from a
+// Docker registry, either through the task's image_resource or through an
+// `image` fetched by a get step of the job. Tasks whose config is loaded from
+// a file are not checked.
+func taskImages(config atc.Config) []taskImage {
+	var images []taskImage
+
+	for _, job := range config.Jobs {
+		fetched := map[string]atc.ResourceConfig{}
+		var tasks []*atc.TaskStep
+
+		_ = job.StepConfig().Visit(atc.StepRecursor{
+			OnGet: func(step *atc.GetStep) error {
+				resource, found := config.Resources.Lookup(step.ResourceName())
+				if found {
+					fetched[step.Name] = resource
+				}
+
+				return nil
+			},
+			OnTask: func(step *atc.TaskStep) error {
+				tasks = append(tasks, step)
+				return nil
+			},
+		})
+
+		for _, step := range tasks {
+			if step.ImageArtifactName != "" {
+				resource, found := fetched[step.ImageArtifactName]
+				if found && imageTagCheckedResourceTypes[resource.Type] {
+					images = append(images, taskImage{job: job.Name, task: step.Name, source: resource.Source})
+				}
+
+				continue
+			}
+
+			if step.Config != nil && step.Config.ImageResource != nil && imageTagCheckedResourceTypes[step.Config.ImageResource.Type] {
+				images = append(images, taskImage{job: job.Name, task: step.Name, source: step.Config.ImageResource.Source})
+			}
+		}
+	}
+
+	return images
+}
+
+// checkImageTags asks the registry of each task image whether its tag exists,
+// authenticating with the `username` and `password` of the image's source,
+// and returns a warning for every image which does not exist or could not be
+// checked.
+func checkImageTags(ctx context.Context, variables vars.Variables, config atc.Config) []string {
+	var warnings []string
+
+	checked := map[string]error{}
+	for _, image := range taskImages(config) {
+		source, err := creds.NewSource(variables, image.source).Evaluate()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not check the image of task '%s' in job '%s': %s", image.task, image.job, err))
+			continue
+		}
+
+		ref, err := parseRegistryImage(source)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not check the image of task '%s' in job '%s': %s", image.task, image.job, err))
+			continue
+		}
+
+		err, found := checked[ref.String()]
+		if !found {
+			username, _ := source["username"].(string)
+			password, _ := source["password"].(string)
+
+			err = ref.checkTagExists(ctx, username, password)
+			checked[ref.String()] = err
+		}
+
+		switch {
+		case err == errImageTagNotFound:
+			warnings = append(warnings, fmt.Sprintf("image '%s' of task '%s' in job '%s' does not exist", ref, image.task, image.job))
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("could not check image '%s' of task '%s' in job '%s': %s", ref, image.task, image.job, err))
+		}
+	}
+
+	return warnings
+}
+
+var errImageTagNotFound = errors.New("image tag not found")
+
+// registryImage is a tag of a repository in a Docker registry.
+type registryImage struct {
+	registry   string
+	repository string
+	tag        string
+}
+
+func (image registryImage) String() string {
+	repository := image.repository
+	if image.registry != defaultImageRegistry {
+		repository = image.registry + "/" + repository
+	}
+
+	return repository + ":" + image.tag
+}
+
+// parseRegistryImage reads the image from the `repository` and `tag` of a
+// source, resolving Docker Hub repositories in the same way as `docker pull`.
+func parseRegistryImage(source atc.Source) (registryImage, error) {
+	repository, _ := source["repository"].(string)
+	if repository == "" {
+		return registryImage{}, fmt.Errorf("no repository specified")
+	}
+
+	image := registryImage{
+		registry:   defaultImageRegistry,
+		repository: repository,
+		tag:        defaultImageTag,
+	}
+
+	if tag, ok := source["tag"].(string); ok && tag != "" {
+		image.tag = tag
+	}
+
+	segs := strings.SplitN(repository, "/", 2)
+	if len(segs) == 2 && (strings.ContainsAny(segs[0], ".:") || segs[0] == "localhost") {
+		image.registry = segs[0]
+		image.repository = segs[1]
+	} else if len(segs) == 1 {
+		image.repository = "library/" + repository
+	}
+
+	return image, nil
+}
+
+// checkTagExists asks the registry for the image's manifest, returning
+// errImageTagNotFound if it does not exist. Registries are only contacted
+// over HTTPS and through setPipelineHTTPClient, so credentials are never sent
+// in plain text or to addresses inside the ATC's network.
+func (image registryImage) checkTagExists(ctx context.Context, username string, password string) error {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", image.registry, image.repository, url.PathEscape(image.tag))
+
+	resp, err := registryManifestRequest(ctx, manifestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorize, err := registryAuthorization(ctx, resp.Header.Get("WWW-Authenticate"), username, password)
+		if err != nil {
+			return err
+		}
+
+		resp, err = registryManifestRequest(ctx, manifestURL, authorize)
+		if err != nil {
+			return err
+		}
+
+		resp.Body.Close()
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return errImageTagNotFound
+	default:
+		return fmt.Errorf("unexpected response from registry: %s", resp.Status)
+	}
+}
+
+func registryManifestRequest(ctx context.Context, manifestURL string, authorize func(*http.Request)) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", registryManifestAcceptHeader)
+
+	if authorize != nil {
+		authorize(req)
+	}
+
+	return setPipelineHTTPClient.Do(req.WithContext(ctx))
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryAuthorization answers the registry's challenge, fetching a token
+// from its auth service when it uses bearer tokens.
+func registryAuthorization(ctx context.Context, challenge string, username string, password string) (func(*http.Request), error) {
+	scheme := strings.SplitN(challenge, " ", 2)[0]
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		return func(req *http.Request) {
+			req.SetBasicAuth(username, password)
+		}, nil
+
+	case "bearer":
+		params := map[string]string{}
+		for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+			params[match[1]] = match[2]
+		}
+
+		token, err := fetchRegistryToken(ctx, params, username, password)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported registry authentication: %q", challenge)
+	}
+}
+
+func fetchRegistryToken(ctx context.Context, params map[string]string, username string, password string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" || realm.Scheme != "https" {
+		return "", fmt.Errorf("invalid registry auth realm: %q", params["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := setPipelineHTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from registry auth: %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", err
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
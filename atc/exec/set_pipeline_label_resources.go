@@ -0,0 +1,32 @@
+package exec
+
+import (
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// lastSetByBuildMetadataKey is the resource metadata key recording the build
+// that last set the resource's pipeline.
+const lastSetByBuildMetadataKey = "last_set_by_build"
+
+// labelResources records the build on each resource of the saved pipeline so
+// operators can trace which build last changed a resource's configuration.
+func labelResources(logger lager.Logger, pipeline db.Pipeline, buildID int) error {
+	resources, err := pipeline.Resources()
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		err = resource.SetMetadata(lastSetByBuildMetadataKey, strconv.Itoa(buildID))
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Debug("labeled-resources", lager.Data{"resources": len(resources)})
+
+	return nil
+}
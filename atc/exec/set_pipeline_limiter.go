@@ -0,0 +1,56 @@
+package exec
+
+import (
+	"context"
+	"sync"
+)
+
+// SetPipelineLimiter limits how many set_pipeline steps of a team may run at
+// once, so that e.g. a meta-pipeline with many parallel set_pipeline steps
+// does not overwhelm the database.
+type SetPipelineLimiter struct {
+	max int
+
+	lock  sync.Mutex
+	teams map[int]chan struct{}
+}
+
+// NewSetPipelineLimiter returns a limiter allowing up to max concurrent
+// set_pipeline steps per team. A max of 0 or less disables the limit.
+func NewSetPipelineLimiter(max int) *SetPipelineLimiter {
+	return &SetPipelineLimiter{
+		max:   max,
+		teams: map[int]chan struct{}{},
+	}
+}
+
+// Acquire blocks until the team has a free slot, returning a function which
+// releases it. It returns the context's error if the context is done before a
+// slot becomes available.
+func (l *SetPipelineLimiter) Acquire(ctx context.Context, teamID int) (func(), error) {
+	if l.max <= 0 {
+		return func() {}, nil
+	}
+
+	slots := l.teamSlots(teamID)
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *SetPipelineLimiter) teamSlots(teamID int) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	slots, found := l.teams[teamID]
+	if !found {
+		slots = make(chan struct{}, l.max)
+		l.teams[teamID] = slots
+	}
+
+	return slots
+}
@@ -0,0 +1,55 @@
+package exec_test
+
+import (
+	"context"
+
+	"github.com/concourse/concourse/atc/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetPipelineLimiter", func() {
+	var limiter *exec.SetPipelineLimiter
+
+	BeforeEach(func() {
+		limiter = exec.NewSetPipelineLimiter(1)
+	})
+
+	It("blocks once the team has no free slot", func() {
+		release, err := limiter.Acquire(context.Background(), 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = limiter.Acquire(ctx, 1)
+		Expect(err).To(Equal(context.Canceled))
+
+		release()
+
+		_, err = limiter.Acquire(context.Background(), 1)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("scopes slots by team", func() {
+		_, err := limiter.Acquire(context.Background(), 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = limiter.Acquire(context.Background(), 2)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when the max is 0", func() {
+		BeforeEach(func() {
+			limiter = exec.NewSetPipelineLimiter(0)
+		})
+
+		It("does not limit", func() {
+			for i := 0; i < 10; i++ {
+				_, err := limiter.Acquire(context.Background(), 1)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	})
+})
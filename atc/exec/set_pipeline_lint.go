@@ -0,0 +1,298 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/atc/runtime"
+	"github.com/concourse/concourse/atc/worker"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// pipelineLintInputDir is the directory, relative to the working
+	// directory of the linting container, which the config, policy and script
+	// are streamed into.
+	pipelineLintInputDir = "input"
+
+	pipelineLintConfigFile = "pipeline.yml"
+	pipelineLintPolicyDir  = "policy"
+	pipelineLintScriptFile = "script"
+)
+
+// pipelineLintScript runs conftest against the config, using the policy if
+// any.
+const pipelineLintScript = `set -e
+mkdir -p input/policy
+conftest test --policy input/policy input/pipeline.yml
+`
+
+// PipelineLintSpec describes a run of a linting tool against a pipeline
+// config.
+type PipelineLintSpec struct {
+	Owner    db.ContainerOwner
+	Metadata db.ContainerMetadata
+	TeamID   int
+	Image    worker.ImageSpec
+	Policy   []byte
+	Config   []byte
+
+	// Script, if set, is run against the config in place of the linting
+	// tool.
+	Script []byte
+}
+
+//go:generate counterfeiter . PipelineLinter
+
+// PipelineLinter runs a linting tool against a pipeline config and returns
+// the exit status of the tool.
+type PipelineLinter interface {
+	Lint(ctx context.Context, spec PipelineLintSpec, stdout io.Writer, stderr io.Writer) (int, error)
+}
+
+type workerPipelineLinter struct {
+	pool     worker.Pool
+	strategy worker.ContainerPlacementStrategy
+}
+
+// NewWorkerPipelineLinter returns a PipelineLinter which runs the linting
+// tool in a task container on one of the team's workers.
+func NewWorkerPipelineLinter(pool worker.Pool, strategy worker.ContainerPlacementStrategy) PipelineLinter {
+	return workerPipelineLinter{
+		pool:     pool,
+		strategy: strategy,
+	}
+}
+
+func (linter workerPipelineLinter) Lint(ctx context.Context, spec PipelineLintSpec, stdout io.Writer, stderr io.Writer) (int, error) {
+	logger := lagerctx.FromContext(ctx).Session("lint-pipeline")
+
+	files := map[string][]byte{
+		pipelineLintConfigFile: spec.Config,
+	}
+
+	if spec.Policy != nil {
+		files[path.Join(pipelineLintPolicyDir, "pipeline.rego")] = spec.Policy
+	}
+
+	script := pipelineLintScript
+	if spec.Script != nil {
+		files[pipelineLintScriptFile] = spec.Script
+		script = preValidateScriptRunner
+	}
+
+	containerSpec := worker.ContainerSpec{
+		TeamID:    spec.TeamID,
+		ImageSpec: spec.Image,
+		Dir:       spec.Metadata.WorkingDirectory,
+		Type:      db.ContainerTypeTask,
+		Inputs: []worker.InputSource{
+			pipelineLintInput{
+				files: files,
+				path:  path.Join(spec.Metadata.WorkingDirectory, pipelineLintInputDir),
+			},
+		},
+	}
+
+	chosenWorker, err := linter.pool.SelectWorker(
+		lagerctx.NewContext(ctx, logger),
+		spec.Owner,
+		containerSpec,
+		worker.WorkerSpec{
+			Platform: "linux",
+			TeamID:   spec.TeamID,
+		},
+		linter.strategy,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := chosenWorker.RunTaskStep(
+		lagerctx.NewContext(ctx, logger),
+		spec.Owner,
+		containerSpec,
+		spec.Metadata,
+		runtime.ProcessSpec{
+			Path:         "sh",
+			Args:         []string{"-c", script},
+			StdoutWriter: stdout,
+			StderrWriter: stderr,
+		},
+		noopStartingEventDelegate{},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ExitStatus, nil
+}
+
+// pipelineLintInput is the input of the linting container holding the files
+// it is run against. The files are streamed into a volume rather than passed
+// through the container's environment, which limits their size and exposes
+// them to anyone who can see the container's spec.
+type pipelineLintInput struct {
+	files map[string][]byte
+	path  string
+}
+
+func (input pipelineLintInput) Source() worker.ArtifactSource {
+	return input
+}
+
+func (input pipelineLintInput) DestinationPath() string {
+	return input.path
+}
+
+// ExistsOn always returns false, so that the files are streamed to the
+// worker the container is created on.
+func (input pipelineLintInput) ExistsOn(lager.Logger, worker.Worker) (worker.Volume, bool, error) {
+	return nil, false, nil
+}
+
+func (input pipelineLintInput) StreamTo(ctx context.Context, destination worker.ArtifactDestination) error {
+	var buf bytes.Buffer
+
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	names := make([]string, 0, len(input.files))
+	for name := range input.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		mode := int64(0644)
+		if name == pipelineLintScriptFile {
+			mode = 0755
+		}
+
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: mode,
+			Size: int64(len(input.files[name])),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tarWriter.Write(input.files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return destination.StreamIn(ctx, ".", baggageclaim.GzipEncoding, &buf)
+}
+
+func (input pipelineLintInput) StreamFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	file, found := input.files[path]
+	if !found {
+		return nil, runtime.FileNotFoundError{Path: path}
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(file)), nil
+}
+
+// lintImageResource returns the image_resource which the image given by
+// lint_image, of the form <repository>[:<tag>], is fetched with.
+func lintImageResource(image string) atc.ImageResource {
+	source := atc.Source{"repository": image}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		source = atc.Source{
+			"repository": image[:i],
+			"tag":        image[i+1:],
+		}
+	}
+
+	return atc.ImageResource{
+		Type:   "registry-image",
+		Source: source,
+	}
+}
+
+type noopStartingEventDelegate struct{}
+
+func (noopStartingEventDelegate) Starting(lager.Logger) {}
+
+// lintPipeline runs the step's lint_image against the resolved config,
+// returning false if the linting tool exits non-zero. The image is fetched in
+// the same way as a task's image_resource.
+func (step *SetPipelineStep) lintPipeline(source setPipelineSource, delegate SetPipelineStepDelegate, atcConfig atc.Config, stdout io.Writer, stderr io.Writer) (bool, error) {
+	if step.linter == nil {
+		return false, fmt.Errorf("linting pipelines is not supported")
+	}
+
+	image, err := delegate.FetchImage(source.ctx, lintImageResource(step.plan.LintImage), nil, false)
+	if err != nil {
+		return false, fmt.Errorf("lint pipeline: fetch image: %w", err)
+	}
+
+	config, err := yaml.Marshal(atcConfig)
+	if err != nil {
+		return false, err
+	}
+
+	var policy []byte
+	if step.plan.LintPolicyFile != "" {
+		policy, err = source.fetchPipelineBits(step.plan.LintPolicyFile)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	exitStatus, err := step.linter.Lint(source.ctx, PipelineLintSpec{
+		Owner: db.NewBuildStepContainerOwner(step.metadata.BuildID, step.planID+"/lint", step.metadata.TeamID),
+		Metadata: db.ContainerMetadata{
+			Type:             db.ContainerTypeTask,
+			StepName:         step.plan.Name,
+			PipelineID:       step.metadata.PipelineID,
+			PipelineName:     step.metadata.PipelineName,
+			JobID:            step.metadata.JobID,
+			JobName:          step.metadata.JobName,
+			BuildID:          step.metadata.BuildID,
+			BuildName:        step.metadata.BuildName,
+			WorkingDirectory: "/tmp/pipeline-lint",
+		},
+		TeamID: step.metadata.TeamID,
+		Image:  image,
+		Policy: policy,
+		Config: config,
+	}, stdout, stderr)
+	if err != nil {
+		return false, fmt.Errorf("lint pipeline: %w", err)
+	}
+
+	if exitStatus != 0 {
+		fmt.Fprintf(stderr, "pipeline config failed linting with %s (exit status %d)\n", step.plan.LintImage, exitStatus)
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,88 @@
+package exec
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/worker/workerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pipelineLintInput", func() {
+	var input pipelineLintInput
+
+	BeforeEach(func() {
+		input = pipelineLintInput{
+			files: map[string][]byte{
+				"pipeline.yml":         []byte("jobs: []"),
+				"policy/pipeline.rego": []byte("package main"),
+				"script":               []byte("#!/bin/sh"),
+			},
+			path: "/tmp/pipeline-lint/input",
+		}
+	})
+
+	It("is streamed to every worker", func() {
+		_, found, err := input.ExistsOn(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("streams its files into the destination", func() {
+		destination := new(workerfakes.FakeArtifactDestination)
+
+		type file struct {
+			mode    int64
+			content string
+		}
+		files := map[string]file{}
+
+		destination.StreamInStub = func(_ context.Context, path string, encoding baggageclaim.Encoding, stream io.Reader) error {
+			Expect(path).To(Equal("."))
+			Expect(encoding).To(Equal(baggageclaim.GzipEncoding))
+
+			gzReader, err := gzip.NewReader(stream)
+			Expect(err).ToNot(HaveOccurred())
+
+			tarReader := tar.NewReader(gzReader)
+			for {
+				header, err := tarReader.Next()
+				if err == io.EOF {
+					return nil
+				}
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := ioutil.ReadAll(tarReader)
+				Expect(err).ToNot(HaveOccurred())
+
+				files[header.Name] = file{mode: header.Mode, content: string(content)}
+			}
+		}
+
+		Expect(input.StreamTo(context.Background(), destination)).To(Succeed())
+		Expect(files).To(Equal(map[string]file{
+			"pipeline.yml":         {mode: 0644, content: "jobs: []"},
+			"policy/pipeline.rego": {mode: 0644, content: "package main"},
+			"script":               {mode: 0755, content: "#!/bin/sh"},
+		}))
+	})
+})
+
+var _ = DescribeTable("lintImageResource",
+	func(image string, source atc.Source) {
+		Expect(lintImageResource(image)).To(Equal(atc.ImageResource{
+			Type:   "registry-image",
+			Source: source,
+		}))
+	},
+	Entry("without a tag", "openpolicyagent/conftest", atc.Source{"repository": "openpolicyagent/conftest"}),
+	Entry("with a tag", "openpolicyagent/conftest:v0.23.0", atc.Source{"repository": "openpolicyagent/conftest", "tag": "v0.23.0"}),
+	Entry("with a registry port", "registry.example.com:5000/conftest", atc.Source{"repository": "registry.example.com:5000/conftest"}),
+)
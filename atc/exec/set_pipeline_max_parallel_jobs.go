@@ -0,0 +1,36 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// MaxParallelJobsExceededError is returned when the builds a pipeline's jobs
+// may run at once exceed the step's max_parallel_jobs.
+type MaxParallelJobsExceededError struct {
+	Parallelism int
+	Max         int
+}
+
+// Error returns a human-friendly error message.
+func (err MaxParallelJobsExceededError) Error() string {
+	return fmt.Sprintf("pipeline config may run %d builds in parallel, more than the %d allowed by max_parallel_jobs", err.Parallelism, err.Max)
+}
+
+// pipelineParallelism returns the number of builds the config's jobs may run
+// at once. Jobs without max_in_flight are unbounded, but count as one so
+// that pipelines with many such jobs still add up.
+func pipelineParallelism(config atc.Config) int {
+	var parallelism int
+	for _, job := range config.Jobs {
+		maxInFlight := job.MaxInFlight()
+		if maxInFlight == 0 {
+			maxInFlight = 1
+		}
+
+		parallelism += maxInFlight
+	}
+
+	return parallelism
+}
@@ -0,0 +1,35 @@
+package exec
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// PipelineNameMismatchError is returned when the name of the pipeline set by
+// a set_pipeline step does not match the step's name_pattern.
+type PipelineNameMismatchError struct {
+	Name    string
+	Pattern string
+}
+
+// Error returns a human-friendly error message.
+func (err PipelineNameMismatchError) Error() string {
+	return fmt.Sprintf("pipeline name '%s' does not match name_pattern %s", err.Name, err.Pattern)
+}
+
+// checkNamePattern returns an error if the name does not match the pattern.
+func checkNamePattern(name string, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid name_pattern: %w", err)
+	}
+
+	if !re.MatchString(name) {
+		return PipelineNameMismatchError{
+			Name:    name,
+			Pattern: pattern,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package exec
+
+import (
+	"fmt"
+	"reflect"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// inheritPinnedVersions copies the pinned version of every resource in the
+// parent pipeline onto the resource of the same name in the child pipeline.
+// Resources pinned through the child's config are left untouched.
+func inheritPinnedVersions(logger lager.Logger, parent db.Pipeline, child db.Pipeline) error {
+	if parent.ID() == child.ID() {
+		return nil
+	}
+
+	resources, err := child.Resources()
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		parentResource, found, err := parent.Resource(resource.Name())
+		if err != nil {
+			return err
+		}
+
+		if !found || parentResource.CurrentPinnedVersion() == nil {
+			continue
+		}
+
+		if resource.ConfigPinnedVersion() != nil {
+			logger.Debug("skipping-resource-pinned-through-config", lager.Data{"resource": resource.Name()})
+			continue
+		}
+
+		version, found, err := parentResource.FindVersion(parentResource.CurrentPinnedVersion())
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			logger.Debug("pinned-version-not-found", lager.Data{"resource": resource.Name()})
+			continue
+		}
+
+		_, err = resource.PinVersion(version.ID())
+		if err != nil {
+			return err
+		}
+
+		logger.Debug("inherited-pinned-version", lager.Data{"resource": resource.Name()})
+	}
+
+	return nil
+}
+
+// lockResourceTypeVersions sets the version of every resource type in the
+// config that does not declare one to the latest version currently used by
+// the existing pipeline, so that saving the config does not pick up newer
+// resource type images. Only types which the pipeline has already checked can
+// be locked: a new pipeline, or a type new to the pipeline, has no version to
+// lock to yet and uses the latest version until the next time it is set. The
+// names of the types which were left unlocked are returned along with the
+// locked config.
+func lockResourceTypeVersions(logger lager.Logger, existing db.Pipeline, config atc.Config) (atc.Config, []string, error) {
+	// the given config is left untouched, so that it can be saved again
+	// against a newer version of the pipeline
+	config.ResourceTypes = append(atc.ResourceTypes{}, config.ResourceTypes...)
+
+	var unlocked []string
+	for i, resourceType := range config.ResourceTypes {
+		if resourceType.Version != nil {
+			continue
+		}
+
+		existingType, found, err := existing.ResourceType(resourceType.Name)
+		if err != nil {
+			return atc.Config{}, nil, err
+		}
+
+		if !found || existingType.Version() == nil || existingType.Type() != resourceType.Type {
+			unlocked = append(unlocked, resourceType.Name)
+			continue
+		}
+
+		config.ResourceTypes[i].Version = existingType.Version()
+
+		logger.Debug("locked-resource-type-version", lager.Data{"resource-type": resourceType.Name})
+	}
+
+	return config, unlocked, nil
+}
+
+// pinBuildInputs pins the versions used by the named inputs of the build onto
+// the resources of the same name in the child pipeline.
+func pinBuildInputs(logger lager.Logger, build db.Build, parent db.Pipeline, child db.Pipeline, names []string) error {
+	inputs, _, err := build.Resources()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var input *db.BuildInput
+		for i := range inputs {
+			if inputs[i].Name == name {
+				input = &inputs[i]
+				break
+			}
+		}
+
+		if input == nil {
+			return fmt.Errorf("build input '%s' not found", name)
+		}
+
+		parentResource, found, err := parent.ResourceByID(input.ResourceID)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("resource for build input '%s' not found", name)
+		}
+
+		version, found, err := parentResource.FindVersion(input.Version)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("version of build input '%s' not found", name)
+		}
+
+		resource, found, err := child.Resource(name)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("resource '%s' not found in pipeline '%s'", name, child.Name())
+		}
+
+		_, err = resource.PinVersion(version.ID())
+		if err != nil {
+			return err
+		}
+
+		logger.Debug("pinned-build-input", lager.Data{"resource": name})
+	}
+
+	return nil
+}
+
+// resourceIdentityFields are the source fields which say what a resource
+// points at, rather than how it is fetched. A resource whose identity fields
+// change is a different resource, even if its name and type stay the same.
+var resourceIdentityFields = []string{"uri", "repository"}
+
+// preserveResourceHistory keeps the version history of every resource whose
+// source has changed while its name, type and identity fields have not. The
+// versions are carried over once the resource has been checked with its new
+// source.
+func preserveResourceHistory(logger lager.Logger, pipeline db.Pipeline, oldConfig atc.Config, newConfig atc.Config) error {
+	for _, diff := range oldConfig.ResourceDiffs(newConfig) {
+		before, changed := diff.Before.(atc.ResourceConfig)
+		if !changed || diff.After == nil {
+			continue
+		}
+
+		after := diff.After.(atc.ResourceConfig)
+		if before.Type != after.Type || reflect.DeepEqual(before.Source, after.Source) {
+			continue
+		}
+
+		if !sameResourceIdentity(before.Source, after.Source) {
+			logger.Debug("skipping-resource-with-new-identity", lager.Data{"resource": after.Name})
+			continue
+		}
+
+		resource, found, err := pipeline.Resource(after.Name)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			continue
+		}
+
+		err = resource.PreserveVersionHistory()
+		if err != nil {
+			return err
+		}
+
+		logger.Debug("preserved-resource-history", lager.Data{"resource": after.Name})
+	}
+
+	return nil
+}
+
+func sameResourceIdentity(before atc.Source, after atc.Source) bool {
+	for _, field := range resourceIdentityFields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc/db"
+)
+
+// preValidateScriptRunner runs the script with the raw config on its stdin.
+const preValidateScriptRunner = `set -e
+input/script < input/pipeline.yml
+`
+
+// PreValidateScriptFailedError is returned when the pre_validate_script
+// rejects the raw pipeline config.
+type PreValidateScriptFailedError struct {
+	Script     string
+	ExitStatus int
+}
+
+// Error returns a human-friendly error message.
+func (err PreValidateScriptFailedError) Error() string {
+	return fmt.Sprintf("pre_validate_script %s failed (exit status %d)", err.Script, err.ExitStatus)
+}
+
+// preValidate runs the step's pre_validate_script against the raw config
+// bytes, before any templating, merging or parsing. The script is run in the
+// pre_validate_image_resource, which is fetched in the same way as a task's
+// image_resource.
+func (s setPipelineSource) preValidate(config []byte) error {
+	step := s.step
+	if step.linter == nil {
+		return fmt.Errorf("pre_validate_script is not supported")
+	}
+
+	script, err := s.fetchPipelineBits(step.plan.PreValidateScript)
+	if err != nil {
+		return err
+	}
+
+	image, err := s.fetchImage(*step.plan.PreValidateImage)
+	if err != nil {
+		return fmt.Errorf("pre_validate_script: fetch image: %w", err)
+	}
+
+	exitStatus, err := step.linter.Lint(s.ctx, PipelineLintSpec{
+		Owner: db.NewBuildStepContainerOwner(step.metadata.BuildID, step.planID+"/pre-validate", step.metadata.TeamID),
+		Metadata: db.ContainerMetadata{
+			Type:             db.ContainerTypeTask,
+			StepName:         step.plan.Name,
+			PipelineID:       step.metadata.PipelineID,
+			PipelineName:     step.metadata.PipelineName,
+			JobID:            step.metadata.JobID,
+			JobName:          step.metadata.JobName,
+			BuildID:          step.metadata.BuildID,
+			BuildName:        step.metadata.BuildName,
+			WorkingDirectory: "/tmp/pipeline-pre-validate",
+		},
+		TeamID: step.metadata.TeamID,
+		Image:  image,
+		Script: script,
+		Config: config,
+	}, s.stderr, s.stderr)
+	if err != nil {
+		return fmt.Errorf("pre_validate_script: %w", err)
+	}
+
+	if exitStatus != 0 {
+		return PreValidateScriptFailedError{
+			Script:     step.plan.PreValidateScript,
+			ExitStatus: exitStatus,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package exec
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxConcurrentVarFileFetches is how many var files a set_pipeline step with
+// prefetch_var_files streams at once.
+const maxConcurrentVarFileFetches = 4
+
+// prefetchVarFiles fetches all of the step's var files concurrently, returning
+// their contents in the order the var files are listed. If any fetch fails,
+// the error of the first failing var file is returned.
+func (s setPipelineSource) prefetchVarFiles() ([][]byte, error) {
+	varFiles := s.step.plan.VarFiles
+
+	if s.progress != nil {
+		s.progress(fmt.Sprintf("prefetching %d var files", len(varFiles)))
+	}
+
+	contents := make([][]byte, len(varFiles))
+	errs := make([]error, len(varFiles))
+
+	slots := make(chan struct{}, maxConcurrentVarFileFetches)
+
+	wg := new(sync.WaitGroup)
+	for i, lvf := range varFiles {
+		wg.Add(1)
+		go func(i int, lvf string) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+			case <-s.ctx.Done():
+				errs[i] = s.ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+
+			contents[i], errs[i] = s.fetchVarFile(lvf)
+		}(i, lvf)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return contents, nil
+}
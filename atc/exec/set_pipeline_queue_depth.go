@@ -0,0 +1,41 @@
+package exec
+
+import (
+	"fmt"
+	"io"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// defaultQueueDepthThreshold is the number of pending builds a job may have
+// before queue_depth_check warns about it.
+const defaultQueueDepthThreshold = 10
+
+// checkQueueDepth warns about every job of the pipeline with more pending
+// builds than the threshold, which usually means the pipeline can't keep up
+// with the builds being scheduled.
+func checkQueueDepth(logger lager.Logger, pipeline db.Pipeline, threshold int, stderr io.Writer) error {
+	if threshold == 0 {
+		threshold = defaultQueueDepthThreshold
+	}
+
+	jobs, err := pipeline.Jobs()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		pending, err := job.GetPendingBuilds()
+		if err != nil {
+			return err
+		}
+
+		if len(pending) > threshold {
+			logger.Info("job-queue-too-deep", lager.Data{"job": job.Name(), "pending": len(pending)})
+			fmt.Fprintf(stderr, "WARNING: job '%s' has %d pending builds\n", job.Name(), len(pending))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc/db"
+)
+
+// pipelineOperatorRoles are the roles which permit setting a team's
+// pipelines, i.e. the `pipeline-operator` role and the roles above it.
+var pipelineOperatorRoles = []string{"owner", "member", "pipeline-operator"}
+
+// PermissionDeniedError is returned when a set_pipeline step sets a pipeline
+// on a team which has not granted the build's team a role permitting it.
+type PermissionDeniedError struct {
+	Team       string
+	TargetTeam string
+}
+
+// Error returns a human-friendly error message.
+func (err PermissionDeniedError) Error() string {
+	return fmt.Sprintf("team %s does not have permission to set pipelines on team %s", err.Team, err.TargetTeam)
+}
+
+// canSetPipelines returns whether the team may set pipelines on the target
+// team. Admin teams may set any team's pipelines; other teams need to be
+// granted the `pipeline-operator` role (or above) in the target team's auth
+// config, e.g. with `fly set-team -c`:
+//
+//	roles:
+//	- name: pipeline-operator
+//	  teams: [some-team]
+func canSetPipelines(team db.Team, target db.Team) bool {
+	if team.ID() == target.ID() || team.Admin() {
+		return true
+	}
+
+	auth := target.Auth()
+	for _, role := range pipelineOperatorRoles {
+		for _, name := range auth[role]["teams"] {
+			if name == team.Name() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// reachabilityCheckedResourceTypes are the resource types whose `source.uri`
+// is known to point at an HTTP(S) endpoint worth probing.
+var reachabilityCheckedResourceTypes = map[string]bool{
+	"git":          true,
+	"s3":           true,
+	"docker-image": true,
+}
+
+// reachabilityCheckTimeout bounds how long checking the reachability of all
+// of a pipeline's resources may take.
+const reachabilityCheckTimeout = 10 * time.Second
+
+// maxReachabilityProbes is the number of resources whose reachability is
+// checked at once.
+const maxReachabilityProbes = 10
+
+// checkResourceReachability probes the `source.uri` of each resource of a
+// known type and returns a warning for every endpoint that could not be
+// reached. Only http and https URIs are probed, in parallel and within
+// reachabilityCheckTimeout overall.
+func checkResourceReachability(ctx context.Context, config atc.Config) []string {
+	ctx, cancel := context.WithTimeout(ctx, reachabilityCheckTimeout)
+	defer cancel()
+
+	warnings := make([]string, len(config.Resources))
+	probes := make(chan struct{}, maxReachabilityProbes)
+
+	var wg sync.WaitGroup
+	for i, resource := range config.Resources {
+		if !reachabilityCheckedResourceTypes[resource.Type] {
+			continue
+		}
+
+		uri, ok := resource.Source["uri"].(string)
+		if !ok {
+			continue
+		}
+
+		u, err := url.Parse(uri)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, name string, u *url.URL) {
+			defer wg.Done()
+
+			probes <- struct{}{}
+			defer func() { <-probes }()
+
+			err := probeURI(ctx, u)
+			if err != nil {
+				warnings[i] = fmt.Sprintf("resource '%s' may be unreachable: %s", name, err)
+			}
+		}(i, resource.Name, u)
+	}
+
+	wg.Wait()
+
+	var found []string
+	for _, warning := range warnings {
+		if warning != "" {
+			found = append(found, warning)
+		}
+	}
+
+	return found
+}
+
+func probeURI(ctx context.Context, u *url.URL) error {
+	req, err := http.NewRequest(http.MethodHead, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := setPipelineHTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
@@ -0,0 +1,125 @@
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/go-concourse/concourse"
+	"github.com/concourse/concourse/vars"
+	"golang.org/x/oauth2"
+)
+
+// RemoteTargetNotAllowedError is returned when a set_pipeline step targets a
+// Concourse which the operator has not allowed remote targets to be set on.
+type RemoteTargetNotAllowedError struct {
+	URL string
+}
+
+// Error returns a human-friendly error message.
+func (err RemoteTargetNotAllowedError) Error() string {
+	return fmt.Sprintf("remote target '%s' is not allowed; it must be configured with --set-pipeline-remote-target", err.URL)
+}
+
+// remoteTargetHTTPClient is used for the requests made to remote targets.
+// Unlike setPipelineHTTPClient it may reach addresses inside the ATC's
+// network, as the operator allowed each remote target.
+var remoteTargetHTTPClient = &http.Client{
+	Timeout: 5 * time.Second,
+}
+
+// remoteTargetAllowed returns whether the operator allowed pipelines to be set
+// on the Concourse at the given URL. The ATC makes requests to the URL on the
+// user's behalf, so no remote targets are allowed unless configured.
+func remoteTargetAllowed(url string) bool {
+	for _, allowed := range atc.SetPipelineRemoteTargets {
+		if strings.TrimSuffix(allowed, "/") == strings.TrimSuffix(url, "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setRemotePipeline sets the pipeline through the API of the Concourse named
+// by the step's target rather than saving it to the local database.
+func (step *SetPipelineStep) setRemotePipeline(logger lager.Logger, state RunState, delegate SetPipelineStepDelegate, config atc.Config) (bool, error) {
+	target := step.plan.Target
+
+	if !remoteTargetAllowed(target.URL) {
+		return false, RemoteTargetNotAllowedError{URL: target.URL}
+	}
+
+	var httpClient *http.Client
+	if target.TokenVar == "" {
+		httpClient = remoteTargetHTTPClient
+	} else {
+		token, found, err := state.Get(vars.Reference{Source: ".", Path: target.TokenVar})
+		if err != nil {
+			return false, err
+		}
+
+		if !found {
+			return false, fmt.Errorf("undefined token var: %s", target.TokenVar)
+		}
+
+		httpClient = &http.Client{
+			Timeout: remoteTargetHTTPClient.Timeout,
+			Transport: &oauth2.Transport{
+				Source: oauth2.StaticTokenSource(&oauth2.Token{
+					TokenType:   "Bearer",
+					AccessToken: fmt.Sprint(token),
+				}),
+				Base: http.DefaultTransport,
+			},
+		}
+	}
+
+	teamName := target.TeamName()
+
+	team := concourse.NewClient(target.URL, httpClient, false).Team(teamName)
+
+	pipelineRef := atc.PipelineRef{
+		Name:         step.plan.Name,
+		InstanceVars: step.plan.InstanceVars,
+	}
+
+	_, configVersion, _, err := team.PipelineConfig(pipelineRef)
+	if err != nil {
+		return false, err
+	}
+
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return false, err
+	}
+
+	stdout := delegate.Stdout()
+	fmt.Fprintf(stdout, "setting pipeline: %s on %s\n", pipelineRef.String(), target.URL)
+
+	created, updated, warnings, err := team.CreateOrUpdatePipelineConfig(pipelineRef, configVersion, payload, false)
+	if err != nil {
+		return false, err
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintf(delegate.Stderr(), "WARNING: %s\n", warning.Message)
+	}
+
+	step.storeResult(state, SetPipelineResult{
+		PipelineName: step.plan.Name,
+		TeamName:     teamName,
+		HadDiff:      created || updated,
+	})
+
+	fmt.Fprintf(stdout, "done\n")
+	logger.Info("saved-remote-pipeline", lager.Data{"url": target.URL, "team": teamName, "pipeline": pipelineRef.String()})
+	delegate.SetPipelineChanged(logger, created || updated)
+	delegate.Finished(logger, true)
+
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package exec
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+const (
+	goroutinesMetric = "/sched/goroutines:goroutines"
+	heapAllocsMetric = "/gc/heap/allocs:bytes"
+	gcPausesMetric   = "/gc/pauses:seconds"
+)
+
+type resourceUsage struct {
+	goroutines uint64
+	heapAllocs uint64
+	gcPause    time.Duration
+}
+
+// readResourceUsage takes a snapshot of the runtime metrics used to attribute
+// resource usage to a set_pipeline step.
+func readResourceUsage() resourceUsage {
+	samples := []metrics.Sample{
+		{Name: goroutinesMetric},
+		{Name: heapAllocsMetric},
+		{Name: gcPausesMetric},
+	}
+
+	metrics.Read(samples)
+
+	var usage resourceUsage
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			switch sample.Name {
+			case goroutinesMetric:
+				usage.goroutines = sample.Value.Uint64()
+			case heapAllocsMetric:
+				usage.heapAllocs = sample.Value.Uint64()
+			}
+		case metrics.KindFloat64Histogram:
+			usage.gcPause = histogramTotal(sample.Value.Float64Histogram())
+		}
+	}
+
+	return usage
+}
+
+// histogramTotal approximates the sum of a histogram of seconds by counting
+// each sample at the lower bound of its bucket.
+func histogramTotal(histogram *metrics.Float64Histogram) time.Duration {
+	var total float64
+	for i, count := range histogram.Counts {
+		lower := histogram.Buckets[i]
+		if lower < 0 {
+			continue
+		}
+		total += float64(count) * lower
+	}
+
+	return time.Duration(total * float64(time.Second))
+}
+
+// since returns the resource usage between the earlier snapshot and this one.
+// The goroutine count is not a delta, but the count at the time of this
+// snapshot.
+func (usage resourceUsage) since(earlier resourceUsage) resourceUsage {
+	return resourceUsage{
+		goroutines: usage.goroutines,
+		heapAllocs: usage.heapAllocs - earlier.heapAllocs,
+		gcPause:    usage.gcPause - earlier.gcPause,
+	}
+}
@@ -0,0 +1,111 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+const rollbackPollingInterval = 10 * time.Second
+
+// ResourceCheckFailedError is returned when a resource check started after
+// the pipeline was saved does not succeed.
+type ResourceCheckFailedError struct {
+	Resource string
+	Status   atc.BuildStatus
+}
+
+// Error returns a human-friendly error message.
+func (err ResourceCheckFailedError) Error() string {
+	return fmt.Sprintf("check of resource %s %s", err.Resource, err.Status)
+}
+
+// setPipelineRollback is the last known-good config of a pipeline which the
+// step re-saves if the checks made after saving the new config fail.
+type setPipelineRollback struct {
+	build    db.Build
+	ref      atc.PipelineRef
+	teamID   int
+	pipeline db.Pipeline
+	config   atc.Config
+}
+
+// watchResourceChecks polls the pipeline's resources until the window
+// elapses, returning an error as soon as a check started since the given time
+// has failed or errored.
+func watchResourceChecks(ctx context.Context, logger lager.Logger, pipeline db.Pipeline, since time.Time, window time.Duration) error {
+	deadline := time.NewTimer(window)
+	defer deadline.Stop()
+
+	for {
+		resources, err := pipeline.Resources()
+		if err != nil {
+			return err
+		}
+
+		for _, resource := range resources {
+			summary := resource.BuildSummary()
+			if summary == nil || summary.StartTime < since.Unix() {
+				continue
+			}
+
+			switch summary.Status {
+			case atc.StatusFailed, atc.StatusErrored:
+				return ResourceCheckFailedError{Resource: resource.Name(), Status: summary.Status}
+			}
+		}
+
+		select {
+		case <-time.After(rollbackPollingInterval):
+		case <-deadline.C:
+			logger.Debug("no-failed-resource-checks", lager.Data{"window": window.String()})
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// rollBackFailedCheck rolls the pipeline back because a check made after
+// saving its new config failed, if the step rolls back on error. Other errors
+// do not say anything about the new config, so they never roll it back.
+func (step *SetPipelineStep) rollBackFailedCheck(logger lager.Logger, stderr io.Writer, err error) {
+	if step.rollback == nil {
+		return
+	}
+
+	step.rollBack(logger, stderr, err.Error())
+}
+
+// rollBack re-saves the pipeline's previous config and records the decision
+// in the pipeline's rollback log.
+func (step *SetPipelineStep) rollBack(logger lager.Logger, stderr io.Writer, reason string) {
+	rollback := step.rollback
+	step.rollback = nil
+
+	failedVersion := rollback.pipeline.ConfigVersion()
+
+	_, _, err := rollback.build.SavePipeline(rollback.ref, rollback.teamID, rollback.config, failedVersion, false)
+	if err != nil {
+		logger.Error("failed-to-roll-back-pipeline", err)
+		fmt.Fprintf(stderr, "\x1b[1;33mWARNING: failed to roll back pipeline %s: %s\x1b[0m\n", rollback.ref, err)
+	} else {
+		logger.Info("rolled-back-pipeline", lager.Data{"pipeline": rollback.ref.String(), "reason": reason})
+		fmt.Fprintf(stderr, "rolled back pipeline %s to its previous config: %s\n", rollback.ref, reason)
+	}
+
+	err = rollback.pipeline.RecordRollback(db.PipelineRollback{
+		BuildID:             step.metadata.BuildID,
+		FailedConfigVersion: failedVersion,
+		Reason:              reason,
+		RolledBack:          err == nil,
+	})
+	if err != nil {
+		logger.Error("failed-to-record-rollback", err)
+	}
+}
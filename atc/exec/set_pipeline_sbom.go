@@ -0,0 +1,164 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/atc/exec/build"
+)
+
+// The artifact and path the SBOM of a pipeline is written to.
+const (
+	sbomArtifactName build.ArtifactName = "_sbom"
+	sbomPath                            = "pipeline.spdx.json"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxDataLicense = "CC0-1.0"
+	spdxNoAssertion = "NOASSERTION"
+	spdxCreator     = "Tool: concourse-set_pipeline"
+)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// pipelineSBOM returns an SPDX document listing the image of each of the
+// pipeline's resource types. An image is identified by the digest of the
+// version last checked for its resource type, or by its tag if it has not
+// been checked yet.
+func pipelineSBOM(metadata StepMetadata, team string, ref atc.PipelineRef, pipeline db.Pipeline, config atc.Config, now time.Time) (spdxDocument, error) {
+	resourceTypes, err := pipeline.ResourceTypes()
+	if err != nil {
+		return spdxDocument{}, err
+	}
+
+	versions := map[string]atc.Version{}
+	for _, resourceType := range resourceTypes {
+		versions[resourceType.Name()] = resourceType.Version()
+	}
+
+	doc := spdxDocument{
+		SPDXVersion: spdxVersion,
+		DataLicense: spdxDataLicense,
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Name:        team + "/" + ref.String(),
+		DocumentNamespace: fmt.Sprintf(
+			"%s/teams/%s/pipelines/%s/sbom/%d",
+			strings.TrimSuffix(metadata.ExternalURL, "/"),
+			url.PathEscape(team),
+			url.PathEscape(ref.String()),
+			pipeline.ConfigVersion(),
+		),
+		CreationInfo: spdxCreationInfo{
+			Created:  now.UTC().Format(time.RFC3339),
+			Creators: []string{spdxCreator},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for i, resourceType := range config.ResourceTypes {
+		pkg := spdxPackage{
+			SPDXID:           fmt.Sprintf("SPDXRef-ResourceType-%d", i+1),
+			Name:             resourceType.Name,
+			VersionInfo:      spdxNoAssertion,
+			DownloadLocation: spdxNoAssertion,
+		}
+
+		image, err := parseRegistryImage(resourceType.Source)
+		if err == nil {
+			pkg.Name = image.repository
+			pkg.VersionInfo = image.tag
+
+			if digest, ok := versions[resourceType.Name]["digest"]; ok {
+				pkg.VersionInfo = digest
+			}
+
+			pkg.ExternalRefs = []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  image.purl(pkg.VersionInfo),
+			}}
+		}
+
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: pkg.SPDXID,
+		})
+	}
+
+	return doc, nil
+}
+
+// purl returns the package URL of the given version of the image.
+func (image registryImage) purl(version string) string {
+	purl := "pkg:docker/" + image.repository + "@" + url.QueryEscape(version)
+	if image.registry != defaultImageRegistry {
+		purl += "?repository_url=" + url.QueryEscape(image.registry)
+	}
+
+	return purl
+}
+
+// writeSBOM writes the SBOM of the pipeline to the _sbom artifact.
+func (step *SetPipelineStep) writeSBOM(ctx context.Context, state RunState, team string, ref atc.PipelineRef, pipeline db.Pipeline, config atc.Config) error {
+	doc, err := pipelineSBOM(step.metadata, team, ref, pipeline, config, time.Now())
+	if err != nil {
+		return fmt.Errorf("generate sbom: %w", err)
+	}
+
+	payload, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = step.writeArtifactFile(ctx, state, sbomArtifactName, sbomPath, payload)
+	if err != nil {
+		return fmt.Errorf("write sbom: %w", err)
+	}
+
+	return nil
+}
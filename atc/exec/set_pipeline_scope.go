@@ -0,0 +1,111 @@
+package exec
+
+import (
+	"sync"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/vars"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// SetPipelineScopeBuild shares the vars resolved by a set_pipeline step with
+// the later set_pipeline steps of the build which have the same scope, so that
+// e.g. a build setting many pipelines looks each secret up only once.
+const SetPipelineScopeBuild = "build"
+
+// buildScopedVarsID is the ID under which the vars shared by set_pipeline
+// steps with `scope: build` are stored in the RunState, whose results are
+// shared by the whole build.
+const buildScopedVarsID atc.PlanID = "set_pipeline.scope.build"
+
+// buildScopedVarsLock guards the creation of the shared vars, so that steps
+// running in parallel do not each create their own.
+var buildScopedVarsLock sync.Mutex
+
+// scopedVars holds the credential manager vars and Vault secrets resolved by
+// the set_pipeline steps of a scope.
+type scopedVars struct {
+	lock    sync.Mutex
+	creds   map[string]interface{}
+	secrets map[string]*vaultapi.Secret
+}
+
+// buildScopedVars returns the vars shared by the set_pipeline steps of the
+// build, creating them for the first step.
+func buildScopedVars(state RunState) *scopedVars {
+	buildScopedVarsLock.Lock()
+	defer buildScopedVarsLock.Unlock()
+
+	var scoped *scopedVars
+	if state.Result(buildScopedVarsID, &scoped) {
+		return scoped
+	}
+
+	scoped = &scopedVars{
+		creds:   map[string]interface{}{},
+		secrets: map[string]*vaultapi.Secret{},
+	}
+
+	state.StoreResult(buildScopedVarsID, scoped)
+
+	return scoped
+}
+
+// readVaultSecret returns the secret at the path, reading it with the reader
+// only if no step of the scope has read it yet.
+func (scoped *scopedVars) readVaultSecret(reader VaultSecretReader, path string) (*vaultapi.Secret, error) {
+	scoped.lock.Lock()
+	secret, found := scoped.secrets[path]
+	scoped.lock.Unlock()
+
+	if found {
+		return secret, nil
+	}
+
+	secret, err := reader.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped.lock.Lock()
+	scoped.secrets[path] = secret
+	scoped.lock.Unlock()
+
+	return secret, nil
+}
+
+// scopedVariables looks vars up through the build's variables, remembering
+// the values found in the scope. Local vars, e.g. those set by load_var steps,
+// are always looked up afresh as they are not secrets.
+type scopedVariables struct {
+	vars.Variables
+
+	scoped *scopedVars
+}
+
+func (v scopedVariables) Get(ref vars.Reference) (interface{}, bool, error) {
+	if ref.Source == "." {
+		return v.Variables.Get(ref)
+	}
+
+	key := ref.String()
+
+	v.scoped.lock.Lock()
+	val, found := v.scoped.creds[key]
+	v.scoped.lock.Unlock()
+
+	if found {
+		return val, true, nil
+	}
+
+	val, found, err := v.Variables.Get(ref)
+	if err != nil || !found {
+		return val, found, err
+	}
+
+	v.scoped.lock.Lock()
+	v.scoped.creds[key] = val
+	v.scoped.lock.Unlock()
+
+	return val, true, nil
+}
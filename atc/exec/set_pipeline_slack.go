@@ -0,0 +1,96 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+type slackMessage struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+// slackNotification describes a pipeline that has been set by a
+// set_pipeline step, along with the changes made to its config.
+type slackNotification struct {
+	Team          string
+	Pipeline      string
+	ConfigVersion db.ConfigVersion
+	JobDiffs      atc.Diffs
+	ResourceDiffs atc.Diffs
+}
+
+func (n slackNotification) text() string {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "pipeline *%s* of team *%s* has been set (config version %d)", n.Pipeline, n.Team, n.ConfigVersion)
+
+	for _, section := range []struct {
+		label string
+		diffs atc.Diffs
+	}{
+		{"jobs", n.JobDiffs},
+		{"resources", n.ResourceDiffs},
+	} {
+		if len(section.diffs) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&text, "\n%s:", section.label)
+
+		for _, diff := range section.diffs {
+			fmt.Fprintf(&text, "\n• %s %s", diff.Name(), diffAction(diff))
+		}
+	}
+
+	return text.String()
+}
+
+func diffAction(diff atc.Diff) string {
+	switch {
+	case diff.Before == nil:
+		return "added"
+	case diff.After == nil:
+		return "removed"
+	default:
+		return "changed"
+	}
+}
+
+// notifySlack posts the notification to the Slack incoming webhook.
+func notifySlack(ctx context.Context, webhook string, channel string, notification slackNotification) error {
+	payload, err := json.Marshal(slackMessage{
+		Channel: channel,
+		Text:    notification.text(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhook, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := setPipelineHTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from slack: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/exec/artifact"
+	"github.com/concourse/concourse/atc/exec/build"
+	"github.com/concourse/concourse/atc/runtime"
+	"github.com/concourse/concourse/atc/runtime/runtimefakes"
+	"github.com/concourse/concourse/atc/worker/workerfakes"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setPipelineSource", func() {
+	Describe("Validate", func() {
+		var source setPipelineSource
+
+		BeforeEach(func() {
+			source = setPipelineSource{
+				step: &SetPipelineStep{
+					plan: atc.SetPipelinePlan{
+						Name: "some-pipeline",
+						File: "some-resource/pipeline.yml",
+					},
+				},
+			}
+		})
+
+		It("passes when the file is specified", func() {
+			Expect(source.Validate()).To(Succeed())
+		})
+
+		It("returns 'file is not specified' when the file is empty", func() {
+			source.step.plan.File = ""
+
+			Expect(source.Validate()).To(MatchError("file is not specified"))
+		})
+	})
+
+	Describe("fetchPipelineBits", func() {
+		var (
+			source               setPipelineSource
+			fakeArtifactStreamer *workerfakes.FakeArtifactStreamer
+			stderr               *gbytes.Buffer
+
+			originalInterval time.Duration
+		)
+
+		BeforeEach(func() {
+			originalInterval = fetchTimeoutRetryInterval
+			fetchTimeoutRetryInterval = time.Millisecond
+
+			fakeArtifactStreamer = new(workerfakes.FakeArtifactStreamer)
+			stderr = gbytes.NewBuffer()
+
+			repo := build.NewRepository()
+			repo.RegisterArtifact("some-resource", new(runtimefakes.FakeArtifact))
+
+			source = setPipelineSource{
+				ctx:              context.Background(),
+				logger:           lagertest.NewTestLogger("test"),
+				repo:             repo,
+				step:             &SetPipelineStep{},
+				artifactStreamer: fakeArtifactStreamer,
+				stderr:           stderr,
+			}
+		})
+
+		AfterEach(func() {
+			fetchTimeoutRetryInterval = originalInterval
+		})
+
+		Context("when streaming times out", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(context.Context, runtime.Artifact, string) (io.ReadCloser, error) {
+					if fakeArtifactStreamer.StreamFileFromArtifactCallCount() < 3 {
+						return nil, timeoutError{}
+					}
+
+					return ioutil.NopCloser(strings.NewReader("some-content")), nil
+				}
+			})
+
+			It("retries until streaming succeeds", func() {
+				bits, err := source.fetchPipelineBits("some-resource/pipeline.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(bits)).To(Equal("some-content"))
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(3))
+			})
+
+			It("warns about each retry", func() {
+				_, err := source.fetchPipelineBits("some-resource/pipeline.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stderr).To(gbytes.Say("WARNING: fetching some-resource/pipeline.yml timed out \\(attempt 1 of 4\\), retrying"))
+				Expect(stderr).To(gbytes.Say("WARNING: fetching some-resource/pipeline.yml timed out \\(attempt 2 of 4\\), retrying"))
+			})
+		})
+
+		Context("when streaming keeps timing out", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(nil, timeoutError{})
+			})
+
+			It("gives up after the max retries", func() {
+				_, err := source.fetchPipelineBits("some-resource/pipeline.yml")
+				Expect(err).To(Equal(timeoutError{}))
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(maxFetchTimeoutRetries + 1))
+			})
+		})
+
+		Context("when the file is not found", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(nil, baggageclaim.ErrFileNotFound)
+			})
+
+			It("does not retry", func() {
+				_, err := source.fetchPipelineBits("some-resource/pipeline.yml")
+				Expect(err).To(Equal(artifact.FileNotFoundError{Name: "some-resource", FilePath: "pipeline.yml"}))
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when streaming fails with a non-timeout error", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(nil, disaster)
+			})
+
+			It("does not retry", func() {
+				_, err := source.fetchPipelineBits("some-resource/pipeline.yml")
+				Expect(err).To(Equal(disaster))
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+				Expect(stderr.Contents()).To(BeEmpty())
+			})
+		})
+	})
+})
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
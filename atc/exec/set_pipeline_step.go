@@ -1,12 +1,22 @@
 package exec
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
+	"net"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagerctx"
@@ -17,16 +27,81 @@ import (
 	"github.com/concourse/concourse/atc/configvalidate"
 	"github.com/concourse/concourse/atc/creds"
 	"github.com/concourse/concourse/atc/db"
+	"github.com/concourse/concourse/atc/eventbus"
 	"github.com/concourse/concourse/atc/exec/artifact"
 	"github.com/concourse/concourse/atc/exec/build"
+	"github.com/concourse/concourse/atc/metric"
 	"github.com/concourse/concourse/atc/policy"
+	"github.com/concourse/concourse/atc/runtime"
 	"github.com/concourse/concourse/atc/worker"
 	"github.com/concourse/concourse/tracing"
 	"github.com/concourse/concourse/vars"
+	"go.opentelemetry.io/otel/api/trace"
 )
 
 const ActionRunSetPipeline = "SetPipeline"
 
+// defaultSetPipelineTimeout is the deadline for a set_pipeline step which
+// does not configure a timeout.
+var defaultSetPipelineTimeout = 10 * time.Minute
+
+// ErrSetPipelineStepDisabled is returned by every set_pipeline step when the
+// operator has disabled them with --disable-set-pipeline-step.
+var ErrSetPipelineStepDisabled = errors.New("set_pipeline step is disabled by the ATC operator")
+
+type StepTimeoutError struct {
+	Duration time.Duration
+}
+
+// Error returns a human-friendly error message.
+func (err StepTimeoutError) Error() string {
+	return fmt.Sprintf("step timed out after %s", err.Duration)
+}
+
+// maxFetchTimeoutRetries is how many times a set_pipeline step retries
+// streaming a file from an artifact which timed out.
+const maxFetchTimeoutRetries = 3
+
+// fetchTimeoutRetryInterval is the delay before the first retry of a timed
+// out fetch. It doubles with each retry.
+var fetchTimeoutRetryInterval = time.Second
+
+// maxConfigVersionMismatchRetries is how many times a set_pipeline step runs
+// again when the pipeline's config was changed while it was being set.
+const maxConfigVersionMismatchRetries = 3
+
+// ConfigVersionMismatchError is returned when a pipeline's config was changed
+// by someone else between the step fetching it and saving the new config.
+type ConfigVersionMismatchError struct {
+	Team            string
+	Pipeline        string
+	ExpectedVersion db.ConfigVersion
+	ActualVersion   db.ConfigVersion
+}
+
+// Error returns a human-friendly error message.
+func (err ConfigVersionMismatchError) Error() string {
+	return fmt.Sprintf(
+		"pipeline %s of team %s was changed concurrently: expected config version %d, but it is at version %d",
+		err.Pipeline,
+		err.Team,
+		err.ExpectedVersion,
+		err.ActualVersion,
+	)
+}
+
+// SetPipelineResult describes the pipeline set by a set_pipeline step. It is
+// stored in the RunState under the step's plan ID.
+type SetPipelineResult struct {
+	PipelineName  string
+	TeamName      string
+	ConfigVersion db.ConfigVersion
+	HadDiff       bool
+
+	// SavedAt is zero if there were no changes to save.
+	SavedAt time.Time
+}
+
 // SetPipelineStep sets a pipeline to current team. This step takes pipeline
 // configure file and var files from some resource in the pipeline, like git.
 type SetPipelineStep struct {
@@ -38,6 +113,27 @@ type SetPipelineStep struct {
 	buildFactory     db.BuildFactory
 	artifactStreamer worker.ArtifactStreamer
 	policyChecker    policy.Checker
+	maxVarFiles      int
+	fileCache        *SetPipelineFileCache
+	limiter          *SetPipelineLimiter
+	configMapFetcher ConfigMapFetcher
+	linter           PipelineLinter
+	artifactWriter   ArtifactFileWriter
+
+	vaultSecretReader          VaultSecretReader
+	eventPublisher             eventbus.Publisher
+	credentialCacheInvalidator CredentialCacheInvalidator
+
+	scopedVars        *scopedVars
+	createdPipeline   db.Pipeline
+	rollback          *setPipelineRollback
+	deadline          *setPipelineDeadline
+	result            *SetPipelineResult
+	streamedArtifacts []atc.SetPipelineArtifact
+
+	// streamedArtifactsLock guards streamedArtifacts while var files are
+	// prefetched concurrently
+	streamedArtifactsLock sync.Mutex
 }
 
 func NewSetPipelineStep(
@@ -49,6 +145,15 @@ func NewSetPipelineStep(
 	buildFactory db.BuildFactory,
 	artifactStreamer worker.ArtifactStreamer,
 	policyChecker policy.Checker,
+	maxVarFiles int,
+	fileCache *SetPipelineFileCache,
+	limiter *SetPipelineLimiter,
+	configMapFetcher ConfigMapFetcher,
+	linter PipelineLinter,
+	artifactWriter ArtifactFileWriter,
+	vaultSecretReader VaultSecretReader,
+	eventPublisher eventbus.Publisher,
+	credentialCacheInvalidator CredentialCacheInvalidator,
 ) Step {
 	return &SetPipelineStep{
 		planID:           planID,
@@ -59,36 +164,135 @@ func NewSetPipelineStep(
 		buildFactory:     buildFactory,
 		artifactStreamer: artifactStreamer,
 		policyChecker:    policyChecker,
+		maxVarFiles:      maxVarFiles,
+		fileCache:        fileCache,
+		limiter:          limiter,
+		configMapFetcher: configMapFetcher,
+		linter:           linter,
+		artifactWriter:   artifactWriter,
+
+		vaultSecretReader:          vaultSecretReader,
+		eventPublisher:             eventPublisher,
+		credentialCacheInvalidator: credentialCacheInvalidator,
 	}
 }
 
 func (step *SetPipelineStep) Run(ctx context.Context, state RunState) (bool, error) {
+	if atc.DisableSetPipelineStep {
+		return false, ErrSetPipelineStepDisabled
+	}
+
+	if step.plan.Name == "" {
+		return false, errors.New("set_pipeline: name is required")
+	}
+
+	attrs := tracing.Attrs{
+		"name":              step.plan.Name,
+		"set_pipeline.file": step.plan.File,
+	}
+
+	if len(step.plan.VarFiles) > 0 {
+		attrs["set_pipeline.var_file_count"] = strconv.Itoa(len(step.plan.VarFiles))
+	}
+
 	delegate := step.delegateFactory.SetPipelineStepDelegate(state)
-	ctx, span := delegate.StartSpan(ctx, "set_pipeline", tracing.Attrs{
-		"name": step.plan.Name,
-	})
+	ctx, span := delegate.StartSpan(ctx, "set_pipeline", attrs)
+
+	ctx = lagerctx.NewContext(ctx, tracing.LoggerWithSpan(lagerctx.FromContext(ctx), ctx))
+
+	timeout := defaultSetPipelineTimeout
+	if step.plan.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(step.plan.Timeout)
+		if err != nil {
+			err = fmt.Errorf("parse timeout: %w", err)
+			tracing.End(span, err)
+			return false, err
+		}
+	}
+
+	// a stalled artifact stream must not hang the build, so the context is
+	// cancelled once the deadline passes regardless of what the step is doing
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	step.deadline = newSetPipelineDeadline(timeout, cancel)
 
 	ok, err := step.run(ctx, state, delegate)
+
+	if !step.deadline.Stop() && err != nil {
+		err = StepTimeoutError{Duration: timeout}
+		delegate.Errored(lagerctx.FromContext(ctx), err.Error())
+	}
+
 	tracing.End(span, err)
 
 	return ok, err
 }
 
 func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate SetPipelineStepDelegate) (bool, error) {
+	usageBefore := readResourceUsage()
+
+	span := trace.SpanFromContext(ctx)
+
 	logger := lagerctx.FromContext(ctx)
 	logger = logger.Session("set-pipeline-step", lager.Data{
 		"step-name": step.plan.Name,
 		"job-id":    step.metadata.JobID,
+		"build-id":  step.metadata.BuildID,
 	})
 
 	delegate.Initializing(logger)
 
-	interpolatedPlan, err := creds.NewSetPipelinePlan(state, step.plan).Evaluate()
+	releaseSlot := func() {}
+	if step.limiter != nil {
+		// waiting is bounded by the step's timeout, which cancels the context
+		release, err := step.limiter.Acquire(ctx, step.metadata.TeamID)
+		if err != nil {
+			return false, fmt.Errorf("wait for set_pipeline slot: %w", err)
+		}
+
+		// the slot is released as soon as the pipeline has been saved, so
+		// that waiting on its builds does not hold up the team's other
+		// set_pipeline steps
+		var once sync.Once
+		releaseSlot = func() { once.Do(release) }
+		defer releaseSlot()
+	}
+
+	if step.plan.When != "" {
+		run, err := evaluateWhen(step.plan.When, state)
+		if err != nil {
+			return false, err
+		}
+
+		if !run {
+			logger.Debug("skipping-due-to-when-expression", lager.Data{"when": step.plan.When})
+			fmt.Fprintf(delegate.Stdout(), "skipping: when expression is false: %s\n", step.plan.When)
+			delegate.Finished(logger, true)
+			return true, nil
+		}
+	}
+
+	var variables vars.Variables = state
+	if step.plan.Scope == SetPipelineScopeBuild {
+		step.scopedVars = buildScopedVars(state)
+		variables = scopedVariables{Variables: state, scoped: step.scopedVars}
+	}
+
+	interpolatedPlan, err := creds.NewSetPipelinePlan(variables, step.plan).Evaluate()
 	if err != nil {
 		return false, err
 	}
 	step.plan = interpolatedPlan
 
+	if step.plan.MinATCVersion != "" {
+		err = checkMinATCVersion(step.plan.MinATCVersion)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	stdout := delegate.Stdout()
 	stderr := delegate.Stderr()
 
@@ -97,6 +301,22 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 	fmt.Fprintln(stderr, "\x1b[33mfollow RFC #31 for updates: https://github.com/concourse/rfcs/pull/31\x1b[0m")
 	fmt.Fprintln(stderr, "")
 
+	if step.plan.TeamVar != "" {
+		if step.plan.Team != "" {
+			return false, errors.New("`team` and `team_var` can not both be specified")
+		}
+
+		teamName, found, err := state.Get(vars.Reference{Source: ".", Path: step.plan.TeamVar})
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return false, fmt.Errorf("undefined team var: %s", step.plan.TeamVar)
+		}
+
+		step.plan.Team = fmt.Sprint(teamName)
+	}
+
 	if step.plan.Name == "self" {
 		fmt.Fprintln(stderr, "\x1b[1;33mWARNING: 'set_pipeline: self' is experimental and may be removed in the future!\x1b[0m")
 		fmt.Fprintln(stderr, "")
@@ -109,12 +329,41 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 		step.plan.Team = ""
 	}
 
+	if step.plan.NamePattern != "" {
+		err = checkNamePattern(step.plan.Name, step.plan.NamePattern)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	step.plan.File, err = expandFilePath(step.plan.File, step.metadata, state)
+	if err != nil {
+		return false, err
+	}
+
+	if step.plan.File == AutoConfigFile {
+		step.plan.File, err = resolveAutoConfigFile(state.ArtifactRepository())
+		if err != nil {
+			return false, err
+		}
+	}
+
 	source := setPipelineSource{
 		ctx:              ctx,
 		logger:           logger,
 		step:             step,
 		repo:             state.ArtifactRepository(),
 		artifactStreamer: step.artifactStreamer,
+		stderr:           stderr,
+		progress: func(message string) {
+			delegate.SetPipelineProgress(logger, message)
+		},
+		varFileFetched: func(fetch VarFileFetch) {
+			delegate.VarFileFetched(logger, fetch)
+		},
+		fetchImage: func(image atc.ImageResource) (worker.ImageSpec, error) {
+			return delegate.FetchImage(ctx, image, nil, false)
+		},
 	}
 
 	err = source.Validate()
@@ -122,22 +371,48 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 		return false, err
 	}
 
+	span.AddEvent(ctx, "fetching_config")
+
 	atcConfig, err := source.FetchPipelineConfig()
 	if err != nil {
 		return false, err
 	}
 
+	err = step.recordStreamedArtifacts()
+	if err != nil {
+		return false, err
+	}
+
+	if step.plan.Extends != "" {
+		atcConfig, err = step.extendPipeline(atcConfig)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if step.plan.CloneFrom != "" {
+		atcConfig, err = step.clonePipeline(atcConfig)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	delegate.Starting(logger)
 
-	warnings, errors := configvalidate.Validate(atcConfig)
+	span.AddEvent(ctx, "validating_config")
+	delegate.SetPipelineProgress(logger, "validating config")
+
+	warnings, errorMessages := configvalidate.Validate(atcConfig)
 	for _, warning := range warnings {
 		fmt.Fprintf(stderr, "WARNING: %s\n", warning.Message)
 	}
 
-	if len(errors) > 0 {
+	step.emitValidationWarnings(logger, warnings)
+
+	if len(errorMessages) > 0 {
 		fmt.Fprintln(delegate.Stderr(), "invalid pipeline:")
 
-		for _, e := range errors {
+		for _, e := range errorMessages {
 			fmt.Fprintf(stderr, "- %s", e)
 		}
 
@@ -145,6 +420,46 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 		return false, nil
 	}
 
+	if step.plan.FeatureFlagCheck {
+		err = checkFeatureFlags(atcConfig)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if len(atcConfig.Jobs) < step.plan.MinJobs {
+		return false, fmt.Errorf("pipeline config has %d jobs, fewer than the %d required by min_jobs", len(atcConfig.Jobs), step.plan.MinJobs)
+	}
+
+	if step.plan.MaxParallelJobs > 0 {
+		parallelism := pipelineParallelism(atcConfig)
+		if parallelism > step.plan.MaxParallelJobs {
+			return false, MaxParallelJobsExceededError{
+				Parallelism: parallelism,
+				Max:         step.plan.MaxParallelJobs,
+			}
+		}
+	}
+
+	if setsItself(step.plan.Name, atcConfig) {
+		fmt.Fprintf(stderr, "WARNING: pipeline '%s' contains a step that sets itself; ensure this does not cause infinite loops\n", step.plan.Name)
+	}
+
+	if !step.plan.SkipReachabilityCheck {
+		for _, warning := range checkResourceReachability(ctx, atcConfig) {
+			fmt.Fprintf(stderr, "WARNING: %s\n", warning)
+		}
+	}
+
+	if step.plan.Target != nil {
+		err = step.checkPolicy(logger, step.plan.Target.TeamName(), &atcConfig)
+		if err != nil {
+			return false, err
+		}
+
+		return step.setRemotePipeline(logger, state, delegate, atcConfig)
+	}
+
 	var team db.Team
 	if step.plan.Team == "" {
 		team = step.teamFactory.GetByID(step.metadata.TeamID)
@@ -159,7 +474,7 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 			return false, err
 		}
 		if !found {
-			return false, fmt.Errorf("team %s not found", step.metadata.TeamName)
+			return false, TeamNotFoundError{Name: step.metadata.TeamName}
 		}
 
 		targetTeam, found, err := step.teamFactory.FindTeam(step.plan.Team)
@@ -167,21 +482,21 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 			return false, err
 		}
 		if !found {
-			return false, fmt.Errorf("team %s not found", step.plan.Team)
-		}
+			if !step.plan.CreateTeamIfMissing {
+				return false, TeamNotFoundError{Name: step.plan.Team}
+			}
 
-		permitted := false
-		if targetTeam.ID() == currentTeam.ID() {
-			permitted = true
-		}
-		if currentTeam.Admin() {
-			permitted = true
+			targetTeam, err = step.createMissingTeam(currentTeam, step.plan.Team, stdout)
+			if err != nil {
+				return false, err
+			}
 		}
-		if !permitted {
-			return false, fmt.Errorf(
-				"only %s team can set another team's pipeline",
-				atc.DefaultTeamName,
-			)
+
+		if !canSetPipelines(currentTeam, targetTeam) {
+			return false, PermissionDeniedError{
+				Team:       currentTeam.Name(),
+				TargetTeam: targetTeam.Name(),
+			}
 		}
 
 		team = targetTeam
@@ -191,6 +506,56 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 		Name:         step.plan.Name,
 		InstanceVars: step.plan.InstanceVars,
 	}
+
+	if step.plan.RenameFrom != "" {
+		err = step.renamePipeline(logger, team, pipelineRef, stdout)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	collisions, err := jobNameCollisions(team, pipelineRef, atcConfig)
+	if err != nil {
+		return false, err
+	}
+
+	for _, warning := range collisions {
+		fmt.Fprintf(stderr, "WARNING: %s\n", warning)
+	}
+
+	for attempt := 1; ; attempt++ {
+		ok, err := step.savePipeline(ctx, logger, state, delegate, source, team, pipelineRef, atcConfig, releaseSlot, usageBefore)
+
+		var mismatch ConfigVersionMismatchError
+		if !errors.As(err, &mismatch) || attempt > maxConfigVersionMismatchRetries {
+			return ok, err
+		}
+
+		fmt.Fprintf(stderr, "\x1b[1;33mWARNING: %s; retrying (attempt %d of %d)\x1b[0m\n", mismatch, attempt, maxConfigVersionMismatchRetries)
+	}
+}
+
+// savePipeline diffs the config against the pipeline's current config and
+// saves it, then applies everything which depends on the saved pipeline. It
+// returns a ConfigVersionMismatchError if the pipeline was changed while the
+// config was being diffed, in which case it can be run again on its own, as
+// nothing before the save has any lasting effect.
+func (step *SetPipelineStep) savePipeline(
+	ctx context.Context,
+	logger lager.Logger,
+	state RunState,
+	delegate SetPipelineStepDelegate,
+	source setPipelineSource,
+	team db.Team,
+	pipelineRef atc.PipelineRef,
+	atcConfig atc.Config,
+	releaseSlot func(),
+	usageBefore resourceUsage,
+) (bool, error) {
+	span := trace.SpanFromContext(ctx)
+	stdout := delegate.Stdout()
+	stderr := delegate.Stderr()
+
 	pipeline, found, err := team.Pipeline(pipelineRef)
 	if err != nil {
 		return false, err
@@ -208,10 +573,123 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 		}
 	}
 
-	diffExists := existingConfig.Diff(stdout, atcConfig)
+	if step.plan.MergeStrategy != nil {
+		atcConfig = existingConfig.MergeWith(atcConfig, *step.plan.MergeStrategy)
+
+		_, errorMessages := configvalidate.Validate(atcConfig)
+		if len(errorMessages) > 0 {
+			return false, fmt.Errorf("invalid pipeline after merging: %s", strings.Join(errorMessages, "; "))
+		}
+	}
+
+	if len(step.plan.ApplyJobs) > 0 || len(step.plan.ApplyResources) > 0 {
+		// partial applies are made on top of each other, each taking what it
+		// applies from the new config, and the result is validated as a whole
+		// so that e.g. a job and the resource only it uses can be removed
+		// together
+		newConfig := atcConfig
+		atcConfig = existingConfig
+
+		if len(step.plan.ApplyJobs) > 0 {
+			atcConfig, err = applyJobs(atcConfig, newConfig, step.plan.ApplyJobs)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		if len(step.plan.ApplyResources) > 0 {
+			atcConfig, err = applyResources(atcConfig, newConfig, step.plan.ApplyResources)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		_, errorMessages := configvalidate.Validate(atcConfig)
+		if len(errorMessages) > 0 {
+			return false, fmt.Errorf("invalid pipeline after partial apply: %s", strings.Join(errorMessages, "; "))
+		}
+	}
+
+	if step.plan.LockResourceTypeVersions {
+		if !found {
+			fmt.Fprintln(stdout, "not locking resource type versions of a new pipeline")
+		} else {
+			var unlocked []string
+			atcConfig, unlocked, err = lockResourceTypeVersions(logger, pipeline, atcConfig)
+			if err != nil {
+				return false, err
+			}
+
+			if len(unlocked) > 0 {
+				fmt.Fprintf(stdout, "not locking resource types without a checked version: %s\n", strings.Join(unlocked, ", "))
+			}
+		}
+	}
+
+	// values are encrypted before diffing so that secrets never show up in
+	// the diff, and unchanged ones are not reported as changed
+	if len(step.plan.EncryptFields) > 0 {
+		atcConfig, err = encryptFields(atcConfig, existingConfig, step.plan.EncryptFields, team)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	span.AddEvent(ctx, "computing_diff")
+	delegate.SetPipelineProgress(logger, "computing diff")
+
+	configHash, err := db.ConfigHash(atcConfig)
+	if err != nil {
+		return false, err
+	}
+
+	var diffExists bool
+	var diffOutput bytes.Buffer
+	// an archived pipeline has to be saved to be unarchived, even if its
+	// config is unchanged
+	if found && !pipeline.Archived() && pipeline.ConfigHash() == configHash {
+		logger.Debug("config-hash-unchanged")
+	} else {
+		// the display override is applied after saving, so it is compared
+		// with the existing config but is not part of the saved config
+		diffConfig := atcConfig
+		if step.plan.Display != nil {
+			diffConfig.Display = step.plan.Display
+		}
+
+		diffExists = diffConfigs(stdout, step.plan.DiffFormat, existingConfig, diffConfig)
+
+		if step.plan.DiffOutput != "" {
+			existingConfig.UnifiedDiff(&diffOutput, diffConfig)
+		}
+
+		if diffExists {
+			metric.SetPipelineDiffSize{
+				Team:             team.Name(),
+				Pipeline:         pipelineRef.String(),
+				ChangedJobs:      len(existingConfig.JobDiffs(diffConfig)),
+				ChangedResources: len(existingConfig.ResourceDiffs(diffConfig)),
+			}.Emit(logger)
+		}
+	}
+
+	if step.plan.DiffOutput != "" {
+		// the file is written even if there are no changes, so that later
+		// steps can always rely on it
+		err = step.writeDiffOutput(ctx, state, diffOutput.Bytes())
+		if err != nil {
+			return false, err
+		}
+	}
+
 	if !diffExists {
 		logger.Debug("no-diff")
 
+		metric.SetPipelineNoDiff{
+			Team:     team.Name(),
+			Pipeline: pipelineRef.String(),
+		}.Emit(logger)
+
 		fmt.Fprintf(stdout, "no changes to apply.\n")
 
 		if found {
@@ -219,29 +697,109 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 			if err != nil {
 				return false, err
 			}
+
+			if pipeline.Frozen() != step.plan.Freeze {
+				err = pipeline.SetFrozen(step.plan.Freeze)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			if pipeline.Description() != step.plan.Description {
+				err = pipeline.SetDescription(step.plan.Description)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			err = step.updatePipelineSettings(pipeline)
+			if err != nil {
+				return false, err
+			}
+
+			// the display override is not part of the config hash, so it can
+			// change without the config changing
+			if step.plan.Display != nil && !reflect.DeepEqual(existingConfig.Display, step.plan.Display) {
+				err = pipeline.UpdateDisplay(*step.plan.Display)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			// the config being unchanged does not mean the versions to pin
+			// are, e.g. when deploying a new version of the same config
+			if step.plan.InheritPinnedVersions || len(step.plan.PinBuildInputs) > 0 {
+				parentBuild, found, err := step.buildFactory.Build(step.metadata.BuildID)
+				if err != nil {
+					return false, err
+				}
+
+				if !found {
+					return false, fmt.Errorf("set_pipeline step not attached to a buildID")
+				}
+
+				err = step.pinVersions(logger, parentBuild, pipeline)
+				if err != nil {
+					return false, err
+				}
+			}
+		}
+
+		if found && step.plan.ArchiveOld {
+			err := step.archiveRenamedPipeline(logger, team, pipeline, stdout)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		if step.plan.ArchiveUnlisted {
+			step.archiveUnlistedPipelines(logger, state, team, stdout)
+		}
+
+		result := SetPipelineResult{
+			PipelineName: step.plan.Name,
+			TeamName:     team.Name(),
+			HadDiff:      false,
+		}
+		if found {
+			result.ConfigVersion = pipeline.ConfigVersion()
 		}
+		step.storeResult(state, result)
 
 		delegate.SetPipelineChanged(logger, false)
 		delegate.Finished(logger, true)
 		return true, nil
 	}
 
-	// conditionally check step
-	if step.policyChecker != nil && step.policyChecker.ShouldCheckAction(ActionRunSetPipeline) {
-		input := policy.PolicyCheckInput{
-			Action:   ActionRunSetPipeline,
-			Team:     team.Name(),
-			Pipeline: step.plan.Name,
-			Data:     &atcConfig,
+	err = step.checkPolicy(logger, team.Name(), &atcConfig)
+	if err != nil {
+		return false, err
+	}
+
+	if step.plan.LintImage != "" {
+		ok, err := step.lintPipeline(source, delegate, atcConfig, stdout, stderr)
+		if err != nil {
+			return false, err
 		}
-		result, err := step.policyChecker.Check(input)
+
+		if !ok {
+			delegate.Finished(logger, false)
+			return false, nil
+		}
+	}
+
+	for _, hook := range setPipelineHooks {
+		err := hook.BeforeSave(atcConfig)
 		if err != nil {
-			return false, fmt.Errorf("error checking policy enforcement")
+			return false, err
 		}
-		if !result.Allowed {
-			return false, fmt.Errorf("policy check failed for set_pipeline: %s", strings.Join(result.Reasons, ", "))
+	}
+
+	if step.plan.ForceCredentialRefresh {
+		err = step.refreshCredentials(logger, team.Name(), pipelineRef.Name, atcConfig)
+		if err != nil {
+			return false, err
 		}
-		logger.Debug("policy check passed for set_pipeline")
 	}
 
 	fmt.Fprintf(stdout, "setting pipeline: %s\n", pipelineRef.String())
@@ -256,21 +814,774 @@ func (step *SetPipelineStep) run(ctx context.Context, state RunState, delegate S
 		return false, fmt.Errorf("set_pipeline step not attached to a buildID")
 	}
 
-	pipeline, _, err = parentBuild.SavePipeline(pipelineRef, team.ID(), atcConfig, fromVersion, false)
-	if err != nil {
-		if err == db.ErrSetByNewerBuild {
-			fmt.Fprintln(stderr, "\x1b[1;33mWARNING: the pipeline was not saved because it was already saved by a newer build\x1b[0m")
-			delegate.Finished(logger, true)
-			return true, nil
-		}
-		return false, err
+	span.AddEvent(ctx, "saving_pipeline")
+
+	pipeline, created, err := parentBuild.SavePipeline(pipelineRef, team.ID(), atcConfig, fromVersion, false)
+	if err == db.ErrConfigComparisonFailed {
+		// the config was computed from, and diffed and checked against, the
+		// config which has just been replaced, so the step has to be run
+		// again rather than just saved again
+		mismatch, err := configVersionMismatch(team, pipelineRef, fromVersion)
+		if err != nil {
+			return false, err
+		}
+
+		logger.Info("config-version-mismatch", lager.Data{
+			"team":             mismatch.Team,
+			"pipeline":         mismatch.Pipeline,
+			"expected-version": mismatch.ExpectedVersion,
+			"actual-version":   mismatch.ActualVersion,
+		})
+
+		return false, mismatch
+	}
+
+	// the slot is kept across a retry after a config version mismatch
+	releaseSlot()
+
+	if err != nil {
+		if err == db.ErrSetByNewerBuild {
+			fmt.Fprintln(stderr, "\x1b[1;33mWARNING: the pipeline was not saved because it was already saved by a newer build\x1b[0m")
+			delegate.Finished(logger, true)
+			return true, nil
+		}
+		if err == db.ErrReadOnlyMode {
+			fmt.Fprintln(stderr, "ATC is in read-only mode; pipeline changes are not permitted")
+			delegate.Finished(logger, false)
+			return false, nil
+		}
+		return false, err
+	}
+
+	savedAt := time.Now()
+
+	delegate.SetBuildAnnotation(logger, "set_pipeline_version", strconv.Itoa(int(pipeline.ConfigVersion())))
+
+	usage := readResourceUsage().since(usageBefore)
+	metric.SetPipelineResourceUsage{
+		Team:       team.Name(),
+		Pipeline:   pipeline.Name(),
+		Goroutines: usage.goroutines,
+		HeapAllocs: usage.heapAllocs,
+		GCPause:    usage.gcPause,
+	}.Emit(logger)
+
+	configBytes, err := json.Marshal(atcConfig)
+	if err == nil {
+		metric.SetPipelineConfigBytes{
+			Team:     team.Name(),
+			Pipeline: pipelineRef.String(),
+			Bytes:    len(configBytes),
+		}.Emit(logger)
+	}
+
+	if !created && step.plan.RollbackOnError {
+		step.rollback = &setPipelineRollback{
+			build:    parentBuild,
+			ref:      pipelineRef,
+			teamID:   team.ID(),
+			pipeline: pipeline,
+			config:   existingConfig,
+		}
+	}
+
+	if created && step.plan.CleanupOnFailure {
+		step.createdPipeline = pipeline
+		state.RegisterAbortable(step)
+	}
+
+	for _, hook := range setPipelineHooks {
+		err := hook.AfterSave(atcConfig, pipeline)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = step.pinVersions(logger, parentBuild, pipeline)
+	if err != nil {
+		return false, err
+	}
+
+	if !created && step.plan.ResetBuildHistory {
+		err = pipeline.ResetBuildHistory()
+		if err != nil {
+			return false, err
+		}
+
+		logger.Info("reset-build-history")
+		fmt.Fprintf(stdout, "reset build history\n")
+	}
+
+	err = step.updatePipelineSettings(pipeline)
+	if err != nil {
+		return false, err
+	}
+
+	if step.plan.NotifyEventBus {
+		err = step.publishPipelineUpdated(ctx, team, pipeline, created, existingConfig, atcConfig)
+		if err != nil {
+			logger.Error("failed-to-publish-pipeline-updated", err)
+			fmt.Fprintf(stderr, "WARNING: failed to notify the event bus: %s\n", err)
+		}
+	}
+
+	if step.plan.CheckImageResourceTypes {
+		warnings, err := imageResourceTypeWarnings(atcConfig, pipeline, team)
+		if err != nil {
+			return false, err
+		}
+
+		for _, warning := range warnings {
+			fmt.Fprintf(stderr, "WARNING: %s\n", warning)
+		}
+	}
+
+	if step.plan.CheckImageTags {
+		for _, warning := range checkImageTags(ctx, state, atcConfig) {
+			fmt.Fprintf(stderr, "WARNING: %s\n", warning)
+		}
+	}
+
+	if step.plan.SBOM {
+		err = step.writeSBOM(ctx, state, team.Name(), pipelineRef, pipeline, atcConfig)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if pipeline.Frozen() != step.plan.Freeze {
+		err = pipeline.SetFrozen(step.plan.Freeze)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if pipeline.Description() != step.plan.Description {
+		err = pipeline.SetDescription(step.plan.Description)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if found && step.plan.PreserveResourceHistory {
+		err = preserveResourceHistory(logger, pipeline, existingConfig, atcConfig)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if step.plan.Display != nil {
+		err = pipeline.UpdateDisplay(*step.plan.Display)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if step.plan.ArchiveOld {
+		err = step.archiveRenamedPipeline(logger, team, pipeline, stdout)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if step.plan.ArchiveUnlisted {
+		step.archiveUnlistedPipelines(logger, state, team, stdout)
+	}
+
+	if step.plan.LabelResources {
+		err = labelResources(logger, pipeline, step.metadata.BuildID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if step.plan.QueueDepthCheck {
+		err = checkQueueDepth(logger, pipeline, step.plan.QueueDepthThreshold, stderr)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if step.plan.TriggerChecks {
+		if step.plan.TriggerChecksJitter != "" {
+			// spread out the checks of pipelines set at the same time, e.g. by
+			// a meta-pipeline, so that they don't all hit the workers at once
+			maxJitter, _ := time.ParseDuration(step.plan.TriggerChecksJitter)
+
+			var jitter time.Duration
+			if maxJitter > 0 {
+				jitter = time.Duration(rand.Int63n(int64(maxJitter)))
+			}
+
+			logger.Debug("delaying-resource-checks", lager.Data{"jitter": jitter.String()})
+
+			err = step.deadline.Exclude(func() error {
+				return sleepContext(ctx, jitter)
+			})
+			if err != nil {
+				return false, err
+			}
+		}
+
+		err = pipeline.TriggerImmediateResourceChecks()
+		if err != nil {
+			return false, err
+		}
+
+		logger.Debug("triggered-resource-checks")
+	}
+
+	if step.plan.TriggerFirstJob {
+		err = triggerFirstJob(logger, pipeline, atcConfig, stdout)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if len(step.plan.CanaryJobs) > 0 {
+		err = step.deadline.Exclude(func() error {
+			return rollOutCanaryJobs(ctx, logger, pipeline, step.plan.CanaryJobs, stdout)
+		})
+		if err != nil {
+			if _, ok := err.(CanaryJobFailedError); ok {
+				fmt.Fprintf(stderr, "%s; the remaining jobs have been left paused\n", err)
+				step.rollBackFailedCheck(logger, stderr, err)
+				delegate.Finished(logger, false)
+				return false, nil
+			}
+
+			return false, err
+		}
+	}
+
+	if step.plan.WaitForSuccess && len(atcConfig.Jobs) > 0 {
+		timeout, _ := time.ParseDuration(step.plan.WaitTimeout)
+
+		err = step.deadline.Exclude(func() error {
+			return waitForFirstBuild(ctx, logger, pipeline, atcConfig.Jobs[0].Name, timeout, stdout)
+		})
+		if err != nil {
+			switch err.(type) {
+			case FirstBuildFailedError, WaitForSuccessTimeoutError:
+				fmt.Fprintf(stderr, "%s\n", err)
+				step.rollBackFailedCheck(logger, stderr, err)
+				delegate.Finished(logger, false)
+				return false, nil
+			}
+
+			return false, err
+		}
+	}
+
+	if step.plan.RollbackOnError && step.plan.RollbackWindow != "" {
+		window, _ := time.ParseDuration(step.plan.RollbackWindow)
+
+		err = step.deadline.Exclude(func() error {
+			return watchResourceChecks(ctx, logger, pipeline, savedAt, window)
+		})
+		if err != nil {
+			if _, ok := err.(ResourceCheckFailedError); ok {
+				fmt.Fprintf(stderr, "%s\n", err)
+				step.rollBackFailedCheck(logger, stderr, err)
+				delegate.Finished(logger, false)
+				return false, nil
+			}
+
+			return false, err
+		}
+	}
+
+	if step.plan.SlackWebhook != "" {
+		err = notifySlack(ctx, step.plan.SlackWebhook, step.plan.SlackChannel, slackNotification{
+			Team:          team.Name(),
+			Pipeline:      pipelineRef.String(),
+			ConfigVersion: pipeline.ConfigVersion(),
+			JobDiffs:      existingConfig.JobDiffs(atcConfig),
+			ResourceDiffs: existingConfig.ResourceDiffs(atcConfig),
+		})
+		if err != nil {
+			logger.Error("failed-to-notify-slack", err)
+			fmt.Fprintf(stderr, "\x1b[1;33mWARNING: failed to send slack notification: %s\x1b[0m\n", err)
+		}
+	}
+
+	if step.plan.GitHubDeployment != nil {
+		deployer, err := newGitHubDeployer(state, *step.plan.GitHubDeployment)
+		if err != nil {
+			return false, err
+		}
+
+		err = deployer.createDeployment(ctx, *step.plan.GitHubDeployment, gitHubDeploymentPayload{
+			Team:          team.Name(),
+			Pipeline:      pipelineRef.String(),
+			ConfigVersion: pipeline.ConfigVersion(),
+			BuildID:       step.metadata.BuildID,
+		})
+		if err != nil {
+			logger.Error("failed-to-create-github-deployment", err)
+			fmt.Fprintf(stderr, "\x1b[1;33mWARNING: failed to create github deployment: %s\x1b[0m\n", err)
+		}
+	}
+
+	if step.plan.PostSaveSleep != "" {
+		// give the scheduler a chance to pick up the new config before any
+		// later steps in the build try to use it
+		sleep, _ := time.ParseDuration(step.plan.PostSaveSleep)
+
+		logger.Debug("sleeping-after-save", lager.Data{"duration": sleep.String()})
+
+		err = step.deadline.Exclude(func() error {
+			return sleepContext(ctx, sleep)
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	step.storeResult(state, SetPipelineResult{
+		PipelineName:  pipeline.Name(),
+		TeamName:      team.Name(),
+		ConfigVersion: pipeline.ConfigVersion(),
+		HadDiff:       true,
+		SavedAt:       savedAt,
+	})
+
+	fmt.Fprintf(stdout, "done\n")
+	logger.Info("saved-pipeline", lager.Data{"team": team.Name(), "pipeline": pipeline.Name()})
+	delegate.Finished(logger, true)
+
+	return true, nil
+}
+
+// pinVersions applies `inherit_pinned_versions` and `pin_build_inputs` to the
+// pipeline, pinning versions from the pipeline of the build running the step.
+func (step *SetPipelineStep) pinVersions(logger lager.Logger, parentBuild db.Build, pipeline db.Pipeline) error {
+	if !step.plan.InheritPinnedVersions && len(step.plan.PinBuildInputs) == 0 {
+		return nil
+	}
+
+	parentPipeline, found, err := parentBuild.Pipeline()
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return nil
+	}
+
+	if step.plan.InheritPinnedVersions {
+		err = inheritPinnedVersions(logger, parentPipeline, pipeline)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(step.plan.PinBuildInputs) > 0 {
+		err = pinBuildInputs(logger, parentBuild, parentPipeline, pipeline, step.plan.PinBuildInputs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordStreamedArtifacts saves the files fetched by the step in the build's
+// metadata.
+func (step *SetPipelineStep) recordStreamedArtifacts() error {
+	if len(step.streamedArtifacts) == 0 {
+		return nil
+	}
+
+	build, found, err := step.buildFactory.Build(step.metadata.BuildID)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("set_pipeline step not attached to a buildID")
+	}
+
+	return build.RecordSetPipelineArtifacts(step.streamedArtifacts)
+}
+
+// configVersionMismatch looks up the current config version of the pipeline
+// after a save failed because its config was changed concurrently.
+func configVersionMismatch(team db.Team, pipelineRef atc.PipelineRef, expected db.ConfigVersion) (ConfigVersionMismatchError, error) {
+	mismatch := ConfigVersionMismatchError{
+		Team:            team.Name(),
+		Pipeline:        pipelineRef.String(),
+		ExpectedVersion: expected,
+	}
+
+	pipeline, found, err := team.Pipeline(pipelineRef)
+	if err != nil {
+		return ConfigVersionMismatchError{}, err
+	}
+
+	if found {
+		mismatch.ActualVersion = pipeline.ConfigVersion()
+	}
+
+	return mismatch, nil
+}
+
+// Result returns what the step has set, once it has run successfully.
+func (step *SetPipelineStep) Result() (SetPipelineResult, bool) {
+	if step.result == nil {
+		return SetPipelineResult{}, false
+	}
+
+	return *step.result, true
+}
+
+// sleepContext waits for the duration, returning early with the context's
+// error if it is done first.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	select {
+	case <-time.After(duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// updatePipelineSettings brings the settings which are stored alongside the
+// pipeline's config in line with the step, whether or not the config changed.
+// Settings which the step does not configure are removed.
+func (step *SetPipelineStep) updatePipelineSettings(pipeline db.Pipeline) error {
+	var err error
+	if step.plan.Notifications == nil {
+		err = pipeline.ClearNotifications()
+	} else {
+		err = pipeline.SetNotifications(*step.plan.Notifications)
+	}
+	if err != nil {
+		return err
+	}
+
+	if step.plan.Owners == nil {
+		err = pipeline.ClearOwnership()
+	} else {
+		err = pipeline.SetOwnership(*step.plan.Owners)
+	}
+	if err != nil {
+		return err
+	}
+
+	if step.plan.ArchiveAfter == "" {
+		return pipeline.ClearArchiveAfter()
+	}
+
+	archiveAfter, err := parseArchiveAfter(step.plan.ArchiveAfter)
+	if err != nil {
+		return fmt.Errorf("invalid archive_after: %w", err)
+	}
+
+	return pipeline.SetArchiveAfter(archiveAfter)
+}
+
+// checkPolicy checks the config against the policies for setting pipelines,
+// if any apply.
+func (step *SetPipelineStep) checkPolicy(logger lager.Logger, teamName string, config *atc.Config) error {
+	if step.policyChecker == nil || !step.policyChecker.ShouldCheckAction(ActionRunSetPipeline) {
+		return nil
+	}
+
+	input := policy.PolicyCheckInput{
+		Action:   ActionRunSetPipeline,
+		Team:     teamName,
+		Pipeline: step.plan.Name,
+		Data:     config,
+	}
+	result, err := step.policyChecker.Check(input)
+	if err != nil {
+		return fmt.Errorf("error checking policy enforcement")
+	}
+	if !result.Allowed {
+		return fmt.Errorf("policy check failed for set_pipeline: %s", strings.Join(result.Reasons, ", "))
+	}
+
+	logger.Debug("policy check passed for set_pipeline")
+
+	return nil
+}
+
+func (step *SetPipelineStep) storeResult(state RunState, result SetPipelineResult) {
+	step.result = &result
+	state.StoreResult(step.planID, result)
+}
+
+// emitValidationWarnings emits the number of validation warnings of each
+// type found in the config of the pipeline being set.
+func (step *SetPipelineStep) emitValidationWarnings(logger lager.Logger, warnings []atc.ConfigWarning) {
+	counts := map[string]int{}
+	for _, warning := range warnings {
+		counts[warning.Type]++
+	}
+
+	teamName := step.plan.Team
+	if teamName == "" {
+		teamName = step.metadata.TeamName
+	}
+
+	pipelineRef := atc.PipelineRef{
+		Name:         step.plan.Name,
+		InstanceVars: step.plan.InstanceVars,
+	}
+
+	for code, count := range counts {
+		metric.SetPipelineValidationWarnings{
+			Team:        teamName,
+			Pipeline:    pipelineRef.String(),
+			WarningCode: code,
+			Count:       count,
+		}.Emit(logger)
+	}
+}
+
+// setsItself returns whether any job in the config has a set_pipeline step
+// which sets the pipeline of the given name.
+func setsItself(name string, config atc.Config) bool {
+	var found bool
+	for _, job := range config.Jobs {
+		_ = job.StepConfig().Visit(atc.StepRecursor{
+			OnSetPipeline: func(step *atc.SetPipelineStep) error {
+				if step.Name == name || step.Name == "self" {
+					found = true
+				}
+				return nil
+			},
+		})
+	}
+
+	return found
+}
+
+// extendPipeline merges the config on top of the current config of the
+// pipeline named by `extends` in the build's team.
+func (step *SetPipelineStep) extendPipeline(config atc.Config) (atc.Config, error) {
+	team := step.teamFactory.GetByID(step.metadata.TeamID)
+
+	base, found, err := team.Pipeline(atc.PipelineRef{Name: step.plan.Extends})
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	if !found {
+		return atc.Config{}, fmt.Errorf("pipeline to extend not found: %s", step.plan.Extends)
+	}
+
+	baseConfig, err := base.Config()
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	return extendConfig(baseConfig, config), nil
+}
+
+// clonePipeline merges the config on top of the config of the `clone_from`
+// pipeline, but only when the pipeline being set does not exist yet. Once it
+// exists, its config is set as-is.
+func (step *SetPipelineStep) clonePipeline(config atc.Config) (atc.Config, error) {
+	var team db.Team
+	if step.plan.Team == "" {
+		team = step.teamFactory.GetByID(step.metadata.TeamID)
+	} else {
+		var found bool
+		var err error
+		team, found, err = step.teamFactory.FindTeam(step.plan.Team)
+		if err != nil {
+			return atc.Config{}, err
+		}
+
+		if !found {
+			return atc.Config{}, fmt.Errorf("team %s not found", step.plan.Team)
+		}
+	}
+
+	_, found, err := team.Pipeline(atc.PipelineRef{Name: step.plan.Name, InstanceVars: step.plan.InstanceVars})
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	if found {
+		return config, nil
+	}
+
+	base, found, err := team.Pipeline(atc.PipelineRef{Name: step.plan.CloneFrom})
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	if !found {
+		return atc.Config{}, fmt.Errorf("pipeline to clone not found: %s", step.plan.CloneFrom)
+	}
+
+	baseConfig, err := base.Config()
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	return extendConfig(baseConfig, config), nil
+}
+
+// archiveUnlistedPipelines arranges for the pipelines managed by the step to
+// be archived once the build has succeeded, if the build did not set them.
+// Later steps of the build may still set some of them, so they can not be
+// archived yet.
+func (step *SetPipelineStep) archiveUnlistedPipelines(logger lager.Logger, state RunState, team db.Team, stdout io.Writer) {
+	if step.metadata.JobID == 0 {
+		// only the pipelines set by a job are known to be managed by it
+		fmt.Fprintln(stdout, "not archiving unlisted pipelines in a one-off build")
+		return
+	}
+
+	state.RegisterFinalizer(unlistedPipelineArchiver{
+		logger:   logger,
+		team:     team,
+		prefix:   step.plan.ManagedPrefix,
+		metadata: step.metadata,
+		stdout:   stdout,
+	})
+}
+
+// unlistedPipelineArchiver archives every pipeline of the team whose name
+// starts with the managed prefix and which was set by the job, but not by the
+// current build. The pipeline the build belongs to is never archived.
+type unlistedPipelineArchiver struct {
+	logger   lager.Logger
+	team     db.Team
+	prefix   string
+	metadata StepMetadata
+	stdout   io.Writer
+}
+
+func (archiver unlistedPipelineArchiver) Finalize(context.Context) error {
+	pipelines, err := archiver.team.Pipelines()
+	if err != nil {
+		return err
+	}
+
+	for _, pipeline := range pipelines {
+		if !strings.HasPrefix(pipeline.Name(), archiver.prefix) {
+			continue
+		}
+
+		if pipeline.Archived() || pipeline.ID() == archiver.metadata.PipelineID {
+			continue
+		}
+
+		if pipeline.ParentJobID() != archiver.metadata.JobID || pipeline.ParentBuildID() == archiver.metadata.BuildID {
+			continue
+		}
+
+		err := pipeline.Archive()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(archiver.stdout, "archived unlisted pipeline: %s\n", atc.PipelineRef{Name: pipeline.Name(), InstanceVars: pipeline.InstanceVars()}.String())
+		archiver.logger.Info("archived-unlisted-pipeline", lager.Data{"pipeline": pipeline.Name()})
+	}
+
+	return nil
+}
+
+// renamePipeline renames the pipeline named by `rename_from` to the step's
+// pipeline name, so that the config is then saved onto it and its build
+// history, jobs and resource versions are kept. If a pipeline with the new
+// name already exists the old one is left alone, to be archived after saving
+// when `archive_old` is set.
+func (step *SetPipelineStep) renamePipeline(logger lager.Logger, team db.Team, pipelineRef atc.PipelineRef, stdout io.Writer) error {
+	oldRef := atc.PipelineRef{
+		Name:         step.plan.RenameFrom,
+		InstanceVars: step.plan.InstanceVars,
+	}
+
+	_, found, err := team.Pipeline(oldRef)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return nil
+	}
+
+	_, found, err = team.Pipeline(pipelineRef)
+	if err != nil {
+		return err
 	}
 
-	fmt.Fprintf(stdout, "done\n")
-	logger.Info("saved-pipeline", lager.Data{"team": team.Name(), "pipeline": pipeline.Name()})
-	delegate.Finished(logger, true)
+	if found {
+		if !step.plan.ArchiveOld {
+			return fmt.Errorf("can not rename pipeline %s to %s: pipeline already exists, set `archive_old` to archive the old pipeline instead", oldRef.String(), pipelineRef.String())
+		}
 
-	return true, nil
+		return nil
+	}
+
+	_, err = team.RenamePipeline(oldRef.Name, pipelineRef.Name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "renamed pipeline: %s to %s\n", oldRef.String(), pipelineRef.String())
+	logger.Info("renamed-pipeline", lager.Data{"from": oldRef.Name, "to": pipelineRef.Name})
+
+	return nil
+}
+
+// archiveRenamedPipeline archives the pipeline named by `rename_from` when it
+// could not be renamed because a pipeline with the new name already existed,
+// keeping its build history as a read-only archive.
+func (step *SetPipelineStep) archiveRenamedPipeline(logger lager.Logger, team db.Team, renamed db.Pipeline, stdout io.Writer) error {
+	oldRef := atc.PipelineRef{
+		Name:         step.plan.RenameFrom,
+		InstanceVars: step.plan.InstanceVars,
+	}
+
+	oldPipeline, found, err := team.Pipeline(oldRef)
+	if err != nil {
+		return err
+	}
+
+	if !found || oldPipeline.ID() == renamed.ID() || oldPipeline.Archived() {
+		return nil
+	}
+
+	err = oldPipeline.Archive()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "archived renamed pipeline: %s\n", oldRef.String())
+	logger.Info("archived-renamed-pipeline", lager.Data{"pipeline": oldRef.Name})
+
+	return nil
+}
+
+// Abort destroys the pipeline if it was created by the step and
+// `cleanup_on_failure` is set. It is called when the build does not succeed.
+func (step *SetPipelineStep) Abort(ctx context.Context) error {
+	if step.createdPipeline == nil {
+		return nil
+	}
+
+	logger := lagerctx.FromContext(ctx).Session("set-pipeline-step-abort", lager.Data{
+		"pipeline": step.createdPipeline.Name(),
+	})
+
+	err := step.createdPipeline.Destroy()
+	if err != nil {
+		logger.Error("failed-to-destroy-pipeline", err)
+		return err
+	}
+
+	logger.Info("destroyed-pipeline")
+	step.createdPipeline = nil
+
+	return nil
 }
 
 type setPipelineSource struct {
@@ -279,6 +1590,30 @@ type setPipelineSource struct {
 	repo             *build.Repository
 	step             *SetPipelineStep
 	artifactStreamer worker.ArtifactStreamer
+	stderr           io.Writer
+
+	// progress, if set, is called with a message as each var file is
+	// fetched
+	progress func(message string)
+
+	// varFileFetched, if set, is called with the digest of each var file
+	// once it has been fetched
+	varFileFetched func(fetch VarFileFetch)
+
+	// fetchImage fetches the image of the containers run against the config
+	fetchImage func(image atc.ImageResource) (worker.ImageSpec, error)
+
+	skipCache bool
+}
+
+// configParseError is returned when a fetched file can not be parsed, which
+// may be caused by the file having been streamed incompletely.
+type configParseError struct {
+	err error
+}
+
+func (err configParseError) Error() string {
+	return err.err.Error()
 }
 
 func (s setPipelineSource) Validate() error {
@@ -290,34 +1625,302 @@ func (s setPipelineSource) Validate() error {
 		return errors.New("support for `instance_vars` is disabled")
 	}
 
+	if s.step.plan.Display != nil {
+		err := configvalidate.ValidateDisplay(*s.step.plan.Display)
+		if err != nil {
+			return fmt.Errorf("invalid display: %w", err)
+		}
+	}
+
+	if s.step.plan.ArchiveUnlisted && s.step.plan.ManagedPrefix == "" {
+		return errors.New("`managed_prefix` must be specified when `archive_unlisted` is set")
+	}
+
+	if len(s.step.plan.StrategicMergeFiles) > 0 && s.step.plan.TemplateEngine != "" {
+		return errors.New("`strategic_merge_files` can not be used with `template_engine`")
+	}
+
+	if s.step.plan.ArchiveOld && s.step.plan.RenameFrom == "" {
+		return errors.New("`rename_from` must be specified when `archive_old` is set")
+	}
+
+	if s.step.plan.PostSaveSleep != "" {
+		_, err := time.ParseDuration(s.step.plan.PostSaveSleep)
+		if err != nil {
+			return fmt.Errorf("invalid post_save_sleep: %w", err)
+		}
+	}
+
+	if s.step.plan.ArchiveAfter != "" {
+		_, err := parseArchiveAfter(s.step.plan.ArchiveAfter)
+		if err != nil {
+			return fmt.Errorf("invalid archive_after: %w", err)
+		}
+	}
+
+	if s.step.plan.MergeStrategy != nil {
+		err := s.step.plan.MergeStrategy.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid merge_strategy: %w", err)
+		}
+	}
+
+	dynamicVarPaths := map[string]string{}
+	for _, dynamicVar := range s.step.plan.VaultDynamicVars {
+		if dynamicVar.Path == "" || len(dynamicVar.Keys) == 0 {
+			return errors.New("`vault_dynamic_vars` entries must specify a `path` and `keys`")
+		}
+
+		for _, key := range dynamicVar.Keys {
+			if path, found := dynamicVarPaths[key]; found {
+				return fmt.Errorf("`vault_dynamic_vars` key '%s' is provided by both '%s' and '%s'", key, path, dynamicVar.Path)
+			}
+
+			dynamicVarPaths[key] = dynamicVar.Path
+		}
+	}
+
+	if s.step.plan.Notifications != nil {
+		err := s.step.plan.Notifications.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid notifications: %w", err)
+		}
+	}
+
+	for _, pattern := range s.step.plan.EncryptFields {
+		_, err := parseEncryptFieldsPattern(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid encrypt_fields pattern: %w", err)
+		}
+	}
+
+	if s.step.plan.MinJobs < 0 {
+		return errors.New("`min_jobs` must not be negative")
+	}
+
+	if s.step.plan.MaxParallelJobs < 0 {
+		return errors.New("`max_parallel_jobs` must not be negative")
+	}
+
+	if s.step.plan.QueueDepthThreshold < 0 {
+		return errors.New("`queue_depth_threshold` must not be negative")
+	}
+
+	if s.step.plan.LintPolicyFile != "" && s.step.plan.LintImage == "" {
+		return errors.New("`lint_image` must be specified when `lint_policy_file` is set")
+	}
+
+	if s.step.plan.PreValidateScript != "" && s.step.plan.PreValidateImage == nil {
+		return errors.New("`pre_validate_image_resource` must be specified when `pre_validate_script` is set")
+	}
+
+	if s.step.plan.Target != nil && s.step.plan.Target.URL == "" {
+		return errors.New("`target.url` must be specified")
+	}
+
+	if deployment := s.step.plan.GitHubDeployment; deployment != nil {
+		if deployment.Owner == "" || deployment.Repo == "" {
+			return errors.New("`github_deployment.owner` and `github_deployment.repo` must be specified")
+		}
+
+		if deployment.TokenVar == "" {
+			return errors.New("`github_deployment.token_var` must be specified")
+		}
+	}
+
+	if s.step.plan.CloneFrom != "" && s.step.plan.Extends != "" {
+		return errors.New("`clone_from` can not be used with `extends`")
+	}
+
+	if s.step.plan.WaitTimeout != "" {
+		_, err := time.ParseDuration(s.step.plan.WaitTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid wait_timeout: %w", err)
+		}
+	}
+
+	if s.step.plan.RollbackOnError {
+		// these can not be undone by re-saving the previous config
+		switch {
+		case s.step.plan.ResetBuildHistory:
+			return errors.New("`rollback_on_error` can not be used with `reset_build_history`")
+		case s.step.plan.ArchiveUnlisted:
+			return errors.New("`rollback_on_error` can not be used with `archive_unlisted`")
+		case s.step.plan.RenameFrom != "":
+			return errors.New("`rollback_on_error` can not be used with `rename_from`")
+		}
+	}
+
+	if s.step.plan.RollbackWindow != "" {
+		if !s.step.plan.RollbackOnError {
+			return errors.New("`rollback_on_error` must be set when `rollback_window` is specified")
+		}
+
+		_, err := time.ParseDuration(s.step.plan.RollbackWindow)
+		if err != nil {
+			return fmt.Errorf("invalid rollback_window: %w", err)
+		}
+	}
+
+	if s.step.plan.TriggerChecksJitter != "" {
+		_, err := time.ParseDuration(s.step.plan.TriggerChecksJitter)
+		if err != nil {
+			return fmt.Errorf("invalid trigger_checks_jitter: %w", err)
+		}
+	}
+
+	switch s.step.plan.TemplateEngine {
+	case "", TemplateEngineGoTemplate:
+	default:
+		return fmt.Errorf("unknown template engine: %s", s.step.plan.TemplateEngine)
+	}
+
+	if s.step.plan.DiffOutput != "" {
+		_, _, err := splitDiffOutput(s.step.plan.DiffOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch s.step.plan.Scope {
+	case "", SetPipelineScopeBuild:
+	default:
+		return fmt.Errorf("unknown scope: %s", s.step.plan.Scope)
+	}
+
+	switch s.step.plan.VarPrecedence {
+	case "", VarPrecedenceVarsFirst, VarPrecedenceFilesFirst:
+	default:
+		return fmt.Errorf("unknown var precedence: %s", s.step.plan.VarPrecedence)
+	}
+
+	switch s.step.plan.DiffFormat {
+	case "", DiffFormatUnified, DiffFormatSideBySide, DiffFormatSummary:
+	default:
+		return fmt.Errorf("unknown diff format: %s", s.step.plan.DiffFormat)
+	}
+
 	return nil
 }
 
 // FetchConfig streams pipeline config file and var files from other resources
 // and construct an atc.Config object
 func (s setPipelineSource) FetchPipelineConfig() (atc.Config, error) {
-	config, err := s.fetchPipelineBits(s.step.plan.File)
+	if len(s.step.plan.VarFiles) > s.step.maxVarFiles {
+		return atc.Config{}, fmt.Errorf("too many var files: %d exceeds the maximum of %d", len(s.step.plan.VarFiles), s.step.maxVarFiles)
+	}
+
+	for attempt := 1; ; attempt++ {
+		atcConfig, err := s.fetchPipelineConfig()
+
+		var parseErr configParseError
+		if !errors.As(err, &parseErr) {
+			return atcConfig, err
+		}
+
+		if attempt > s.step.plan.MaxParseRetries {
+			return atc.Config{}, parseErr.err
+		}
+
+		s.logger.Info("retrying-after-parse-failure", lager.Data{"attempt": attempt, "error": parseErr.err.Error()})
+		fmt.Fprintf(s.stderr, "WARNING: failed to parse fetched files (attempt %d of %d), retrying: %s\n", attempt, s.step.plan.MaxParseRetries+1, parseErr.err)
+
+		// the file may have been cached before it was found to be incomplete
+		s.skipCache = true
+	}
+}
+
+func (s setPipelineSource) fetchPipelineConfig() (atc.Config, error) {
+	s.step.streamedArtifacts = nil
+
+	var prefetchedVarFiles [][]byte
+	if s.step.plan.PrefetchVarFiles && len(s.step.plan.VarFiles) > 0 {
+		var err error
+		prefetchedVarFiles, err = s.prefetchVarFiles()
+		if err != nil {
+			return atc.Config{}, err
+		}
+	}
+
+	config, err := s.fetchPipelineBitsInSpan("fetch_config_file", tracing.Attrs{"config_file.path": s.step.plan.File}, s.step.plan.File)
 	if err != nil {
 		return atc.Config{}, err
 	}
 
-	staticVars := []vars.Variables{}
-	if len(s.step.plan.Vars) > 0 {
-		staticVars = append(staticVars, vars.StaticVariables(s.step.plan.Vars))
+	if len(s.step.plan.BannedPatterns) > 0 {
+		matches := findBannedPatterns(config, s.step.plan.BannedPatterns)
+		if len(matches) > 0 {
+			return atc.Config{}, BannedPatternsError{File: s.step.plan.File, Matches: matches}
+		}
+	}
+
+	if s.step.plan.PreValidateScript != "" {
+		err = s.preValidate(config)
+		if err != nil {
+			return atc.Config{}, err
+		}
+	}
+
+	// templates are only valid YAML once rendered, so their documents are
+	// merged afterwards
+	if s.step.plan.TemplateEngine == "" {
+		config, err = mergeDocuments(config)
+		if err != nil {
+			return atc.Config{}, err
+		}
 	}
-	for _, lvf := range s.step.plan.VarFiles {
-		bytes, err := s.fetchPipelineBits(lvf)
+
+	if len(s.step.plan.StrategicMergeFiles) > 0 {
+		var patches [][]byte
+		for _, path := range s.step.plan.StrategicMergeFiles {
+			patch, err := s.fetchPipelineBits(path)
+			if err != nil {
+				return atc.Config{}, err
+			}
+
+			patches = append(patches, patch)
+		}
+
+		config, err = strategicMerge(config, patches)
 		if err != nil {
 			return atc.Config{}, err
 		}
+	}
+
+	fileVars := []vars.Variables{}
+	for i, lvf := range s.step.plan.VarFiles {
+		var bytes []byte
+		if prefetchedVarFiles != nil {
+			bytes = prefetchedVarFiles[i]
+		} else {
+			if s.progress != nil {
+				s.progress(fmt.Sprintf("fetching var_file %d of %d", i+1, len(s.step.plan.VarFiles)))
+			}
+
+			bytes, err = s.fetchVarFile(lvf)
+			if err != nil {
+				return atc.Config{}, err
+			}
+		}
 
 		sv := vars.StaticVariables{}
 		err = yaml.Unmarshal(bytes, &sv)
 		if err != nil {
-			return atc.Config{}, err
+			return atc.Config{}, configParseError{err}
 		}
 
-		staticVars = append(staticVars, sv)
+		fileVars = append(fileVars, sv)
+	}
+
+	staticVars := []vars.Variables{}
+	if s.step.plan.VarPrecedence == VarPrecedenceFilesFirst {
+		staticVars = append(staticVars, fileVars...)
+	}
+	if len(s.step.plan.Vars) > 0 {
+		staticVars = append(staticVars, vars.StaticVariables(s.step.plan.Vars))
+	}
+	if s.step.plan.VarPrecedence != VarPrecedenceFilesFirst {
+		staticVars = append(staticVars, fileVars...)
 	}
 
 	if len(s.step.plan.InstanceVars) > 0 {
@@ -328,6 +1931,43 @@ func (s setPipelineSource) FetchPipelineConfig() (atc.Config, error) {
 		staticVars = append(staticVars, iv)
 	}
 
+	// vault_dynamic_vars take precedence over every other var
+	if len(s.step.plan.VaultDynamicVars) > 0 {
+		teamName := s.step.plan.Team
+		if teamName == "" {
+			teamName = s.step.metadata.TeamName
+		}
+
+		dynamicVars, err := s.step.fetchVaultDynamicVars(teamName, s.step.plan.Name)
+		if err != nil {
+			return atc.Config{}, err
+		}
+
+		staticVars = append([]vars.Variables{dynamicVars}, staticVars...)
+	}
+
+	if s.step.plan.TemplateEngine == TemplateEngineGoTemplate {
+		config, err = renderGoTemplate(s.step.plan.File, config, staticVars)
+		if err != nil {
+			return atc.Config{}, err
+		}
+
+		config, err = mergeDocuments(config)
+		if err != nil {
+			return atc.Config{}, err
+		}
+	}
+
+	// check the syntax before resolving vars so that an incomplete file can be
+	// told apart from errors in the vars
+	if !vars.PresentDeprecated(config) {
+		var obj interface{}
+		err = yaml.Unmarshal(config, &obj)
+		if err != nil {
+			return atc.Config{}, configParseError{err}
+		}
+	}
+
 	if len(staticVars) > 0 {
 		config, err = vars.NewTemplateResolver(config, staticVars).Resolve(false, false)
 		if err != nil {
@@ -338,13 +1978,29 @@ func (s setPipelineSource) FetchPipelineConfig() (atc.Config, error) {
 	atcConfig := atc.Config{}
 	err = atc.UnmarshalConfig(config, &atcConfig)
 	if err != nil {
-		return atc.Config{}, err
+		return atc.Config{}, configParseError{err}
 	}
 
 	return atcConfig, nil
 }
 
+// fetchPipelineBitsInSpan fetches the file within a child span of the step's
+// span, so that the latency of each file is visible in traces.
+func (s setPipelineSource) fetchPipelineBitsInSpan(spanName string, attrs tracing.Attrs, path string) ([]byte, error) {
+	ctx, span := tracing.StartSpan(s.ctx, spanName, attrs)
+	s.ctx = ctx
+
+	bits, err := s.fetchPipelineBits(path)
+	tracing.End(span, err)
+
+	return bits, err
+}
+
 func (s setPipelineSource) fetchPipelineBits(path string) ([]byte, error) {
+	if strings.HasPrefix(path, ConfigMapSourcePrefix) {
+		return s.fetchFromConfigMap(path)
+	}
+
 	segs := strings.SplitN(path, "/", 2)
 	if len(segs) != 2 {
 		return nil, UnspecifiedArtifactSourceError{path}
@@ -353,26 +2009,120 @@ func (s setPipelineSource) fetchPipelineBits(path string) ([]byte, error) {
 	artifactName := segs[0]
 	filePath := segs[1]
 
-	stream, err := s.retrieveFromArtifact(artifactName, filePath)
+	art, found := s.repo.ArtifactFor(build.ArtifactName(artifactName))
+	if !found {
+		return nil, UnknownArtifactSourceError{build.ArtifactName(artifactName), filePath}
+	}
+
+	teamID := s.step.metadata.TeamID
+	version := artifactVersion(art)
+	if s.step.fileCache != nil && !s.skipCache {
+		byteConfig, found := s.step.fileCache.Get(teamID, art.ID(), version, filePath)
+		if found {
+			metric.Metrics.SetPipelineFileCacheHits.Inc()
+			s.logger.Debug("file-cache-hit", lager.Data{"path": path})
+			s.recordArtifact(artifactName, filePath, byteConfig)
+			return byteConfig, nil
+		}
+
+		metric.Metrics.SetPipelineFileCacheMisses.Inc()
+	}
+
+	byteConfig, err := s.streamWithTimeoutRetries(art, artifactName, filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer stream.Close()
 
-	byteConfig, err := ioutil.ReadAll(stream)
+	if s.step.fileCache != nil {
+		s.step.fileCache.Set(teamID, art.ID(), version, filePath, byteConfig)
+	}
+
+	s.recordArtifact(artifactName, filePath, byteConfig)
+
+	return byteConfig, nil
+}
+
+// recordArtifact keeps track of a fetched file so that the build records
+// exactly what the step consumed.
+func (s setPipelineSource) recordArtifact(name string, path string, content []byte) {
+	digest := sha256.Sum256(content)
+
+	s.step.streamedArtifactsLock.Lock()
+	defer s.step.streamedArtifactsLock.Unlock()
+
+	s.step.streamedArtifacts = append(s.step.streamedArtifacts, atc.SetPipelineArtifact{
+		Name:       name,
+		Path:       path,
+		Digest:     "sha256:" + hex.EncodeToString(digest[:]),
+		SizeBytes:  len(content),
+		StreamedAt: time.Now().Unix(),
+	})
+}
+
+// fetchFromConfigMap fetches a file given as configmap:<name>/<key> from the
+// team's Kubernetes namespace.
+func (s setPipelineSource) fetchFromConfigMap(path string) ([]byte, error) {
+	if s.step.configMapFetcher == nil {
+		return nil, fmt.Errorf("cannot fetch %s: kubernetes configmaps are not configured", path)
+	}
+
+	segs := strings.SplitN(strings.TrimPrefix(path, ConfigMapSourcePrefix), "/", 2)
+	if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+		return nil, fmt.Errorf("invalid configmap path %s: must be of the form configmap:<name>/<key>", path)
+	}
+
+	byteConfig, err := s.step.configMapFetcher.FetchConfigMapFile(s.ctx, s.step.metadata.TeamName, segs[0], segs[1])
 	if err != nil {
 		return nil, err
 	}
 
+	s.recordArtifact(ConfigMapSourcePrefix+segs[0], segs[1], byteConfig)
+
 	return byteConfig, nil
 }
 
-func (s setPipelineSource) retrieveFromArtifact(name, file string) (io.ReadCloser, error) {
-	art, found := s.repo.ArtifactFor(build.ArtifactName(name))
-	if !found {
-		return nil, UnknownArtifactSourceError{build.ArtifactName(name), file}
+// streamWithTimeoutRetries streams the file from the artifact, retrying with
+// exponential backoff when streaming times out. Other errors, e.g. the file
+// not being found, are returned straight away.
+func (s setPipelineSource) streamWithTimeoutRetries(art runtime.Artifact, name, file string) ([]byte, error) {
+	interval := fetchTimeoutRetryInterval
+
+	for attempt := 1; ; attempt++ {
+		byteConfig, err := s.streamFile(art, name, file)
+		if err == nil || !isTimeout(err) || attempt > maxFetchTimeoutRetries {
+			return byteConfig, err
+		}
+
+		s.logger.Info("retrying-timed-out-fetch", lager.Data{"file": name + "/" + file, "attempt": attempt})
+		fmt.Fprintf(s.stderr, "WARNING: fetching %s/%s timed out (attempt %d of %d), retrying: %s\n", name, file, attempt, maxFetchTimeoutRetries+1, err)
+
+		select {
+		case <-time.After(interval):
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		}
+
+		interval *= 2
+	}
+}
+
+func (s setPipelineSource) streamFile(art runtime.Artifact, name, file string) ([]byte, error) {
+	stream, err := s.retrieveFromArtifact(art, name, file)
+	if err != nil {
+		return nil, err
 	}
+	defer stream.Close()
+
+	return ioutil.ReadAll(stream)
+}
+
+// isTimeout returns true if the error is a network timeout.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
 
+func (s setPipelineSource) retrieveFromArtifact(art runtime.Artifact, name, file string) (io.ReadCloser, error) {
 	stream, err := s.artifactStreamer.StreamFileFromArtifact(lagerctx.NewContext(s.ctx, s.logger), art, file)
 	if err != nil {
 		if err == baggageclaim.ErrFileNotFound {
@@ -2,27 +2,50 @@ package exec_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/api/trace/tracetest"
+	"go.opentelemetry.io/otel/label"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	"code.cloudfoundry.org/lager/lagerctx"
 	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/concourse"
 	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/creds"
 	"github.com/concourse/concourse/atc/db"
 	"github.com/concourse/concourse/atc/db/dbfakes"
+	"github.com/concourse/concourse/atc/eventbus"
+	"github.com/concourse/concourse/atc/eventbus/eventbusfakes"
 	"github.com/concourse/concourse/atc/exec"
 	"github.com/concourse/concourse/atc/exec/build"
 	"github.com/concourse/concourse/atc/exec/build/buildfakes"
 	"github.com/concourse/concourse/atc/exec/execfakes"
 	"github.com/concourse/concourse/atc/policy"
 	"github.com/concourse/concourse/atc/policy/policyfakes"
+	"github.com/concourse/concourse/atc/runtime"
+	"github.com/concourse/concourse/atc/runtime/runtimefakes"
+	"github.com/concourse/concourse/atc/worker"
 	"github.com/concourse/concourse/atc/worker/workerfakes"
+	"github.com/concourse/concourse/tracing"
+	"github.com/concourse/concourse/tracing/tracingfakes"
 	"github.com/concourse/concourse/vars"
+	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/onsi/gomega/gbytes"
 )
 
@@ -125,6 +148,20 @@ jobs:
 
 		stdout, stderr *gbytes.Buffer
 
+		maxVarFiles          int
+		fileCache            *exec.SetPipelineFileCache
+		limiter              *exec.SetPipelineLimiter
+		fakeConfigMapFetcher *execfakes.FakeConfigMapFetcher
+		configMapFetcher     exec.ConfigMapFetcher
+		fakeLinter           *execfakes.FakePipelineLinter
+		fakeArtifactWriter   *execfakes.FakeArtifactFileWriter
+
+		fakeCredentialInvalidator *execfakes.FakeCredentialCacheInvalidator
+		fakeVaultReader           *execfakes.FakeVaultSecretReader
+		vaultSecretReader         exec.VaultSecretReader
+		fakeEventPublisher        *eventbusfakes.FakePublisher
+		eventPublisher            eventbus.Publisher
+
 		planID = "56"
 	)
 
@@ -191,6 +228,21 @@ jobs:
 
 		fakeArtifactStreamer = new(workerfakes.FakeArtifactStreamer)
 
+		*fakeSetPipelineHook = execfakes.FakeSetPipelineHook{}
+
+		maxVarFiles = 20
+		fileCache = nil
+		limiter = nil
+		fakeConfigMapFetcher = new(execfakes.FakeConfigMapFetcher)
+		configMapFetcher = fakeConfigMapFetcher
+		fakeLinter = new(execfakes.FakePipelineLinter)
+		fakeArtifactWriter = new(execfakes.FakeArtifactFileWriter)
+		fakeCredentialInvalidator = new(execfakes.FakeCredentialCacheInvalidator)
+		fakeVaultReader = new(execfakes.FakeVaultSecretReader)
+		vaultSecretReader = fakeVaultReader
+		fakeEventPublisher = new(eventbusfakes.FakePublisher)
+		eventPublisher = fakeEventPublisher
+
 		spPlan = &atc.SetPipelinePlan{
 			Name:         "some-pipeline",
 			File:         "some-resource/pipeline.yml",
@@ -217,11 +269,129 @@ jobs:
 			fakeBuildFactory,
 			fakeArtifactStreamer,
 			fakeChecker,
+			maxVarFiles,
+			fileCache,
+			limiter,
+			configMapFetcher,
+			fakeLinter,
+			fakeArtifactWriter,
+			vaultSecretReader,
+			eventPublisher,
+			fakeCredentialInvalidator,
 		)
 
 		stepOk, stepErr = spStep.Run(ctx, state)
 	})
 
+	Context("when the set_pipeline step is disabled", func() {
+		BeforeEach(func() {
+			atc.DisableSetPipelineStep = true
+		})
+
+		AfterEach(func() {
+			atc.DisableSetPipelineStep = false
+		})
+
+		It("should fail before doing anything else", func() {
+			Expect(stepErr).To(MatchError(exec.ErrSetPipelineStepDisabled))
+			Expect(stepErr).To(MatchError("set_pipeline step is disabled by the ATC operator"))
+			Expect(fakeDelegateFactory.SetPipelineStepDelegateCallCount()).To(Equal(0))
+			Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when name is not configured", func() {
+		BeforeEach(func() {
+			spPlan = &atc.SetPipelinePlan{
+				File: "some-resource/pipeline.yml",
+			}
+		})
+
+		It("should fail before doing anything else", func() {
+			Expect(stepErr).To(MatchError("set_pipeline: name is required"))
+			Expect(fakeDelegateFactory.SetPipelineStepDelegateCallCount()).To(Equal(0))
+			Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when name_pattern is configured", func() {
+		Context("when the name does not match", func() {
+			BeforeEach(func() {
+				spPlan.NamePattern = "^[a-z][a-z0-9]{3,63}$"
+			})
+
+			It("should fail before fetching the config", func() {
+				Expect(stepErr).To(Equal(exec.PipelineNameMismatchError{
+					Name:    "some-pipeline",
+					Pattern: "^[a-z][a-z0-9]{3,63}$",
+				}))
+				Expect(stepErr).To(MatchError("pipeline name 'some-pipeline' does not match name_pattern ^[a-z][a-z0-9]{3,63}$"))
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the name is self", func() {
+			BeforeEach(func() {
+				spPlan.Name = "self"
+
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			Context("when the current pipeline's name matches", func() {
+				BeforeEach(func() {
+					spPlan.NamePattern = "^some-"
+				})
+
+				It("should save the pipeline", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the current pipeline's name does not match", func() {
+				BeforeEach(func() {
+					spPlan.NamePattern = "^self$"
+				})
+
+				It("should check the resolved name", func() {
+					Expect(stepErr).To(Equal(exec.PipelineNameMismatchError{
+						Name:    "some-pipeline",
+						Pattern: "^self$",
+					}))
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the name matches", func() {
+			BeforeEach(func() {
+				spPlan.NamePattern = "^[a-z][a-z0-9-]{3,63}$"
+
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should save the pipeline", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the pattern is invalid", func() {
+			BeforeEach(func() {
+				spPlan.NamePattern = "^[a-z"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError(ContainSubstring("invalid name_pattern")))
+			})
+		})
+	})
+
 	Context("when file is not configured", func() {
 		BeforeEach(func() {
 			spPlan = &atc.SetPipelinePlan{
@@ -235,121 +405,4857 @@ jobs:
 		})
 	})
 
+	Context("when the file path references variables", func() {
+		BeforeEach(func() {
+			state.GetStub = vars.StaticVariables{"PIPELINE_FILE": "pipelines/some-pipeline.yml"}.Get
+			spPlan.File = "some-resource/$BUILD_TEAM_NAME/${PIPELINE_FILE}"
+
+			fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+			fakeTeam.PipelineReturns(nil, false, nil)
+			fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+		})
+
+		It("should expand them from the build metadata and local vars", func() {
+			Expect(stepErr).ToNot(HaveOccurred())
+			Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+			_, _, path := fakeArtifactStreamer.StreamFileFromArtifactArgsForCall(0)
+			Expect(path).To(Equal("some-team/pipelines/some-pipeline.yml"))
+		})
+
+		Context("when a variable is not defined", func() {
+			BeforeEach(func() {
+				spPlan.File = "some-resource/$MISSING"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError("undefined variable in file path: $MISSING"))
+			})
+		})
+	})
+
 	Context("when file is configured", func() {
 		Context("pipeline file not exist", func() {
 			BeforeEach(func() {
 				fakeArtifactStreamer.StreamFileFromArtifactReturns(nil, errors.New("file not found"))
 			})
 
-			It("should fail with error of file not configured", func() {
-				Expect(stepErr).To(HaveOccurred())
-				Expect(stepErr.Error()).To(Equal("file not found"))
-			})
-		})
+			It("should fail with error of file not configured", func() {
+				Expect(stepErr).To(HaveOccurred())
+				Expect(stepErr.Error()).To(Equal("file not found"))
+			})
+		})
+
+		Context("when pipeline file exists but bad syntax", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: badPipelineContentWithInvalidSyntax}, nil)
+			})
+
+			It("should not return error", func() {
+				Expect(stepErr).NotTo(HaveOccurred())
+			})
+
+			It("should stderr have error message", func() {
+				Expect(stderr).To(gbytes.Say("invalid pipeline:"))
+				Expect(stderr).To(gbytes.Say("- invalid jobs:"))
+			})
+
+			It("should finish unsuccessfully", func() {
+				Expect(fakeDelegate.FinishedCallCount()).To(Equal(1))
+				_, succeeded := fakeDelegate.FinishedArgsForCall(0)
+				Expect(succeeded).To(BeFalse())
+			})
+		})
+
+		Context("when pipeline file has both warnings and errors", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+jobs:
+- name: Some-Job
+  plan:
+  - get: missing-resource
+`}, nil)
+			})
+
+			It("should not return error", func() {
+				Expect(stepErr).NotTo(HaveOccurred())
+			})
+
+			It("should stderr have the warnings before the errors", func() {
+				Expect(stderr).To(gbytes.Say("WARNING: jobs.Some-Job: 'Some-Job' is not a valid identifier"))
+				Expect(stderr).To(gbytes.Say("invalid pipeline:"))
+				Expect(stderr).To(gbytes.Say("- invalid jobs:"))
+				Expect(stderr).To(gbytes.Say("unknown resource 'missing-resource'"))
+			})
+
+			It("should finish unsuccessfully", func() {
+				Expect(stepOk).To(BeFalse())
+				Expect(fakeDelegate.FinishedCallCount()).To(Equal(1))
+				_, succeeded := fakeDelegate.FinishedArgsForCall(0)
+				Expect(succeeded).To(BeFalse())
+			})
+
+			It("should not save the pipeline", func() {
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when pipeline file exists but is empty", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: badPipelineContentWithEmptyContent}, nil)
+			})
+
+			It("should return an error", func() {
+				Expect(stepErr).NotTo(HaveOccurred())
+			})
+
+			It("should log an error message", func() {
+				Expect(stderr).To(gbytes.Say("pipeline must contain at least one job"))
+			})
+
+			It("should not update the job and build id", func() {
+				Expect(fakePipeline.SetParentIDsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the pipeline file is received incompletely", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturnsOnCall(0, &fakeReadCloser{str: "jobs: [{name: some-job, plan: [{"}, nil)
+				fakeArtifactStreamer.StreamFileFromArtifactReturnsOnCall(1, &fakeReadCloser{str: pipelineContent}, nil)
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should return the parse error", func() {
+				Expect(stepErr).To(HaveOccurred())
+				Expect(stepErr.Error()).To(ContainSubstring("error converting YAML to JSON"))
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+			})
+
+			Context("when max_parse_retries is set", func() {
+				BeforeEach(func() {
+					spPlan.MaxParseRetries = 2
+				})
+
+				It("should re-fetch the file and save the pipeline", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(2))
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				})
+
+				It("should warn about the retry", func() {
+					Expect(stderr).To(gbytes.Say(`WARNING: failed to parse fetched files \(attempt 1 of 3\), retrying`))
+				})
+
+				Context("when the file keeps failing to parse", func() {
+					BeforeEach(func() {
+						fakeArtifactStreamer.StreamFileFromArtifactReturnsOnCall(1, &fakeReadCloser{str: "jobs: [{"}, nil)
+						fakeArtifactStreamer.StreamFileFromArtifactReturnsOnCall(2, &fakeReadCloser{str: "jobs: [{"}, nil)
+					})
+
+					It("should give up after the retries", func() {
+						Expect(stepErr).To(HaveOccurred())
+						Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(3))
+					})
+				})
+
+				Context("when a file cache is configured", func() {
+					BeforeEach(func() {
+						fileCache = exec.NewSetPipelineFileCache(fakeclock.NewFakeClock(time.Now()), time.Minute, 10)
+					})
+
+					It("should not use the cached incomplete file", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(2))
+					})
+				})
+			})
+		})
+
+		Context("when the config file has multiple documents", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+					return &fakeReadCloser{str: `
+---
+resources:
+- name: some-repo
+  type: git
+  source: {uri: "git@github.com:concourse/concourse.git", branch: master}
+jobs:
+- name: some-job
+  plan:
+  - get: some-repo
+--- # overrides
+resources:
+- name: some-repo
+  source: {branch: release}
+---
+jobs:
+- name: other-job
+  plan:
+  - get: some-repo
+`}, nil
+				}
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should merge the documents by name", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+
+				_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+				Expect(config.Resources).To(HaveLen(1))
+				Expect(config.Resources[0].Source).To(Equal(atc.Source{
+					"uri":    "git@github.com:concourse/concourse.git",
+					"branch": "release",
+				}))
+
+				var jobNames []string
+				for _, job := range config.Jobs {
+					jobNames = append(jobNames, job.Name)
+				}
+				Expect(jobNames).To(ConsistOf("some-job", "other-job"))
+			})
+
+			Context("when a document is malformed", func() {
+				BeforeEach(func() {
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						return &fakeReadCloser{str: "jobs: []\n---\njobs: [\n"}, nil
+					}
+				})
+
+				It("should return error without saving", func() {
+					Expect(stepErr).To(HaveOccurred())
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when strategic_merge_files is set", func() {
+			BeforeEach(func() {
+				spPlan.StrategicMergeFiles = []string{"some-resource/overlay.yml"}
+
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+					if path == "overlay.yml" {
+						return &fakeReadCloser{str: `
+resources:
+- name: some-repo
+  source: {branch: release}
+jobs:
+- name: other-job
+  plan:
+  - get: some-repo
+`}, nil
+					}
+
+					return &fakeReadCloser{str: `
+resources:
+- name: some-repo
+  type: git
+  source: {uri: "git@github.com:concourse/concourse.git", branch: master}
+jobs:
+- name: some-job
+  plan:
+  - get: some-repo
+`}, nil
+				}
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should merge the files by name", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+
+				_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+				Expect(config.Resources).To(HaveLen(1))
+				Expect(config.Resources[0].Type).To(Equal("git"))
+				Expect(config.Resources[0].Source).To(Equal(atc.Source{
+					"uri":    "git@github.com:concourse/concourse.git",
+					"branch": "release",
+				}))
+
+				var jobNames []string
+				for _, job := range config.Jobs {
+					jobNames = append(jobNames, job.Name)
+				}
+				Expect(jobNames).To(ConsistOf("some-job", "other-job"))
+			})
+
+			Context("when template_engine is set", func() {
+				BeforeEach(func() {
+					spPlan.TemplateEngine = "go-template"
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(MatchError("`strategic_merge_files` can not be used with `template_engine`"))
+				})
+			})
+		})
+
+		Context("when template_engine is go-template", func() {
+			BeforeEach(func() {
+				spPlan.TemplateEngine = "go-template"
+				spPlan.VarFiles = []string{"some-resource/vars.yml"}
+
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+					if path == "vars.yml" {
+						return &fakeReadCloser{str: `{jobs: [alpha, beta], greeting: "  hello  "}`}, nil
+					}
+
+					return &fakeReadCloser{str: `
+jobs:
+{{- range .jobs }}
+- name: {{ . }}
+  plan:
+  - task: say-{{ . }}
+    config:
+      platform: linux
+      rootfs_uri: some-image
+      run:
+        path: echo
+        args: [{{ trim $.greeting | title }}]
+{{- end }}
+`}, nil
+				}
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should render the config with the var files", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+
+				_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+				Expect(config.Jobs).To(HaveLen(2))
+				Expect(config.Jobs[0].Name).To(Equal("alpha"))
+				Expect(config.Jobs[1].Name).To(Equal("beta"))
+				Expect(config.Jobs[1].PlanSequence[0].Config.(*atc.TaskStep).Config.Run.Args).To(Equal([]string{"Hello"}))
+			})
+
+			Context("when the template references a missing var", func() {
+				BeforeEach(func() {
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						if path == "vars.yml" {
+							return &fakeReadCloser{str: `{}`}, nil
+						}
+
+						return &fakeReadCloser{str: `jobs: [{name: {{ .missing }}}]`}, nil
+					}
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(HaveOccurred())
+					Expect(stepErr.Error()).To(ContainSubstring("render template"))
+				})
+			})
+		})
+
+		Context("when a var is set by both vars and a var file", func() {
+			BeforeEach(func() {
+				spPlan.Vars = map[string]interface{}{"greeting": "from-vars"}
+				spPlan.VarFiles = []string{"some-resource/vars.yml"}
+
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+					if path == "vars.yml" {
+						return &fakeReadCloser{str: `greeting: from-file`}, nil
+					}
+
+					return &fakeReadCloser{str: `
+jobs:
+- name: some-job
+  plan:
+  - task: some-task
+    config:
+      platform: linux
+      rootfs_uri: some-image
+      run:
+        path: echo
+        args: [((greeting))]
+`}, nil
+				}
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			savedArgs := func() []string {
+				_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+				return config.Jobs[0].PlanSequence[0].Config.(*atc.TaskStep).Config.Run.Args
+			}
+
+			It("should use the value from vars", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(savedArgs()).To(Equal([]string{"from-vars"}))
+			})
+
+			Context("when var_precedence is files_first", func() {
+				BeforeEach(func() {
+					spPlan.VarPrecedence = exec.VarPrecedenceFilesFirst
+				})
+
+				It("should use the value from the var file", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(savedArgs()).To(Equal([]string{"from-file"}))
+				})
+			})
+
+			Context("when var_precedence is unknown", func() {
+				BeforeEach(func() {
+					spPlan.VarPrecedence = "random"
+				})
+
+				It("should return error without saving", func() {
+					Expect(stepErr).To(MatchError("unknown var precedence: random"))
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when a file cache is configured", func() {
+			BeforeEach(func() {
+				fakeSource.IDReturns("some-artifact-id")
+				fileCache = exec.NewSetPipelineFileCache(fakeclock.NewFakeClock(time.Now()), 5*time.Minute, 10)
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			Context("when the file is not cached", func() {
+				BeforeEach(func() {
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+				})
+
+				It("should stream the file", func() {
+					Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+				})
+
+				It("should cache the file", func() {
+					content, found := fileCache.Get(stepMetadata.TeamID, "some-artifact-id", "", "pipeline.yml")
+					Expect(found).To(BeTrue())
+					Expect(string(content)).To(Equal(pipelineContent))
+				})
+			})
+
+			Context("when the file is cached", func() {
+				BeforeEach(func() {
+					fileCache.Set(stepMetadata.TeamID, "some-artifact-id", "", "pipeline.yml", []byte(pipelineContent))
+				})
+
+				It("should not stream the file", func() {
+					Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+				})
+
+				It("should save the cached pipeline", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+					Expect(config).To(Equal(pipelineObject))
+				})
+			})
+
+			Context("when the artifact holds a version of a resource", func() {
+				BeforeEach(func() {
+					artifactRepository.RegisterArtifact("some-resource", runtime.GetArtifact{
+						VolumeHandle:    "some-artifact-id",
+						ResourceVersion: atc.Version{"ref": "v1"},
+					})
+
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+				})
+
+				Context("when the file is cached for the same version", func() {
+					BeforeEach(func() {
+						fileCache.Set(stepMetadata.TeamID, "some-artifact-id", `{"ref":"v1"}`, "pipeline.yml", []byte(pipelineContent))
+					})
+
+					It("should not stream the file", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the file is cached for another version", func() {
+					BeforeEach(func() {
+						fileCache.Set(stepMetadata.TeamID, "some-artifact-id", `{"ref":"v0"}`, "pipeline.yml", []byte(pipelineContent))
+					})
+
+					It("should stream the file", func() {
+						Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+					})
+
+					It("should cache the file for the artifact's version", func() {
+						_, found := fileCache.Get(stepMetadata.TeamID, "some-artifact-id", `{"ref":"v1"}`, "pipeline.yml")
+						Expect(found).To(BeTrue())
+					})
+				})
+			})
+
+			Context("when the file is cached for another team", func() {
+				BeforeEach(func() {
+					fileCache.Set(stepMetadata.TeamID+1, "some-artifact-id", "", "pipeline.yml", []byte(pipelineContent))
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+				})
+
+				It("should stream the file", func() {
+					Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when there are more var files than allowed", func() {
+			BeforeEach(func() {
+				maxVarFiles = 1
+				spPlan.VarFiles = []string{"some-resource/vars-1.yml", "some-resource/vars-2.yml"}
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError("too many var files: 2 exceeds the maximum of 1"))
+			})
+
+			It("should not stream any files", func() {
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when prefetch_var_files is set", func() {
+			var concurrent bool
+
+			BeforeEach(func() {
+				spPlan.PrefetchVarFiles = true
+				spPlan.VarFiles = []string{"some-resource/vars-1.yml", "some-resource/vars-2.yml"}
+
+				// each var file waits for the other to be requested, which only
+				// happens in time if they are fetched concurrently
+				var lock sync.Mutex
+				requested := 0
+				bothRequested := make(chan struct{})
+				concurrent = true
+
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+					switch path {
+					case "vars-1.yml", "vars-2.yml":
+						lock.Lock()
+						requested++
+						if requested == 2 {
+							close(bothRequested)
+						}
+						lock.Unlock()
+
+						select {
+						case <-bothRequested:
+						case <-time.After(time.Second):
+							lock.Lock()
+							concurrent = false
+							lock.Unlock()
+						}
+
+						if path == "vars-1.yml" {
+							return &fakeReadCloser{str: "repository: from-vars-1\nsome-var: from-vars-1\n"}, nil
+						}
+						return &fakeReadCloser{str: "repository: from-vars-2\n"}, nil
+					default:
+						return &fakeReadCloser{str: strings.Replace(pipelineContent, "repository: busybox", "repository: ((repository))", 1)}, nil
+					}
+				}
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should fetch the var files concurrently", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(concurrent).To(BeTrue())
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(3))
+			})
+
+			It("should apply the var files in order", func() {
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+				task := config.Jobs[0].PlanSequence[0].Config.(*atc.TaskStep)
+				Expect(task.Config.ImageResource.Source).To(Equal(atc.Source{"repository": "from-vars-1"}))
+			})
+
+			Context("when fetching a var file fails", func() {
+				BeforeEach(func() {
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						if path == "vars-2.yml" {
+							return nil, errors.New("nope")
+						}
+						return &fakeReadCloser{str: pipelineContent}, nil
+					}
+				})
+
+				It("should return error without saving", func() {
+					Expect(stepErr).To(MatchError("nope"))
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the file is in a configmap", func() {
+			BeforeEach(func() {
+				spPlan.File = "configmap:some-configmap/pipeline.yml"
+
+				fakeConfigMapFetcher.FetchConfigMapFileReturns([]byte(pipelineContent), nil)
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			It("should fetch the key from the team's configmap", func() {
+				Expect(fakeConfigMapFetcher.FetchConfigMapFileCallCount()).To(Equal(1))
+				_, teamName, name, key := fakeConfigMapFetcher.FetchConfigMapFileArgsForCall(0)
+				Expect(teamName).To(Equal("some-team"))
+				Expect(name).To(Equal("some-configmap"))
+				Expect(key).To(Equal("pipeline.yml"))
+			})
+
+			It("should save the config without streaming from an artifact", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				_, _, savedConfig, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+				Expect(savedConfig).To(Equal(pipelineObject))
+			})
+
+			Context("when the path has no key", func() {
+				BeforeEach(func() {
+					spPlan.File = "configmap:some-configmap"
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(MatchError("invalid configmap path configmap:some-configmap: must be of the form configmap:<name>/<key>"))
+				})
+			})
+
+			Context("when fetching the configmap fails", func() {
+				BeforeEach(func() {
+					fakeConfigMapFetcher.FetchConfigMapFileReturns(nil, errors.New("nope"))
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(MatchError("nope"))
+				})
+			})
+
+			Context("when configmaps are not configured", func() {
+				BeforeEach(func() {
+					configMapFetcher = nil
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(MatchError("cannot fetch configmap:some-configmap/pipeline.yml: kubernetes configmaps are not configured"))
+				})
+			})
+		})
+
+		Context("when template_engine is unknown", func() {
+			BeforeEach(func() {
+				spPlan.TemplateEngine = "jinja"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError("unknown template engine: jinja"))
+			})
+		})
+
+		Context("when diff_output has no path within the artifact", func() {
+			BeforeEach(func() {
+				spPlan.DiffOutput = "my-resource"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError("invalid diff_output: my-resource: must be of the form <artifact>/<path>"))
+			})
+		})
+
+		Context("when scope is unknown", func() {
+			BeforeEach(func() {
+				spPlan.Scope = "team"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError("unknown scope: team"))
+			})
+		})
+
+		Context("when diff_format is unknown", func() {
+			BeforeEach(func() {
+				spPlan.DiffFormat = "fancy"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(MatchError("unknown diff format: fancy"))
+			})
+		})
+
+		Context("when fetching the pipeline config stalls", func() {
+			BeforeEach(func() {
+				spPlan.Timeout = "10ms"
+
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(ctx context.Context, _ runtime.Artifact, _ string) (io.ReadCloser, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+			})
+
+			It("should fail with a timeout error", func() {
+				Expect(stepErr).To(Equal(exec.StepTimeoutError{Duration: 10 * time.Millisecond}))
+			})
+
+			It("should emit the timeout to the build event stream", func() {
+				Expect(fakeDelegate.ErroredCallCount()).To(Equal(1))
+				_, message := fakeDelegate.ErroredArgsForCall(0)
+				Expect(message).To(Equal("step timed out after 10ms"))
+			})
+
+			It("should not save the pipeline", func() {
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when fetching the pipeline config stalls without a timeout", func() {
+			var restoreTimeout func()
+
+			BeforeEach(func() {
+				restoreTimeout = exec.SetDefaultSetPipelineTimeout(10 * time.Millisecond)
+
+				fakeArtifactStreamer.StreamFileFromArtifactStub = func(ctx context.Context, _ runtime.Artifact, _ string) (io.ReadCloser, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+			})
+
+			AfterEach(func() {
+				restoreTimeout()
+			})
+
+			It("should fail with the default timeout", func() {
+				Expect(stepErr).To(Equal(exec.StepTimeoutError{Duration: 10 * time.Millisecond}))
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the team has no free set_pipeline slot", func() {
+			BeforeEach(func() {
+				spPlan.Timeout = "10ms"
+
+				limiter = exec.NewSetPipelineLimiter(1)
+				_, err := limiter.Acquire(context.Background(), stepMetadata.TeamID)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail with a timeout error", func() {
+				Expect(stepErr).To(Equal(exec.StepTimeoutError{Duration: 10 * time.Millisecond}))
+			})
+
+			It("should not fetch the pipeline config", func() {
+				Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the team is limited to one set_pipeline step at a time", func() {
+			var slotFreeDuringSave, slotFreeAfterSave bool
+
+			slotFree := func() bool {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				release, err := limiter.Acquire(ctx, stepMetadata.TeamID)
+				if err != nil {
+					return false
+				}
+
+				release()
+				return true
+			}
+
+			BeforeEach(func() {
+				limiter = exec.NewSetPipelineLimiter(1)
+
+				spPlan.TriggerChecks = true
+
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineStub = func(atc.PipelineRef, int, atc.Config, db.ConfigVersion, bool) (db.Pipeline, bool, error) {
+					slotFreeDuringSave = slotFree()
+					return fakePipeline, true, nil
+				}
+				fakePipeline.TriggerImmediateResourceChecksStub = func() error {
+					slotFreeAfterSave = slotFree()
+					return nil
+				}
+			})
+
+			It("should hold the slot until the pipeline has been saved", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(slotFreeDuringSave).To(BeFalse())
+				Expect(slotFreeAfterSave).To(BeTrue())
+			})
+		})
+
+		Context("when the timeout is malformed", func() {
+			BeforeEach(func() {
+				spPlan.Timeout = "bogus"
+			})
+
+			It("should return error", func() {
+				Expect(stepErr).To(HaveOccurred())
+				Expect(stepErr.Error()).To(ContainSubstring("parse timeout"))
+			})
+		})
+
+		Context("when pipeline has resources with a uri", func() {
+			var (
+				server   *httptest.Server
+				requests int
+				restore  func()
+			)
+
+			BeforeEach(func() {
+				restore = exec.AllowLoopbackAddresses()
+
+				requests = 0
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					w.WriteHeader(http.StatusOK)
+				}))
+
+				closedServer := httptest.NewServer(http.NotFoundHandler())
+				closedServer.Close()
+
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: fmt.Sprintf(`
+resources:
+- name: reachable-repo
+  type: git
+  source: {uri: %s}
+- name: unreachable-repo
+  type: git
+  source: {uri: %s}
+- name: ssh-repo
+  type: git
+  source: {uri: "git@github.com:concourse/concourse.git"}
+- name: other-resource
+  type: time
+  source: {uri: %s}
+jobs:
+- name: some-job
+  plan:
+  - get: reachable-repo
+  - get: unreachable-repo
+  - get: ssh-repo
+  - get: other-resource
+`, server.URL, closedServer.URL, closedServer.URL)}, nil)
+
+				fakeTeam.PipelineReturns(nil, false, nil)
+				fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+			})
+
+			AfterEach(func() {
+				server.Close()
+				restore()
+			})
+
+			It("should probe the uri of known resource types", func() {
+				Expect(requests).To(Equal(1))
+			})
+
+			It("should warn about unreachable resources", func() {
+				Expect(stderr).To(gbytes.Say("WARNING: resource 'unreachable-repo' may be unreachable"))
+				Expect(stderr).ToNot(gbytes.Say("other-resource"))
+			})
+
+			It("should still save the pipeline", func() {
+				Expect(stepErr).ToNot(HaveOccurred())
+				Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+			})
+
+			Context("when the reachability check is skipped", func() {
+				BeforeEach(func() {
+					spPlan.SkipReachabilityCheck = true
+				})
+
+				It("should not probe any resources", func() {
+					Expect(requests).To(Equal(0))
+					Expect(stderr).ToNot(gbytes.Say("may be unreachable"))
+				})
+			})
+
+			Context("when the resources are inside the ATC's network", func() {
+				BeforeEach(func() {
+					restore()
+				})
+
+				It("should not probe them", func() {
+					Expect(requests).To(Equal(0))
+					Expect(stderr).To(gbytes.Say("WARNING: resource 'reachable-repo' may be unreachable: .*requests to 127.0.0.1 are not allowed"))
+				})
+			})
+		})
+
+		Context("when pipeline file is good", func() {
+			BeforeEach(func() {
+				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
+			})
+
+			Context("when get pipeline fails", func() {
+				BeforeEach(func() {
+					fakeTeam.PipelineReturns(nil, false, errors.New("fail to get pipeline"))
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(HaveOccurred())
+					Expect(stepErr.Error()).To(Equal("fail to get pipeline"))
+				})
+			})
+
+			Context("when banned_patterns is set", func() {
+				BeforeEach(func() {
+					spPlan.BannedPatterns = []string{"TODO"}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should save the pipeline when no pattern matches", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				})
+
+				Context("when the config contains a banned pattern", func() {
+					BeforeEach(func() {
+						spPlan.BannedPatterns = []string{"TODO", "BusyBox"}
+					})
+
+					It("should return error listing the matches without saving", func() {
+						Expect(stepErr).To(Equal(exec.BannedPatternsError{
+							File: "some-resource/pipeline.yml",
+							Matches: []exec.BannedPatternMatch{
+								{Pattern: "BusyBox", Line: 11},
+							},
+						}))
+						Expect(stepErr.Error()).To(Equal("some-resource/pipeline.yml contains banned patterns:\n- line 11: BusyBox"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when encrypt_fields is set", func() {
+				BeforeEach(func() {
+					spPlan.EncryptFields = []string{"jobs[*].plan[0].config.image_resource.source.repository"}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeTeam.EncryptedVarReferenceStub = func(value string) (string, error) {
+						return "((encrypted:some-nonce/" + value + "))", nil
+					}
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should save the matching values as encrypted var references", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeTeam.EncryptedVarReferenceCallCount()).To(Equal(1))
+					Expect(fakeTeam.EncryptedVarReferenceArgsForCall(0)).To(Equal("busybox"))
+
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+					task := config.Jobs[0].PlanSequence[0].Config.(*atc.TaskStep)
+					Expect(task.Config.ImageResource.Source).To(Equal(atc.Source{
+						"repository": "((encrypted:some-nonce/busybox))",
+					}))
+				})
+
+				Context("when the existing pipeline has the value encrypted", func() {
+					BeforeEach(func() {
+						existingConfig := atc.Config{
+							Jobs: atc.JobConfigs{
+								{
+									Name: "some-job",
+									PlanSequence: []atc.Step{
+										{
+											Config: &atc.TaskStep{
+												Name: "some-task",
+												Config: &atc.TaskConfig{
+													Platform: "linux",
+													ImageResource: &atc.ImageResource{
+														Type:   "registry-image",
+														Source: atc.Source{"repository": "((encrypted:1/some-nonce/some-ciphertext))"},
+													},
+													Run: atc.TaskRunConfig{
+														Path: "echo",
+														Args: []string{"hello"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						}
+
+						fakeTeam.PipelineReturns(fakePipeline, true, nil)
+						fakePipeline.ConfigReturns(existingConfig, nil)
+						fakeTeam.DecryptVarReferenceReturns("busybox", true, nil)
+					})
+
+					It("should keep the existing reference and not report a diff", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeTeam.DecryptVarReferenceArgsForCall(0)).To(Equal("((encrypted:1/some-nonce/some-ciphertext))"))
+						Expect(fakeTeam.EncryptedVarReferenceCallCount()).To(Equal(0))
+						Expect(stdout).To(gbytes.Say("no changes to apply."))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+
+					Context("when the value has changed", func() {
+						BeforeEach(func() {
+							fakeTeam.DecryptVarReferenceReturns("alpine", true, nil)
+						})
+
+						It("should encrypt the new value before diffing", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeTeam.EncryptedVarReferenceCallCount()).To(Equal(1))
+							Expect(stdout).ToNot(gbytes.Say(`repository: busybox`))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+				})
+
+				Context("when the value can not be encrypted", func() {
+					BeforeEach(func() {
+						fakeTeam.EncryptedVarReferenceStub = nil
+						fakeTeam.EncryptedVarReferenceReturns("", db.ErrEncryptionNotConfigured)
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError(ContainSubstring("no encryption key is configured")))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when a pattern is malformed", func() {
+					BeforeEach(func() {
+						spPlan.EncryptFields = []string{"resources[x].source"}
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError(ContainSubstring("invalid encrypt_fields pattern")))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when check_image_tags is set", func() {
+				var (
+					registry     *httptest.Server
+					host         string
+					realmScheme  string
+					restore      func()
+					restoreTrust func()
+				)
+
+				BeforeEach(func() {
+					realmScheme = "https"
+					restore = exec.AllowLoopbackAddresses()
+				})
+
+				AfterEach(func() {
+					restore()
+					restoreTrust()
+				})
+
+				BeforeEach(func() {
+					registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						switch r.URL.Path {
+						case "/token":
+							username, password, _ := r.BasicAuth()
+							if username != "some-user" || password != "some-password" {
+								w.WriteHeader(http.StatusUnauthorized)
+								return
+							}
+							w.Write([]byte(`{"token":"some-token"}`))
+						case "/v2/private/app/manifests/latest":
+							if r.Header.Get("Authorization") != "Bearer some-token" {
+								w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s://%s/token",service="registry",scope="repository:private/app:pull"`, realmScheme, r.Host))
+								w.WriteHeader(http.StatusUnauthorized)
+								return
+							}
+						case "/v2/myapp/manifests/latest":
+						default:
+							w.WriteHeader(http.StatusNotFound)
+						}
+					}))
+
+					restoreTrust = exec.TrustTLSServer(registry)
+					host = strings.TrimPrefix(registry.URL, "https://")
+
+					spPlan.CheckImageTags = true
+
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: fmt.Sprintf(`
+resources:
+- name: app-image
+  type: docker-image
+  source: {repository: %[1]s/private/app, username: some-user, password: ((registry-password))}
+jobs:
+- name: some-job
+  plan:
+  - get: app-image
+  - task: good-task
+    image: app-image
+    config:
+      platform: linux
+      run: {path: echo}
+  - task: typo-task
+    config:
+      platform: linux
+      image_resource:
+        type: docker-image
+        source: {repository: %[1]s/myapp, tag: ltest}
+      run: {path: echo}
+  - task: other-task
+    config:
+      platform: linux
+      image_resource:
+        type: docker-image
+        source: {repository: %[1]s/myapp}
+      run: {path: echo}
+`, host)}, nil)
+
+					state.GetStub = vars.StaticVariables{"registry-password": "some-password"}.Get
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				AfterEach(func() {
+					registry.Close()
+				})
+
+				It("should warn about each image that does not exist after saving", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+
+					Expect(stderr).To(gbytes.Say(fmt.Sprintf("WARNING: image '%s/myapp:ltest' of task 'typo-task' in job 'some-job' does not exist", host)))
+					Expect(stderr.Contents()).ToNot(ContainSubstring("good-task"))
+					Expect(stderr.Contents()).ToNot(ContainSubstring("other-task"))
+				})
+
+				Context("when the registry can not be reached", func() {
+					BeforeEach(func() {
+						registry.Close()
+					})
+
+					It("should warn without failing", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say("WARNING: could not check image '.*/private/app:latest' of task 'good-task' in job 'some-job'"))
+					})
+				})
+
+				Context("when the registry is inside the ATC's network", func() {
+					BeforeEach(func() {
+						restore()
+					})
+
+					It("should not contact it", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say("WARNING: could not check image '.*/private/app:latest' of task 'good-task' in job 'some-job': .*requests to 127.0.0.1 are not allowed"))
+					})
+				})
+
+				Context("when the registry's auth service is not served over https", func() {
+					BeforeEach(func() {
+						realmScheme = "http"
+					})
+
+					It("should not send the credentials", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say(`WARNING: could not check image '.*/private/app:latest' of task 'good-task' in job 'some-job': invalid registry auth realm: "http://`))
+					})
+				})
+			})
+
+			Context("when check_image_resource_types is set", func() {
+				BeforeEach(func() {
+					spPlan.CheckImageResourceTypes = true
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				Context("when a worker provides the image's resource type", func() {
+					BeforeEach(func() {
+						fakeWorker := new(dbfakes.FakeWorker)
+						fakeWorker.ResourceTypesReturns([]atc.WorkerResourceType{{Type: "registry-image"}})
+						fakeTeam.WorkersReturns([]db.Worker{fakeWorker}, nil)
+					})
+
+					It("should not warn", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr.Contents()).ToNot(ContainSubstring("image_resource"))
+					})
+				})
+
+				Context("when no worker provides the image's resource type", func() {
+					It("should warn after saving", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(stderr).To(gbytes.Say("WARNING: image_resource of task 'some-task' in job 'some-job' uses resource type 'registry-image' which is not provided by any worker"))
+					})
+				})
+
+				Context("when the image's resource type is a pipeline resource type without a version", func() {
+					BeforeEach(func() {
+						fakeResourceType := new(dbfakes.FakeResourceType)
+						fakeResourceType.NameReturns("registry-image")
+						fakePipeline.ResourceTypesReturns(db.ResourceTypes{fakeResourceType}, nil)
+					})
+
+					It("should warn", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say("WARNING: image_resource of task 'some-task' in job 'some-job' uses resource type 'registry-image' which has no version yet"))
+					})
+				})
+			})
+
+			Context("when vault_dynamic_vars are set", func() {
+				BeforeEach(func() {
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						if path == "vars.yml" {
+							return &fakeReadCloser{str: "repository: from-var-file\n"}, nil
+						}
+						return &fakeReadCloser{str: strings.Replace(pipelineContent, "repository: busybox", "repository: ((repository))", 1)}, nil
+					}
+
+					spPlan.VarFiles = []string{"some-resource/vars.yml"}
+					spPlan.VaultDynamicVars = []atc.VaultDynamicVar{
+						{Path: "images", Keys: []string{"repository"}},
+					}
+
+					fakeVaultReader.NewSecretLookupPathsReturns([]creds.SecretLookupPath{
+						creds.NewSecretLookupWithPrefix("/concourse/some-team/some-pipeline/"),
+						creds.NewSecretLookupWithPrefix("/concourse/some-team/"),
+					})
+					fakeVaultReader.ReadStub = func(path string) (*vaultapi.Secret, error) {
+						if path == "/concourse/some-team/images" {
+							return &vaultapi.Secret{
+								Data: map[string]interface{}{"repository": "from-vault", "tag": "latest"},
+							}, nil
+						}
+
+						return nil, nil
+					}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should look the secret up under the team's paths", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+
+					team, pipeline, allowRootPath := fakeVaultReader.NewSecretLookupPathsArgsForCall(0)
+					Expect(team).To(Equal("some-team"))
+					Expect(pipeline).To(Equal("some-pipeline"))
+					Expect(allowRootPath).To(BeFalse())
+
+					Expect(fakeVaultReader.ReadCallCount()).To(Equal(2))
+					Expect(fakeVaultReader.ReadArgsForCall(0)).To(Equal("/concourse/some-team/some-pipeline/images"))
+					Expect(fakeVaultReader.ReadArgsForCall(1)).To(Equal("/concourse/some-team/images"))
+				})
+
+				It("should resolve vars to vault references over var files", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+
+					_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+					task := config.Jobs[0].PlanSequence[0].Config.(*atc.TaskStep)
+					Expect(task.Config.ImageResource.Source).To(Equal(atc.Source{"repository": "((images.repository))"}))
+				})
+
+				Context("when a key is missing from the secret", func() {
+					BeforeEach(func() {
+						spPlan.VaultDynamicVars[0].Keys = []string{"password"}
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(Equal(exec.VaultKeyNotFoundError{Path: "images", Key: "password"}))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when two entries provide the same key", func() {
+					BeforeEach(func() {
+						spPlan.VaultDynamicVars = append(spPlan.VaultDynamicVars, atc.VaultDynamicVar{
+							Path: "other-images",
+							Keys: []string{"repository"},
+						})
+					})
+
+					It("should return error without reading them", func() {
+						Expect(stepErr).To(MatchError("`vault_dynamic_vars` key 'repository' is provided by both 'images' and 'other-images'"))
+						Expect(fakeVaultReader.ReadCallCount()).To(Equal(0))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the secret does not exist", func() {
+					BeforeEach(func() {
+						fakeVaultReader.ReadStub = nil
+						fakeVaultReader.ReadReturns(nil, nil)
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("secret 'images' not found in vault"))
+					})
+				})
+
+				Context("when the path escapes the team's paths", func() {
+					BeforeEach(func() {
+						spPlan.VaultDynamicVars[0].Path = "../other-team/images"
+					})
+
+					It("should return error without reading it", func() {
+						Expect(stepErr).To(MatchError("vault_dynamic_vars path '../other-team/images' must not contain '..'"))
+						Expect(fakeVaultReader.ReadCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the path is absolute", func() {
+					BeforeEach(func() {
+						spPlan.VaultDynamicVars[0].Path = "/secret/images"
+					})
+
+					It("should return error without reading it", func() {
+						Expect(stepErr).To(MatchError("vault_dynamic_vars path '/secret/images' must be relative to the team's secret paths"))
+						Expect(fakeVaultReader.ReadCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when vault is not configured", func() {
+					BeforeEach(func() {
+						vaultSecretReader = nil
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("vault_dynamic_vars requires vault to be configured as the credential manager"))
+					})
+				})
+
+				Context("when scope is build", func() {
+					var tagLookups int
+
+					runAnotherStep := func() (bool, error) {
+						return exec.NewSetPipelineStep(
+							"57",
+							*spPlan,
+							stepMetadata,
+							fakeDelegateFactory,
+							fakeTeamFactory,
+							fakeBuildFactory,
+							fakeArtifactStreamer,
+							fakeChecker,
+							maxVarFiles,
+							fileCache,
+							limiter,
+							configMapFetcher,
+							fakeLinter,
+							fakeArtifactWriter,
+							vaultSecretReader,
+							eventPublisher,
+							fakeCredentialInvalidator,
+						).Run(ctx, state)
+					}
+
+					BeforeEach(func() {
+						results := map[atc.PlanID]interface{}{}
+						state.StoreResultStub = func(id atc.PlanID, val interface{}) {
+							results[id] = val
+						}
+						state.ResultStub = func(id atc.PlanID, to interface{}) bool {
+							val, found := results[id]
+							if !found {
+								return false
+							}
+
+							reflect.ValueOf(to).Elem().Set(reflect.ValueOf(val))
+							return true
+						}
+
+						tagLookups = 0
+						state.GetStub = func(ref vars.Reference) (interface{}, bool, error) {
+							if ref.Path == "image-tag" {
+								tagLookups++
+								return "latest", true, nil
+							}
+
+							return nil, false, nil
+						}
+
+						spPlan.Vars = map[string]interface{}{"tag": "((image-tag))"}
+						spPlan.Scope = exec.SetPipelineScopeBuild
+					})
+
+					It("should reuse the resolved vars in later steps of the scope", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+
+						ok, err := runAnotherStep()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(ok).To(BeTrue())
+
+						Expect(fakeVaultReader.ReadCallCount()).To(Equal(2))
+						Expect(tagLookups).To(Equal(1))
+					})
+
+					Context("when a later step has no scope", func() {
+						It("should resolve the vars again", func() {
+							spPlan.Scope = ""
+
+							_, err := runAnotherStep()
+							Expect(err).ToNot(HaveOccurred())
+
+							Expect(fakeVaultReader.ReadCallCount()).To(Equal(4))
+							Expect(tagLookups).To(Equal(2))
+						})
+					})
+				})
+			})
+
+			Context("when notifications are set", func() {
+				BeforeEach(func() {
+					spPlan.Notifications = &atc.PipelineNotifications{
+						OnFailure: []string{"email:ops@example.com"},
+					}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should store the notifications after saving", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakePipeline.SetNotificationsCallCount()).To(Equal(1))
+					Expect(fakePipeline.SetNotificationsArgsForCall(0)).To(Equal(atc.PipelineNotifications{
+						OnFailure: []string{"email:ops@example.com"},
+					}))
+				})
+
+				Context("when a recipient is invalid", func() {
+					BeforeEach(func() {
+						spPlan.Notifications.OnSuccess = []string{"pager:ops"}
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("invalid notifications: unknown notification scheme 'pager' in recipient: pager:ops"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when notifications are not set", func() {
+					BeforeEach(func() {
+						spPlan.Notifications = nil
+					})
+
+					It("should clear the stored notifications", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakePipeline.SetNotificationsCallCount()).To(Equal(0))
+						Expect(fakePipeline.ClearNotificationsCallCount()).To(Equal(1))
+					})
+
+					Context("when clearing the notifications fails", func() {
+						BeforeEach(func() {
+							fakePipeline.ClearNotificationsReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+			})
+
+			Context("when owners are set", func() {
+				BeforeEach(func() {
+					spPlan.Owners = &atc.PipelineOwnership{
+						Team:    "platform-eng",
+						Contact: "platform@example.com",
+					}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should store the ownership after saving", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakePipeline.SetOwnershipCallCount()).To(Equal(1))
+					Expect(fakePipeline.SetOwnershipArgsForCall(0)).To(Equal(atc.PipelineOwnership{
+						Team:    "platform-eng",
+						Contact: "platform@example.com",
+					}))
+					Expect(fakePipeline.ClearOwnershipCallCount()).To(Equal(0))
+				})
+
+				Context("when owners are removed", func() {
+					BeforeEach(func() {
+						spPlan.Owners = nil
+					})
+
+					It("should clear the ownership", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakePipeline.SetOwnershipCallCount()).To(Equal(0))
+						Expect(fakePipeline.ClearOwnershipCallCount()).To(Equal(1))
+					})
+
+					Context("when clearing the ownership fails", func() {
+						BeforeEach(func() {
+							fakePipeline.ClearOwnershipReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when storing the ownership fails", func() {
+					BeforeEach(func() {
+						fakePipeline.SetOwnershipReturns(errors.New("nope"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("nope"))
+					})
+				})
+			})
+
+			Context("when archive_after is set", func() {
+				BeforeEach(func() {
+					spPlan.ArchiveAfter = "30d"
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should register the ttl after saving", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakePipeline.SetArchiveAfterCallCount()).To(Equal(1))
+					Expect(fakePipeline.SetArchiveAfterArgsForCall(0)).To(Equal(30 * 24 * time.Hour))
+					Expect(fakePipeline.ClearArchiveAfterCallCount()).To(Equal(0))
+				})
+
+				Context("when it is removed", func() {
+					BeforeEach(func() {
+						spPlan.ArchiveAfter = ""
+					})
+
+					It("should clear the ttl", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakePipeline.SetArchiveAfterCallCount()).To(Equal(0))
+						Expect(fakePipeline.ClearArchiveAfterCallCount()).To(Equal(1))
+					})
+
+					Context("when clearing the ttl fails", func() {
+						BeforeEach(func() {
+							fakePipeline.ClearArchiveAfterReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when it is a go duration", func() {
+					BeforeEach(func() {
+						spPlan.ArchiveAfter = "36h"
+					})
+
+					It("should register the ttl", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakePipeline.SetArchiveAfterArgsForCall(0)).To(Equal(36 * time.Hour))
+					})
+				})
+
+				Context("when it is not a valid duration", func() {
+					BeforeEach(func() {
+						spPlan.ArchiveAfter = "soon"
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError(ContainSubstring("invalid archive_after")))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when it is not positive", func() {
+					BeforeEach(func() {
+						spPlan.ArchiveAfter = "0d"
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("invalid archive_after: must be positive"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when registering the ttl fails", func() {
+					BeforeEach(func() {
+						fakePipeline.SetArchiveAfterReturns(errors.New("nope"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("nope"))
+					})
+				})
+			})
+
+			Context("when the pipeline is saved", func() {
+				BeforeEach(func() {
+					fakePipeline.ConfigVersionReturns(3)
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should annotate the build with the config version", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeDelegate.SetBuildAnnotationCallCount()).To(Equal(1))
+
+					_, key, value := fakeDelegate.SetBuildAnnotationArgsForCall(0)
+					Expect(key).To(Equal("set_pipeline_version"))
+					Expect(value).To(Equal("3"))
+				})
+			})
+
+			Context("when notify_event_bus is set", func() {
+				BeforeEach(func() {
+					spPlan.NotifyEventBus = true
+
+					fakeTeam.NameReturns("some-team")
+					fakePipeline.NameReturns("some-pipeline")
+					fakePipeline.ConfigVersionReturns(3)
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should publish a pipeline updated event", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeEventPublisher.PublishCallCount()).To(Equal(1))
+
+					_, eventType, data := fakeEventPublisher.PublishArgsForCall(0)
+					Expect(eventType).To(Equal("concourse.pipeline.updated"))
+					Expect(data).To(Equal(eventbus.PipelineUpdated{
+						Team:          "some-team",
+						Pipeline:      "some-pipeline",
+						InstanceVars:  atc.InstanceVars{"branch": "feature/foo"},
+						ConfigVersion: 3,
+						Created:       true,
+						Diff: atc.DiffConfigResponse{
+							Added:    []atc.ConfigDiffEntry{{Type: "job", Name: "some-job"}},
+							Removed:  []atc.ConfigDiffEntry{},
+							Modified: []atc.ConfigDiffEntry{},
+						},
+						BuildID: 42,
+					}))
+				})
+
+				Context("when publishing fails", func() {
+					BeforeEach(func() {
+						fakeEventPublisher.PublishReturns(errors.New("broker down"))
+					})
+
+					It("should warn without failing", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stepOk).To(BeTrue())
+						Expect(stderr).To(gbytes.Say("WARNING: failed to notify the event bus: broker down"))
+					})
+				})
+
+				Context("when no event bus is configured", func() {
+					BeforeEach(func() {
+						eventPublisher = nil
+					})
+
+					It("should warn without failing", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say("WARNING: failed to notify the event bus: no event bus is configured"))
+					})
+				})
+			})
+
+			Context("when notify_event_bus is not set", func() {
+				BeforeEach(func() {
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should not publish an event", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeEventPublisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when file is auto", func() {
+				BeforeEach(func() {
+					spPlan.File = "auto"
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				Context("when exactly one artifact has a yaml extension", func() {
+					BeforeEach(func() {
+						artifactRepository.RegisterArtifact("pipeline.yml", new(buildfakes.FakeRegisterableArtifact))
+					})
+
+					It("should read the config from that artifact", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+						_, _, path := fakeArtifactStreamer.StreamFileFromArtifactArgsForCall(0)
+						Expect(path).To(Equal("pipeline.yml"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when no artifact has a yaml extension", func() {
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("file: auto found no artifact with a .yml or .yaml extension"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when multiple artifacts have a yaml extension", func() {
+					BeforeEach(func() {
+						artifactRepository.RegisterArtifact("pipeline.yml", new(buildfakes.FakeRegisterableArtifact))
+						artifactRepository.RegisterArtifact("other.yaml", new(buildfakes.FakeRegisterableArtifact))
+					})
+
+					It("should return error listing the candidates", func() {
+						Expect(stepErr).To(MatchError("file: auto matched multiple artifacts: other.yaml, pipeline.yml"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when feature_flag_check is set", func() {
+				BeforeEach(func() {
+					spPlan.FeatureFlagCheck = true
+
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+jobs:
+- name: set-branches
+  plan:
+  - set_pipeline: branch
+    file: some-resource/branch.yml
+    instance_vars: {branch: main}
+`}, nil)
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				Context("when the features used by the config are enabled", func() {
+					It("should save the pipeline", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when a feature used by the config is disabled", func() {
+					BeforeEach(func() {
+						atc.EnablePipelineInstances = false
+						spPlan.InstanceVars = nil
+					})
+
+					AfterEach(func() {
+						atc.EnablePipelineInstances = true
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("pipeline uses features which are disabled on this ATC: pipeline_instances (job 'set-branches')"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when force_credential_refresh is set", func() {
+				BeforeEach(func() {
+					spPlan.ForceCredentialRefresh = true
+
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+resources:
+- name: some-resource
+  type: git
+  source: {uri: ((uri)), private_key: ((git.private_key)), token: ((vault:token))}
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+  - put: some-resource
+    params: {key: ((git.private_key))}
+`}, nil)
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should invalidate each credential manager var of the pipeline once", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeCredentialInvalidator.InvalidateVariableCallCount()).To(Equal(2))
+
+					team, pipeline, ref := fakeCredentialInvalidator.InvalidateVariableArgsForCall(0)
+					Expect(team).To(Equal("some-team"))
+					Expect(pipeline).To(Equal("some-pipeline"))
+					Expect(ref.Path).To(Equal("git"))
+
+					_, _, ref = fakeCredentialInvalidator.InvalidateVariableArgsForCall(1)
+					Expect(ref.Path).To(Equal("uri"))
+				})
+
+				Context("when invalidating fails", func() {
+					BeforeEach(func() {
+						fakeCredentialInvalidator.InvalidateVariableReturns(errors.New("nope"))
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("nope"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when sbom is set", func() {
+				var fakeArtifact *runtimefakes.FakeArtifact
+
+				BeforeEach(func() {
+					spPlan.SBOM = true
+
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+resource_types:
+- name: checked-type
+  type: registry-image
+  source: {repository: ghcr.io/some-org/checked-type, tag: "1.0"}
+- name: unchecked-type
+  type: registry-image
+  source: {repository: unchecked-type}
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+resources:
+- name: some-resource
+  type: checked-type
+  source: {}
+`}, nil)
+
+					checkedType := new(dbfakes.FakeResourceType)
+					checkedType.NameReturns("checked-type")
+					checkedType.VersionReturns(atc.Version{"digest": "sha256:abc"})
+					fakePipeline.ResourceTypesReturns(db.ResourceTypes{checkedType}, nil)
+					fakePipeline.ConfigVersionReturns(7)
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+
+					fakeArtifact = new(runtimefakes.FakeArtifact)
+					fakeArtifactWriter.WriteArtifactFileReturns(fakeArtifact, nil)
+				})
+
+				It("should write an SPDX document listing the resource type images", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeArtifactWriter.WriteArtifactFileCallCount()).To(Equal(1))
+
+					_, spec := fakeArtifactWriter.WriteArtifactFileArgsForCall(0)
+					Expect(spec.Name).To(Equal("_sbom"))
+					Expect(spec.Path).To(Equal("pipeline.spdx.json"))
+
+					var doc map[string]interface{}
+					Expect(json.Unmarshal(spec.Content, &doc)).To(Succeed())
+					Expect(doc["spdxVersion"]).To(Equal("SPDX-2.3"))
+					Expect(doc["name"]).To(Equal(`some-team/some-pipeline/branch:"feature/foo"`))
+					Expect(doc["packages"]).To(Equal([]interface{}{
+						map[string]interface{}{
+							"SPDXID":           "SPDXRef-ResourceType-1",
+							"name":             "some-org/checked-type",
+							"versionInfo":      "sha256:abc",
+							"downloadLocation": "NOASSERTION",
+							"filesAnalyzed":    false,
+							"externalRefs": []interface{}{
+								map[string]interface{}{
+									"referenceCategory": "PACKAGE-MANAGER",
+									"referenceType":     "purl",
+									"referenceLocator":  "pkg:docker/some-org/checked-type@sha256%3Aabc?repository_url=ghcr.io",
+								},
+							},
+						},
+						map[string]interface{}{
+							"SPDXID":           "SPDXRef-ResourceType-2",
+							"name":             "library/unchecked-type",
+							"versionInfo":      "latest",
+							"downloadLocation": "NOASSERTION",
+							"filesAnalyzed":    false,
+							"externalRefs": []interface{}{
+								map[string]interface{}{
+									"referenceCategory": "PACKAGE-MANAGER",
+									"referenceType":     "purl",
+									"referenceLocator":  "pkg:docker/library/unchecked-type@latest",
+								},
+							},
+						},
+					}))
+				})
+
+				It("should register the artifact", func() {
+					artifact, found := artifactRepository.ArtifactFor("_sbom")
+					Expect(found).To(BeTrue())
+					Expect(artifact).To(Equal(fakeArtifact))
+				})
+			})
+
+			Context("when min_jobs is set", func() {
+				BeforeEach(func() {
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				Context("when the config has enough jobs", func() {
+					BeforeEach(func() {
+						spPlan.MinJobs = 1
+					})
+
+					It("should save the pipeline", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when the config has too few jobs", func() {
+					BeforeEach(func() {
+						spPlan.MinJobs = 2
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("pipeline config has 1 jobs, fewer than the 2 required by min_jobs"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when min_jobs is negative", func() {
+					BeforeEach(func() {
+						spPlan.MinJobs = -1
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("`min_jobs` must not be negative"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when max_parallel_jobs is set", func() {
+				BeforeEach(func() {
+					fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+jobs:
+- name: some-job
+  plan: [{task: some-task, config: {platform: linux, run: {path: echo}}}]
+- name: some-parallel-job
+  max_in_flight: 4
+  plan: [{task: some-task, config: {platform: linux, run: {path: echo}}}]
+- name: some-serial-job
+  serial: true
+  plan: [{task: some-task, config: {platform: linux, run: {path: echo}}}]
+`}, nil)
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				Context("when the jobs fit within the limit", func() {
+					BeforeEach(func() {
+						spPlan.MaxParallelJobs = 6
+					})
+
+					It("should save the pipeline", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when the jobs exceed the limit", func() {
+					BeforeEach(func() {
+						spPlan.MaxParallelJobs = 5
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("pipeline config may run 6 builds in parallel, more than the 5 allowed by max_parallel_jobs"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when max_parallel_jobs is negative", func() {
+					BeforeEach(func() {
+						spPlan.MaxParallelJobs = -1
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(MatchError("`max_parallel_jobs` must not be negative"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when reporting progress", func() {
+				BeforeEach(func() {
+					spPlan.VarFiles = []string{"some-resource/vars-1.yml", "some-resource/vars-2.yml"}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should report each stage of setting the pipeline", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+
+					var messages []string
+					for i := 0; i < fakeDelegate.SetPipelineProgressCallCount(); i++ {
+						_, message := fakeDelegate.SetPipelineProgressArgsForCall(i)
+						messages = append(messages, message)
+					}
+
+					Expect(messages).To(Equal([]string{
+						"fetching var_file 1 of 2",
+						"fetching var_file 2 of 2",
+						"validating config",
+						"computing diff",
+					}))
+				})
+
+				Context("when var files are fetched", func() {
+					varFileContent := "foo: bar\n"
+
+					BeforeEach(func() {
+						fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+							if path == "pipeline.yml" {
+								return &fakeReadCloser{str: pipelineContent}, nil
+							}
+
+							return &fakeReadCloser{str: varFileContent}, nil
+						}
+					})
+
+					It("should report the digest of each var file", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeDelegate.VarFileFetchedCallCount()).To(Equal(2))
+
+						digest := sha256.Sum256([]byte(varFileContent))
+						for i, path := range spPlan.VarFiles {
+							_, fetch := fakeDelegate.VarFileFetchedArgsForCall(i)
+							Expect(fetch.Path).To(Equal(path))
+							Expect(fetch.DigestHex).To(Equal(hex.EncodeToString(digest[:])))
+							Expect(fetch.SizeBytes).To(Equal(int64(len(varFileContent))))
+						}
+					})
+				})
+			})
+
+			Context("when lint_image is set", func() {
+				BeforeEach(func() {
+					spPlan.LintImage = "openpolicyagent/conftest:v0.23.0"
+					spPlan.LintPolicyFile = "some-resource/policies/pipeline.rego"
+
+					fakeDelegate.FetchImageReturns(worker.ImageSpec{ImageURL: "some-fetched-image"}, nil)
+
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						if path == "policies/pipeline.rego" {
+							return &fakeReadCloser{str: "package main"}, nil
+						}
+						return &fakeReadCloser{str: pipelineContent}, nil
+					}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should lint the resolved config with the policy before saving", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeLinter.LintCallCount()).To(Equal(1))
+
+					_, spec, _, _ := fakeLinter.LintArgsForCall(0)
+					Expect(spec.Image).To(Equal(worker.ImageSpec{ImageURL: "some-fetched-image"}))
+					Expect(spec.TeamID).To(Equal(stepMetadata.TeamID))
+					Expect(string(spec.Policy)).To(Equal("package main"))
+					Expect(string(spec.Config)).To(ContainSubstring("repository: busybox"))
+
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				})
+
+				It("should fetch the image like an image_resource", func() {
+					Expect(fakeDelegate.FetchImageCallCount()).To(Equal(1))
+
+					_, image, types, privileged := fakeDelegate.FetchImageArgsForCall(0)
+					Expect(image).To(Equal(atc.ImageResource{
+						Type:   "registry-image",
+						Source: atc.Source{"repository": "openpolicyagent/conftest", "tag": "v0.23.0"},
+					}))
+					Expect(types).To(BeNil())
+					Expect(privileged).To(BeFalse())
+				})
+
+				Context("when the image can not be fetched", func() {
+					BeforeEach(func() {
+						fakeDelegate.FetchImageReturns(worker.ImageSpec{}, errors.New("image check failed"))
+					})
+
+					It("should return error without linting", func() {
+						Expect(stepErr).To(MatchError("lint pipeline: fetch image: image check failed"))
+						Expect(fakeLinter.LintCallCount()).To(Equal(0))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the linting tool exits non-zero", func() {
+					BeforeEach(func() {
+						fakeLinter.LintReturns(1, nil)
+					})
+
+					It("should fail without saving", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stepOk).To(BeFalse())
+						Expect(stderr).To(gbytes.Say("pipeline config failed linting with openpolicyagent/conftest:v0.23.0 \\(exit status 1\\)"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the linting tool can not be run", func() {
+					BeforeEach(func() {
+						fakeLinter.LintReturns(0, errors.New("no workers"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("lint pipeline: no workers"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when pre_validate_script is set", func() {
+				BeforeEach(func() {
+					spPlan.PreValidateScript = "some-resource/scripts/validate.sh"
+					spPlan.PreValidateImage = &atc.ImageResource{
+						Type:   "registry-image",
+						Source: atc.Source{"repository": "alpine", "tag": "3.13"},
+					}
+
+					fakeDelegate.FetchImageReturns(worker.ImageSpec{ImageURL: "some-fetched-image"}, nil)
+
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						if path == "scripts/validate.sh" {
+							return &fakeReadCloser{str: "#!/bin/sh\ngrep -q jobs"}, nil
+						}
+						return &fakeReadCloser{str: pipelineContent}, nil
+					}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should run the script against the raw config before saving", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeLinter.LintCallCount()).To(Equal(1))
+
+					_, spec, _, _ := fakeLinter.LintArgsForCall(0)
+					Expect(spec.Image).To(Equal(worker.ImageSpec{ImageURL: "some-fetched-image"}))
+					Expect(spec.TeamID).To(Equal(stepMetadata.TeamID))
+					Expect(string(spec.Script)).To(Equal("#!/bin/sh\ngrep -q jobs"))
+					Expect(string(spec.Config)).To(Equal(pipelineContent))
+
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				})
+
+				It("should run the script in the configured image", func() {
+					Expect(fakeDelegate.FetchImageCallCount()).To(Equal(1))
+
+					_, image, _, _ := fakeDelegate.FetchImageArgsForCall(0)
+					Expect(image).To(Equal(atc.ImageResource{
+						Type:   "registry-image",
+						Source: atc.Source{"repository": "alpine", "tag": "3.13"},
+					}))
+				})
+
+				Context("when the image can not be fetched", func() {
+					BeforeEach(func() {
+						fakeDelegate.FetchImageReturns(worker.ImageSpec{}, errors.New("image check failed"))
+					})
+
+					It("should return error without running the script", func() {
+						Expect(stepErr).To(MatchError("pre_validate_script: fetch image: image check failed"))
+						Expect(fakeLinter.LintCallCount()).To(Equal(0))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when no image is configured", func() {
+					BeforeEach(func() {
+						spPlan.PreValidateImage = nil
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("`pre_validate_image_resource` must be specified when `pre_validate_script` is set"))
+						Expect(fakeLinter.LintCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the script exits non-zero", func() {
+					BeforeEach(func() {
+						fakeLinter.LintReturns(3, nil)
+					})
+
+					It("should return error without saving", func() {
+						Expect(stepErr).To(Equal(exec.PreValidateScriptFailedError{
+							Script:     "some-resource/scripts/validate.sh",
+							ExitStatus: 3,
+						}))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the script can not be run", func() {
+					BeforeEach(func() {
+						fakeLinter.LintReturns(0, errors.New("no workers"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("pre_validate_script: no workers"))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when both pre_validate_script and lint_image are set", func() {
+				BeforeEach(func() {
+					spPlan.PreValidateScript = "some-resource/scripts/validate.sh"
+					spPlan.PreValidateImage = &atc.ImageResource{
+						Type:   "registry-image",
+						Source: atc.Source{"repository": "alpine", "tag": "3.13"},
+					}
+					spPlan.LintImage = "openpolicyagent/conftest:v0.23.0"
+					spPlan.LintPolicyFile = "some-resource/policies/pipeline.rego"
+
+					fakeDelegate.FetchImageReturns(worker.ImageSpec{ImageURL: "some-fetched-image"}, nil)
+
+					fakeArtifactStreamer.StreamFileFromArtifactStub = func(_ context.Context, _ runtime.Artifact, path string) (io.ReadCloser, error) {
+						switch path {
+						case "scripts/validate.sh":
+							return &fakeReadCloser{str: "#!/bin/sh\ngrep -q jobs"}, nil
+						case "policies/pipeline.rego":
+							return &fakeReadCloser{str: "package main"}, nil
+						}
+						return &fakeReadCloser{str: pipelineContent}, nil
+					}
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should run each in its own container", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(fakeLinter.LintCallCount()).To(Equal(2))
+
+					_, preValidateSpec, _, _ := fakeLinter.LintArgsForCall(0)
+					_, lintSpec, _, _ := fakeLinter.LintArgsForCall(1)
+
+					Expect(preValidateSpec.Owner).To(Equal(db.NewBuildStepContainerOwner(stepMetadata.BuildID, atc.PlanID(planID+"/pre-validate"), stepMetadata.TeamID)))
+					Expect(lintSpec.Owner).To(Equal(db.NewBuildStepContainerOwner(stepMetadata.BuildID, atc.PlanID(planID+"/lint"), stepMetadata.TeamID)))
+					Expect(preValidateSpec.Owner).ToNot(Equal(lintSpec.Owner))
+				})
+			})
+
+			Context("when lint_policy_file is set without lint_image", func() {
+				BeforeEach(func() {
+					spPlan.LintPolicyFile = "some-resource/policies/pipeline.rego"
+				})
+
+				It("should return error", func() {
+					Expect(stepErr).To(MatchError("`lint_image` must be specified when `lint_policy_file` is set"))
+				})
+			})
+
+			Context("when a remote target is set", func() {
+				var (
+					server        *httptest.Server
+					authorization string
+					savedBody     []byte
+					saveStatus    int
+				)
+
+				BeforeEach(func() {
+					saveStatus = http.StatusCreated
+					savedBody = nil
+
+					server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if r.URL.Path != "/api/v1/teams/main/pipelines/some-pipeline/config" {
+							w.WriteHeader(http.StatusTeapot)
+							return
+						}
+
+						authorization = r.Header.Get("Authorization")
+
+						switch r.Method {
+						case http.MethodGet:
+							w.WriteHeader(http.StatusNotFound)
+						case http.MethodPut:
+							savedBody, _ = ioutil.ReadAll(r.Body)
+							w.WriteHeader(saveStatus)
+							fmt.Fprint(w, `{"warnings":[{"type":"some-type","message":"some-warning"}]}`)
+						}
+					}))
+
+					state.GetStub = vars.StaticVariables{"TOKEN": "some-token"}.Get
+					spPlan.Target = &atc.SetPipelineTarget{
+						URL:      server.URL,
+						Team:     "main",
+						TokenVar: "TOKEN",
+					}
+
+					atc.SetPipelineRemoteTargets = []string{server.URL + "/"}
+				})
+
+				AfterEach(func() {
+					atc.SetPipelineRemoteTargets = nil
+					server.Close()
+				})
+
+				It("should set the pipeline through the remote API", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(stepOk).To(BeTrue())
+					Expect(authorization).To(Equal("Bearer some-token"))
+
+					var config atc.Config
+					Expect(atc.UnmarshalConfig(savedBody, &config)).To(Succeed())
+					Expect(config).To(Equal(pipelineObject))
+				})
+
+				It("should not save the pipeline locally", func() {
+					Expect(fakeTeam.PipelineCallCount()).To(Equal(0))
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+				})
+
+				It("should print the warnings from the remote", func() {
+					Expect(stdout).To(gbytes.Say("setting pipeline: some-pipeline/branch:\"feature/foo\" on " + server.URL))
+					Expect(stderr).To(gbytes.Say("WARNING: some-warning"))
+				})
+
+				It("should check the config against the policies of the remote team", func() {
+					Expect(fakeAgent.CheckCallCount()).To(Equal(1))
+					input := fakeAgent.CheckArgsForCall(0)
+					Expect(input.Team).To(Equal("main"))
+					Expect(input.Pipeline).To(Equal("some-pipeline"))
+				})
+
+				Context("when the remote target is not allowed", func() {
+					BeforeEach(func() {
+						atc.SetPipelineRemoteTargets = []string{"https://other.example.com"}
+					})
+
+					It("should return error without contacting the remote", func() {
+						Expect(stepErr).To(Equal(exec.RemoteTargetNotAllowedError{URL: server.URL}))
+						Expect(savedBody).To(BeNil())
+					})
+				})
+
+				Context("when the policy check fails", func() {
+					BeforeEach(func() {
+						result := policy.FailedPolicyCheck()
+						result.Reasons = append(result.Reasons, "foo")
+						fakeAgent.CheckReturns(result, nil)
+					})
+
+					It("should return error without setting the pipeline", func() {
+						Expect(stepErr).To(MatchError("policy check failed for set_pipeline: foo"))
+						Expect(savedBody).To(BeNil())
+					})
+				})
+
+				Context("when the token var is not defined", func() {
+					BeforeEach(func() {
+						spPlan.Target.TokenVar = "MISSING"
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("undefined token var: MISSING"))
+					})
+				})
+
+				Context("when the remote rejects the config", func() {
+					BeforeEach(func() {
+						saveStatus = http.StatusForbidden
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(HaveOccurred())
+					})
+				})
+
+				Context("when the url is missing", func() {
+					BeforeEach(func() {
+						spPlan.Target.URL = ""
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("`target.url` must be specified"))
+					})
+				})
+			})
+
+			Context("when another pipeline of the team has a job named like the pipeline", func() {
+				BeforeEach(func() {
+					fakeJob := new(dbfakes.FakeJob)
+					fakeJob.NameReturns("some-pipeline")
+					fakeJob.PipelineRefReturns(atc.PipelineRef{Name: "other-pipeline"})
+
+					ownJob := new(dbfakes.FakeJob)
+					ownJob.NameReturns("some-pipeline")
+					ownJob.PipelineRefReturns(atc.PipelineRef{Name: "some-pipeline", InstanceVars: atc.InstanceVars{"branch": "feature/foo"}})
+
+					fakeTeam.JobsNamedReturns(db.Jobs{fakeJob, ownJob}, nil)
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should look up the jobs with the pipeline's name", func() {
+					Expect(fakeTeam.JobsNamedCallCount()).To(Equal(1))
+					Expect(fakeTeam.JobsNamedArgsForCall(0)).To(Equal("some-pipeline"))
+				})
+
+				It("should warn about the collision and save the pipeline", func() {
+					Expect(stepErr).ToNot(HaveOccurred())
+					Expect(stderr).To(gbytes.Say("WARNING: pipeline 'some-pipeline' has the same name as job 'some-pipeline' in pipeline 'other-pipeline'"))
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+				})
+
+				It("should not warn about the pipeline's own jobs", func() {
+					Expect(stderr).ToNot(gbytes.Say("in pipeline 'some-pipeline/branch:feature/foo'"))
+				})
+
+				Context("when looking up the jobs fails", func() {
+					BeforeEach(func() {
+						fakeTeam.JobsNamedReturns(nil, errors.New("nope"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("nope"))
+					})
+				})
+			})
+
+			Context("when the pipeline has a job with its own name", func() {
+				BeforeEach(func() {
+					spPlan.Name = "some-job"
+					spPlan.InstanceVars = nil
+
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should warn about the collision", func() {
+					Expect(stderr).To(gbytes.Say("WARNING: pipeline 'some-job' has a job with the same name"))
+				})
+			})
+
+			Context("when specified pipeline not found", func() {
+				BeforeEach(func() {
+					fakeTeam.PipelineReturns(nil, false, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+				})
+
+				It("should save the pipeline", func() {
+					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+					ref, _, _, _, paused := fakeBuild.SavePipelineArgsForCall(0)
+					Expect(ref).To(Equal(atc.PipelineRef{
+						Name:         "some-pipeline",
+						InstanceVars: atc.InstanceVars{"branch": "feature/foo"},
+					}))
+					Expect(paused).To(BeFalse())
+				})
+
+				It("should stdout have message", func() {
+					Expect(stdout).To(gbytes.Say("done"))
+				})
+
+				It("should record the streamed files in the build", func() {
+					Expect(fakeBuild.RecordSetPipelineArtifactsCallCount()).To(Equal(1))
+					artifacts := fakeBuild.RecordSetPipelineArtifactsArgsForCall(0)
+					Expect(artifacts).To(HaveLen(1))
+					Expect(artifacts[0].Name).To(Equal("some-resource"))
+					Expect(artifacts[0].Path).To(Equal("pipeline.yml"))
+					Expect(artifacts[0].SizeBytes).To(Equal(len(pipelineContent)))
+					Expect(artifacts[0].Digest).To(HavePrefix("sha256:"))
+					Expect(artifacts[0].StreamedAt).ToNot(BeZero())
+				})
+
+				Context("when recording the streamed files fails", func() {
+					BeforeEach(func() {
+						fakeBuild.RecordSetPipelineArtifactsReturns(errors.New("nope"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("nope"))
+					})
+				})
+
+				It("should call the registered hooks", func() {
+					Expect(fakeSetPipelineHook.BeforeSaveCallCount()).To(Equal(1))
+					Expect(fakeSetPipelineHook.BeforeSaveArgsForCall(0)).To(Equal(pipelineObject))
+
+					Expect(fakeSetPipelineHook.AfterSaveCallCount()).To(Equal(1))
+					config, pipeline := fakeSetPipelineHook.AfterSaveArgsForCall(0)
+					Expect(config).To(Equal(pipelineObject))
+					Expect(pipeline).To(Equal(fakePipeline))
+				})
+
+				Context("when a before save hook fails", func() {
+					BeforeEach(func() {
+						fakeSetPipelineHook.BeforeSaveReturns(errors.New("not allowed"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(HaveOccurred())
+						Expect(stepErr.Error()).To(Equal("not allowed"))
+					})
+
+					It("should not save the pipeline", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						Expect(fakeSetPipelineHook.AfterSaveCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when trigger_checks is set", func() {
+					BeforeEach(func() {
+						spPlan.TriggerChecks = true
+					})
+
+					It("should trigger immediate resource checks", func() {
+						Expect(fakePipeline.TriggerImmediateResourceChecksCallCount()).To(Equal(1))
+					})
+
+					Context("when triggering checks fails", func() {
+						BeforeEach(func() {
+							fakePipeline.TriggerImmediateResourceChecksReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+
+					Context("when trigger_checks_jitter is set", func() {
+						BeforeEach(func() {
+							spPlan.TriggerChecksJitter = "10ms"
+						})
+
+						It("should trigger immediate resource checks", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakePipeline.TriggerImmediateResourceChecksCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when trigger_checks_jitter is invalid", func() {
+						BeforeEach(func() {
+							spPlan.TriggerChecksJitter = "soon"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError(ContainSubstring("invalid trigger_checks_jitter")))
+							Expect(fakePipeline.TriggerImmediateResourceChecksCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when label_resources is set", func() {
+					var fakeResource *dbfakes.FakeResource
+
+					BeforeEach(func() {
+						spPlan.LabelResources = true
+
+						fakeResource = new(dbfakes.FakeResource)
+						fakePipeline.ResourcesReturns(db.Resources{fakeResource}, nil)
+					})
+
+					It("should label the resources with the build", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeResource.SetMetadataCallCount()).To(Equal(1))
+						key, value := fakeResource.SetMetadataArgsForCall(0)
+						Expect(key).To(Equal("last_set_by_build"))
+						Expect(value).To(Equal("42"))
+					})
+
+					Context("when labeling a resource fails", func() {
+						BeforeEach(func() {
+							fakeResource.SetMetadataReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when queue_depth_check is set", func() {
+					var (
+						busyJob  *dbfakes.FakeJob
+						quietJob *dbfakes.FakeJob
+					)
+
+					BeforeEach(func() {
+						spPlan.QueueDepthCheck = true
+
+						busyJob = new(dbfakes.FakeJob)
+						busyJob.NameReturns("deploy")
+						busyJob.GetPendingBuildsReturns(make([]db.Build, 15), nil)
+
+						quietJob = new(dbfakes.FakeJob)
+						quietJob.NameReturns("unit")
+						quietJob.GetPendingBuildsReturns(make([]db.Build, 10), nil)
+
+						fakePipeline.JobsReturns(db.Jobs{busyJob, quietJob}, nil)
+					})
+
+					It("should warn about jobs with more pending builds than the default threshold", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say("WARNING: job 'deploy' has 15 pending builds"))
+						Expect(stderr.Contents()).ToNot(ContainSubstring("job 'unit'"))
+					})
+
+					Context("when queue_depth_threshold is set", func() {
+						BeforeEach(func() {
+							spPlan.QueueDepthThreshold = 5
+						})
+
+						It("should warn about jobs with more pending builds than the threshold", func() {
+							Expect(stderr).To(gbytes.Say("WARNING: job 'deploy' has 15 pending builds"))
+							Expect(stderr).To(gbytes.Say("WARNING: job 'unit' has 10 pending builds"))
+						})
+					})
+
+					Context("when fetching the pending builds fails", func() {
+						BeforeEach(func() {
+							busyJob.GetPendingBuildsReturns(nil, errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when trigger_first_job is set", func() {
+					var (
+						fakeJob  *dbfakes.FakeJob
+						firstRun *dbfakes.FakeBuild
+					)
+
+					BeforeEach(func() {
+						spPlan.TriggerFirstJob = true
+
+						firstRun = new(dbfakes.FakeBuild)
+						firstRun.NameReturns("1")
+
+						fakeJob = new(dbfakes.FakeJob)
+						fakeJob.CreateBuildReturns(firstRun, nil)
+						fakePipeline.JobReturns(fakeJob, true, nil)
+					})
+
+					It("should trigger a build of the first job", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakePipeline.JobCallCount()).To(Equal(1))
+						Expect(fakePipeline.JobArgsForCall(0)).To(Equal("some-job"))
+						Expect(fakeJob.CreateBuildCallCount()).To(Equal(1))
+						Expect(fakeJob.CreateBuildArgsForCall(0)).To(Equal("set_pipeline"))
+						Expect(stdout).To(gbytes.Say("triggered job some-job build #1"))
+					})
+
+					Context("when the first job has manual triggering disabled", func() {
+						BeforeEach(func() {
+							fakeJob.DisableManualTriggerReturns(true)
+						})
+
+						It("should not trigger a build", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeJob.CreateBuildCallCount()).To(Equal(0))
+							Expect(stdout).To(gbytes.Say("not triggering job some-job: manual triggering is disabled"))
+						})
+					})
+
+					Context("when the first job is not found", func() {
+						BeforeEach(func() {
+							fakePipeline.JobReturns(nil, false, nil)
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("job some-job not found"))
+						})
+					})
+
+					Context("when creating the build fails", func() {
+						BeforeEach(func() {
+							fakeJob.CreateBuildReturns(nil, errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when canary_jobs is set", func() {
+					var (
+						canaryJob   *dbfakes.FakeJob
+						otherJob    *dbfakes.FakeJob
+						canaryBuild *dbfakes.FakeBuild
+						previousRun *dbfakes.FakeBuild
+					)
+
+					BeforeEach(func() {
+						spPlan.CanaryJobs = []string{"canary-job"}
+
+						previousRun = new(dbfakes.FakeBuild)
+						previousRun.IDReturns(1)
+						previousRun.StatusReturns(db.BuildStatusSucceeded)
+
+						canaryBuild = new(dbfakes.FakeBuild)
+						canaryBuild.IDReturns(2)
+						canaryBuild.StatusReturns(db.BuildStatusSucceeded)
+
+						canaryJob = new(dbfakes.FakeJob)
+						canaryJob.NameReturns("canary-job")
+						canaryJob.FinishedAndNextBuildReturnsOnCall(0, previousRun, nil, nil)
+						canaryJob.FinishedAndNextBuildReturns(canaryBuild, nil, nil)
+
+						otherJob = new(dbfakes.FakeJob)
+						otherJob.NameReturns("other-job")
+
+						fakePipeline.JobsReturns(db.Jobs{canaryJob, otherJob}, nil)
+					})
+
+					It("should pause the other jobs until the canary job succeeds", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stepOk).To(BeTrue())
+						Expect(canaryJob.PauseCallCount()).To(Equal(0))
+						Expect(canaryJob.UnpauseCallCount()).To(Equal(0))
+						Expect(otherJob.PauseCallCount()).To(Equal(1))
+						Expect(otherJob.UnpauseCallCount()).To(Equal(1))
+						Expect(stdout).To(gbytes.Say("canary job canary-job succeeded"))
+					})
+
+					Context("when jobs were paused beforehand", func() {
+						BeforeEach(func() {
+							canaryJob.PausedReturns(true)
+							otherJob.PausedReturns(true)
+						})
+
+						It("should leave them paused", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(otherJob.PauseCallCount()).To(Equal(0))
+							Expect(otherJob.UnpauseCallCount()).To(Equal(0))
+						})
+
+						It("should pause the canary job again once it succeeded", func() {
+							Expect(canaryJob.UnpauseCallCount()).To(Equal(1))
+							Expect(canaryJob.PauseCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when checking the canary build errors", func() {
+						BeforeEach(func() {
+							canaryJob.FinishedAndNextBuildReturns(nil, nil, errors.New("disaster"))
+						})
+
+						It("should unpause the jobs it paused", func() {
+							Expect(stepErr).To(MatchError("disaster"))
+							Expect(otherJob.PauseCallCount()).To(Equal(1))
+							Expect(otherJob.UnpauseCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when the build is aborted while waiting", func() {
+						BeforeEach(func() {
+							canaryJob.FinishedAndNextBuildReturns(previousRun, nil, nil)
+
+							abortCtx, abort := context.WithCancel(context.Background())
+							fakeDelegate.StartSpanReturns(abortCtx, trace.NoopSpan{})
+
+							otherJob.PauseStub = func() error {
+								abort()
+								return nil
+							}
+						})
+
+						It("should unpause the jobs it paused", func() {
+							Expect(stepErr).To(Equal(context.Canceled))
+							Expect(otherJob.UnpauseCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when the canary build fails", func() {
+						BeforeEach(func() {
+							canaryBuild.StatusReturns(db.BuildStatusFailed)
+						})
+
+						It("should fail and leave the other jobs paused", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeFalse())
+							Expect(otherJob.PauseCallCount()).To(Equal(1))
+							Expect(otherJob.UnpauseCallCount()).To(Equal(0))
+							Expect(stderr).To(gbytes.Say("canary job canary-job failed; the remaining jobs have been left paused"))
+						})
+					})
+
+					Context("when a canary job is not in the pipeline", func() {
+						BeforeEach(func() {
+							spPlan.CanaryJobs = []string{"missing-job"}
+						})
+
+						It("should return error without pausing any jobs", func() {
+							Expect(stepErr).To(MatchError("canary job missing-job not found"))
+							Expect(otherJob.PauseCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when wait_for_success is set", func() {
+					var (
+						fakeJob     *dbfakes.FakeJob
+						newBuild    *dbfakes.FakeBuild
+						previousRun *dbfakes.FakeBuild
+					)
+
+					BeforeEach(func() {
+						spPlan.WaitForSuccess = true
+
+						previousRun = new(dbfakes.FakeBuild)
+						previousRun.IDReturns(1)
+						previousRun.StatusReturns(db.BuildStatusSucceeded)
+
+						newBuild = new(dbfakes.FakeBuild)
+						newBuild.IDReturns(2)
+						newBuild.StatusReturns(db.BuildStatusSucceeded)
+
+						fakeJob = new(dbfakes.FakeJob)
+						fakeJob.NameReturns("some-job")
+						fakeJob.FinishedAndNextBuildReturnsOnCall(0, previousRun, nil, nil)
+						fakeJob.FinishedAndNextBuildReturns(newBuild, nil, nil)
+
+						fakePipeline.JobReturns(fakeJob, true, nil)
+					})
+
+					It("should wait for the first job's next build to succeed", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stepOk).To(BeTrue())
+						Expect(fakePipeline.JobArgsForCall(0)).To(Equal("some-job"))
+						Expect(stdout).To(gbytes.Say("job some-job succeeded"))
+					})
+
+					Context("when the build fails", func() {
+						BeforeEach(func() {
+							newBuild.StatusReturns(db.BuildStatusFailed)
+						})
+
+						It("should fail", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeFalse())
+							Expect(stderr).To(gbytes.Say("first build of job some-job failed"))
+						})
+					})
+
+					Context("when the wait_timeout elapses", func() {
+						BeforeEach(func() {
+							spPlan.WaitTimeout = "10ms"
+							fakeJob.FinishedAndNextBuildReturns(previousRun, nil, nil)
+						})
+
+						It("should fail", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeFalse())
+							Expect(stderr).To(gbytes.Say("timed out after 10ms waiting for job some-job to succeed"))
+						})
+					})
+
+					Context("when the wait_timeout is invalid", func() {
+						BeforeEach(func() {
+							spPlan.WaitTimeout = "bogus"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(HaveOccurred())
+							Expect(stepErr.Error()).To(ContainSubstring("invalid wait_timeout"))
+						})
+					})
+				})
+
+				Context("when freeze is set", func() {
+					BeforeEach(func() {
+						spPlan.Freeze = true
+					})
+
+					It("should freeze the pipeline after saving", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(fakePipeline.SetFrozenCallCount()).To(Equal(1))
+						Expect(fakePipeline.SetFrozenArgsForCall(0)).To(BeTrue())
+					})
+
+					Context("when freezing the pipeline fails", func() {
+						BeforeEach(func() {
+							fakePipeline.SetFrozenReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when freeze is not set", func() {
+					It("should not change whether the pipeline is frozen", func() {
+						Expect(fakePipeline.SetFrozenCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when description is set", func() {
+					BeforeEach(func() {
+						spPlan.Description = "Deploys the API service"
+					})
+
+					It("should set the description after saving", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(fakePipeline.SetDescriptionCallCount()).To(Equal(1))
+						Expect(fakePipeline.SetDescriptionArgsForCall(0)).To(Equal("Deploys the API service"))
+					})
+
+					Context("when the pipeline already has the description", func() {
+						BeforeEach(func() {
+							fakePipeline.DescriptionReturns("Deploys the API service")
+						})
+
+						It("should not set the description", func() {
+							Expect(fakePipeline.SetDescriptionCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when setting the description fails", func() {
+						BeforeEach(func() {
+							fakePipeline.SetDescriptionReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when the pipeline contains a step that sets itself", func() {
+					BeforeEach(func() {
+						fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+jobs:
+- name: reconfigure
+  plan:
+  - set_pipeline: some-pipeline
+    file: some-resource/pipeline.yml
+`}, nil)
+					})
+
+					It("should warn", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stderr).To(gbytes.Say("WARNING: pipeline 'some-pipeline' contains a step that sets itself; ensure this does not cause infinite loops"))
+					})
+				})
+
+				Context("when slack_webhook is set", func() {
+					var (
+						server   *httptest.Server
+						messages []map[string]string
+						status   int
+						restore  func()
+					)
+
+					BeforeEach(func() {
+						restore = exec.AllowLoopbackAddresses()
+					})
+
+					AfterEach(func() {
+						restore()
+					})
+
+					BeforeEach(func() {
+						messages = nil
+						status = http.StatusOK
+						server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							var message map[string]string
+							Expect(json.NewDecoder(r.Body).Decode(&message)).To(Succeed())
+							messages = append(messages, message)
+							w.WriteHeader(status)
+						}))
+
+						spPlan.SlackWebhook = server.URL
+						spPlan.SlackChannel = "#infra"
+						fakePipeline.ConfigVersionReturns(2)
+					})
+
+					AfterEach(func() {
+						server.Close()
+					})
+
+					It("should post a summary of the changes", func() {
+						Expect(messages).To(HaveLen(1))
+						Expect(messages[0]["channel"]).To(Equal("#infra"))
+						Expect(messages[0]["text"]).To(Equal("pipeline *some-pipeline/branch:\"feature/foo\"* of team *some-team* has been set (config version 2)\njobs:\n• some-job added"))
+					})
+
+					Context("when slack responds with an error", func() {
+						BeforeEach(func() {
+							status = http.StatusInternalServerError
+						})
+
+						It("should warn without failing", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stderr).To(gbytes.Say("WARNING: failed to send slack notification: unexpected response from slack: 500 Internal Server Error"))
+						})
+					})
+				})
+
+				Context("when github_deployment is set", func() {
+					var (
+						server      *httptest.Server
+						requests    []*http.Request
+						deployments []map[string]interface{}
+						status      int
+						restore     func()
+					)
+
+					BeforeEach(func() {
+						restore = exec.AllowLoopbackAddresses()
+					})
+
+					AfterEach(func() {
+						restore()
+					})
+
+					BeforeEach(func() {
+						requests = nil
+						deployments = nil
+						status = http.StatusCreated
+						server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							requests = append(requests, r)
+
+							switch r.URL.Path {
+							case "/repos/org/repo":
+								w.Write([]byte(`{"default_branch":"main"}`))
+							case "/repos/org/repo/deployments":
+								var deployment map[string]interface{}
+								Expect(json.NewDecoder(r.Body).Decode(&deployment)).To(Succeed())
+								deployments = append(deployments, deployment)
+								w.WriteHeader(status)
+							default:
+								w.WriteHeader(http.StatusNotFound)
+							}
+						}))
+
+						state.GetStub = vars.StaticVariables{"GH_TOKEN": "some-token"}.Get
+
+						spPlan.GitHubDeployment = &atc.GitHubDeployment{
+							Owner:    "org",
+							Repo:     "repo",
+							TokenVar: "GH_TOKEN",
+							APIURL:   server.URL,
+						}
+						fakePipeline.ConfigVersionReturns(2)
+					})
+
+					AfterEach(func() {
+						server.Close()
+					})
+
+					It("should create a deployment on the default branch", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(deployments).To(HaveLen(1))
+						Expect(deployments[0]["ref"]).To(Equal("main"))
+						Expect(deployments[0]["environment"]).To(Equal("production"))
+						Expect(deployments[0]["required_contexts"]).To(BeEmpty())
+						Expect(deployments[0]["description"]).To(Equal("set pipeline some-pipeline/branch:\"feature/foo\" of team some-team (config version 2)"))
+					})
+
+					It("should authenticate with the token", func() {
+						Expect(requests).ToNot(BeEmpty())
+						for _, request := range requests {
+							Expect(request.Header.Get("Authorization")).To(Equal("token some-token"))
+						}
+					})
+
+					Context("when a ref and environment are configured", func() {
+						BeforeEach(func() {
+							spPlan.GitHubDeployment.Ref = "v1.2.3"
+							spPlan.GitHubDeployment.Environment = "staging"
+						})
+
+						It("should create the deployment without looking up the default branch", func() {
+							Expect(requests).To(HaveLen(1))
+							Expect(deployments[0]["ref"]).To(Equal("v1.2.3"))
+							Expect(deployments[0]["environment"]).To(Equal("staging"))
+						})
+					})
+
+					Context("when github responds with an error", func() {
+						BeforeEach(func() {
+							status = http.StatusConflict
+						})
+
+						It("should warn without failing", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stderr).To(gbytes.Say("WARNING: failed to create github deployment: unexpected response from github: 409 Conflict"))
+						})
+					})
+
+					Context("when the token var is undefined", func() {
+						BeforeEach(func() {
+							spPlan.GitHubDeployment.TokenVar = "MISSING"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("undefined token var: MISSING"))
+						})
+					})
+
+					Context("when the repo is missing", func() {
+						BeforeEach(func() {
+							spPlan.GitHubDeployment.Repo = ""
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("`github_deployment.owner` and `github_deployment.repo` must be specified"))
+						})
+					})
+				})
+
+				Context("when inherit_pinned_versions is set", func() {
+					var (
+						fakeParentPipeline *dbfakes.FakePipeline
+						fakeParentResource *dbfakes.FakeResource
+						fakeChildResource  *dbfakes.FakeResource
+						fakeVersion        *dbfakes.FakeResourceConfigVersion
+					)
+
+					BeforeEach(func() {
+						spPlan.InheritPinnedVersions = true
+
+						fakeParentResource = new(dbfakes.FakeResource)
+						fakeParentResource.CurrentPinnedVersionReturns(atc.Version{"ref": "v1"})
+
+						fakeVersion = new(dbfakes.FakeResourceConfigVersion)
+						fakeVersion.IDReturns(99)
+						fakeParentResource.FindVersionReturns(fakeVersion, true, nil)
+
+						fakeParentPipeline = new(dbfakes.FakePipeline)
+						fakeParentPipeline.IDReturns(1)
+						fakeParentPipeline.ResourceReturns(fakeParentResource, true, nil)
+						fakeBuild.PipelineReturns(fakeParentPipeline, true, nil)
+
+						fakeChildResource = new(dbfakes.FakeResource)
+						fakeChildResource.NameReturns("some-resource")
+						fakePipeline.IDReturns(2)
+						fakePipeline.ResourcesReturns(db.Resources{fakeChildResource}, nil)
+					})
+
+					It("should pin the parent's version in the new pipeline", func() {
+						Expect(fakeParentPipeline.ResourceArgsForCall(0)).To(Equal("some-resource"))
+						Expect(fakeParentResource.FindVersionArgsForCall(0)).To(Equal(atc.Version{"ref": "v1"}))
+						Expect(fakeChildResource.PinVersionCallCount()).To(Equal(1))
+						Expect(fakeChildResource.PinVersionArgsForCall(0)).To(Equal(99))
+					})
+
+					Context("when the parent resource is not pinned", func() {
+						BeforeEach(func() {
+							fakeParentResource.CurrentPinnedVersionReturns(nil)
+						})
+
+						It("should not pin the resource", func() {
+							Expect(fakeChildResource.PinVersionCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when the resource is pinned through the new pipeline's config", func() {
+						BeforeEach(func() {
+							fakeChildResource.ConfigPinnedVersionReturns(atc.Version{"ref": "v2"})
+						})
+
+						It("should not override the pin", func() {
+							Expect(fakeChildResource.PinVersionCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when the new pipeline is the parent pipeline", func() {
+						BeforeEach(func() {
+							fakePipeline.IDReturns(1)
+						})
+
+						It("should not pin the resource", func() {
+							Expect(fakeChildResource.PinVersionCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when pinning fails", func() {
+						BeforeEach(func() {
+							fakeChildResource.PinVersionReturns(false, errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+				})
+
+				Context("when pin_build_inputs is set", func() {
+					var (
+						fakeParentPipeline *dbfakes.FakePipeline
+						fakeParentResource *dbfakes.FakeResource
+						fakeChildResource  *dbfakes.FakeResource
+						fakeVersion        *dbfakes.FakeResourceConfigVersion
+					)
+
+					BeforeEach(func() {
+						spPlan.PinBuildInputs = []string{"some-input"}
+
+						fakeBuild.ResourcesReturns([]db.BuildInput{
+							{Name: "other-input", Version: atc.Version{"ref": "other"}, ResourceID: 11},
+							{Name: "some-input", Version: atc.Version{"ref": "tested"}, ResourceID: 12},
+						}, nil, nil)
+
+						fakeVersion = new(dbfakes.FakeResourceConfigVersion)
+						fakeVersion.IDReturns(99)
+
+						fakeParentResource = new(dbfakes.FakeResource)
+						fakeParentResource.FindVersionReturns(fakeVersion, true, nil)
+
+						fakeParentPipeline = new(dbfakes.FakePipeline)
+						fakeParentPipeline.ResourceByIDReturns(fakeParentResource, true, nil)
+						fakeBuild.PipelineReturns(fakeParentPipeline, true, nil)
+
+						fakeChildResource = new(dbfakes.FakeResource)
+						fakePipeline.ResourceReturns(fakeChildResource, true, nil)
+					})
+
+					It("should pin the build input's version in the new pipeline", func() {
+						Expect(fakeParentPipeline.ResourceByIDArgsForCall(0)).To(Equal(12))
+						Expect(fakeParentResource.FindVersionArgsForCall(0)).To(Equal(atc.Version{"ref": "tested"}))
+						Expect(fakePipeline.ResourceArgsForCall(0)).To(Equal("some-input"))
+						Expect(fakeChildResource.PinVersionCallCount()).To(Equal(1))
+						Expect(fakeChildResource.PinVersionArgsForCall(0)).To(Equal(99))
+					})
+
+					Context("when the build has no such input", func() {
+						BeforeEach(func() {
+							spPlan.PinBuildInputs = []string{"missing-input"}
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("build input 'missing-input' not found"))
+						})
+					})
+
+					Context("when the new pipeline has no such resource", func() {
+						BeforeEach(func() {
+							fakePipeline.ResourceReturns(nil, false, nil)
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("resource 'some-input' not found in pipeline 'some-pipeline'"))
+						})
+					})
+				})
+
+				Context("when cleanup_on_failure is set", func() {
+					BeforeEach(func() {
+						spPlan.CleanupOnFailure = true
+					})
+
+					It("should register the step to be aborted", func() {
+						Expect(state.RegisterAbortableCallCount()).To(Equal(1))
+					})
+
+					It("should destroy the pipeline when aborted", func() {
+						abortable := state.RegisterAbortableArgsForCall(0)
+						Expect(abortable.Abort(ctx)).To(Succeed())
+						Expect(fakePipeline.DestroyCallCount()).To(Equal(1))
+					})
+
+					Context("when destroying fails", func() {
+						BeforeEach(func() {
+							fakePipeline.DestroyReturns(errors.New("nope"))
+						})
+
+						It("should return the error from abort", func() {
+							abortable := state.RegisterAbortableArgsForCall(0)
+							Expect(abortable.Abort(ctx)).To(MatchError("nope"))
+						})
+					})
+
+					Context("when the pipeline already existed", func() {
+						BeforeEach(func() {
+							fakeBuild.SavePipelineReturns(fakePipeline, false, nil)
+						})
+
+						It("should not register the step to be aborted", func() {
+							Expect(state.RegisterAbortableCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when display is set", func() {
+					BeforeEach(func() {
+						spPlan.Display = &atc.DisplayConfig{BackgroundImage: "https://example.com/image.png"}
+					})
+
+					It("should update the pipeline's display after saving", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(fakePipeline.UpdateDisplayCallCount()).To(Equal(1))
+						Expect(fakePipeline.UpdateDisplayArgsForCall(0)).To(Equal(atc.DisplayConfig{BackgroundImage: "https://example.com/image.png"}))
+					})
+
+					It("should save the config without the override", func() {
+						_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+						Expect(config.Display).To(BeNil())
+					})
+
+					Context("when updating the display fails", func() {
+						BeforeEach(func() {
+							fakePipeline.UpdateDisplayReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
+					})
+
+					Context("when the background image is invalid", func() {
+						BeforeEach(func() {
+							spPlan.Display = &atc.DisplayConfig{BackgroundImage: "javascript:alert(1)"}
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("invalid display: background_image scheme must be either http, https or relative"))
+						})
+					})
+				})
+
+				Context("when extends is set", func() {
+					var fakeBasePipeline *dbfakes.FakePipeline
+
+					BeforeEach(func() {
+						spPlan.Extends = "base-pipeline"
+
+						fakeBasePipeline = new(dbfakes.FakePipeline)
+						fakeBasePipeline.ConfigReturns(atc.Config{
+							Resources: atc.ResourceConfigs{
+								{Name: "base-resource", Type: "git", Source: atc.Source{"uri": "git@example.com:base"}},
+							},
+							Jobs: atc.JobConfigs{
+								{
+									Name:         "some-job",
+									PlanSequence: []atc.Step{{Config: &atc.GetStep{Name: "base-resource"}}},
+								},
+								{
+									Name:         "base-job",
+									PlanSequence: []atc.Step{{Config: &atc.GetStep{Name: "base-resource"}}},
+								},
+							},
+						}, nil)
+
+						fakeTeam.PipelineStub = func(ref atc.PipelineRef) (db.Pipeline, bool, error) {
+							if ref.Name == "base-pipeline" {
+								return fakeBasePipeline, true, nil
+							}
+							return nil, false, nil
+						}
+					})
+
+					It("should save the config merged on top of the base pipeline's", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+
+						Expect(config.Resources).To(HaveLen(1))
+						Expect(config.Resources[0].Name).To(Equal("base-resource"))
+
+						Expect(config.Jobs).To(HaveLen(2))
+						Expect(config.Jobs[0]).To(Equal(pipelineObject.Jobs[0]))
+						Expect(config.Jobs[1].Name).To(Equal("base-job"))
+					})
+
+					Context("when the base pipeline does not exist", func() {
+						BeforeEach(func() {
+							spPlan.Extends = "missing-pipeline"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("pipeline to extend not found: missing-pipeline"))
+						})
+					})
+				})
+
+				Context("when clone_from is set", func() {
+					var (
+						fakeBasePipeline *dbfakes.FakePipeline
+						targetExists     bool
+					)
+
+					BeforeEach(func() {
+						spPlan.CloneFrom = "base-pipeline"
+						targetExists = false
+
+						fakeBasePipeline = new(dbfakes.FakePipeline)
+						fakeBasePipeline.ConfigReturns(atc.Config{
+							Resources: atc.ResourceConfigs{
+								{Name: "base-resource", Type: "git", Source: atc.Source{"uri": "git@example.com:base"}},
+							},
+							Jobs: atc.JobConfigs{
+								{
+									Name:         "base-job",
+									PlanSequence: []atc.Step{{Config: &atc.GetStep{Name: "base-resource"}}},
+								},
+							},
+						}, nil)
+
+						fakeTeam.PipelineStub = func(ref atc.PipelineRef) (db.Pipeline, bool, error) {
+							switch ref.Name {
+							case "base-pipeline":
+								return fakeBasePipeline, true, nil
+							case "some-pipeline":
+								if targetExists {
+									return fakePipeline, true, nil
+								}
+							}
+							return nil, false, nil
+						}
+					})
+
+					Context("when the pipeline does not exist yet", func() {
+						It("should save the config merged on top of the cloned pipeline's", func() {
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+
+							Expect(config.Resources).To(HaveLen(1))
+							Expect(config.Resources[0].Name).To(Equal("base-resource"))
+
+							Expect(config.Jobs).To(HaveLen(2))
+							Expect(config.Jobs[0].Name).To(Equal("base-job"))
+							Expect(config.Jobs[1]).To(Equal(pipelineObject.Jobs[0]))
+						})
+
+						Context("when the pipeline to clone does not exist", func() {
+							BeforeEach(func() {
+								spPlan.CloneFrom = "missing-pipeline"
+							})
+
+							It("should return error", func() {
+								Expect(stepErr).To(MatchError("pipeline to clone not found: missing-pipeline"))
+							})
+						})
+					})
+
+					Context("when the pipeline exists", func() {
+						BeforeEach(func() {
+							targetExists = true
+						})
+
+						It("should save the config as-is", func() {
+							Expect(fakeBasePipeline.ConfigCallCount()).To(Equal(0))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config).To(Equal(pipelineObject))
+						})
+					})
+
+					Context("when extends is also set", func() {
+						BeforeEach(func() {
+							spPlan.Extends = "base-pipeline"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("`clone_from` can not be used with `extends`"))
+						})
+					})
+				})
+
+				Context("when archive_unlisted is set", func() {
+					var (
+						staleManaged    *dbfakes.FakePipeline
+						archivedManaged *dbfakes.FakePipeline
+						unmanaged       *dbfakes.FakePipeline
+						otherJobs       *dbfakes.FakePipeline
+						ownPipeline     *dbfakes.FakePipeline
+
+						finalizeErr error
+					)
+
+					BeforeEach(func() {
+						spPlan.ArchiveUnlisted = true
+						spPlan.ManagedPrefix = "some-"
+
+						stepMetadata.JobID = 87
+
+						fakePipeline.ParentJobIDReturns(stepMetadata.JobID)
+						fakePipeline.ParentBuildIDReturns(stepMetadata.BuildID)
+
+						staleManaged = new(dbfakes.FakePipeline)
+						staleManaged.NameReturns("some-stale-pipeline")
+						staleManaged.ParentJobIDReturns(stepMetadata.JobID)
+						staleManaged.ParentBuildIDReturns(stepMetadata.BuildID - 1)
+
+						archivedManaged = new(dbfakes.FakePipeline)
+						archivedManaged.NameReturns("some-archived-pipeline")
+						archivedManaged.ParentJobIDReturns(stepMetadata.JobID)
+						archivedManaged.ArchivedReturns(true)
+
+						unmanaged = new(dbfakes.FakePipeline)
+						unmanaged.NameReturns("other-pipeline")
+						unmanaged.ParentJobIDReturns(stepMetadata.JobID)
+
+						otherJobs = new(dbfakes.FakePipeline)
+						otherJobs.NameReturns("some-pipeline-of-another-job")
+						otherJobs.ParentJobIDReturns(stepMetadata.JobID + 1)
+
+						ownPipeline = new(dbfakes.FakePipeline)
+						ownPipeline.IDReturns(stepMetadata.PipelineID)
+						ownPipeline.NameReturns("some-parent-pipeline")
+						ownPipeline.ParentJobIDReturns(stepMetadata.JobID)
+
+						fakeTeam.PipelinesReturns([]db.Pipeline{fakePipeline, staleManaged, archivedManaged, unmanaged, otherJobs, ownPipeline}, nil)
+					})
+
+					JustBeforeEach(func() {
+						finalizeErr = nil
+						for i := 0; i < state.RegisterFinalizerCallCount(); i++ {
+							err := state.RegisterFinalizerArgsForCall(i).Finalize(context.Background())
+							if err != nil {
+								finalizeErr = err
+							}
+						}
+					})
+
+					It("should archive once the build has succeeded", func() {
+						Expect(state.RegisterFinalizerCallCount()).To(Equal(1))
+					})
+
+					It("should archive managed pipelines set by the job but not by the build", func() {
+						Expect(finalizeErr).ToNot(HaveOccurred())
+						Expect(staleManaged.ArchiveCallCount()).To(Equal(1))
+						Expect(stdout).To(gbytes.Say("archived unlisted pipeline: some-stale-pipeline"))
+					})
+
+					It("should not archive the pipeline it set", func() {
+						Expect(fakePipeline.ArchiveCallCount()).To(Equal(0))
+					})
+
+					It("should not archive already archived or unmanaged pipelines", func() {
+						Expect(archivedManaged.ArchiveCallCount()).To(Equal(0))
+						Expect(unmanaged.ArchiveCallCount()).To(Equal(0))
+					})
+
+					It("should not archive pipelines set by other jobs", func() {
+						Expect(otherJobs.ArchiveCallCount()).To(Equal(0))
+					})
+
+					It("should not archive the pipeline the build belongs to", func() {
+						Expect(ownPipeline.ArchiveCallCount()).To(Equal(0))
+					})
+
+					Context("when archiving fails", func() {
+						BeforeEach(func() {
+							staleManaged.ArchiveReturns(errors.New("nope"))
+						})
+
+						It("should fail to finalize", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(finalizeErr).To(MatchError("nope"))
+						})
+					})
+
+					Context("when running in a one-off build", func() {
+						BeforeEach(func() {
+							stepMetadata.JobID = 0
+						})
+
+						It("should not archive anything", func() {
+							Expect(stdout).To(gbytes.Say("not archiving unlisted pipelines in a one-off build"))
+							Expect(state.RegisterFinalizerCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when managed_prefix is not set", func() {
+						BeforeEach(func() {
+							spPlan.ManagedPrefix = ""
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("`managed_prefix` must be specified when `archive_unlisted` is set"))
+						})
+
+						It("should not archive anything", func() {
+							Expect(staleManaged.ArchiveCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when rename_from is set", func() {
+					var (
+						oldPipeline *dbfakes.FakePipeline
+						newExists   bool
+						renamed     bool
+					)
+
+					BeforeEach(func() {
+						spPlan.RenameFrom = "some-old-pipeline"
+
+						fakePipeline.IDReturns(1)
+
+						oldPipeline = new(dbfakes.FakePipeline)
+						oldPipeline.IDReturns(2)
+
+						newExists = false
+						renamed = false
+
+						fakeTeam.PipelineStub = func(ref atc.PipelineRef) (db.Pipeline, bool, error) {
+							switch ref.Name {
+							case "some-old-pipeline":
+								if renamed {
+									return nil, false, nil
+								}
+								return oldPipeline, true, nil
+							case "some-pipeline":
+								if renamed {
+									return oldPipeline, true, nil
+								}
+								if newExists {
+									return fakePipeline, true, nil
+								}
+							}
+							return nil, false, nil
+						}
+
+						fakeTeam.RenamePipelineStub = func(string, string) (bool, error) {
+							renamed = true
+							return true, nil
+						}
+					})
+
+					It("should rename the old pipeline before saving onto it", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+
+						Expect(fakeTeam.RenamePipelineCallCount()).To(Equal(1))
+						oldName, newName := fakeTeam.RenamePipelineArgsForCall(0)
+						Expect(oldName).To(Equal("some-old-pipeline"))
+						Expect(newName).To(Equal("some-pipeline"))
+						Expect(stdout).To(gbytes.Say(`renamed pipeline: some-old-pipeline/branch:"feature/foo" to some-pipeline/branch:"feature/foo"`))
+
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(oldPipeline.DestroyCallCount()).To(Equal(0))
+						Expect(oldPipeline.ArchiveCallCount()).To(Equal(0))
+					})
+
+					Context("when renaming fails", func() {
+						BeforeEach(func() {
+							fakeTeam.RenamePipelineStub = nil
+							fakeTeam.RenamePipelineReturns(false, errors.New("nope"))
+						})
+
+						It("should return error without saving", func() {
+							Expect(stepErr).To(MatchError("nope"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when a pipeline with the new name already exists", func() {
+						BeforeEach(func() {
+							newExists = true
+						})
+
+						It("should return error without touching either pipeline", func() {
+							Expect(stepErr).To(MatchError(ContainSubstring(`can not rename pipeline some-old-pipeline/branch:"feature/foo" to some-pipeline/branch:"feature/foo": pipeline already exists`)))
+							Expect(fakeTeam.RenamePipelineCallCount()).To(Equal(0))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							Expect(oldPipeline.DestroyCallCount()).To(Equal(0))
+						})
+
+						Context("when archive_old is set", func() {
+							BeforeEach(func() {
+								spPlan.ArchiveOld = true
+							})
+
+							It("should archive the old pipeline after saving", func() {
+								Expect(stepErr).ToNot(HaveOccurred())
+								Expect(fakeTeam.RenamePipelineCallCount()).To(Equal(0))
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+								Expect(oldPipeline.ArchiveCallCount()).To(Equal(1))
+								Expect(oldPipeline.DestroyCallCount()).To(Equal(0))
+								Expect(stdout).To(gbytes.Say("archived renamed pipeline: some-old-pipeline"))
+							})
+
+							Context("when the old pipeline is already archived", func() {
+								BeforeEach(func() {
+									oldPipeline.ArchivedReturns(true)
+								})
+
+								It("should leave it alone", func() {
+									Expect(oldPipeline.ArchiveCallCount()).To(Equal(0))
+								})
+							})
+
+							Context("when archiving fails", func() {
+								BeforeEach(func() {
+									oldPipeline.ArchiveReturns(errors.New("nope"))
+								})
+
+								It("should return error", func() {
+									Expect(stepErr).To(MatchError("nope"))
+								})
+							})
+						})
+					})
+
+					Context("when the old pipeline does not exist", func() {
+						BeforeEach(func() {
+							fakeTeam.PipelineStub = nil
+							fakeTeam.PipelineReturns(nil, false, nil)
+						})
+
+						It("should succeed without renaming", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeTeam.RenamePipelineCallCount()).To(Equal(0))
+							Expect(oldPipeline.DestroyCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when archive_old is set without rename_from", func() {
+					BeforeEach(func() {
+						spPlan.ArchiveOld = true
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(MatchError("`rename_from` must be specified when `archive_old` is set"))
+					})
+				})
+
+				Context("when min_atc_version is specified", func() {
+					var currentVersion string
+
+					BeforeEach(func() {
+						currentVersion = concourse.Version
+						concourse.Version = "7.1.0"
+					})
+
+					AfterEach(func() {
+						concourse.Version = currentVersion
+					})
+
+					Context("when the ATC is new enough", func() {
+						BeforeEach(func() {
+							spPlan.MinATCVersion = "7.1.0"
+						})
+
+						It("should save the pipeline", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when the ATC is too old", func() {
+						BeforeEach(func() {
+							spPlan.MinATCVersion = "7.2.0"
+						})
+
+						It("should return error without saving", func() {
+							Expect(stepErr).To(MatchError("pipeline requires ATC version 7.2.0 or later, but this ATC is running version 7.1.0"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when the ATC is a development build", func() {
+						BeforeEach(func() {
+							concourse.Version = "0.0.0-dev"
+							spPlan.MinATCVersion = "99.0.0"
+						})
+
+						It("should save the pipeline", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when it is not a valid version", func() {
+						BeforeEach(func() {
+							spPlan.MinATCVersion = "not a version"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError(ContainSubstring("invalid min_atc_version")))
+						})
+					})
+				})
+
+				Context("when a when expression is specified", func() {
+					BeforeEach(func() {
+						state.GetStub = vars.StaticVariables{"build_branch": "main"}.Get
+					})
+
+					Context("when it is true", func() {
+						BeforeEach(func() {
+							spPlan.When = "((build_branch)) == 'main'"
+						})
+
+						It("should save the pipeline", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when it is false", func() {
+						BeforeEach(func() {
+							spPlan.When = "((build_branch)) != \"main\""
+						})
+
+						It("should skip the step and succeed", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeTrue())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							Expect(stdout).To(gbytes.Say("skipping: when expression is false"))
+
+							Expect(fakeDelegate.FinishedCallCount()).To(Equal(1))
+							_, succeeded := fakeDelegate.FinishedArgsForCall(0)
+							Expect(succeeded).To(BeTrue())
+						})
+					})
+
+					Context("when it is a boolean", func() {
+						BeforeEach(func() {
+							spPlan.When = "false"
+						})
+
+						It("should skip the step", func() {
+							Expect(stepOk).To(BeTrue())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when it references an undefined var", func() {
+						BeforeEach(func() {
+							spPlan.When = "((missing)) == 'main'"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("resolve when expression: undefined vars: missing"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when both operands are quoted", func() {
+						BeforeEach(func() {
+							spPlan.When = "'((build_branch))' == 'main'"
+						})
+
+						It("should save the pipeline", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when a var's value would mean something else in yaml", func() {
+						BeforeEach(func() {
+							state.GetStub = vars.StaticVariables{"build_branch": "release: #1"}.Get
+							spPlan.When = "((build_branch)) == 'release: #1'"
+						})
+
+						It("should compare the value as it is", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when a var's value contains a comparison operator", func() {
+						BeforeEach(func() {
+							state.GetStub = vars.StaticVariables{
+								"build_branch": "a==b",
+								"expected":     "a==b",
+							}.Get
+							spPlan.When = "((build_branch)) == ((expected))"
+						})
+
+						It("should compare the value as it is", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when it does not evaluate to a boolean", func() {
+						BeforeEach(func() {
+							spPlan.When = "((build_branch))"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("when expression does not evaluate to a boolean: main"))
+						})
+					})
+				})
+
+				Context("when post_save_sleep is specified", func() {
+					BeforeEach(func() {
+						spPlan.PostSaveSleep = "10ms"
+					})
+
+					It("should finish after saving the pipeline", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(stdout).To(gbytes.Say("done"))
+					})
+
+					Context("when it is longer than the step's timeout", func() {
+						BeforeEach(func() {
+							spPlan.PostSaveSleep = "100ms"
+							spPlan.Timeout = "50ms"
+						})
+
+						It("should not count the sleep towards the timeout", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeTrue())
+						})
+					})
+
+					Context("when it is not a valid duration", func() {
+						BeforeEach(func() {
+							spPlan.PostSaveSleep = "forever"
+						})
+
+						It("should return error without saving", func() {
+							Expect(stepErr).To(MatchError(ContainSubstring("invalid post_save_sleep")))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				It("should not register the step to be aborted by default", func() {
+					Expect(state.RegisterAbortableCallCount()).To(Equal(0))
+				})
+
+				It("should not inherit pinned versions by default", func() {
+					Expect(fakeBuild.PipelineCallCount()).To(Equal(0))
+				})
+
+				It("should not trigger resource checks by default", func() {
+					Expect(fakePipeline.TriggerImmediateResourceChecksCallCount()).To(Equal(0))
+				})
+
+				Context("when an after save hook fails", func() {
+					BeforeEach(func() {
+						fakeSetPipelineHook.AfterSaveReturns(errors.New("hook failed"))
+					})
+
+					It("should return error", func() {
+						Expect(stepErr).To(HaveOccurred())
+						Expect(stepErr.Error()).To(Equal("hook failed"))
+					})
+				})
+
+				It("should start a span with the file path", func() {
+					Expect(fakeDelegate.StartSpanCallCount()).To(Equal(1))
+					_, name, attrs := fakeDelegate.StartSpanArgsForCall(0)
+					Expect(name).To(Equal("set_pipeline"))
+					Expect(attrs).To(Equal(tracing.Attrs{
+						"name":              "some-pipeline",
+						"set_pipeline.file": "some-resource/pipeline.yml",
+					}))
+				})
+
+				Context("when var files are specified", func() {
+					BeforeEach(func() {
+						spPlan.VarFiles = []string{"some-resource/vars-1.yml", "some-resource/vars-2.yml"}
+					})
+
+					It("should include the number of var files in the span", func() {
+						_, _, attrs := fakeDelegate.StartSpanArgsForCall(0)
+						Expect(attrs).To(HaveKeyWithValue("set_pipeline.var_file_count", "2"))
+					})
+				})
+
+				Context("when logging", func() {
+					BeforeEach(func() {
+						spanCtx = lagerctx.NewContext(context.Background(), testLogger)
+						fakeDelegate.StartSpanReturns(spanCtx, trace.NoopSpan{})
+					})
+
+					It("includes the build id in the session data", func() {
+						var found bool
+						for _, log := range testLogger.Logs() {
+							if log.Message == "set-pipeline-action-test.set-pipeline-step.saved-pipeline" {
+								found = true
+								Expect(log.Data).To(HaveKeyWithValue("build-id", float64(stepMetadata.BuildID)))
+							}
+						}
+						Expect(found).To(BeTrue())
+					})
+				})
+
+				Context("when a span is active", func() {
+					var (
+						spanContext trace.SpanContext
+						fakeSpan    *tracingfakes.FakeSpan
+					)
+
+					BeforeEach(func() {
+						spanContext = trace.SpanContext{
+							TraceID: trace.ID{1},
+							SpanID:  trace.SpanID{2},
+						}
+
+						fakeSpan = new(tracingfakes.FakeSpan)
+						fakeSpan.SpanContextReturns(spanContext)
+
+						spanCtx = trace.ContextWithSpan(lagerctx.NewContext(context.Background(), testLogger), fakeSpan)
+						fakeDelegate.StartSpanReturns(spanCtx, fakeSpan)
+					})
+
+					It("includes the span in every log line", func() {
+						logs := testLogger.LogMessages()
+						Expect(logs).To(ContainElement("set-pipeline-action-test.set-pipeline-step.saved-pipeline"))
+
+						for _, log := range testLogger.Logs() {
+							if !strings.HasPrefix(log.Message, "set-pipeline-action-test.set-pipeline-step") {
+								continue
+							}
+							Expect(log.Data).To(HaveKeyWithValue("trace_id", spanContext.TraceID.String()))
+							Expect(log.Data).To(HaveKeyWithValue("span_id", spanContext.SpanID.String()))
+						}
+					})
+
+					It("adds a span event for each phase", func() {
+						var events []string
+						for i := 0; i < fakeSpan.AddEventCallCount(); i++ {
+							_, name, _ := fakeSpan.AddEventArgsForCall(i)
+							events = append(events, name)
+						}
+
+						Expect(events).To(Equal([]string{
+							"fetching_config",
+							"validating_config",
+							"computing_diff",
+							"saving_pipeline",
+						}))
+					})
+				})
+
+				Context("when tracing is enabled", func() {
+					var recorder *tracetest.StandardSpanRecorder
+
+					BeforeEach(func() {
+						recorder = new(tracetest.StandardSpanRecorder)
+						tracing.ConfigureTraceProvider(tracetest.NewProvider(tracetest.WithSpanRecorder(recorder)))
+
+						spPlan.VarFiles = []string{"some-resource/vars.yml"}
+
+						var buildSpan trace.Span
+						spanCtx, buildSpan = tracing.StartSpan(lagerctx.NewContext(context.Background(), testLogger), "build", nil)
+						fakeDelegate.StartSpanReturns(spanCtx, buildSpan)
+					})
+
+					AfterEach(func() {
+						tracing.Configured = false
+					})
+
+					It("creates a child span for each fetched file", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+
+						spans := map[string]*tracetest.Span{}
+						for _, span := range recorder.Completed() {
+							spans[span.Name()] = span
+						}
+
+						Expect(spans).To(HaveKey("fetch_config_file"))
+						Expect(spans["fetch_config_file"].Attributes()).To(HaveKeyWithValue(label.Key("config_file.path"), label.StringValue("some-resource/pipeline.yml")))
+
+						Expect(spans).To(HaveKey("fetch_var_file"))
+						Expect(spans["fetch_var_file"].Attributes()).To(HaveKeyWithValue(label.Key("var_file.path"), label.StringValue("some-resource/vars.yml")))
+					})
+				})
+			})
+
+			Context("when specified pipeline exists already", func() {
+				BeforeEach(func() {
+					fakeTeam.PipelineReturns(fakePipeline, true, nil)
+					fakeBuild.SavePipelineReturns(fakePipeline, false, nil)
+				})
+
+				Context("when apply_jobs is set", func() {
+					var oldJob, otherJob atc.JobConfig
+
+					BeforeEach(func() {
+						oldJob = atc.JobConfig{
+							Name:         "some-job",
+							PlanSequence: []atc.Step{{Config: &atc.TaskStep{Name: "old-task", ConfigPath: "some/task.yml"}}},
+						}
+						otherJob = atc.JobConfig{
+							Name:         "other-job",
+							PlanSequence: []atc.Step{{Config: &atc.TaskStep{Name: "other-task", ConfigPath: "some/task.yml"}}},
+						}
+
+						fakePipeline.ConfigReturns(atc.Config{Jobs: atc.JobConfigs{oldJob, otherJob}}, nil)
+					})
+
+					Context("when the job is in the new config", func() {
+						BeforeEach(func() {
+							spPlan.ApplyJobs = []string{"some-job"}
+						})
+
+						It("should only replace the listed job", func() {
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config.Jobs).To(Equal(atc.JobConfigs{pipelineObject.Jobs[0], otherJob}))
+						})
+					})
+
+					Context("when the job is not in the new config", func() {
+						BeforeEach(func() {
+							spPlan.ApplyJobs = []string{"other-job"}
+						})
+
+						It("should remove the job and leave the others unchanged", func() {
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config.Jobs).To(Equal(atc.JobConfigs{oldJob}))
+						})
+					})
+
+					Context("when the job is in neither config", func() {
+						BeforeEach(func() {
+							spPlan.ApplyJobs = []string{"missing-job"}
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("job to apply not found: missing-job"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when apply_resources is set", func() {
+					var oldRepo, otherResource atc.ResourceConfig
+					var repoJob, otherJob atc.JobConfig
+
+					BeforeEach(func() {
+						fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+resources:
+- name: git-repo
+  type: git
+  source: {uri: https://example.com/new.git}
+jobs:
+- name: repo-job
+  plan:
+  - get: git-repo
+`}, nil)
+
+						oldRepo = atc.ResourceConfig{
+							Name:   "git-repo",
+							Type:   "git",
+							Source: atc.Source{"uri": "https://example.com/old.git"},
+						}
+						otherResource = atc.ResourceConfig{
+							Name:   "other-resource",
+							Type:   "git",
+							Source: atc.Source{"uri": "https://example.com/other.git"},
+						}
+						repoJob = atc.JobConfig{
+							Name:         "repo-job",
+							PlanSequence: []atc.Step{{Config: &atc.GetStep{Name: "git-repo"}}},
+						}
+						otherJob = atc.JobConfig{
+							Name:         "other-job",
+							PlanSequence: []atc.Step{{Config: &atc.GetStep{Name: "other-resource"}}},
+						}
+
+						fakePipeline.ConfigReturns(atc.Config{
+							Resources: atc.ResourceConfigs{oldRepo, otherResource},
+							Jobs:      atc.JobConfigs{repoJob, otherJob},
+						}, nil)
+					})
+
+					Context("when the resource is in the new config", func() {
+						BeforeEach(func() {
+							spPlan.ApplyResources = []string{"git-repo"}
+						})
+
+						It("should only replace the listed resource", func() {
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config.Resources).To(Equal(atc.ResourceConfigs{
+								{
+									Name:   "git-repo",
+									Type:   "git",
+									Source: atc.Source{"uri": "https://example.com/new.git"},
+								},
+								otherResource,
+							}))
+							Expect(config.Jobs).To(Equal(atc.JobConfigs{repoJob, otherJob}))
+						})
+					})
+
+					Context("when the resource is not in the new config", func() {
+						BeforeEach(func() {
+							spPlan.ApplyResources = []string{"other-resource"}
+						})
+
+						It("should return error as a job still uses it", func() {
+							Expect(stepErr).To(MatchError(ContainSubstring("invalid pipeline after partial apply")))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+
+						Context("when the job using it is also applied", func() {
+							BeforeEach(func() {
+								spPlan.ApplyJobs = []string{"other-job"}
+							})
+
+							It("should remove both and leave the others unchanged", func() {
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+								_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+								Expect(config.Resources).To(Equal(atc.ResourceConfigs{oldRepo}))
+								Expect(config.Jobs).To(Equal(atc.JobConfigs{repoJob}))
+							})
+						})
+					})
+
+					Context("when the resource is in neither config", func() {
+						BeforeEach(func() {
+							spPlan.ApplyResources = []string{"missing-resource"}
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("resource to apply not found: missing-resource"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when merge_strategy is set", func() {
+					var otherJob atc.JobConfig
+
+					BeforeEach(func() {
+						otherJob = atc.JobConfig{
+							Name:         "other-job",
+							PlanSequence: []atc.Step{{Config: &atc.TaskStep{Name: "other-task", ConfigPath: "some/task.yml"}}},
+						}
+
+						fakePipeline.ConfigReturns(atc.Config{Jobs: atc.JobConfigs{otherJob}}, nil)
+						spPlan.MergeStrategy = &atc.MergeStrategy{Jobs: atc.MergeUpsert}
+					})
+
+					It("should merge the new config into the existing one", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+						Expect(config.Jobs).To(Equal(atc.JobConfigs{otherJob, pipelineObject.Jobs[0]}))
+					})
+
+					Context("when the strategy is unknown", func() {
+						BeforeEach(func() {
+							spPlan.MergeStrategy = &atc.MergeStrategy{Jobs: "bogus"}
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("invalid merge_strategy: unknown merge strategy for jobs: bogus"))
+						})
+					})
+				})
+
+				Context("when reset_build_history is set", func() {
+					BeforeEach(func() {
+						spPlan.ResetBuildHistory = true
+					})
+
+					It("should reset the build history after saving", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(fakePipeline.ResetBuildHistoryCallCount()).To(Equal(1))
+						Expect(stdout).To(gbytes.Say("reset build history"))
+					})
+
+					Context("when resetting the build history fails", func() {
+						BeforeEach(func() {
+							fakePipeline.ResetBuildHistoryReturns(errors.New("disaster"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("disaster"))
+						})
+					})
+				})
+
+				Context("when rollback_on_error is set", func() {
+					var previousConfig atc.Config
+
+					BeforeEach(func() {
+						spPlan.RollbackOnError = true
+
+						previousConfig = atc.Config{Jobs: atc.JobConfigs{{
+							Name:         "old-job",
+							PlanSequence: []atc.Step{{Config: &atc.TaskStep{Name: "old-task", ConfigPath: "some/task.yml"}}},
+						}}}
+
+						fakePipeline.ConfigReturns(previousConfig, nil)
+						fakePipeline.ConfigVersionReturns(2)
+					})
+
+					It("should not roll back when the step succeeds", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stepOk).To(BeTrue())
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						Expect(fakePipeline.RecordRollbackCallCount()).To(Equal(0))
+					})
+
+					Context("when the step errors after saving", func() {
+						BeforeEach(func() {
+							spPlan.Display = &atc.DisplayConfig{BackgroundImage: "some-image"}
+							fakePipeline.UpdateDisplayReturns(errors.New("disaster"))
+						})
+
+						It("should not roll back", func() {
+							Expect(stepErr).To(MatchError("disaster"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							Expect(fakePipeline.RecordRollbackCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("when the first build fails", func() {
+						BeforeEach(func() {
+							spPlan.WaitForSuccess = true
+
+							failedBuild := new(dbfakes.FakeBuild)
+							failedBuild.IDReturns(2)
+							failedBuild.StatusReturns(db.BuildStatusFailed)
+
+							fakeJob := new(dbfakes.FakeJob)
+							fakeJob.NameReturns("some-job")
+							fakeJob.FinishedAndNextBuildReturnsOnCall(0, nil, nil, nil)
+							fakeJob.FinishedAndNextBuildReturns(failedBuild, nil, nil)
+
+							fakePipeline.JobReturns(fakeJob, true, nil)
+						})
+
+						It("should re-save the previous config", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeFalse())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(2))
+							_, _, config, from, _ := fakeBuild.SavePipelineArgsForCall(1)
+							Expect(config).To(Equal(previousConfig))
+							Expect(from).To(Equal(db.ConfigVersion(2)))
+							Expect(stderr).To(gbytes.Say("to its previous config: first build of job some-job failed"))
+						})
+
+						It("should record the rollback", func() {
+							Expect(fakePipeline.RecordRollbackCallCount()).To(Equal(1))
+							rollback := fakePipeline.RecordRollbackArgsForCall(0)
+							Expect(rollback.BuildID).To(Equal(stepMetadata.BuildID))
+							Expect(rollback.FailedConfigVersion).To(Equal(db.ConfigVersion(2)))
+							Expect(rollback.RolledBack).To(BeTrue())
+						})
+
+						Context("when re-saving the previous config fails", func() {
+							BeforeEach(func() {
+								fakeBuild.SavePipelineReturnsOnCall(1, nil, false, errors.New("nope"))
+							})
+
+							It("should record that the pipeline was not rolled back", func() {
+								Expect(stderr).To(gbytes.Say(`failed to roll back pipeline .*: nope`))
+								Expect(fakePipeline.RecordRollbackCallCount()).To(Equal(1))
+								Expect(fakePipeline.RecordRollbackArgsForCall(0).RolledBack).To(BeFalse())
+							})
+						})
+					})
+
+					Context("when rollback_window is set and a resource check fails", func() {
+						BeforeEach(func() {
+							spPlan.RollbackWindow = "1m"
+
+							fakeResource := new(dbfakes.FakeResource)
+							fakeResource.NameReturns("some-resource")
+							fakeResource.BuildSummaryReturns(&atc.BuildSummary{
+								Status:    atc.StatusErrored,
+								StartTime: time.Now().Add(time.Minute).Unix(),
+							})
+
+							fakePipeline.ResourcesReturns(db.Resources{fakeResource}, nil)
+						})
+
+						It("should fail and roll back", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeFalse())
+							Expect(stderr).To(gbytes.Say("check of resource some-resource errored"))
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(2))
+							Expect(fakePipeline.RecordRollbackArgsForCall(0).Reason).To(Equal("check of resource some-resource errored"))
+						})
+					})
+
+					for _, incompatible := range []struct {
+						field string
+						set   func(*atc.SetPipelinePlan)
+					}{
+						{"reset_build_history", func(plan *atc.SetPipelinePlan) { plan.ResetBuildHistory = true }},
+						{"archive_unlisted", func(plan *atc.SetPipelinePlan) { plan.ArchiveUnlisted = true; plan.ManagedPrefix = "some-" }},
+						{"rename_from", func(plan *atc.SetPipelinePlan) { plan.RenameFrom = "old-pipeline" }},
+					} {
+						incompatible := incompatible
+
+						Context("when "+incompatible.field+" is set", func() {
+							BeforeEach(func() {
+								incompatible.set(spPlan)
+							})
+
+							It("should return error without saving", func() {
+								Expect(stepErr).To(MatchError("`rollback_on_error` can not be used with `" + incompatible.field + "`"))
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							})
+						})
+					}
+
+					Context("when rollback_window is set without rollback_on_error", func() {
+						BeforeEach(func() {
+							spPlan.RollbackOnError = false
+							spPlan.RollbackWindow = "1m"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(HaveOccurred())
+							Expect(stepErr.Error()).To(ContainSubstring("`rollback_on_error` must be set when `rollback_window` is specified"))
+						})
+					})
+				})
+
+				Context("when no diff", func() {
+					BeforeEach(func() {
+						fakePipeline.ConfigReturns(pipelineObject, nil)
+						fakePipeline.SetParentIDsReturns(nil)
+					})
+
+					It("should log 'no changes to apply'", func() {
+						Expect(stdout).To(gbytes.Say("no changes to apply."))
+					})
+
+					It("should send a set pipeline changed event", func() {
+						Expect(fakeDelegate.SetPipelineChangedCallCount()).To(Equal(1))
+						_, changed := fakeDelegate.SetPipelineChangedArgsForCall(0)
+						Expect(changed).To(BeFalse())
+					})
+
+					It("should store a result without a diff", func() {
+						Expect(state.StoreResultCallCount()).To(Equal(1))
+						id, val := state.StoreResultArgsForCall(0)
+						Expect(id).To(Equal(atc.PlanID(planID)))
+
+						result, ok := spStep.(*exec.SetPipelineStep).Result()
+						Expect(ok).To(BeTrue())
+						Expect(val).To(Equal(result))
+						Expect(result.PipelineName).To(Equal("some-pipeline"))
+						Expect(result.TeamName).To(Equal("some-team"))
+						Expect(result.HadDiff).To(BeFalse())
+						Expect(result.SavedAt).To(BeZero())
+					})
+
+					It("should update the job and build id", func() {
+						Expect(fakePipeline.SetParentIDsCallCount()).To(Equal(1))
+						jobID, buildID := fakePipeline.SetParentIDsArgsForCall(0)
+						Expect(jobID).To(Equal(stepMetadata.JobID))
+						Expect(buildID).To(Equal(stepMetadata.BuildID))
+					})
+
+					It("should clear the ttl", func() {
+						Expect(fakePipeline.ClearArchiveAfterCallCount()).To(Equal(1))
+					})
+
+					It("should clear the ownership", func() {
+						Expect(fakePipeline.ClearOwnershipCallCount()).To(Equal(1))
+					})
+
+					It("should clear the notifications", func() {
+						Expect(fakePipeline.ClearNotificationsCallCount()).To(Equal(1))
+					})
+
+					Context("when notifications are set", func() {
+						BeforeEach(func() {
+							spPlan.Notifications = &atc.PipelineNotifications{OnFailure: []string{"email:ops@example.com"}}
+						})
+
+						It("should still store the notifications", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakePipeline.SetNotificationsCallCount()).To(Equal(1))
+							Expect(fakePipeline.SetNotificationsArgsForCall(0)).To(Equal(atc.PipelineNotifications{OnFailure: []string{"email:ops@example.com"}}))
+						})
+					})
+
+					Context("when owners are set", func() {
+						BeforeEach(func() {
+							spPlan.Owners = &atc.PipelineOwnership{Team: "platform-eng"}
+						})
+
+						It("should still store the ownership", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakePipeline.SetOwnershipCallCount()).To(Equal(1))
+							Expect(fakePipeline.SetOwnershipArgsForCall(0)).To(Equal(atc.PipelineOwnership{Team: "platform-eng"}))
+						})
+					})
+
+					Context("when archive_after is set", func() {
+						BeforeEach(func() {
+							spPlan.ArchiveAfter = "30d"
+						})
+
+						It("should still register the ttl", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakePipeline.SetArchiveAfterCallCount()).To(Equal(1))
+							Expect(fakePipeline.SetArchiveAfterArgsForCall(0)).To(Equal(30 * 24 * time.Hour))
+						})
+					})
+
+					Context("when pin_build_inputs is set", func() {
+						var fakeChildResource *dbfakes.FakeResource
+
+						BeforeEach(func() {
+							spPlan.PinBuildInputs = []string{"some-input"}
+
+							fakeBuild.ResourcesReturns([]db.BuildInput{
+								{Name: "some-input", Version: atc.Version{"ref": "tested"}, ResourceID: 12},
+							}, nil, nil)
+
+							fakeVersion := new(dbfakes.FakeResourceConfigVersion)
+							fakeVersion.IDReturns(99)
+
+							fakeParentResource := new(dbfakes.FakeResource)
+							fakeParentResource.FindVersionReturns(fakeVersion, true, nil)
+
+							fakeParentPipeline := new(dbfakes.FakePipeline)
+							fakeParentPipeline.ResourceByIDReturns(fakeParentResource, true, nil)
+							fakeBuild.PipelineReturns(fakeParentPipeline, true, nil)
+
+							fakeChildResource = new(dbfakes.FakeResource)
+							fakePipeline.ResourceReturns(fakeChildResource, true, nil)
+						})
+
+						It("should still pin the build input's version", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							Expect(fakeChildResource.PinVersionCallCount()).To(Equal(1))
+							Expect(fakeChildResource.PinVersionArgsForCall(0)).To(Equal(99))
+						})
+					})
+
+					Context("when inherit_pinned_versions is set", func() {
+						var fakeChildResource *dbfakes.FakeResource
+
+						BeforeEach(func() {
+							spPlan.InheritPinnedVersions = true
+
+							fakeVersion := new(dbfakes.FakeResourceConfigVersion)
+							fakeVersion.IDReturns(99)
+
+							fakeParentResource := new(dbfakes.FakeResource)
+							fakeParentResource.CurrentPinnedVersionReturns(atc.Version{"ref": "v1"})
+							fakeParentResource.FindVersionReturns(fakeVersion, true, nil)
+
+							fakeParentPipeline := new(dbfakes.FakePipeline)
+							fakeParentPipeline.IDReturns(1)
+							fakeParentPipeline.ResourceReturns(fakeParentResource, true, nil)
+							fakeBuild.PipelineReturns(fakeParentPipeline, true, nil)
+
+							fakeChildResource = new(dbfakes.FakeResource)
+							fakeChildResource.NameReturns("some-resource")
+							fakePipeline.IDReturns(2)
+							fakePipeline.ResourcesReturns(db.Resources{fakeChildResource}, nil)
+						})
+
+						It("should still inherit the parent's pinned versions", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							Expect(fakeChildResource.PinVersionCallCount()).To(Equal(1))
+							Expect(fakeChildResource.PinVersionArgsForCall(0)).To(Equal(99))
+						})
+					})
+
+					Context("when archive_unlisted is set", func() {
+						var staleManaged *dbfakes.FakePipeline
+
+						BeforeEach(func() {
+							spPlan.ArchiveUnlisted = true
+							spPlan.ManagedPrefix = "some-"
+
+							stepMetadata.JobID = 87
+
+							staleManaged = new(dbfakes.FakePipeline)
+							staleManaged.NameReturns("some-stale-pipeline")
+							staleManaged.ParentJobIDReturns(stepMetadata.JobID)
+							fakeTeam.PipelinesReturns([]db.Pipeline{staleManaged}, nil)
+						})
+
+						It("should still archive unlisted pipelines once the build has succeeded", func() {
+							Expect(state.RegisterFinalizerCallCount()).To(Equal(1))
+							Expect(state.RegisterFinalizerArgsForCall(0).Finalize(context.Background())).To(Succeed())
+							Expect(staleManaged.ArchiveCallCount()).To(Equal(1))
+						})
+					})
+				})
+
+				Context("when the config hash is unchanged", func() {
+					BeforeEach(func() {
+						configHash, err := db.ConfigHash(pipelineObject)
+						Expect(err).ToNot(HaveOccurred())
+						fakePipeline.ConfigHashReturns(configHash)
+
+						pipelineObject.Jobs[0].PlanSequence[0].Config.(*atc.TaskStep).Config.Run.Args = []string{"hello world"}
+						fakePipeline.ConfigReturns(pipelineObject, nil)
+					})
+
+					AfterEach(func() {
+						pipelineObject.Jobs[0].PlanSequence[0].Config.(*atc.TaskStep).Config.Run.Args = []string{"hello"}
+					})
+
+					It("should not compute the diff", func() {
+						Expect(stdout).ToNot(gbytes.Say("job some-job has changed:"))
+					})
+
+					It("should log 'no changes to apply'", func() {
+						Expect(stdout).To(gbytes.Say("no changes to apply."))
+					})
+
+					It("should not save the pipeline", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+					})
+
+					Context("when the pipeline is archived", func() {
+						BeforeEach(func() {
+							fakePipeline.ArchivedReturns(true)
+							fakePipeline.ConfigReturns(atc.Config{}, nil)
+						})
+
+						It("should save the pipeline to unarchive it", func() {
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						})
+					})
+				})
+
+				Context("when display is set and only differs by the override", func() {
+					BeforeEach(func() {
+						spPlan.Display = &atc.DisplayConfig{BackgroundImage: "https://example.com/image.png"}
+
+						existingConfig := pipelineObject
+						existingConfig.Display = spPlan.Display
+						fakePipeline.ConfigReturns(existingConfig, nil)
+					})
+
+					It("should not save the pipeline", func() {
+						Expect(stdout).To(gbytes.Say("no changes to apply."))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						Expect(fakePipeline.UpdateDisplayCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when only the display override changes", func() {
+					BeforeEach(func() {
+						spPlan.Display = &atc.DisplayConfig{BackgroundImage: "https://example.com/new.png"}
+
+						configHash, err := db.ConfigHash(pipelineObject)
+						Expect(err).ToNot(HaveOccurred())
+						fakePipeline.ConfigHashReturns(configHash)
+
+						existingConfig := pipelineObject
+						existingConfig.Display = &atc.DisplayConfig{BackgroundImage: "https://example.com/old.png"}
+						fakePipeline.ConfigReturns(existingConfig, nil)
+					})
+
+					It("should update the display without saving the pipeline", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(stdout).To(gbytes.Say("no changes to apply."))
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						Expect(fakePipeline.UpdateDisplayCallCount()).To(Equal(1))
+						Expect(fakePipeline.UpdateDisplayArgsForCall(0)).To(Equal(atc.DisplayConfig{BackgroundImage: "https://example.com/new.png"}))
+					})
+				})
+
+				Context("when freeze is removed from a frozen pipeline without changes", func() {
+					BeforeEach(func() {
+						fakePipeline.ConfigReturns(pipelineObject, nil)
+						fakePipeline.FrozenReturns(true)
+					})
+
+					It("should unfreeze the pipeline", func() {
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+						Expect(fakePipeline.SetFrozenCallCount()).To(Equal(1))
+						Expect(fakePipeline.SetFrozenArgsForCall(0)).To(BeFalse())
+					})
+				})
+
+				Context("when lock_resource_type_versions is set", func() {
+					var fakeResourceType *dbfakes.FakeResourceType
 
-		Context("when pipeline file exists but bad syntax", func() {
-			BeforeEach(func() {
-				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: badPipelineContentWithInvalidSyntax}, nil)
-			})
+					BeforeEach(func() {
+						spPlan.LockResourceTypeVersions = true
 
-			It("should not return error", func() {
-				Expect(stepErr).NotTo(HaveOccurred())
-			})
+						fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+resource_types:
+- name: some-type
+  type: registry-image
+  source: {repository: some/type}
+- name: versioned-type
+  type: registry-image
+  source: {repository: versioned/type}
+  version: {digest: sha256:pinned}
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+resources:
+- name: some-resource
+  type: some-type
+  source: {}
+`}, nil)
 
-			It("should stderr have error message", func() {
-				Expect(stderr).To(gbytes.Say("invalid pipeline:"))
-				Expect(stderr).To(gbytes.Say("- invalid jobs:"))
-			})
+						fakeResourceType = new(dbfakes.FakeResourceType)
+						fakeResourceType.TypeReturns("registry-image")
+						fakeResourceType.VersionReturns(atc.Version{"digest": "sha256:latest"})
+						fakePipeline.ResourceTypeReturns(fakeResourceType, true, nil)
+					})
 
-			It("should finish unsuccessfully", func() {
-				Expect(fakeDelegate.FinishedCallCount()).To(Equal(1))
-				_, succeeded := fakeDelegate.FinishedArgsForCall(0)
-				Expect(succeeded).To(BeFalse())
-			})
-		})
+					It("should lock resource types to their current version", func() {
+						Expect(fakePipeline.ResourceTypeCallCount()).To(Equal(1))
+						Expect(fakePipeline.ResourceTypeArgsForCall(0)).To(Equal("some-type"))
 
-		Context("when pipeline file exists but is empty", func() {
-			BeforeEach(func() {
-				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: badPipelineContentWithEmptyContent}, nil)
-			})
+						Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+						_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+						Expect(config.ResourceTypes[0].Version).To(Equal(atc.Version{"digest": "sha256:latest"}))
+						Expect(config.ResourceTypes[1].Version).To(Equal(atc.Version{"digest": "sha256:pinned"}))
+					})
 
-			It("should return an error", func() {
-				Expect(stepErr).NotTo(HaveOccurred())
-			})
+					Context("when the resource type has not been checked yet", func() {
+						BeforeEach(func() {
+							fakeResourceType.VersionReturns(nil)
+						})
 
-			It("should log an error message", func() {
-				Expect(stderr).To(gbytes.Say("pipeline must contain at least one job"))
-			})
+						It("should not lock the version", func() {
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config.ResourceTypes[0].Version).To(BeNil())
+						})
 
-			It("should not update the job and build id", func() {
-				Expect(fakePipeline.SetParentIDsCallCount()).To(Equal(0))
-			})
-		})
+						It("should say which resource types were not locked", func() {
+							Expect(stdout).To(gbytes.Say("not locking resource types without a checked version: some-type"))
+						})
+					})
 
-		Context("when pipeline file is good", func() {
-			BeforeEach(func() {
-				fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: pipelineContent}, nil)
-			})
+					Context("when the resource type is new to the pipeline", func() {
+						BeforeEach(func() {
+							fakePipeline.ResourceTypeReturns(nil, false, nil)
+						})
 
-			Context("when get pipeline fails", func() {
-				BeforeEach(func() {
-					fakeTeam.PipelineReturns(nil, false, errors.New("fail to get pipeline"))
-				})
+						It("should save it without a version", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config.ResourceTypes[0].Version).To(BeNil())
+							Expect(config.ResourceTypes[1].Version).To(Equal(atc.Version{"digest": "sha256:pinned"}))
+						})
 
-				It("should return error", func() {
-					Expect(stepErr).To(HaveOccurred())
-					Expect(stepErr.Error()).To(Equal("fail to get pipeline"))
-				})
-			})
+						It("should say which resource types were not locked", func() {
+							Expect(stdout).To(gbytes.Say("not locking resource types without a checked version: some-type"))
+						})
+					})
 
-			Context("when specified pipeline not found", func() {
-				BeforeEach(func() {
-					fakeTeam.PipelineReturns(nil, false, nil)
-					fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
-				})
+					Context("when the pipeline does not exist yet", func() {
+						BeforeEach(func() {
+							fakeTeam.PipelineReturns(nil, false, nil)
+							fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+						})
 
-				It("should save the pipeline", func() {
-					Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
-					ref, _, _, _, paused := fakeBuild.SavePipelineArgsForCall(0)
-					Expect(ref).To(Equal(atc.PipelineRef{
-						Name:         "some-pipeline",
-						InstanceVars: atc.InstanceVars{"branch": "feature/foo"},
-					}))
-					Expect(paused).To(BeFalse())
-				})
+						It("should save the resource types as configured", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakePipeline.ResourceTypeCallCount()).To(Equal(0))
 
-				It("should stdout have message", func() {
-					Expect(stdout).To(gbytes.Say("done"))
-				})
-			})
+							Expect(fakeBuild.SavePipelineCallCount()).To(Equal(1))
+							_, _, config, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(config.ResourceTypes[0].Version).To(BeNil())
+							Expect(config.ResourceTypes[1].Version).To(Equal(atc.Version{"digest": "sha256:pinned"}))
+						})
 
-			Context("when specified pipeline exists already", func() {
-				BeforeEach(func() {
-					fakeTeam.PipelineReturns(fakePipeline, true, nil)
-					fakeBuild.SavePipelineReturns(fakePipeline, false, nil)
+						It("should say that the versions were not locked", func() {
+							Expect(stdout).To(gbytes.Say("not locking resource type versions of a new pipeline"))
+						})
+					})
 				})
 
-				Context("when no diff", func() {
+				Context("when preserve_resource_history is set", func() {
+					var repoResource, imageResource, otherRepoResource *dbfakes.FakeResource
+
 					BeforeEach(func() {
-						fakePipeline.ConfigReturns(pipelineObject, nil)
-						fakePipeline.SetParentIDsReturns(nil)
+						spPlan.PreserveResourceHistory = true
+
+						fakeArtifactStreamer.StreamFileFromArtifactReturns(&fakeReadCloser{str: `
+resources:
+- name: some-repo
+  type: git
+  source: {uri: some-uri, branch: feature}
+- name: some-image
+  type: registry-image
+  source: {repository: busybox}
+- name: other-repo
+  type: git
+  source: {uri: other-uri, branch: main}
+jobs:
+- name: some-job
+  plan:
+  - get: some-repo
+  - get: some-image
+  - get: other-repo
+`}, nil)
+
+						fakePipeline.ConfigReturns(atc.Config{
+							Resources: atc.ResourceConfigs{
+								{Name: "some-repo", Type: "git", Source: atc.Source{"uri": "some-uri", "branch": "main"}},
+								{Name: "some-image", Type: "docker-image", Source: atc.Source{"repository": "busybox"}},
+								{Name: "other-repo", Type: "git", Source: atc.Source{"uri": "some-uri", "branch": "main"}},
+							},
+						}, nil)
+
+						repoResource = new(dbfakes.FakeResource)
+						imageResource = new(dbfakes.FakeResource)
+						otherRepoResource = new(dbfakes.FakeResource)
+						fakePipeline.ResourceStub = func(name string) (db.Resource, bool, error) {
+							switch name {
+							case "some-repo":
+								return repoResource, true, nil
+							case "some-image":
+								return imageResource, true, nil
+							case "other-repo":
+								return otherRepoResource, true, nil
+							}
+							return nil, false, nil
+						}
 					})
 
-					It("should log 'no changes to apply'", func() {
-						Expect(stdout).To(gbytes.Say("no changes to apply."))
+					It("should preserve the history of resources whose source changed", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(repoResource.PreserveVersionHistoryCallCount()).To(Equal(1))
 					})
 
-					It("should send a set pipeline changed event", func() {
-						Expect(fakeDelegate.SetPipelineChangedCallCount()).To(Equal(1))
-						_, changed := fakeDelegate.SetPipelineChangedArgsForCall(0)
-						Expect(changed).To(BeFalse())
+					It("should not preserve the history of resources whose type changed", func() {
+						Expect(imageResource.PreserveVersionHistoryCallCount()).To(Equal(0))
 					})
 
-					It("should update the job and build id", func() {
-						Expect(fakePipeline.SetParentIDsCallCount()).To(Equal(1))
-						jobID, buildID := fakePipeline.SetParentIDsArgsForCall(0)
-						Expect(jobID).To(Equal(stepMetadata.JobID))
-						Expect(buildID).To(Equal(stepMetadata.BuildID))
+					It("should not preserve the history of resources which point somewhere else", func() {
+						Expect(otherRepoResource.PreserveVersionHistoryCallCount()).To(Equal(0))
+					})
+
+					Context("when preserving fails", func() {
+						BeforeEach(func() {
+							repoResource.PreserveVersionHistoryReturns(errors.New("nope"))
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("nope"))
+						})
 					})
 				})
 
@@ -363,6 +5269,68 @@ jobs:
 						Expect(stdout).To(gbytes.Say("job some-job has changed:"))
 					})
 
+					Context("when diff_format is summary", func() {
+						BeforeEach(func() {
+							spPlan.DiffFormat = exec.DiffFormatSummary
+						})
+
+						It("should only log the counts", func() {
+							Expect(stdout).To(gbytes.Say(`~1 job\n`))
+							Expect(stdout).ToNot(gbytes.Say("has changed"))
+						})
+					})
+
+					Context("when diff_output is set", func() {
+						var fakeArtifact *runtimefakes.FakeArtifact
+
+						BeforeEach(func() {
+							spPlan.DiffOutput = "my-resource/pipeline.diff"
+
+							fakeArtifact = new(runtimefakes.FakeArtifact)
+							fakeArtifactWriter.WriteArtifactFileReturns(fakeArtifact, nil)
+						})
+
+						It("should write the diff without terminal formatting", func() {
+							Expect(fakeArtifactWriter.WriteArtifactFileCallCount()).To(Equal(1))
+							_, spec := fakeArtifactWriter.WriteArtifactFileArgsForCall(0)
+							Expect(spec.TeamID).To(Equal(stepMetadata.TeamID))
+							Expect(spec.BuildID).To(Equal(stepMetadata.BuildID))
+							Expect(spec.Name).To(Equal("my-resource"))
+							Expect(spec.Path).To(Equal("pipeline.diff"))
+							Expect(string(spec.Content)).To(ContainSubstring("job some-job has changed:"))
+							Expect(string(spec.Content)).To(MatchRegexp(`\n- +- hello world\n\+ +- hello\n`))
+							Expect(string(spec.Content)).ToNot(ContainSubstring("\x1b"))
+						})
+
+						It("should register the artifact", func() {
+							artifact, found := artifactRepository.ArtifactFor("my-resource")
+							Expect(found).To(BeTrue())
+							Expect(artifact).To(Equal(fakeArtifact))
+						})
+
+						Context("when writing the artifact fails", func() {
+							BeforeEach(func() {
+								fakeArtifactWriter.WriteArtifactFileReturns(nil, errors.New("nope"))
+							})
+
+							It("should return error", func() {
+								Expect(stepErr).To(MatchError("write diff output: nope"))
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							})
+						})
+					})
+
+					Context("when diff_format is sidebyside", func() {
+						BeforeEach(func() {
+							spPlan.DiffFormat = exec.DiffFormatSideBySide
+						})
+
+						It("should log the diff in two columns", func() {
+							Expect(stdout).To(gbytes.Say("job some-job has changed:"))
+							Expect(stdout).To(gbytes.Say(`- hello world.*>.*- hello`))
+						})
+					})
+
 					It("should send a set pipeline changed event", func() {
 						Expect(fakeDelegate.SetPipelineChangedCallCount()).To(Equal(1))
 						_, changed := fakeDelegate.SetPipelineChangedArgsForCall(0)
@@ -392,6 +5360,107 @@ jobs:
 							Expect(stepOk).To(BeTrue())
 						})
 					})
+
+					Context("due to the ATC being in read-only mode", func() {
+						BeforeEach(func() {
+							fakeBuild.SavePipelineReturns(nil, false, db.ErrReadOnlyMode)
+						})
+						It("writes a message to stderr", func() {
+							Expect(stderr).To(gbytes.Say("ATC is in read-only mode; pipeline changes are not permitted"))
+						})
+						It("fails the step without erroring", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(stepOk).To(BeFalse())
+						})
+					})
+
+					Context("due to the pipeline's config having been changed concurrently", func() {
+						var newerPipeline *dbfakes.FakePipeline
+
+						BeforeEach(func() {
+							fakePipeline.ConfigVersionReturns(1)
+
+							newerPipeline = new(dbfakes.FakePipeline)
+							newerPipeline.ConfigVersionReturns(2)
+
+							fakeArtifactStreamer.StreamFileFromArtifactStub = func(context.Context, runtime.Artifact, string) (io.ReadCloser, error) {
+								return &fakeReadCloser{str: pipelineContent}, nil
+							}
+
+							var lookups int
+							fakeTeam.PipelineStub = func(atc.PipelineRef) (db.Pipeline, bool, error) {
+								lookups++
+								if lookups == 1 {
+									return fakePipeline, true, nil
+								}
+								return newerPipeline, true, nil
+							}
+
+							fakeBuild.SavePipelineReturnsOnCall(0, nil, false, db.ErrConfigComparisonFailed)
+						})
+
+						Context("when the retry succeeds", func() {
+							BeforeEach(func() {
+								fakeBuild.SavePipelineReturnsOnCall(1, fakePipeline, false, nil)
+							})
+
+							It("retries against the current config version", func() {
+								Expect(stepErr).ToNot(HaveOccurred())
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(2))
+								_, _, _, fromVersion, _ := fakeBuild.SavePipelineArgsForCall(1)
+								Expect(fromVersion).To(Equal(db.ConfigVersion(2)))
+							})
+
+							It("recomputes the config against the current one", func() {
+								Expect(newerPipeline.ConfigCallCount()).To(BeNumerically(">", 0))
+							})
+
+							It("does not repeat what comes before the diff", func() {
+								Expect(fakeDelegate.InitializingCallCount()).To(Equal(1))
+								Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+								Expect(fakeBuild.RecordSetPipelineArtifactsCallCount()).To(Equal(1))
+							})
+
+							It("explains the conflict", func() {
+								Expect(stderr).To(gbytes.Say(`WARNING: pipeline some-pipeline/branch:"feature/foo" of team some-team was changed concurrently: expected config version 1, but it is at version 2; retrying \(attempt 1 of 3\)`))
+							})
+
+							Context("when a logger is in the context", func() {
+								BeforeEach(func() {
+									spanCtx = lagerctx.NewContext(context.Background(), testLogger)
+									fakeDelegate.StartSpanReturns(spanCtx, trace.NoopSpan{})
+								})
+
+								It("logs the conflict details", func() {
+									var found bool
+									for _, log := range testLogger.Logs() {
+										if strings.HasSuffix(log.Message, "config-version-mismatch") {
+											found = true
+											Expect(log.Data).To(HaveKeyWithValue("expected-version", float64(1)))
+											Expect(log.Data).To(HaveKeyWithValue("actual-version", float64(2)))
+										}
+									}
+									Expect(found).To(BeTrue())
+								})
+							})
+						})
+
+						Context("when the config keeps changing", func() {
+							BeforeEach(func() {
+								fakeBuild.SavePipelineReturns(nil, false, db.ErrConfigComparisonFailed)
+							})
+
+							It("returns the conflict after retrying", func() {
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(4))
+
+								var mismatch exec.ConfigVersionMismatchError
+								Expect(errors.As(stepErr, &mismatch)).To(BeTrue())
+								Expect(mismatch.Team).To(Equal("some-team"))
+								Expect(mismatch.ExpectedVersion).To(Equal(db.ConfigVersion(2)))
+								Expect(mismatch.ActualVersion).To(Equal(db.ConfigVersion(2)))
+							})
+						})
+					})
 				})
 
 				It("should save the pipeline un-paused", func() {
@@ -409,6 +5478,24 @@ jobs:
 					Expect(stdout).To(gbytes.Say("done"))
 				})
 
+				It("should store the result of the step", func() {
+					Expect(state.StoreResultCallCount()).To(Equal(1))
+					id, val := state.StoreResultArgsForCall(0)
+					Expect(id).To(Equal(atc.PlanID(planID)))
+					Expect(val).To(Equal(exec.SetPipelineResult{
+						PipelineName:  "some-pipeline",
+						TeamName:      "some-team",
+						ConfigVersion: fakePipeline.ConfigVersion(),
+						HadDiff:       true,
+						SavedAt:       val.(exec.SetPipelineResult).SavedAt,
+					}))
+					Expect(val.(exec.SetPipelineResult).SavedAt).ToNot(BeZero())
+
+					result, ok := spStep.(*exec.SetPipelineStep).Result()
+					Expect(ok).To(BeTrue())
+					Expect(result).To(Equal(val))
+				})
+
 				It("should finish successfully", func() {
 					Expect(fakeDelegate.FinishedCallCount()).To(Equal(1))
 					_, succeeded := fakeDelegate.FinishedArgsForCall(0)
@@ -492,8 +5579,57 @@ jobs:
 					})
 
 					It("should return error", func() {
-						Expect(stepErr).To(HaveOccurred())
+						Expect(stepErr).To(Equal(exec.TeamNotFoundError{Name: "not-found"}))
 						Expect(stepErr.Error()).To(Equal("team not-found not found"))
+						Expect(fakeTeamFactory.CreateTeamCallCount()).To(Equal(0))
+					})
+
+					Context("when create_team_if_missing is set", func() {
+						var fakeCreatedTeam *dbfakes.FakeTeam
+
+						BeforeEach(func() {
+							spPlan.CreateTeamIfMissing = true
+
+							fakeCreatedTeam = new(dbfakes.FakeTeam)
+							fakeCreatedTeam.IDReturns(222)
+							fakeCreatedTeam.NameReturns("not-found")
+							fakeCreatedTeam.PipelineReturns(nil, false, nil)
+							fakeTeamFactory.CreateTeamReturns(fakeCreatedTeam, nil)
+
+							fakeUserCurrentTeam.AdminReturns(true)
+							fakeBuild.SavePipelineReturns(fakePipeline, true, nil)
+						})
+
+						It("should create the team and save the pipeline to it", func() {
+							Expect(stepErr).ToNot(HaveOccurred())
+							Expect(fakeTeamFactory.CreateTeamCallCount()).To(Equal(1))
+							Expect(fakeTeamFactory.CreateTeamArgsForCall(0)).To(Equal(atc.Team{Name: "not-found"}))
+							Expect(stdout).To(gbytes.Say("created team not-found"))
+
+							_, teamID, _, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+							Expect(teamID).To(Equal(222))
+						})
+
+						Context("when the build is not on the main team", func() {
+							BeforeEach(func() {
+								fakeUserCurrentTeam.NameReturns("other-team")
+							})
+
+							It("should return error without creating the team", func() {
+								Expect(stepErr).To(MatchError("team other-team does not have permission to create team not-found"))
+								Expect(fakeTeamFactory.CreateTeamCallCount()).To(Equal(0))
+							})
+						})
+
+						Context("when creating the team fails", func() {
+							BeforeEach(func() {
+								fakeTeamFactory.CreateTeamReturns(nil, errors.New("nope"))
+							})
+
+							It("should return error", func() {
+								Expect(stepErr).To(MatchError("create team not-found: nope"))
+							})
+						})
 					})
 				})
 
@@ -553,15 +5689,95 @@ jobs:
 
 						Context("when the current team is not an admin team", func() {
 							It("should return error", func() {
-
-								Expect(stepErr).To(HaveOccurred())
+								Expect(stepErr).To(Equal(exec.PermissionDeniedError{
+									Team:       "main",
+									TargetTeam: fakeTeam.Name(),
+								}))
 								Expect(stepErr.Error()).To(Equal(
-									"only main team can set another team's pipeline",
+									"team main does not have permission to set pipelines on team some-team",
 								))
+								Expect(fakeBuild.SavePipelineCallCount()).To(Equal(0))
+							})
+
+							Context("when the target team grants it the pipeline-operator role", func() {
+								BeforeEach(func() {
+									fakeTeam.AuthReturns(atc.TeamAuth{
+										"pipeline-operator": map[string][]string{
+											"teams": {"main"},
+										},
+									})
+
+									fakeBuild.PipelineReturns(fakePipeline, true, nil)
+									fakeBuild.SavePipelineReturns(fakePipeline, false, nil)
+								})
+
+								It("should finish successfully", func() {
+									_, teamID, _, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+									Expect(teamID).To(Equal(fakeTeam.ID()))
+									_, succeeded := fakeDelegate.FinishedArgsForCall(0)
+									Expect(succeeded).To(BeTrue())
+								})
+							})
+
+							Context("when the target team grants it the viewer role", func() {
+								BeforeEach(func() {
+									fakeTeam.AuthReturns(atc.TeamAuth{
+										"viewer": map[string][]string{
+											"teams": {"main"},
+										},
+									})
+								})
+
+								It("should return error", func() {
+									Expect(stepErr).To(BeAssignableToTypeOf(exec.PermissionDeniedError{}))
+								})
 							})
 						})
 					})
 				})
+
+				Context("when team_var is configured", func() {
+					BeforeEach(func() {
+						state.GetStub = vars.StaticVariables{"target_team": "some-other-team"}.Get
+						spPlan.TeamVar = "target_team"
+
+						fakeUserCurrentTeam.AdminReturns(true)
+						fakeTeamFactory.FindTeamReturnsOnCall(
+							1,
+							fakeTeam, true, nil,
+						)
+
+						fakeBuild.PipelineReturns(fakePipeline, true, nil)
+						fakeBuild.SavePipelineReturns(fakePipeline, false, nil)
+					})
+
+					It("should set the pipeline on the team named by the var", func() {
+						Expect(stepErr).ToNot(HaveOccurred())
+						Expect(fakeTeamFactory.FindTeamArgsForCall(1)).To(Equal("some-other-team"))
+						_, teamID, _, _, _ := fakeBuild.SavePipelineArgsForCall(0)
+						Expect(teamID).To(Equal(fakeTeam.ID()))
+					})
+
+					Context("when the var is not defined", func() {
+						BeforeEach(func() {
+							spPlan.TeamVar = "missing"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("undefined team var: missing"))
+						})
+					})
+
+					Context("when team is also configured", func() {
+						BeforeEach(func() {
+							spPlan.Team = "some-team"
+						})
+
+						It("should return error", func() {
+							Expect(stepErr).To(MatchError("`team` and `team_var` can not both be specified"))
+						})
+					})
+				})
 			})
 
 			Context("when policy checker enabled", func() {
@@ -0,0 +1,77 @@
+package exec
+
+import (
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// strategicMergeSchema declares which lists of a pipeline config are merged
+// by the `name` of their items. All other lists are replaced.
+type strategicMergeSchema struct {
+	Groups        []map[string]interface{} `json:"groups,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	VarSources    []map[string]interface{} `json:"var_sources,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	Resources     []map[string]interface{} `json:"resources,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	ResourceTypes []map[string]interface{} `json:"resource_types,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	Jobs          []map[string]interface{} `json:"jobs,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+}
+
+// strategicMergeMeta looks up patch strategies from strategicMergeSchema,
+// falling back to the default strategies for anything not declared in it,
+// such as the free-form `source` of a resource.
+type strategicMergeMeta struct {
+	schema strategicpatch.LookupPatchMeta
+}
+
+func (meta strategicMergeMeta) LookupPatchMetadataForStruct(key string) (strategicpatch.LookupPatchMeta, strategicpatch.PatchMeta, error) {
+	if meta.schema != nil {
+		subschema, patchMeta, err := meta.schema.LookupPatchMetadataForStruct(key)
+		if err == nil {
+			return strategicMergeMeta{subschema}, patchMeta, nil
+		}
+	}
+
+	return strategicMergeMeta{}, strategicpatch.PatchMeta{}, nil
+}
+
+func (meta strategicMergeMeta) LookupPatchMetadataForSlice(key string) (strategicpatch.LookupPatchMeta, strategicpatch.PatchMeta, error) {
+	if meta.schema != nil {
+		subschema, patchMeta, err := meta.schema.LookupPatchMetadataForSlice(key)
+		if err == nil {
+			return strategicMergeMeta{subschema}, patchMeta, nil
+		}
+	}
+
+	return strategicMergeMeta{}, strategicpatch.PatchMeta{}, nil
+}
+
+func (meta strategicMergeMeta) Name() string {
+	return "pipeline config"
+}
+
+// strategicMerge applies each patch on top of the config in order, merging
+// groups, var sources, resources, resource types and jobs by name.
+func strategicMerge(config []byte, patches [][]byte) ([]byte, error) {
+	schema, err := strategicpatch.NewPatchMetaFromStruct(strategicMergeSchema{})
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := yaml.YAMLToJSON(config)
+	if err != nil {
+		return nil, configParseError{err}
+	}
+
+	for _, patch := range patches {
+		jsonPatch, err := yaml.YAMLToJSON(patch)
+		if err != nil {
+			return nil, configParseError{err}
+		}
+
+		merged, err = strategicpatch.StrategicMergePatchUsingLookupPatchMeta(merged, jsonPatch, strategicMergeMeta{schema})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return yaml.JSONToYAML(merged)
+}
@@ -0,0 +1,51 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/concourse/concourse/vars"
+)
+
+// TemplateEngineGoTemplate renders the pipeline config with text/template
+// before it is parsed.
+const TemplateEngineGoTemplate = "go-template"
+
+var pipelineTemplateFuncs = template.FuncMap{
+	"title":   strings.Title,
+	"trim":    strings.TrimSpace,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"replace": strings.ReplaceAll,
+	"join":    strings.Join,
+	"split":   strings.Split,
+}
+
+// renderGoTemplate renders the config as a text/template. The static vars are
+// merged into the template data, with earlier vars taking precedence over
+// later ones, matching the precedence used when resolving ((vars)).
+func renderGoTemplate(name string, config []byte, staticVars []vars.Variables) ([]byte, error) {
+	data := map[string]interface{}{}
+	for i := len(staticVars) - 1; i >= 0; i-- {
+		if sv, ok := staticVars[i].(vars.StaticVariables); ok {
+			for k, v := range sv {
+				data[k] = v
+			}
+		}
+	}
+
+	tmpl, err := template.New(name).Funcs(pipelineTemplateFuncs).Option("missingkey=error").Parse(string(config))
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = tmpl.Execute(buf, data)
+	if err != nil {
+		return nil, fmt.Errorf("render template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+package exec
+
+import (
+	"fmt"
+	"io"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/db"
+)
+
+// triggerFirstJobCreatedBy is who builds triggered by trigger_first_job are
+// recorded as being created by.
+const triggerFirstJobCreatedBy = "set_pipeline"
+
+// firstJob returns the name of the first job of the config in topological
+// order, i.e. the first job none of whose inputs have `passed` constraints.
+func firstJob(config atc.Config) (string, bool) {
+	for _, job := range config.Jobs {
+		upstream := false
+		for _, input := range job.Inputs() {
+			if len(input.Passed) > 0 {
+				upstream = true
+				break
+			}
+		}
+
+		if !upstream {
+			return job.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// triggerFirstJob creates a manually triggered build of the first job of the
+// pipeline.
+func triggerFirstJob(logger lager.Logger, pipeline db.Pipeline, config atc.Config, stdout io.Writer) error {
+	name, found := firstJob(config)
+	if !found {
+		return nil
+	}
+
+	job, found, err := pipeline.Job(name)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("job %s not found", name)
+	}
+
+	if job.DisableManualTrigger() {
+		fmt.Fprintf(stdout, "not triggering job %s: manual triggering is disabled\n", name)
+		return nil
+	}
+
+	build, err := job.CreateBuild(triggerFirstJobCreatedBy)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("triggered-first-job", lager.Data{"job": name, "build": build.Name()})
+
+	fmt.Fprintf(stdout, "triggered job %s build #%s\n", name, build.Name())
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package exec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/concourse/concourse/tracing"
+)
+
+// VarFileFetch describes a var file fetched by a set_pipeline step, so that
+// the exact contents used can be audited even when the artifact it came from
+// is not pinned to a version.
+type VarFileFetch struct {
+	Path      string
+	DigestHex string
+	SizeBytes int64
+	Duration  time.Duration
+}
+
+func (s setPipelineSource) fetchVarFile(path string) ([]byte, error) {
+	start := time.Now()
+
+	content, err := s.fetchPipelineBitsInSpan("fetch_var_file", tracing.Attrs{"var_file.path": path}, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.varFileFetched != nil {
+		digest := sha256.Sum256(content)
+		s.varFileFetched(VarFileFetch{
+			Path:      path,
+			DigestHex: hex.EncodeToString(digest[:]),
+			SizeBytes: int64(len(content)),
+			Duration:  time.Since(start),
+		})
+	}
+
+	return content, nil
+}
@@ -0,0 +1,9 @@
+package exec
+
+// The orders in which the vars and var files of a set_pipeline step take
+// precedence over each other. By default the vars take precedence over the var
+// files; with files_first a var file may override a var.
+const (
+	VarPrecedenceVarsFirst  = "vars_first"
+	VarPrecedenceFilesFirst = "files_first"
+)
@@ -0,0 +1,128 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/concourse/concourse/atc/creds"
+	"github.com/concourse/concourse/vars"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+//go:generate counterfeiter . VaultSecretReader
+
+// VaultSecretReader reads secrets from the ATC's Vault for the
+// vault_dynamic_vars of set_pipeline steps. Secrets are only read under the
+// lookup paths of the team, as for any other var.
+type VaultSecretReader interface {
+	Read(path string) (*vaultapi.Secret, error)
+	NewSecretLookupPaths(teamName string, pipelineName string, allowRootPath bool) []creds.SecretLookupPath
+}
+
+// VaultSecretNotFoundError is returned when a vault_dynamic_vars path has no
+// secret.
+type VaultSecretNotFoundError struct {
+	Path string
+}
+
+// Error returns a human-friendly error message.
+func (err VaultSecretNotFoundError) Error() string {
+	return fmt.Sprintf("secret '%s' not found in vault", err.Path)
+}
+
+// VaultKeyNotFoundError is returned when a vault_dynamic_vars secret does not
+// contain one of the listed keys.
+type VaultKeyNotFoundError struct {
+	Path string
+	Key  string
+}
+
+// Error returns a human-friendly error message.
+func (err VaultKeyNotFoundError) Error() string {
+	return fmt.Sprintf("key '%s' not found in vault secret '%s'", err.Key, err.Path)
+}
+
+// validateVaultDynamicVarPath rejects paths which could escape the team's
+// lookup paths.
+func validateVaultDynamicVarPath(path string) error {
+	if path == "" || strings.HasPrefix(path, "/") {
+		return fmt.Errorf("vault_dynamic_vars path '%s' must be relative to the team's secret paths", path)
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".." || segment == "." {
+			return fmt.Errorf("vault_dynamic_vars path '%s' must not contain '%s'", path, segment)
+		}
+	}
+
+	return nil
+}
+
+// fetchVaultDynamicVars checks that the keys listed by the step's
+// vault_dynamic_vars exist in Vault, looking each path up under the team's
+// secret paths like any other var. The vars resolve to ((path.key))
+// references, so that the values are never baked into the saved config and
+// are resolved with the team's credentials when the pipeline runs.
+func (step *SetPipelineStep) fetchVaultDynamicVars(teamName string, pipelineName string) (vars.StaticVariables, error) {
+	if step.vaultSecretReader == nil {
+		return nil, errors.New("vault_dynamic_vars requires vault to be configured as the credential manager")
+	}
+
+	lookupPaths := step.vaultSecretReader.NewSecretLookupPaths(teamName, pipelineName, false)
+
+	dynamicVars := vars.StaticVariables{}
+	for _, dynamicVar := range step.plan.VaultDynamicVars {
+		err := validateVaultDynamicVarPath(dynamicVar.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		secret, err := step.readVaultDynamicVar(lookupPaths, dynamicVar.Path)
+		if err != nil {
+			return nil, fmt.Errorf("read vault secret '%s': %w", dynamicVar.Path, err)
+		}
+
+		if secret == nil || secret.Data == nil {
+			return nil, VaultSecretNotFoundError{dynamicVar.Path}
+		}
+
+		for _, key := range dynamicVar.Keys {
+			if _, found := secret.Data[key]; !found {
+				return nil, VaultKeyNotFoundError{Path: dynamicVar.Path, Key: key}
+			}
+
+			ref := vars.Reference{Path: dynamicVar.Path, Fields: []string{key}}
+			dynamicVars[key] = "((" + ref.String() + "))"
+		}
+	}
+
+	return dynamicVars, nil
+}
+
+// readVaultDynamicVar returns the secret at the first of the lookup paths
+// which has one.
+func (step *SetPipelineStep) readVaultDynamicVar(lookupPaths []creds.SecretLookupPath, path string) (*vaultapi.Secret, error) {
+	for _, lookupPath := range lookupPaths {
+		secretPath, err := lookupPath.VariableToSecretPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var secret *vaultapi.Secret
+		if step.scopedVars != nil {
+			secret, err = step.scopedVars.readVaultSecret(step.vaultSecretReader, secretPath)
+		} else {
+			secret, err = step.vaultSecretReader.Read(secretPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if secret != nil && secret.Data != nil {
+			return secret, nil
+		}
+	}
+
+	return nil, nil
+}
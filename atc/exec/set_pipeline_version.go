@@ -0,0 +1,35 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/cppforlife/go-semi-semantic/version"
+)
+
+const devATCVersion = "0.0.0-dev"
+
+// checkMinATCVersion returns an error if the running ATC is older than the
+// minimum version required by the set_pipeline step. Development builds are
+// assumed to satisfy every constraint.
+func checkMinATCVersion(minVersion string) error {
+	required, err := version.NewVersionFromString(minVersion)
+	if err != nil {
+		return fmt.Errorf("invalid min_atc_version: %w", err)
+	}
+
+	if atc.RunningVersion() == devATCVersion {
+		return nil
+	}
+
+	current, err := version.NewVersionFromString(atc.RunningVersion())
+	if err != nil {
+		return fmt.Errorf("parse ATC version: %w", err)
+	}
+
+	if current.IsLt(required) {
+		return fmt.Errorf("pipeline requires ATC version %s or later, but this ATC is running version %s", required, current)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/concourse/atc/db"
+)
+
+const (
+	waitForSuccessInitialInterval = time.Second
+	waitForSuccessMaxInterval     = 30 * time.Second
+)
+
+// FirstBuildFailedError is returned when the first build of a pipeline's
+// first job after the pipeline was set does not succeed.
+type FirstBuildFailedError struct {
+	Job    string
+	Status db.BuildStatus
+}
+
+// Error returns a human-friendly error message.
+func (err FirstBuildFailedError) Error() string {
+	return fmt.Sprintf("first build of job %s %s", err.Job, err.Status)
+}
+
+// WaitForSuccessTimeoutError is returned when the first build of a
+// pipeline's first job does not complete within the wait_timeout.
+type WaitForSuccessTimeoutError struct {
+	Job     string
+	Timeout time.Duration
+}
+
+// Error returns a human-friendly error message.
+func (err WaitForSuccessTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for job %s to succeed", err.Timeout, err.Job)
+}
+
+// waitForFirstBuild polls, with exponential backoff, for the first job of the
+// pipeline to complete a build newer than the one it had finished when the
+// pipeline was saved. A timeout of zero waits until the context is done.
+func waitForFirstBuild(ctx context.Context, logger lager.Logger, pipeline db.Pipeline, jobName string, timeout time.Duration, stdout io.Writer) error {
+	job, found, err := pipeline.Job(jobName)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("job %s not found", jobName)
+	}
+
+	var lastBuildID int
+	finished, _, err := job.FinishedAndNextBuild()
+	if err != nil {
+		return err
+	}
+
+	if finished != nil {
+		lastBuildID = finished.ID()
+	}
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	fmt.Fprintf(stdout, "waiting for job %s to succeed\n", jobName)
+
+	interval := waitForSuccessInitialInterval
+	for {
+		finished, _, err := job.FinishedAndNextBuild()
+		if err != nil {
+			return err
+		}
+
+		if finished != nil && finished.ID() > lastBuildID {
+			if finished.Status() != db.BuildStatusSucceeded {
+				return FirstBuildFailedError{Job: jobName, Status: finished.Status()}
+			}
+
+			logger.Debug("first-build-succeeded", lager.Data{"job": jobName, "build": finished.ID()})
+			fmt.Fprintf(stdout, "job %s succeeded\n", jobName)
+			return nil
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-deadline:
+			return WaitForSuccessTimeoutError{Job: jobName, Timeout: timeout}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		interval *= 2
+		if interval > waitForSuccessMaxInterval {
+			interval = waitForSuccessMaxInterval
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package exec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/concourse/vars"
+)
+
+// whenVarRegex matches the ((vars)) of a `when` expression.
+var whenVarRegex = regexp.MustCompile(`\(\(([^()]+)\)\)`)
+
+// evaluateWhen evaluates a set_pipeline `when` expression. The expression is
+// either a boolean, or two operands compared with == or !=. Operands may be
+// quoted with single or double quotes. The expression is split into its
+// operands before their ((vars)) are resolved, so an operator in a var's value
+// is compared as part of it. Vars are resolved as plain text rather than
+// parsed as YAML, so their values are compared exactly as they are.
+func evaluateWhen(expression string, variables vars.Variables) (bool, error) {
+	for _, op := range []string{"==", "!="} {
+		operands := strings.Split(expression, op)
+		if len(operands) != 2 {
+			continue
+		}
+
+		left, err := resolveWhen(operands[0], variables)
+		if err != nil {
+			return false, fmt.Errorf("resolve when expression: %w", err)
+		}
+
+		right, err := resolveWhen(operands[1], variables)
+		if err != nil {
+			return false, fmt.Errorf("resolve when expression: %w", err)
+		}
+
+		equal := unquoteOperand(left) == unquoteOperand(right)
+		if op == "==" {
+			return equal, nil
+		}
+
+		return !equal, nil
+	}
+
+	resolved, err := resolveWhen(expression, variables)
+	if err != nil {
+		return false, fmt.Errorf("resolve when expression: %w", err)
+	}
+
+	evaluated := unquoteOperand(resolved)
+
+	result, err := strconv.ParseBool(evaluated)
+	if err != nil {
+		return false, fmt.Errorf("when expression does not evaluate to a boolean: %s", evaluated)
+	}
+
+	return result, nil
+}
+
+// resolveWhen replaces each ((var)) in the expression with its value.
+func resolveWhen(expression string, variables vars.Variables) (string, error) {
+	var missing []string
+	var resolveErr error
+
+	resolved := whenVarRegex.ReplaceAllStringFunc(expression, func(match string) string {
+		name := whenVarRegex.FindStringSubmatch(match)[1]
+
+		ref, err := vars.ParseReference(name)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+
+		value, found, err := variables.Get(ref)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+
+		if !found {
+			missing = append(missing, name)
+			return match
+		}
+
+		return fmt.Sprint(value)
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	if len(missing) > 0 {
+		return "", vars.UndefinedVarsError{Vars: missing}
+	}
+
+	return resolved, nil
+}
+
+func unquoteOperand(operand string) string {
+	operand = strings.TrimSpace(operand)
+
+	if len(operand) >= 2 {
+		first, last := operand[0], operand[len(operand)-1]
+		if first == last && (first == '\'' || first == '"') {
+			return operand[1 : len(operand)-1]
+		}
+	}
+
+	return operand
+}
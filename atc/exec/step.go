@@ -29,6 +29,23 @@ type Step interface {
 	Run(context.Context, RunState) (bool, error)
 }
 
+//go:generate counterfeiter . Abortable
+
+// An Abortable is registered with the RunState by steps which need to undo
+// their effects when the build they ran in does not succeed.
+type Abortable interface {
+	Abort(context.Context) error
+}
+
+//go:generate counterfeiter . Finalizer
+
+// A Finalizer is registered with the RunState by steps which need to act once
+// the build they ran in has succeeded, e.g. because they depend on what every
+// other step of the build has done.
+type Finalizer interface {
+	Finalize(context.Context) error
+}
+
 //go:generate counterfeiter . BuildStepDelegate
 
 type BuildOutputFilter func(text string) string
@@ -52,6 +69,12 @@ type RunState interface {
 	Run(context.Context, atc.Plan) (bool, error)
 
 	Parent() RunState
+
+	RegisterAbortable(Abortable)
+	AbortAll(context.Context) error
+
+	RegisterFinalizer(Finalizer)
+	FinalizeAll(context.Context) error
 }
 
 // ExitStatus is the resulting exit code from the process that the step ran.
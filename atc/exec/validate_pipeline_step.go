@@ -0,0 +1,143 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/configvalidate"
+	"github.com/concourse/concourse/atc/creds"
+	"github.com/concourse/concourse/atc/worker"
+	"github.com/concourse/concourse/tracing"
+)
+
+// ValidatePipelineStep fetches and validates a pipeline config the same way
+// as a SetPipelineStep, but never saves it.
+type ValidatePipelineStep struct {
+	planID           atc.PlanID
+	plan             atc.ValidatePipelinePlan
+	metadata         StepMetadata
+	delegateFactory  BuildStepDelegateFactory
+	artifactStreamer worker.ArtifactStreamer
+	maxVarFiles      int
+	fileCache        *SetPipelineFileCache
+	configMapFetcher ConfigMapFetcher
+}
+
+func NewValidatePipelineStep(
+	planID atc.PlanID,
+	plan atc.ValidatePipelinePlan,
+	metadata StepMetadata,
+	delegateFactory BuildStepDelegateFactory,
+	artifactStreamer worker.ArtifactStreamer,
+	maxVarFiles int,
+	fileCache *SetPipelineFileCache,
+	configMapFetcher ConfigMapFetcher,
+) Step {
+	return &ValidatePipelineStep{
+		planID:           planID,
+		plan:             plan,
+		metadata:         metadata,
+		delegateFactory:  delegateFactory,
+		artifactStreamer: artifactStreamer,
+		maxVarFiles:      maxVarFiles,
+		fileCache:        fileCache,
+		configMapFetcher: configMapFetcher,
+	}
+}
+
+func (step *ValidatePipelineStep) Run(ctx context.Context, state RunState) (bool, error) {
+	delegate := step.delegateFactory.BuildStepDelegate(state)
+	ctx, span := delegate.StartSpan(ctx, "validate_pipeline", tracing.Attrs{
+		"name":                   step.plan.Name,
+		"validate_pipeline.file": step.plan.File,
+	})
+
+	ok, err := step.run(ctx, state, delegate)
+	tracing.End(span, err)
+
+	return ok, err
+}
+
+func (step *ValidatePipelineStep) run(ctx context.Context, state RunState, delegate BuildStepDelegate) (bool, error) {
+	logger := lagerctx.FromContext(ctx)
+	logger = logger.Session("validate-pipeline-step", lager.Data{
+		"step-name": step.plan.Name,
+		"job-id":    step.metadata.JobID,
+		"build-id":  step.metadata.BuildID,
+	})
+
+	delegate.Initializing(logger)
+
+	interpolatedPlan, err := creds.NewSetPipelinePlan(state, atc.SetPipelinePlan{
+		Name:     step.plan.Name,
+		File:     step.plan.File,
+		VarFiles: step.plan.VarFiles,
+		Vars:     step.plan.Vars,
+	}).Evaluate()
+	if err != nil {
+		return false, err
+	}
+
+	interpolatedPlan.File, err = expandFilePath(interpolatedPlan.File, step.metadata, state)
+	if err != nil {
+		return false, err
+	}
+
+	stderr := delegate.Stderr()
+
+	// the config is fetched through a set_pipeline step which is never run, so
+	// that the files are fetched and interpolated exactly as they would be
+	// when setting the pipeline
+	source := setPipelineSource{
+		ctx:    ctx,
+		logger: logger,
+		step: &SetPipelineStep{
+			planID:           step.planID,
+			plan:             interpolatedPlan,
+			metadata:         step.metadata,
+			artifactStreamer: step.artifactStreamer,
+			maxVarFiles:      step.maxVarFiles,
+			fileCache:        step.fileCache,
+			configMapFetcher: step.configMapFetcher,
+		},
+		repo:             state.ArtifactRepository(),
+		artifactStreamer: step.artifactStreamer,
+		stderr:           stderr,
+	}
+
+	err = source.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	atcConfig, err := source.FetchPipelineConfig()
+	if err != nil {
+		return false, err
+	}
+
+	delegate.Starting(logger)
+
+	warnings, errorMessages := configvalidate.Validate(atcConfig)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "WARNING: %s\n", warning.Message)
+	}
+
+	if len(errorMessages) > 0 {
+		fmt.Fprintln(stderr, "invalid pipeline:")
+
+		for _, e := range errorMessages {
+			fmt.Fprintf(stderr, "- %s\n", e)
+		}
+
+		delegate.Finished(logger, false)
+		return false, nil
+	}
+
+	fmt.Fprintf(delegate.Stdout(), "pipeline %s is valid\n", step.plan.Name)
+	delegate.Finished(logger, true)
+
+	return true, nil
+}
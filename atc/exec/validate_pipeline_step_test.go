@@ -0,0 +1,171 @@
+package exec_test
+
+import (
+	"context"
+	"io"
+
+	"code.cloudfoundry.org/lager/lagerctx"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"go.opentelemetry.io/otel/api/trace"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/exec"
+	"github.com/concourse/concourse/atc/exec/build"
+	"github.com/concourse/concourse/atc/exec/build/buildfakes"
+	"github.com/concourse/concourse/atc/exec/execfakes"
+	"github.com/concourse/concourse/atc/runtime"
+	"github.com/concourse/concourse/atc/worker/workerfakes"
+	"github.com/concourse/concourse/vars"
+)
+
+const validPipelineString = `
+jobs:
+- name: some-job
+  plan:
+  - task: some-task
+    file: some-resource/task.yml
+`
+
+const invalidPipelineString = `
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`
+
+var _ = Describe("ValidatePipelineStep", func() {
+	var (
+		ctx        context.Context
+		cancel     func()
+		testLogger *lagertest.TestLogger
+
+		fakeDelegate        *execfakes.FakeBuildStepDelegate
+		fakeDelegateFactory *execfakes.FakeBuildStepDelegateFactory
+
+		fakeArtifactStreamer *workerfakes.FakeArtifactStreamer
+
+		validatePipelinePlan atc.ValidatePipelinePlan
+		artifactRepository   *build.Repository
+		state                *execfakes.FakeRunState
+		fakeSource           *buildfakes.FakeRegisterableArtifact
+
+		pipelineString string
+
+		vpStep  exec.Step
+		stepOk  bool
+		stepErr error
+
+		stepMetadata = exec.StepMetadata{
+			TeamID:       123,
+			TeamName:     "some-team",
+			BuildID:      42,
+			BuildName:    "some-build",
+			PipelineID:   4567,
+			PipelineName: "some-pipeline",
+		}
+
+		stdout, stderr *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testLogger = lagertest.NewTestLogger("validate-pipeline-step-test")
+		ctx, cancel = context.WithCancel(context.Background())
+		ctx = lagerctx.NewContext(ctx, testLogger)
+
+		artifactRepository = build.NewRepository()
+		state = new(execfakes.FakeRunState)
+		state.ArtifactRepositoryReturns(artifactRepository)
+		state.GetStub = vars.StaticVariables{}.Get
+
+		fakeSource = new(buildfakes.FakeRegisterableArtifact)
+		artifactRepository.RegisterArtifact("some-resource", fakeSource)
+
+		stdout = gbytes.NewBuffer()
+		stderr = gbytes.NewBuffer()
+
+		fakeDelegate = new(execfakes.FakeBuildStepDelegate)
+		fakeDelegate.StdoutReturns(stdout)
+		fakeDelegate.StderrReturns(stderr)
+		fakeDelegate.StartSpanReturns(ctx, trace.NoopSpan{})
+
+		fakeDelegateFactory = new(execfakes.FakeBuildStepDelegateFactory)
+		fakeDelegateFactory.BuildStepDelegateReturns(fakeDelegate)
+
+		pipelineString = validPipelineString
+
+		fakeArtifactStreamer = new(workerfakes.FakeArtifactStreamer)
+		fakeArtifactStreamer.StreamFileFromArtifactStub = func(context.Context, runtime.Artifact, string) (io.ReadCloser, error) {
+			return &fakeReadCloser{str: pipelineString}, nil
+		}
+
+		validatePipelinePlan = atc.ValidatePipelinePlan{
+			Name: "some-pipeline",
+			File: "some-resource/pipeline.yml",
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	JustBeforeEach(func() {
+		vpStep = exec.NewValidatePipelineStep(
+			atc.PlanID("56"),
+			validatePipelinePlan,
+			stepMetadata,
+			fakeDelegateFactory,
+			fakeArtifactStreamer,
+			10,
+			nil,
+			nil,
+		)
+
+		stepOk, stepErr = vpStep.Run(ctx, state)
+	})
+
+	Context("when the pipeline is valid", func() {
+		It("succeeds", func() {
+			Expect(stepErr).ToNot(HaveOccurred())
+			Expect(stepOk).To(BeTrue())
+			Expect(stdout).To(gbytes.Say("pipeline some-pipeline is valid"))
+		})
+
+		It("fetches the pipeline file", func() {
+			Expect(fakeArtifactStreamer.StreamFileFromArtifactCallCount()).To(Equal(1))
+			_, _, path := fakeArtifactStreamer.StreamFileFromArtifactArgsForCall(0)
+			Expect(path).To(Equal("pipeline.yml"))
+		})
+
+		It("finishes the step successfully", func() {
+			Expect(fakeDelegate.FinishedCallCount()).To(Equal(1))
+			_, succeeded := fakeDelegate.FinishedArgsForCall(0)
+			Expect(succeeded).To(BeTrue())
+		})
+	})
+
+	Context("when the pipeline is invalid", func() {
+		BeforeEach(func() {
+			pipelineString = invalidPipelineString
+		})
+
+		It("fails with the errors on stderr", func() {
+			Expect(stepErr).ToNot(HaveOccurred())
+			Expect(stepOk).To(BeFalse())
+			Expect(stderr).To(gbytes.Say("invalid pipeline:"))
+			Expect(stderr).To(gbytes.Say("unknown resource 'some-resource'"))
+		})
+	})
+
+	Context("when the file is not specified", func() {
+		BeforeEach(func() {
+			validatePipelinePlan.File = ""
+		})
+
+		It("returns an error", func() {
+			Expect(stepErr).To(MatchError("file is not specified"))
+		})
+	})
+})
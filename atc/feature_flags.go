@@ -6,4 +6,40 @@ var (
 	EnableBuildRerunWhenWorkerDisappears bool
 	EnableAcrossStep                     bool
 	EnablePipelineInstances              bool
+
+	// ReadOnlyMode prevents pipeline configs from being saved.
+	ReadOnlyMode bool
+
+	// DisableSetPipelineStep makes every set_pipeline step fail, so that
+	// pipelines can only be changed through the API.
+	DisableSetPipelineStep bool
+
+	// SetPipelineRemoteTargets are the URLs of the remote Concourses which
+	// set_pipeline steps may set pipelines on.
+	SetPipelineRemoteTargets []string
+
+	// NotificationWebhookURLs are the URL prefixes of the webhooks which
+	// set_pipeline steps may configure as notification recipients.
+	NotificationWebhookURLs []string
 )
+
+// The features which may be enabled with feature flags.
+const (
+	FeatureGlobalResources                = "global_resources"
+	FeatureRedactSecrets                  = "redact_secrets"
+	FeatureBuildRerunWhenWorkerDisappears = "build_rerun_when_worker_disappears"
+	FeatureAcrossStep                     = "across_step"
+	FeaturePipelineInstances              = "pipeline_instances"
+)
+
+// EnabledFeatures returns whether each feature behind a feature flag is
+// enabled on this ATC.
+func EnabledFeatures() map[string]bool {
+	return map[string]bool{
+		FeatureGlobalResources:                EnableGlobalResources,
+		FeatureRedactSecrets:                  EnableRedactSecrets,
+		FeatureBuildRerunWhenWorkerDisappears: EnableBuildRerunWhenWorkerDisappears,
+		FeatureAcrossStep:                     EnableAcrossStep,
+		FeaturePipelineInstances:              EnablePipelineInstances,
+	}
+}
@@ -29,5 +29,11 @@ func (pc *pipelineCollector) Run(ctx context.Context) error {
 		return err
 	}
 
+	err = pc.pipelineLifecycle.ArchiveExpiredPipelines()
+	if err != nil {
+		logger.Error("failed-to-archive-expired-pipelines", err)
+		return err
+	}
+
 	return nil
 }
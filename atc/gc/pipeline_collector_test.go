@@ -26,5 +26,12 @@ var _ = Describe("PipelineCollector", func() {
 
 			Expect(fakePipelineLifecycle.ArchiveAbandonedPipelinesCallCount()).To(Equal(1))
 		})
+
+		It("tells the pipeline lifecycle to archive expired pipelines", func() {
+			err := collector.Run(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePipelineLifecycle.ArchiveExpiredPipelinesCallCount()).To(Equal(1))
+		})
 	})
 })
@@ -68,6 +68,9 @@ type Monitor struct {
 	ConcurrentRequestsLimitHit map[string]*Counter
 
 	VolumesStreamed Counter
+
+	SetPipelineFileCacheHits   Counter
+	SetPipelineFileCacheMisses Counter
 }
 
 var Metrics = NewMonitor()
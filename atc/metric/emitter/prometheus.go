@@ -54,6 +54,11 @@ type PrometheusEmitter struct {
 
 	volumesStreamed prometheus.Counter
 
+	setPipelineNoDiff             *prometheus.CounterVec
+	setPipelineValidationWarnings *prometheus.CounterVec
+	setPipelineConfigBytes        *prometheus.HistogramVec
+	setPipelineDiffSize           *prometheus.HistogramVec
+
 	workerContainers        *prometheus.GaugeVec
 	workerUnknownContainers *prometheus.GaugeVec
 	workerVolumes           *prometheus.GaugeVec
@@ -403,6 +408,52 @@ func (config *PrometheusConfig) NewEmitter() (metric.Emitter, error) {
 	)
 	prometheus.MustRegister(volumesStreamed)
 
+	setPipelineNoDiff := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "concourse",
+			Subsystem: "set_pipeline",
+			Name:      "no_diff_total",
+			Help:      "Number of times a set_pipeline step found no changes to apply.",
+		},
+		[]string{"team", "pipeline"},
+	)
+	prometheus.MustRegister(setPipelineNoDiff)
+
+	setPipelineValidationWarnings := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "concourse",
+			Subsystem: "set_pipeline",
+			Name:      "validation_warnings_total",
+			Help:      "Number of warnings found when validating the configs of set_pipeline steps.",
+		},
+		[]string{"team", "pipeline", "warning_code"},
+	)
+	prometheus.MustRegister(setPipelineValidationWarnings)
+
+	setPipelineConfigBytes := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "concourse",
+			Subsystem: "set_pipeline",
+			Name:      "config_bytes",
+			Help:      "Size in bytes of the pipeline configs saved by set_pipeline steps.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+		},
+		[]string{"team", "pipeline"},
+	)
+	prometheus.MustRegister(setPipelineConfigBytes)
+
+	setPipelineDiffSize := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "concourse",
+			Subsystem: "set_pipeline",
+			Name:      "diff_size",
+			Help:      "Number of jobs and resources changed by set_pipeline steps.",
+			Buckets:   []float64{0, 1, 2, 5, 10, 20, 50, 100},
+		},
+		[]string{"team", "pipeline", "kind"},
+	)
+	prometheus.MustRegister(setPipelineDiffSize)
+
 	listener, err := net.Listen("tcp", config.bind())
 	if err != nil {
 		return nil, err
@@ -457,6 +508,11 @@ func (config *PrometheusConfig) NewEmitter() (metric.Emitter, error) {
 		workerUnknownVolumes:    workerUnknownVolumes,
 
 		volumesStreamed: volumesStreamed,
+
+		setPipelineNoDiff:             setPipelineNoDiff,
+		setPipelineValidationWarnings: setPipelineValidationWarnings,
+		setPipelineConfigBytes:        setPipelineConfigBytes,
+		setPipelineDiffSize:           setPipelineDiffSize,
 	}
 	go emitter.periodicMetricGC()
 
@@ -534,6 +590,32 @@ func (emitter *PrometheusEmitter) Emit(logger lager.Logger, event metric.Event)
 		emitter.checksQueueSize.Set(event.Value)
 	case "volumes streamed":
 		emitter.volumesStreamed.Add(event.Value)
+	case "set pipeline no diff":
+		emitter.setPipelineNoDiff.
+			WithLabelValues(
+				event.Attributes["team"],
+				event.Attributes["pipeline"],
+			).Add(event.Value)
+	case "set pipeline validation warnings":
+		emitter.setPipelineValidationWarnings.
+			WithLabelValues(
+				event.Attributes["team"],
+				event.Attributes["pipeline"],
+				event.Attributes["warning_code"],
+			).Add(event.Value)
+	case "set pipeline config bytes":
+		emitter.setPipelineConfigBytes.
+			WithLabelValues(
+				event.Attributes["team"],
+				event.Attributes["pipeline"],
+			).Observe(event.Value)
+	case "set pipeline diff size":
+		emitter.setPipelineDiffSize.
+			WithLabelValues(
+				event.Attributes["team"],
+				event.Attributes["pipeline"],
+				event.Attributes["kind"],
+			).Observe(event.Value)
 	default:
 		// unless we have a specific metric, we do nothing
 	}
@@ -463,6 +463,136 @@ func ms(duration time.Duration) float64 {
 	return float64(duration) / 1000000
 }
 
+type SetPipelineResourceUsage struct {
+	Team       string
+	Pipeline   string
+	Goroutines uint64
+	HeapAllocs uint64
+	GCPause    time.Duration
+}
+
+func (event SetPipelineResourceUsage) Emit(logger lager.Logger) {
+	attrs := map[string]string{
+		"team":     event.Team,
+		"pipeline": event.Pipeline,
+	}
+
+	Metrics.emit(
+		logger.Session("set-pipeline-goroutines"),
+		Event{
+			Name:       "set pipeline goroutines",
+			Value:      float64(event.Goroutines),
+			Attributes: attrs,
+		},
+	)
+
+	Metrics.emit(
+		logger.Session("set-pipeline-heap-allocations"),
+		Event{
+			Name:       "set pipeline heap allocations",
+			Value:      float64(event.HeapAllocs),
+			Attributes: attrs,
+		},
+	)
+
+	Metrics.emit(
+		logger.Session("set-pipeline-gc-pause"),
+		Event{
+			Name:       "set pipeline gc pause",
+			Value:      ms(event.GCPause),
+			Attributes: attrs,
+		},
+	)
+}
+
+type SetPipelineNoDiff struct {
+	Team     string
+	Pipeline string
+}
+
+func (event SetPipelineNoDiff) Emit(logger lager.Logger) {
+	Metrics.emit(
+		logger.Session("set-pipeline-no-diff"),
+		Event{
+			Name:  "set pipeline no diff",
+			Value: 1,
+			Attributes: map[string]string{
+				"team":     event.Team,
+				"pipeline": event.Pipeline,
+			},
+		},
+	)
+}
+
+type SetPipelineValidationWarnings struct {
+	Team        string
+	Pipeline    string
+	WarningCode string
+	Count       int
+}
+
+func (event SetPipelineValidationWarnings) Emit(logger lager.Logger) {
+	Metrics.emit(
+		logger.Session("set-pipeline-validation-warnings"),
+		Event{
+			Name:  "set pipeline validation warnings",
+			Value: float64(event.Count),
+			Attributes: map[string]string{
+				"team":         event.Team,
+				"pipeline":     event.Pipeline,
+				"warning_code": event.WarningCode,
+			},
+		},
+	)
+}
+
+type SetPipelineConfigBytes struct {
+	Team     string
+	Pipeline string
+	Bytes    int
+}
+
+func (event SetPipelineConfigBytes) Emit(logger lager.Logger) {
+	Metrics.emit(
+		logger.Session("set-pipeline-config-bytes"),
+		Event{
+			Name:  "set pipeline config bytes",
+			Value: float64(event.Bytes),
+			Attributes: map[string]string{
+				"team":     event.Team,
+				"pipeline": event.Pipeline,
+			},
+		},
+	)
+}
+
+type SetPipelineDiffSize struct {
+	Team             string
+	Pipeline         string
+	ChangedJobs      int
+	ChangedResources int
+}
+
+func (event SetPipelineDiffSize) Emit(logger lager.Logger) {
+	for kind, count := range map[string]int{
+		"changed_jobs":      event.ChangedJobs,
+		"changed_resources": event.ChangedResources,
+	} {
+		Metrics.emit(
+			logger.Session("set-pipeline-diff-size"),
+			Event{
+				Name:  "set pipeline diff size",
+				Value: float64(count),
+				Attributes: map[string]string{
+					"team":     event.Team,
+					"pipeline": event.Pipeline,
+					"kind":     kind,
+				},
+			},
+		)
+	}
+}
+
 type ErrorLog struct {
 	Message string
 	Value   int
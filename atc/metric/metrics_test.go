@@ -1,6 +1,8 @@
 package metric_test
 
 import (
+	"time"
+
 	"github.com/concourse/concourse/atc/db"
 	"github.com/concourse/concourse/atc/metric"
 	"github.com/concourse/concourse/atc/metric/metricfakes"
@@ -49,6 +51,138 @@ var _ = Describe("Metrics", func() {
 			Expect(event.Value).To(Equal(float64(1)))
 		})
 	})
+
+	Describe("set pipeline metrics", func() {
+		var (
+			emitter         *metricfakes.FakeEmitter
+			originalMonitor *metric.Monitor
+		)
+
+		BeforeEach(func() {
+			emitter = new(metricfakes.FakeEmitter)
+			originalMonitor = metric.Metrics
+
+			emitterFactory := new(metricfakes.FakeEmitterFactory)
+			emitterFactory.IsConfiguredReturns(true)
+			emitterFactory.NewEmitterReturns(emitter, nil)
+
+			metric.Metrics = metric.NewMonitor()
+			metric.Metrics.RegisterEmitter(emitterFactory)
+			metric.Metrics.Initialize(testLogger, "test", map[string]string{}, 1000)
+		})
+
+		AfterEach(func() {
+			metric.Metrics = originalMonitor
+		})
+
+		It("emits the usage tagged with the team and pipeline", func() {
+			metric.SetPipelineResourceUsage{
+				Team:       "some-team",
+				Pipeline:   "some-pipeline",
+				Goroutines: 12,
+				HeapAllocs: 1024,
+				GCPause:    2 * time.Millisecond,
+			}.Emit(testLogger)
+
+			Eventually(emitter.EmitCallCount).Should(Equal(3))
+
+			values := map[string]float64{}
+			for i := 0; i < emitter.EmitCallCount(); i++ {
+				_, event := emitter.EmitArgsForCall(i)
+				Expect(event.Attributes).To(Equal(map[string]string{
+					"team":     "some-team",
+					"pipeline": "some-pipeline",
+				}))
+				values[event.Name] = event.Value
+			}
+
+			Expect(values).To(Equal(map[string]float64{
+				"set pipeline goroutines":       12,
+				"set pipeline heap allocations": 1024,
+				"set pipeline gc pause":         2,
+			}))
+		})
+
+		It("emits a no diff count tagged with the team and pipeline", func() {
+			metric.SetPipelineNoDiff{
+				Team:     "some-team",
+				Pipeline: "some-pipeline",
+			}.Emit(testLogger)
+
+			Eventually(emitter.EmitCallCount).Should(Equal(1))
+
+			_, event := emitter.EmitArgsForCall(0)
+			Expect(event.Name).To(Equal("set pipeline no diff"))
+			Expect(event.Value).To(Equal(float64(1)))
+			Expect(event.Attributes).To(Equal(map[string]string{
+				"team":     "some-team",
+				"pipeline": "some-pipeline",
+			}))
+		})
+
+		It("emits a validation warning count tagged with the team, pipeline and warning code", func() {
+			metric.SetPipelineValidationWarnings{
+				Team:        "some-team",
+				Pipeline:    "some-pipeline",
+				WarningCode: "invalid_identifier",
+				Count:       2,
+			}.Emit(testLogger)
+
+			Eventually(emitter.EmitCallCount).Should(Equal(1))
+
+			_, event := emitter.EmitArgsForCall(0)
+			Expect(event.Name).To(Equal("set pipeline validation warnings"))
+			Expect(event.Value).To(Equal(float64(2)))
+			Expect(event.Attributes).To(Equal(map[string]string{
+				"team":         "some-team",
+				"pipeline":     "some-pipeline",
+				"warning_code": "invalid_identifier",
+			}))
+		})
+
+		It("emits the config size tagged with the team and pipeline", func() {
+			metric.SetPipelineConfigBytes{
+				Team:     "some-team",
+				Pipeline: "some-pipeline",
+				Bytes:    2048,
+			}.Emit(testLogger)
+
+			Eventually(emitter.EmitCallCount).Should(Equal(1))
+
+			_, event := emitter.EmitArgsForCall(0)
+			Expect(event.Name).To(Equal("set pipeline config bytes"))
+			Expect(event.Value).To(Equal(float64(2048)))
+			Expect(event.Attributes).To(Equal(map[string]string{
+				"team":     "some-team",
+				"pipeline": "some-pipeline",
+			}))
+		})
+
+		It("emits the number of changed jobs and resources tagged with their kind", func() {
+			metric.SetPipelineDiffSize{
+				Team:             "some-team",
+				Pipeline:         "some-pipeline",
+				ChangedJobs:      3,
+				ChangedResources: 1,
+			}.Emit(testLogger)
+
+			Eventually(emitter.EmitCallCount).Should(Equal(2))
+
+			counts := map[string]float64{}
+			for i := 0; i < 2; i++ {
+				_, event := emitter.EmitArgsForCall(i)
+				Expect(event.Name).To(Equal("set pipeline diff size"))
+				Expect(event.Attributes).To(HaveKeyWithValue("team", "some-team"))
+				Expect(event.Attributes).To(HaveKeyWithValue("pipeline", "some-pipeline"))
+				counts[event.Attributes["kind"]] = event.Value
+			}
+
+			Expect(counts).To(Equal(map[string]float64{
+				"changed_jobs":      3,
+				"changed_resources": 1,
+			}))
+		})
+	})
 })
 
 type smartFakeEmitter struct {
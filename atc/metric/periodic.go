@@ -228,6 +228,28 @@ func tick(logger lager.Logger, m *Monitor) {
 		},
 	)
 
+	m.emit(
+		logger.Session("set-pipeline-file-cache-hits"),
+		Event{
+			Name:  "set pipeline file cache",
+			Value: m.SetPipelineFileCacheHits.Delta(),
+			Attributes: map[string]string{
+				"result": "hit",
+			},
+		},
+	)
+
+	m.emit(
+		logger.Session("set-pipeline-file-cache-misses"),
+		Event{
+			Name:  "set pipeline file cache",
+			Value: m.SetPipelineFileCacheMisses.Delta(),
+			Attributes: map[string]string{
+				"result": "miss",
+			},
+		},
+	)
+
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
 
@@ -168,4 +168,37 @@ var _ = Describe("Periodic emission of metrics", func() {
 			)
 		})
 	})
+
+	Context("set pipeline file cache metrics", func() {
+		BeforeEach(func() {
+			monitor.SetPipelineFileCacheHits.IncDelta(3)
+			monitor.SetPipelineFileCacheMisses.IncDelta(1)
+		})
+
+		It("emits", func() {
+			Eventually(events).Should(
+				ContainElement(
+					MatchFields(IgnoreExtras, Fields{
+						"Name":  Equal("set pipeline file cache"),
+						"Value": Equal(float64(3)),
+						"Attributes": Equal(map[string]string{
+							"result": "hit",
+						}),
+					}),
+				),
+			)
+
+			Eventually(events).Should(
+				ContainElement(
+					MatchFields(IgnoreExtras, Fields{
+						"Name":  Equal("set pipeline file cache"),
+						"Value": Equal(float64(1)),
+						"Attributes": Equal(map[string]string{
+							"result": "miss",
+						}),
+					}),
+				),
+			)
+		})
+	})
 })
@@ -0,0 +1,89 @@
+package atc
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// NotificationSchemeEmail notifies an email address, e.g.
+	// email:ops@example.com.
+	NotificationSchemeEmail = "email"
+
+	// NotificationSchemeWebhook posts the notification as JSON to a URL, e.g.
+	// webhook:https://example.com/hooks/concourse.
+	NotificationSchemeWebhook = "webhook"
+)
+
+// PipelineNotifications configures who is notified when a build of one of a
+// pipeline's jobs completes. Each recipient is of the form <scheme>:<target>.
+type PipelineNotifications struct {
+	OnFailure []string `json:"on_failure,omitempty"`
+	OnSuccess []string `json:"on_success,omitempty"`
+}
+
+func (notifications PipelineNotifications) Validate() error {
+	for _, recipient := range append(append([]string{}, notifications.OnFailure...), notifications.OnSuccess...) {
+		scheme, target, err := ParseNotificationRecipient(recipient)
+		if err != nil {
+			return err
+		}
+
+		if scheme == NotificationSchemeWebhook {
+			u, err := url.Parse(target)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				return fmt.Errorf("invalid webhook url: %s", target)
+			}
+
+			if !NotificationWebhookAllowed(target) {
+				return fmt.Errorf("webhook url is not allowed: %s", target)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Recipients returns the recipients to notify of a build which completed with
+// the given status. Failed and errored builds notify the on_failure
+// recipients.
+func (notifications PipelineNotifications) Recipients(status BuildStatus) []string {
+	switch status {
+	case StatusSucceeded:
+		return notifications.OnSuccess
+	case StatusFailed, StatusErrored:
+		return notifications.OnFailure
+	default:
+		return nil
+	}
+}
+
+// NotificationWebhookAllowed returns whether the operator allowed webhook
+// notifications to be posted to the URL. The ATC posts to the URL from inside
+// its network, so no webhooks are allowed unless configured.
+func NotificationWebhookAllowed(webhookURL string) bool {
+	for _, allowed := range NotificationWebhookURLs {
+		prefix := strings.TrimSuffix(allowed, "/")
+		if webhookURL == prefix || strings.HasPrefix(webhookURL, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseNotificationRecipient splits a recipient into its scheme and target.
+func ParseNotificationRecipient(recipient string) (string, string, error) {
+	segs := strings.SplitN(recipient, ":", 2)
+	if len(segs) != 2 || segs[1] == "" {
+		return "", "", fmt.Errorf("invalid notification recipient: %s", recipient)
+	}
+
+	switch segs[0] {
+	case NotificationSchemeEmail, NotificationSchemeWebhook:
+		return segs[0], segs[1], nil
+	default:
+		return "", "", fmt.Errorf("unknown notification scheme '%s' in recipient: %s", segs[0], recipient)
+	}
+}
@@ -0,0 +1,143 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// ErrEmailNotConfigured is returned when notifying an email recipient while
+// no SMTP server is configured.
+var ErrEmailNotConfigured = errors.New("no smtp server is configured for email notifications")
+
+// Notification describes a completed build of one of a pipeline's jobs.
+type Notification struct {
+	TeamName     string          `json:"team_name"`
+	PipelineName string          `json:"pipeline_name"`
+	JobName      string          `json:"job_name"`
+	BuildID      int             `json:"build_id"`
+	BuildName    string          `json:"build_name"`
+	Status       atc.BuildStatus `json:"status"`
+	URL          string          `json:"url"`
+}
+
+func (n Notification) subject() string {
+	return fmt.Sprintf("%s/%s #%s %s", n.PipelineName, n.JobName, n.BuildName, n.Status)
+}
+
+//go:generate counterfeiter . Notifier
+
+// Notifier notifies a recipient, of the form <scheme>:<target>, of a
+// completed build.
+type Notifier interface {
+	Notify(ctx context.Context, recipient string, notification Notification) error
+}
+
+// SMTPConfig configures the server used to send email notifications.
+type SMTPConfig struct {
+	Address  string
+	From     string
+	Username string
+	Password string
+}
+
+type notifier struct {
+	externalURL string
+	httpClient  *http.Client
+	smtp        SMTPConfig
+}
+
+// NewNotifier returns a Notifier which posts webhook notifications with the
+// HTTP client and sends email notifications through the SMTP server. Build
+// URLs are relative to the external URL.
+func NewNotifier(externalURL string, httpClient *http.Client, smtp SMTPConfig) Notifier {
+	return notifier{
+		externalURL: externalURL,
+		httpClient:  httpClient,
+		smtp:        smtp,
+	}
+}
+
+func (n notifier) Notify(ctx context.Context, recipient string, notification Notification) error {
+	scheme, target, err := atc.ParseNotificationRecipient(recipient)
+	if err != nil {
+		return err
+	}
+
+	notification.URL = fmt.Sprintf("%s/builds/%d", strings.TrimSuffix(n.externalURL, "/"), notification.BuildID)
+
+	switch scheme {
+	case atc.NotificationSchemeEmail:
+		return n.sendEmail(target, notification)
+	default:
+		if !atc.NotificationWebhookAllowed(target) {
+			return fmt.Errorf("webhook url is not allowed: %s", target)
+		}
+
+		return n.postWebhook(ctx, target, notification)
+	}
+}
+
+func (n notifier) postWebhook(ctx context.Context, url string, notification Notification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response from webhook: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (n notifier) sendEmail(to string, notification Notification) error {
+	if n.smtp.Address == "" {
+		return ErrEmailNotConfigured
+	}
+
+	var auth smtp.Auth
+	if n.smtp.Username != "" {
+		host := strings.SplitN(n.smtp.Address, ":", 2)[0]
+		auth = smtp.PlainAuth("", n.smtp.Username, n.smtp.Password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.smtp.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", notification.subject())
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "\r\n")
+	fmt.Fprintf(&msg, "build %s of job %s in pipeline %s of team %s %s.\r\n",
+		notification.BuildName,
+		notification.JobName,
+		notification.PipelineName,
+		notification.TeamName,
+		notification.Status,
+	)
+	fmt.Fprintf(&msg, "\r\n%s\r\n", notification.URL)
+
+	return smtp.SendMail(n.smtp.Address, auth, n.smtp.From, []string{to}, []byte(msg.String()))
+}
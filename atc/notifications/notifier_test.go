@@ -0,0 +1,80 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/notifications"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		server       *httptest.Server
+		status       int
+		received     []byte
+		notifier     notifications.Notifier
+		notification notifications.Notification
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		received = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(status)
+		}))
+
+		notifier = notifications.NewNotifier("https://ci.example.com/", server.Client(), notifications.SMTPConfig{})
+		atc.NotificationWebhookURLs = []string{server.URL}
+
+		notification = notifications.Notification{
+			TeamName:     "some-team",
+			PipelineName: "some-pipeline",
+			JobName:      "some-job",
+			BuildID:      42,
+			BuildName:    "7",
+			Status:       atc.StatusFailed,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		atc.NotificationWebhookURLs = nil
+	})
+
+	It("posts the notification to webhooks", func() {
+		Expect(notifier.Notify(context.Background(), "webhook:"+server.URL, notification)).To(Succeed())
+
+		var payload notifications.Notification
+		Expect(json.Unmarshal(received, &payload)).To(Succeed())
+
+		notification.URL = "https://ci.example.com/builds/42"
+		Expect(payload).To(Equal(notification))
+	})
+
+	It("returns an error when the webhook fails", func() {
+		status = http.StatusInternalServerError
+		Expect(notifier.Notify(context.Background(), "webhook:"+server.URL, notification)).To(MatchError("unexpected response from webhook: 500 Internal Server Error"))
+	})
+
+	It("does not post to webhooks which are no longer allowed", func() {
+		atc.NotificationWebhookURLs = nil
+		Expect(notifier.Notify(context.Background(), "webhook:"+server.URL, notification)).To(MatchError("webhook url is not allowed: " + server.URL))
+		Expect(received).To(BeNil())
+	})
+
+	It("returns an error for email recipients without an smtp server", func() {
+		Expect(notifier.Notify(context.Background(), "email:ops@example.com", notification)).To(Equal(notifications.ErrEmailNotConfigured))
+	})
+
+	It("rejects invalid recipients", func() {
+		Expect(notifier.Notify(context.Background(), "sms:555-1234", notification)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// ErrQueueFull is returned when a notification is dropped because too many
+// notifications are already waiting to be sent.
+var ErrQueueFull = errors.New("notification queue is full")
+
+type queuedNotification struct {
+	recipient    string
+	notification Notification
+}
+
+type queuedNotifier struct {
+	logger   lager.Logger
+	notifier Notifier
+	timeout  time.Duration
+	queue    chan queuedNotification
+}
+
+// NewQueuedNotifier returns a Notifier which hands notifications to a fixed
+// number of background workers, so that slow recipients do not hold up
+// finishing builds. Sending each notification may take up to the timeout.
+// Once queueSize notifications are waiting, further notifications are
+// dropped.
+func NewQueuedNotifier(logger lager.Logger, notifier Notifier, workers int, queueSize int, timeout time.Duration) Notifier {
+	n := queuedNotifier{
+		logger:   logger,
+		notifier: notifier,
+		timeout:  timeout,
+		queue:    make(chan queuedNotification, queueSize),
+	}
+
+	for i := 0; i < workers; i++ {
+		go n.work()
+	}
+
+	return n
+}
+
+// Notify queues the notification without waiting for it to be sent. Errors
+// sending it are logged.
+func (n queuedNotifier) Notify(ctx context.Context, recipient string, notification Notification) error {
+	select {
+	case n.queue <- queuedNotification{recipient: recipient, notification: notification}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (n queuedNotifier) work() {
+	for queued := range n.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+		err := n.notifier.Notify(ctx, queued.recipient, queued.notification)
+		cancel()
+
+		if err != nil {
+			n.logger.Error("failed-to-notify", err, lager.Data{
+				"recipient": queued.recipient,
+				"build-id":  queued.notification.BuildID,
+			})
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/atc/notifications"
+	"github.com/concourse/concourse/atc/notifications/notificationsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QueuedNotifier", func() {
+	var (
+		logger       *lagertest.TestLogger
+		fakeNotifier *notificationsfakes.FakeNotifier
+		release      chan struct{}
+		notifier     notifications.Notifier
+		notification notifications.Notification
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeNotifier = new(notificationsfakes.FakeNotifier)
+
+		release = make(chan struct{})
+		fakeNotifier.NotifyStub = func(ctx context.Context, recipient string, notification notifications.Notification) error {
+			<-release
+			if recipient == "email:broken@example.com" {
+				return errors.New("smtp down")
+			}
+			return nil
+		}
+
+		notifier = notifications.NewQueuedNotifier(logger, fakeNotifier, 1, 1, time.Minute)
+
+		notification = notifications.Notification{
+			PipelineName: "some-pipeline",
+			JobName:      "some-job",
+			BuildID:      42,
+			Status:       atc.StatusFailed,
+		}
+	})
+
+	AfterEach(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+
+	It("returns without waiting for the notification to be sent", func() {
+		Expect(notifier.Notify(context.Background(), "email:ops@example.com", notification)).To(Succeed())
+		Eventually(fakeNotifier.NotifyCallCount).Should(Equal(1))
+
+		close(release)
+	})
+
+	It("sends each notification with its own deadline", func() {
+		close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		Expect(notifier.Notify(ctx, "email:ops@example.com", notification)).To(Succeed())
+		cancel()
+
+		Eventually(fakeNotifier.NotifyCallCount).Should(Equal(1))
+
+		sendCtx, recipient, sent := fakeNotifier.NotifyArgsForCall(0)
+		Expect(recipient).To(Equal("email:ops@example.com"))
+		Expect(sent).To(Equal(notification))
+
+		_, hasDeadline := sendCtx.Deadline()
+		Expect(hasDeadline).To(BeTrue())
+	})
+
+	It("drops notifications once the queue is full", func() {
+		Expect(notifier.Notify(context.Background(), "email:ops@example.com", notification)).To(Succeed())
+		Eventually(fakeNotifier.NotifyCallCount).Should(Equal(1))
+
+		Expect(notifier.Notify(context.Background(), "email:dev@example.com", notification)).To(Succeed())
+		Expect(notifier.Notify(context.Background(), "email:qa@example.com", notification)).To(Equal(notifications.ErrQueueFull))
+
+		close(release)
+
+		Eventually(fakeNotifier.NotifyCallCount).Should(Equal(2))
+		Consistently(fakeNotifier.NotifyCallCount).Should(Equal(2))
+	})
+
+	It("logs failures to notify", func() {
+		close(release)
+
+		Expect(notifier.Notify(context.Background(), "email:broken@example.com", notification)).To(Succeed())
+
+		Eventually(logger.LogMessages).Should(ContainElement("test.failed-to-notify"))
+	})
+})
@@ -0,0 +1,59 @@
+package atc_test
+
+import (
+	. "github.com/concourse/concourse/atc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PipelineNotifications", func() {
+	notifications := PipelineNotifications{
+		OnFailure: []string{"email:ops@example.com"},
+		OnSuccess: []string{"webhook:https://example.com/hook"},
+	}
+
+	Describe("Validate", func() {
+		BeforeEach(func() {
+			NotificationWebhookURLs = []string{"https://example.com/"}
+		})
+
+		AfterEach(func() {
+			NotificationWebhookURLs = nil
+		})
+
+		It("accepts email and webhook recipients", func() {
+			Expect(notifications.Validate()).To(Succeed())
+		})
+
+		It("rejects unknown schemes", func() {
+			Expect(PipelineNotifications{OnFailure: []string{"sms:555-1234"}}.Validate()).To(MatchError("unknown notification scheme 'sms' in recipient: sms:555-1234"))
+		})
+
+		It("rejects recipients without a target", func() {
+			Expect(PipelineNotifications{OnSuccess: []string{"email:"}}.Validate()).To(MatchError("invalid notification recipient: email:"))
+		})
+
+		It("rejects webhooks which are not http urls", func() {
+			Expect(PipelineNotifications{OnSuccess: []string{"webhook:ftp://example.com"}}.Validate()).To(MatchError("invalid webhook url: ftp://example.com"))
+		})
+
+		It("rejects webhooks which the operator did not allow", func() {
+			Expect(PipelineNotifications{OnSuccess: []string{"webhook:https://example.com.evil.com/hook"}}.Validate()).To(MatchError("webhook url is not allowed: https://example.com.evil.com/hook"))
+			Expect(PipelineNotifications{OnSuccess: []string{"webhook:http://169.254.169.254/latest"}}.Validate()).To(MatchError("webhook url is not allowed: http://169.254.169.254/latest"))
+		})
+
+		It("rejects every webhook when none are allowed", func() {
+			NotificationWebhookURLs = nil
+			Expect(PipelineNotifications{OnSuccess: []string{"webhook:https://example.com/hook"}}.Validate()).To(MatchError("webhook url is not allowed: https://example.com/hook"))
+		})
+	})
+
+	Describe("Recipients", func() {
+		It("returns the recipients for the build status", func() {
+			Expect(notifications.Recipients(StatusSucceeded)).To(Equal([]string{"webhook:https://example.com/hook"}))
+			Expect(notifications.Recipients(StatusFailed)).To(Equal([]string{"email:ops@example.com"}))
+			Expect(notifications.Recipients(StatusErrored)).To(Equal([]string{"email:ops@example.com"}))
+			Expect(notifications.Recipients(StatusAborted)).To(BeEmpty())
+		})
+	})
+})
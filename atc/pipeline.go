@@ -21,6 +21,7 @@ type Pipeline struct {
 	Groups       GroupConfigs   `json:"groups,omitempty"`
 	TeamName     string         `json:"team_name"`
 	Display      *DisplayConfig `json:"display,omitempty"`
+	Description  string         `json:"description,omitempty"`
 	LastUpdated  int64          `json:"last_updated,omitempty"`
 }
 
@@ -0,0 +1,8 @@
+package atc
+
+// PipelineOwnership records who is responsible for a pipeline, so that
+// problems with it can be routed to the right people.
+type PipelineOwnership struct {
+	Team    string `json:"team,omitempty"`
+	Contact string `json:"contact,omitempty"`
+}
@@ -4,12 +4,13 @@ type Plan struct {
 	ID       PlanID `json:"id"`
 	Attempts []int  `json:"attempts,omitempty"`
 
-	Get         *GetPlan         `json:"get,omitempty"`
-	Put         *PutPlan         `json:"put,omitempty"`
-	Check       *CheckPlan       `json:"check,omitempty"`
-	Task        *TaskPlan        `json:"task,omitempty"`
-	SetPipeline *SetPipelinePlan `json:"set_pipeline,omitempty"`
-	LoadVar     *LoadVarPlan     `json:"load_var,omitempty"`
+	Get              *GetPlan              `json:"get,omitempty"`
+	Put              *PutPlan              `json:"put,omitempty"`
+	Check            *CheckPlan            `json:"check,omitempty"`
+	Task             *TaskPlan             `json:"task,omitempty"`
+	SetPipeline      *SetPipelinePlan      `json:"set_pipeline,omitempty"`
+	LoadVar          *LoadVarPlan          `json:"load_var,omitempty"`
+	ValidatePipeline *ValidatePipelinePlan `json:"validate_pipeline,omitempty"`
 
 	Do         *DoPlan         `json:"do,omitempty"`
 	InParallel *InParallelPlan `json:"in_parallel,omitempty"`
@@ -312,6 +313,124 @@ type SetPipelinePlan struct {
 	Vars         map[string]interface{} `json:"vars,omitempty"`
 	VarFiles     []string               `json:"var_files,omitempty"`
 	InstanceVars map[string]interface{} `json:"instance_vars,omitempty"`
+
+	SkipReachabilityCheck    bool           `json:"skip_reachability_check,omitempty"`
+	TriggerChecks            bool           `json:"trigger_checks,omitempty"`
+	InheritPinnedVersions    bool           `json:"inherit_pinned_versions,omitempty"`
+	LockResourceTypeVersions bool           `json:"lock_resource_type_versions,omitempty"`
+	PinBuildInputs           []string       `json:"pin_build_inputs,omitempty"`
+	TemplateEngine           string         `json:"template_engine,omitempty"`
+	CleanupOnFailure         bool           `json:"cleanup_on_failure,omitempty"`
+	ArchiveUnlisted          bool           `json:"archive_unlisted,omitempty"`
+	ManagedPrefix            string         `json:"managed_prefix,omitempty"`
+	Extends                  string         `json:"extends,omitempty"`
+	Display                  *DisplayConfig `json:"display,omitempty"`
+	SlackWebhook             string         `json:"slack_webhook,omitempty"`
+	SlackChannel             string         `json:"slack_channel,omitempty"`
+	Freeze                   bool           `json:"freeze,omitempty"`
+
+	// A deadline for the step, excluding the waits after the pipeline has been
+	// saved. Defaults to 10 minutes.
+	Timeout                 string                 `json:"timeout,omitempty"`
+	MaxParseRetries         int                    `json:"max_parse_retries,omitempty"`
+	RenameFrom              string                 `json:"rename_from,omitempty"`
+	ArchiveOld              bool                   `json:"archive_old,omitempty"`
+	StrategicMergeFiles     []string               `json:"strategic_merge_files,omitempty"`
+	PostSaveSleep           string                 `json:"post_save_sleep,omitempty"`
+	MinATCVersion           string                 `json:"min_atc_version,omitempty"`
+	When                    string                 `json:"when,omitempty"`
+	PreserveResourceHistory bool                   `json:"preserve_resource_history,omitempty"`
+	TeamVar                 string                 `json:"team_var,omitempty"`
+	CanaryJobs              []string               `json:"canary_jobs,omitempty"`
+	TriggerChecksJitter     string                 `json:"trigger_checks_jitter,omitempty"`
+	CloneFrom               string                 `json:"clone_from,omitempty"`
+	ApplyJobs               []string               `json:"apply_jobs,omitempty"`
+	Target                  *SetPipelineTarget     `json:"target,omitempty"`
+	WaitForSuccess          bool                   `json:"wait_for_success,omitempty"`
+	WaitTimeout             string                 `json:"wait_timeout,omitempty"`
+	RollbackOnError         bool                   `json:"rollback_on_error,omitempty"`
+	RollbackWindow          string                 `json:"rollback_window,omitempty"`
+	BannedPatterns          []string               `json:"banned_patterns,omitempty"`
+	ResetBuildHistory       bool                   `json:"reset_build_history,omitempty"`
+	MergeStrategy           *MergeStrategy         `json:"merge_strategy,omitempty"`
+	EncryptFields           []string               `json:"encrypt_fields,omitempty"`
+	LintImage               string                 `json:"lint_image,omitempty"`
+	LintPolicyFile          string                 `json:"lint_policy_file,omitempty"`
+	Notifications           *PipelineNotifications `json:"notifications,omitempty"`
+	VaultDynamicVars        []VaultDynamicVar      `json:"vault_dynamic_vars,omitempty"` // substituted as ((path.key)) references, not values
+	CheckImageResourceTypes bool                   `json:"check_image_resource_types,omitempty"`
+	MinJobs                 int                    `json:"min_jobs,omitempty"`
+	NotifyEventBus          bool                   `json:"notify_event_bus,omitempty"`
+	ApplyResources          []string               `json:"apply_resources,omitempty"`
+	DiffFormat              string                 `json:"diff_format,omitempty"`
+	GitHubDeployment        *GitHubDeployment      `json:"github_deployment,omitempty"`
+	DiffOutput              string                 `json:"diff_output,omitempty"`
+	Scope                   string                 `json:"scope,omitempty"`
+	VarPrecedence           string                 `json:"var_precedence,omitempty"`
+	CheckImageTags          bool                   `json:"check_image_tags,omitempty"`
+	FeatureFlagCheck        bool                   `json:"feature_flag_check,omitempty"`
+	SBOM                    bool                   `json:"sbom,omitempty"`
+	NamePattern             string                 `json:"name_pattern,omitempty"`
+	PrefetchVarFiles        bool                   `json:"prefetch_var_files,omitempty"`
+	ForceCredentialRefresh  bool                   `json:"force_credential_refresh,omitempty"`
+	TriggerFirstJob         bool                   `json:"trigger_first_job,omitempty"`
+	LabelResources          bool                   `json:"label_resources,omitempty"`
+	PreValidateScript       string                 `json:"pre_validate_script,omitempty"`
+	PreValidateImage        *ImageResource         `json:"pre_validate_image_resource,omitempty"`
+	MaxParallelJobs         int                    `json:"max_parallel_jobs,omitempty"`
+	CreateTeamIfMissing     bool                   `json:"create_team_if_missing,omitempty"`
+	QueueDepthCheck         bool                   `json:"queue_depth_check,omitempty"`
+	QueueDepthThreshold     int                    `json:"queue_depth_threshold,omitempty"`
+	Description             string                 `json:"description,omitempty"`
+	Owners                  *PipelineOwnership     `json:"owners,omitempty"`
+	ArchiveAfter            string                 `json:"archive_after,omitempty"`
+}
+
+// SetPipelineTarget is a remote Concourse on which a set_pipeline step sets
+// the pipeline, authenticating with the token held by the named var.
+type SetPipelineTarget struct {
+	URL      string `json:"url"`
+	Team     string `json:"team,omitempty"`
+	TokenVar string `json:"token_var,omitempty"`
+}
+
+// TeamName returns the team on the remote Concourse which the pipeline is set
+// in, which is the main team unless another one is configured.
+func (target SetPipelineTarget) TeamName() string {
+	if target.Team == "" {
+		return DefaultTeamName
+	}
+
+	return target.Team
+}
+
+// GitHubDeployment is a GitHub repository in which a set_pipeline step creates
+// a deployment once it has changed the pipeline, authenticating with the token
+// held by the named var.
+type GitHubDeployment struct {
+	Owner       string `json:"owner"`
+	Repo        string `json:"repo"`
+	Environment string `json:"environment,omitempty"`
+	Ref         string `json:"ref,omitempty"`
+	TokenVar    string `json:"token_var"`
+	APIURL      string `json:"api_url,omitempty"`
+}
+
+// VaultDynamicVar is a secret under the team's Vault paths which a
+// set_pipeline step provides as vars named after its keys. Each var is
+// substituted as a ((path.key)) reference, so the secret's values are never
+// baked into the saved config and are resolved when the pipeline runs. A key
+// may only be provided by one entry.
+type VaultDynamicVar struct {
+	Path string   `json:"path"`
+	Keys []string `json:"keys"`
+}
+
+type ValidatePipelinePlan struct {
+	Name     string                 `json:"name"`
+	File     string                 `json:"file"`
+	VarFiles []string               `json:"var_files,omitempty"`
+	Vars     map[string]interface{} `json:"vars,omitempty"`
 }
 
 type LoadVarPlan struct {
@@ -41,6 +41,8 @@ func (factory PlanFactory) NewPlan(step PlanConfig) Plan {
 		plan.SetPipeline = &t
 	case LoadVarPlan:
 		plan.LoadVar = &t
+	case ValidatePipelinePlan:
+		plan.ValidatePipeline = &t
 	case CheckPlan:
 		plan.Check = &t
 	case OnAbortPlan:
@@ -6,26 +6,27 @@ func (plan Plan) Public() *json.RawMessage {
 	var public struct {
 		ID PlanID `json:"id"`
 
-		InParallel     *json.RawMessage `json:"in_parallel,omitempty"`
-		Across         *json.RawMessage `json:"across,omitempty"`
-		Do             *json.RawMessage `json:"do,omitempty"`
-		Get            *json.RawMessage `json:"get,omitempty"`
-		Put            *json.RawMessage `json:"put,omitempty"`
-		Check          *json.RawMessage `json:"check,omitempty"`
-		Task           *json.RawMessage `json:"task,omitempty"`
-		SetPipeline    *json.RawMessage `json:"set_pipeline,omitempty"`
-		LoadVar        *json.RawMessage `json:"load_var,omitempty"`
-		OnAbort        *json.RawMessage `json:"on_abort,omitempty"`
-		OnError        *json.RawMessage `json:"on_error,omitempty"`
-		Ensure         *json.RawMessage `json:"ensure,omitempty"`
-		OnSuccess      *json.RawMessage `json:"on_success,omitempty"`
-		OnFailure      *json.RawMessage `json:"on_failure,omitempty"`
-		Try            *json.RawMessage `json:"try,omitempty"`
-		DependentGet   *json.RawMessage `json:"dependent_get,omitempty"`
-		Timeout        *json.RawMessage `json:"timeout,omitempty"`
-		Retry          *json.RawMessage `json:"retry,omitempty"`
-		ArtifactInput  *json.RawMessage `json:"artifact_input,omitempty"`
-		ArtifactOutput *json.RawMessage `json:"artifact_output,omitempty"`
+		InParallel       *json.RawMessage `json:"in_parallel,omitempty"`
+		Across           *json.RawMessage `json:"across,omitempty"`
+		Do               *json.RawMessage `json:"do,omitempty"`
+		Get              *json.RawMessage `json:"get,omitempty"`
+		Put              *json.RawMessage `json:"put,omitempty"`
+		Check            *json.RawMessage `json:"check,omitempty"`
+		Task             *json.RawMessage `json:"task,omitempty"`
+		SetPipeline      *json.RawMessage `json:"set_pipeline,omitempty"`
+		LoadVar          *json.RawMessage `json:"load_var,omitempty"`
+		ValidatePipeline *json.RawMessage `json:"validate_pipeline,omitempty"`
+		OnAbort          *json.RawMessage `json:"on_abort,omitempty"`
+		OnError          *json.RawMessage `json:"on_error,omitempty"`
+		Ensure           *json.RawMessage `json:"ensure,omitempty"`
+		OnSuccess        *json.RawMessage `json:"on_success,omitempty"`
+		OnFailure        *json.RawMessage `json:"on_failure,omitempty"`
+		Try              *json.RawMessage `json:"try,omitempty"`
+		DependentGet     *json.RawMessage `json:"dependent_get,omitempty"`
+		Timeout          *json.RawMessage `json:"timeout,omitempty"`
+		Retry            *json.RawMessage `json:"retry,omitempty"`
+		ArtifactInput    *json.RawMessage `json:"artifact_input,omitempty"`
+		ArtifactOutput   *json.RawMessage `json:"artifact_output,omitempty"`
 	}
 
 	public.ID = plan.ID
@@ -66,6 +67,10 @@ func (plan Plan) Public() *json.RawMessage {
 		public.LoadVar = plan.LoadVar.Public()
 	}
 
+	if plan.ValidatePipeline != nil {
+		public.ValidatePipeline = plan.ValidatePipeline.Public()
+	}
+
 	if plan.OnAbort != nil {
 		public.OnAbort = plan.OnAbort.Public()
 	}
@@ -294,6 +299,14 @@ func (plan LoadVarPlan) Public() *json.RawMessage {
 	})
 }
 
+func (plan ValidatePipelinePlan) Public() *json.RawMessage {
+	return enc(struct {
+		Name string `json:"name"`
+	}{
+		Name: plan.Name,
+	})
+}
+
 func (plan TimeoutPlan) Public() *json.RawMessage {
 	return enc(struct {
 		Step     *json.RawMessage `json:"step"`
@@ -9,6 +9,19 @@ type SaveConfigResponse struct {
 	Warnings []ConfigWarning `json:"warnings,omitempty"`
 }
 
+// DiffConfigResponse lists the objects that would be added, removed or
+// modified by saving a config over the current one.
+type DiffConfigResponse struct {
+	Added    []ConfigDiffEntry `json:"added"`
+	Removed  []ConfigDiffEntry `json:"removed"`
+	Modified []ConfigDiffEntry `json:"modified"`
+}
+
+type ConfigDiffEntry struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
 type ConfigResponse struct {
 	Config Config `json:"config"`
 }
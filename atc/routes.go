@@ -3,8 +3,10 @@ package atc
 import "github.com/tedsuo/rata"
 
 const (
-	SaveConfig = "SaveConfig"
-	GetConfig  = "GetConfig"
+	SaveConfig   = "SaveConfig"
+	GetConfig    = "GetConfig"
+	DiffConfig   = "DiffConfig"
+	WhatIfConfig = "WhatIfConfig"
 
 	GetBuild            = "GetBuild"
 	GetBuildPlan        = "GetBuildPlan"
@@ -12,6 +14,8 @@ const (
 	ListBuilds          = "ListBuilds"
 	BuildEvents         = "BuildEvents"
 	BuildResources      = "BuildResources"
+	GetBuildInputs      = "GetBuildInputs"
+	GetBuildAnnotations = "GetBuildAnnotations"
 	AbortBuild          = "AbortBuild"
 	GetBuildPreparation = "GetBuildPreparation"
 
@@ -53,20 +57,21 @@ const (
 
 	GetCC = "GetCC"
 
-	ListAllPipelines    = "ListAllPipelines"
-	ListPipelines       = "ListPipelines"
-	GetPipeline         = "GetPipeline"
-	DeletePipeline      = "DeletePipeline"
-	OrderPipelines      = "OrderPipelines"
-	PausePipeline       = "PausePipeline"
-	ArchivePipeline     = "ArchivePipeline"
-	UnpausePipeline     = "UnpausePipeline"
-	ExposePipeline      = "ExposePipeline"
-	HidePipeline        = "HidePipeline"
-	RenamePipeline      = "RenamePipeline"
-	ListPipelineBuilds  = "ListPipelineBuilds"
-	CreatePipelineBuild = "CreatePipelineBuild"
-	PipelineBadge       = "PipelineBadge"
+	ListAllPipelines     = "ListAllPipelines"
+	ListPipelines        = "ListPipelines"
+	GetPipeline          = "GetPipeline"
+	DeletePipeline       = "DeletePipeline"
+	OrderPipelines       = "OrderPipelines"
+	PausePipeline        = "PausePipeline"
+	ArchivePipeline      = "ArchivePipeline"
+	UnpausePipeline      = "UnpausePipeline"
+	ExposePipeline       = "ExposePipeline"
+	HidePipeline         = "HidePipeline"
+	RenamePipeline       = "RenamePipeline"
+	ListPipelineBuilds   = "ListPipelineBuilds"
+	CreatePipelineBuild  = "CreatePipelineBuild"
+	PipelineBadge        = "PipelineBadge"
+	GetPipelineOwnership = "GetPipelineOwnership"
 
 	RegisterWorker  = "RegisterWorker"
 	LandWorker      = "LandWorker"
@@ -120,6 +125,8 @@ const (
 var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config", Method: "PUT", Name: SaveConfig},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config", Method: "GET", Name: GetConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/diff", Method: "POST", Name: DiffConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/what-if", Method: "POST", Name: WhatIfConfig},
 
 	{Path: "/api/v1/teams/:team_name/builds", Method: "POST", Name: CreateBuild},
 
@@ -128,6 +135,8 @@ var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/builds/:build_id/plan", Method: "GET", Name: GetBuildPlan},
 	{Path: "/api/v1/builds/:build_id/events", Method: "GET", Name: BuildEvents},
 	{Path: "/api/v1/builds/:build_id/resources", Method: "GET", Name: BuildResources},
+	{Path: "/api/v1/builds/:build_id/inputs", Method: "GET", Name: GetBuildInputs},
+	{Path: "/api/v1/builds/:build_id/annotations", Method: "GET", Name: GetBuildAnnotations},
 	{Path: "/api/v1/builds/:build_id/abort", Method: "PUT", Name: AbortBuild},
 	{Path: "/api/v1/builds/:build_id/preparation", Method: "GET", Name: GetBuildPreparation},
 	{Path: "/api/v1/builds/:build_id/artifacts", Method: "GET", Name: ListBuildArtifacts},
@@ -163,6 +172,7 @@ var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/builds", Method: "GET", Name: ListPipelineBuilds},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/builds", Method: "POST", Name: CreatePipelineBuild},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/badge", Method: "GET", Name: PipelineBadge},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/ownership", Method: "GET", Name: GetPipelineOwnership},
 
 	{Path: "/api/v1/resources", Method: "GET", Name: ListAllResources},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources", Method: "GET", Name: ListResources},
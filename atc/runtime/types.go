@@ -52,13 +52,26 @@ func (art CacheArtifact) ID() string {
 
 // TODO (Krishna/Sameer): get rid of these - can GetArtifact and TaskArtifact be merged ?
 type GetArtifact struct {
-	VolumeHandle string
+	VolumeHandle    string
+	ResourceVersion atc.Version
 }
 
 func (art GetArtifact) ID() string {
 	return art.VolumeHandle
 }
 
+// Version returns the version of the resource fetched into the artifact.
+func (art GetArtifact) Version() atc.Version {
+	return art.ResourceVersion
+}
+
+// VersionedArtifact is an Artifact holding a version of a resource, whose
+// contents do not change for as long as the version does not.
+type VersionedArtifact interface {
+	Artifact
+	Version() atc.Version
+}
+
 type TaskArtifact struct {
 	VolumeHandle string
 }
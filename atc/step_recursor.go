@@ -22,6 +22,13 @@ type StepRecursor struct {
 
 	// OnLoadVar will be invoked for any *LoadVarStep present in the StepConfig.
 	OnLoadVar func(*LoadVarStep) error
+
+	// OnValidatePipeline will be invoked for any *ValidatePipelineStep present in the StepConfig.
+	OnValidatePipeline func(*ValidatePipelineStep) error
+
+	// OnAcross will be invoked for any *AcrossStep present in the StepConfig,
+	// before recursing through to the wrapped step.
+	OnAcross func(*AcrossStep) error
 }
 
 // VisitTask calls the OnTask hook if configured.
@@ -69,6 +76,15 @@ func (recursor StepRecursor) VisitLoadVar(step *LoadVarStep) error {
 	return nil
 }
 
+// VisitValidatePipeline calls the OnValidatePipeline hook if configured.
+func (recursor StepRecursor) VisitValidatePipeline(step *ValidatePipelineStep) error {
+	if recursor.OnValidatePipeline != nil {
+		return recursor.OnValidatePipeline(step)
+	}
+
+	return nil
+}
+
 // VisitTry recurses through to the wrapped step.
 func (recursor StepRecursor) VisitTry(step *TryStep) error {
 	return step.Step.Config.Visit(recursor)
@@ -98,8 +114,16 @@ func (recursor StepRecursor) VisitInParallel(step *InParallelStep) error {
 	return nil
 }
 
-// VisitAcross recurses through to the wrapped step.
+// VisitAcross calls the OnAcross hook if configured and recurses through to
+// the wrapped step.
 func (recursor StepRecursor) VisitAcross(step *AcrossStep) error {
+	if recursor.OnAcross != nil {
+		err := recursor.OnAcross(step)
+		if err != nil {
+			return err
+		}
+	}
+
 	return step.Step.Visit(recursor)
 }
 
@@ -208,6 +208,25 @@ func (validator *StepValidator) VisitSetPipeline(step *SetPipelineStep) error {
 	return nil
 }
 
+func (validator *StepValidator) VisitValidatePipeline(step *ValidatePipelineStep) error {
+	validator.pushContext(".validate_pipeline(%s)", step.Name)
+	defer validator.popContext()
+
+	warning, err := ValidateIdentifier(step.Name, validator.context...)
+	if err != nil {
+		validator.recordError(err.Error())
+	}
+	if warning != nil {
+		validator.recordWarning(*warning)
+	}
+
+	if step.File == "" {
+		validator.recordError("no file specified")
+	}
+
+	return nil
+}
+
 func (validator *StepValidator) VisitLoadVar(step *LoadVarStep) error {
 	validator.pushContext(".load_var(%s)", step.Name)
 	defer validator.popContext()
@@ -396,6 +415,91 @@ func (validator *StepValidator) VisitEnsure(step *EnsureStep) error {
 	return validator.Validate(step.Hook)
 }
 
+// ValidateUniqueStepNames returns a DuplicateStepNameError if more than one
+// step within the step (including steps nested in do, try, in_parallel and
+// hooks) has the same name. Names shared only by the get and put steps of a
+// resource are allowed, and names repeated only by load_var steps are left to
+// the step validator, which already reports them.
+func ValidateUniqueStepNames(step Step) error {
+	var names []string
+	kinds := map[string]map[string]int{}
+
+	record := func(kind string, name string) {
+		if kinds[name] == nil {
+			names = append(names, name)
+			kinds[name] = map[string]int{}
+		}
+
+		kinds[name][kind]++
+	}
+
+	_ = step.Config.Visit(StepRecursor{
+		OnGet: func(step *GetStep) error {
+			record("get", step.Name)
+			return nil
+		},
+		OnPut: func(step *PutStep) error {
+			record("put", step.Name)
+			return nil
+		},
+		OnTask: func(step *TaskStep) error {
+			record("task", step.Name)
+			return nil
+		},
+		OnSetPipeline: func(step *SetPipelineStep) error {
+			record("set_pipeline", step.Name)
+			return nil
+		},
+		OnLoadVar: func(step *LoadVarStep) error {
+			record("load_var", step.Name)
+			return nil
+		},
+		OnValidatePipeline: func(step *ValidatePipelineStep) error {
+			record("validate_pipeline", step.Name)
+			return nil
+		},
+	})
+
+	var duplicates []string
+	for _, name := range names {
+		if stepNameRepeated(kinds[name]) {
+			duplicates = append(duplicates, name)
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return DuplicateStepNameError{Names: duplicates}
+	}
+
+	return nil
+}
+
+func stepNameRepeated(kinds map[string]int) bool {
+	resourceSteps := true
+	for kind := range kinds {
+		if kind != "get" && kind != "put" {
+			resourceSteps = false
+		}
+	}
+
+	// gets and puts of a resource share its name
+	if resourceSteps {
+		return false
+	}
+
+	if len(kinds) > 1 {
+		return true
+	}
+
+	for kind, count := range kinds {
+		if count > 1 && kind != "load_var" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (validator *StepValidator) recordWarning(warning ConfigWarning) {
 	validator.Warnings = append(validator.Warnings, warning)
 }
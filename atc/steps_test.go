@@ -136,6 +136,74 @@ var factoryTests = []StepTest{
 			vars: {some: vars}
 			var_files: [file-1, file-2]
 			instance_vars: {branch: feature/foo}
+			skip_reachability_check: true
+			trigger_checks: true
+			inherit_pinned_versions: true
+			lock_resource_type_versions: true
+			pin_build_inputs: [some-input]
+			template_engine: go-template
+			cleanup_on_failure: true
+			archive_unlisted: true
+			managed_prefix: team-
+			extends: base-pipeline
+			display: {background_image: "https://example.com/image.png"}
+			slack_webhook: https://hooks.slack.com/services/some-hook
+			slack_channel: "#infra"
+			freeze: true
+			max_parse_retries: 2
+			rename_from: some-old-pipeline
+			archive_old: true
+			strategic_merge_files: ["some-resource/overlay.yml"]
+			post_save_sleep: 2s
+			min_atc_version: 7.0.0
+			when: "((build_branch)) == 'main'"
+			preserve_resource_history: true
+			team_var: target_team
+			canary_jobs: [job-a, job-b]
+			trigger_checks_jitter: 30s
+			clone_from: base-pipeline
+			apply_jobs: [job-a, job-b]
+			target: {url: "https://ci.example.com", team: main, token_var: TOKEN}
+			wait_for_success: true
+			wait_timeout: 10m
+			rollback_on_error: true
+			rollback_window: 5m
+			banned_patterns: [TODO, FIXME]
+			reset_build_history: true
+			merge_strategy: {jobs: replace, resources: upsert, groups: union}
+			encrypt_fields: ["resources[*].source.password"]
+			lint_image: "openpolicyagent/conftest"
+			lint_policy_file: "policies/pipeline.rego"
+			notifications: {on_failure: ["email:ops@example.com"]}
+			vault_dynamic_vars: [{path: "secret/data/db", keys: ["password"]}]
+			check_image_resource_types: true
+			min_jobs: 1
+			notify_event_bus: true
+			apply_resources: [git-repo, docker-image]
+			diff_format: summary
+			github_deployment: {owner: org, repo: repo, environment: production, token_var: GH_TOKEN}
+			diff_output: my-resource/pipeline.diff
+			scope: build
+			var_precedence: files_first
+			check_image_tags: true
+			feature_flag_check: true
+			sbom: true
+			name_pattern: ^[a-z][a-z0-9-]{3,63}$
+			prefetch_var_files: true
+			force_credential_refresh: true
+			trigger_first_job: true
+			label_resources: true
+			pre_validate_script: scripts/validate.sh
+			pre_validate_image_resource:
+			  type: registry-image
+			  source: {repository: busybox}
+			max_parallel_jobs: 10
+			create_team_if_missing: true
+			queue_depth_check: true
+			queue_depth_threshold: 20
+			description: Deploys the API service
+			owners: {team: platform-eng, contact: platform@example.com}
+			archive_after: 30d
 		`,
 
 		StepConfig: &atc.SetPipelineStep{
@@ -144,6 +212,81 @@ var factoryTests = []StepTest{
 			Vars:         atc.Params{"some": "vars"},
 			VarFiles:     []string{"file-1", "file-2"},
 			InstanceVars: atc.InstanceVars{"branch": "feature/foo"},
+
+			SkipReachabilityCheck:    true,
+			TriggerChecks:            true,
+			InheritPinnedVersions:    true,
+			LockResourceTypeVersions: true,
+			PinBuildInputs:           []string{"some-input"},
+			TemplateEngine:           "go-template",
+			CleanupOnFailure:         true,
+			ArchiveUnlisted:          true,
+			ManagedPrefix:            "team-",
+			Extends:                  "base-pipeline",
+			Display:                  &atc.DisplayConfig{BackgroundImage: "https://example.com/image.png"},
+			SlackWebhook:             "https://hooks.slack.com/services/some-hook",
+			SlackChannel:             "#infra",
+			Freeze:                   true,
+			MaxParseRetries:          2,
+			RenameFrom:               "some-old-pipeline",
+			ArchiveOld:               true,
+			StrategicMergeFiles:      []string{"some-resource/overlay.yml"},
+			PostSaveSleep:            "2s",
+			MinATCVersion:            "7.0.0",
+			When:                     "((build_branch)) == 'main'",
+			PreserveResourceHistory:  true,
+			TeamVar:                  "target_team",
+			CanaryJobs:               []string{"job-a", "job-b"},
+			TriggerChecksJitter:      "30s",
+			CloneFrom:                "base-pipeline",
+			ApplyJobs:                []string{"job-a", "job-b"},
+			Target:                   &atc.SetPipelineTarget{URL: "https://ci.example.com", Team: "main", TokenVar: "TOKEN"},
+			WaitForSuccess:           true,
+			WaitTimeout:              "10m",
+			RollbackOnError:          true,
+			RollbackWindow:           "5m",
+			BannedPatterns:           []string{"TODO", "FIXME"},
+			ResetBuildHistory:        true,
+			MergeStrategy:            &atc.MergeStrategy{Jobs: atc.MergeReplace, Resources: atc.MergeUpsert, Groups: atc.MergeUnion},
+			EncryptFields:            []string{"resources[*].source.password"},
+			LintImage:                "openpolicyagent/conftest",
+			LintPolicyFile:           "policies/pipeline.rego",
+			Notifications:            &atc.PipelineNotifications{OnFailure: []string{"email:ops@example.com"}},
+			VaultDynamicVars:         []atc.VaultDynamicVar{{Path: "secret/data/db", Keys: []string{"password"}}},
+			CheckImageResourceTypes:  true,
+			MinJobs:                  1,
+			NotifyEventBus:           true,
+			ApplyResources:           []string{"git-repo", "docker-image"},
+			DiffFormat:               "summary",
+			GitHubDeployment: &atc.GitHubDeployment{
+				Owner:       "org",
+				Repo:        "repo",
+				Environment: "production",
+				TokenVar:    "GH_TOKEN",
+			},
+			DiffOutput:             "my-resource/pipeline.diff",
+			Scope:                  "build",
+			VarPrecedence:          "files_first",
+			CheckImageTags:         true,
+			FeatureFlagCheck:       true,
+			SBOM:                   true,
+			NamePattern:            "^[a-z][a-z0-9-]{3,63}$",
+			PrefetchVarFiles:       true,
+			ForceCredentialRefresh: true,
+			TriggerFirstJob:        true,
+			LabelResources:         true,
+			PreValidateScript:      "scripts/validate.sh",
+			PreValidateImage:       &atc.ImageResource{Type: "registry-image", Source: atc.Source{"repository": "busybox"}},
+			MaxParallelJobs:        10,
+			CreateTeamIfMissing:    true,
+			QueueDepthCheck:        true,
+			QueueDepthThreshold:    20,
+			Description:            "Deploys the API service",
+			Owners: &atc.PipelineOwnership{
+				Team:    "platform-eng",
+				Contact: "platform@example.com",
+			},
+			ArchiveAfter: "30d",
 		},
 	},
 	{
@@ -163,6 +306,24 @@ var factoryTests = []StepTest{
 			Reveal: true,
 		},
 	},
+	{
+		Title: "validate_pipeline step",
+
+		ConfigYAML: `
+			validate_pipeline: some-pipeline
+			file: some-input/pipeline.yml
+			var_files: [some-input/vars.yml]
+			vars:
+			  some: var
+		`,
+
+		StepConfig: &atc.ValidatePipelineStep{
+			Name:     "some-pipeline",
+			File:     "some-input/pipeline.yml",
+			VarFiles: []string{"some-input/vars.yml"},
+			Vars:     map[string]interface{}{"some": "var"},
+		},
+	},
 	{
 		Title: "try step",
 
@@ -29,8 +29,9 @@ func (auth TeamAuth) Validate() error {
 	for _, config := range auth {
 		users := config["users"]
 		groups := config["groups"]
+		teams := config["teams"]
 
-		if len(users) == 0 && len(groups) == 0 {
+		if len(users) == 0 && len(groups) == 0 && len(teams) == 0 {
 			return ErrAuthConfigInvalid
 		}
 	}
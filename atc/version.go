@@ -0,0 +1,9 @@
+package atc
+
+import "github.com/concourse/concourse"
+
+// RunningVersion returns the version of the running ATC. It can't be called
+// Version as that name is taken by the resource version type.
+func RunningVersion() string {
+	return concourse.Version
+}
@@ -121,7 +121,10 @@ func (s *fetchSource) Find() (GetResult, Volume, bool, error) {
 				Version:  s.cache.Version(),
 				Metadata: atcMetaData,
 			},
-			GetArtifact: runtime.GetArtifact{VolumeHandle: volume.Handle()},
+			GetArtifact: runtime.GetArtifact{
+				VolumeHandle:    volume.Handle(),
+				ResourceVersion: s.cache.Version(),
+			},
 		},
 		volume, true, nil
 }
@@ -194,7 +197,8 @@ func (s *fetchSource) Create(ctx context.Context) (GetResult, Volume, error) {
 		ExitStatus:    0,
 		VersionResult: vr,
 		GetArtifact: runtime.GetArtifact{
-			VolumeHandle: volume.Handle(),
+			VolumeHandle:    volume.Handle(),
+			ResourceVersion: s.cache.Version(),
 		},
 	}, volume, nil
 }
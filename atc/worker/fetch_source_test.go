@@ -75,6 +75,7 @@ var _ = Describe("FetchSource", func() {
 		fakeResourceCacheFactory = new(dbfakes.FakeResourceCacheFactory)
 		fakeUsedResourceCache = new(dbfakes.FakeUsedResourceCache)
 		fakeUsedResourceCache.IDReturns(42)
+		fakeUsedResourceCache.VersionReturns(atc.Version{"some": "version"})
 		fakeResourceCacheFactory.FindOrCreateResourceCacheReturns(fakeUsedResourceCache, nil)
 
 		owner = db.NewBuildStepContainerOwner(43, atc.PlanID("some-plan-id"), 42)
@@ -129,8 +130,11 @@ var _ = Describe("FetchSource", func() {
 				}
 				expectedGetResult = worker.GetResult{
 					ExitStatus:    0,
-					VersionResult: runtime.VersionResult{Metadata: expectedMetadata},
-					GetArtifact:   runtime.GetArtifact{VolumeHandle: fakeVolume.Handle()},
+					VersionResult: runtime.VersionResult{Version: atc.Version{"some": "version"}, Metadata: expectedMetadata},
+					GetArtifact: runtime.GetArtifact{
+						VolumeHandle:    fakeVolume.Handle(),
+						ResourceVersion: atc.Version{"some": "version"},
+					},
 				}
 			})
 
@@ -180,8 +184,11 @@ var _ = Describe("FetchSource", func() {
 				}
 				expectedGetResult = worker.GetResult{
 					ExitStatus:    0,
-					VersionResult: runtime.VersionResult{Metadata: expectedMetadata},
-					GetArtifact:   runtime.GetArtifact{VolumeHandle: fakeVolume.Handle()},
+					VersionResult: runtime.VersionResult{Version: atc.Version{"some": "version"}, Metadata: expectedMetadata},
+					GetArtifact: runtime.GetArtifact{
+						VolumeHandle:    fakeVolume.Handle(),
+						ResourceVersion: atc.Version{"some": "version"},
+					},
 				}
 			})
 
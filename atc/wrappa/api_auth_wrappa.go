@@ -47,6 +47,8 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 		case atc.GetBuildPreparation,
 			atc.BuildEvents,
 			atc.GetBuildPlan,
+			atc.GetBuildInputs,
+			atc.GetBuildAnnotations,
 			atc.ListBuildArtifacts:
 			newHandler = wrappa.checkBuildReadAccessHandlerFactory.CheckIfPrivateJobHandler(handler, rejector)
 
@@ -68,6 +70,7 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 		case atc.GetPipeline,
 			atc.GetJobBuild,
 			atc.PipelineBadge,
+			atc.GetPipelineOwnership,
 			atc.JobBadge,
 			atc.ListJobs,
 			atc.GetJob,
@@ -137,6 +140,8 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			atc.UnpinResource,
 			atc.SetPinCommentOnResource,
 			atc.GetConfig,
+			atc.DiffConfig,
+			atc.WhatIfConfig,
 			atc.GetCC,
 			atc.GetVersionsDB,
 			atc.ListJobInputs,
@@ -42,8 +42,12 @@ func (rw *RejectArchivedWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			// leave the handler as-is
 		case
 			atc.GetConfig,
+			atc.DiffConfig,
+			atc.WhatIfConfig,
 			atc.GetBuild,
 			atc.BuildResources,
+			atc.GetBuildInputs,
+			atc.GetBuildAnnotations,
 			atc.BuildEvents,
 			atc.ListBuildArtifacts,
 			atc.GetBuildPreparation,
@@ -59,6 +63,7 @@ func (rw *RejectArchivedWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			atc.GetPipeline,
 			atc.GetJobBuild,
 			atc.PipelineBadge,
+			atc.GetPipelineOwnership,
 			atc.JobBadge,
 			atc.ListJobs,
 			atc.GetJob,
@@ -95,6 +95,7 @@ func (command *PipelinesCommand) Execute([]string) error {
 			row = append(row, archivedColumn)
 		}
 		row = append(row, ui.TableCell{Contents: time.Unix(p.LastUpdated, 0).String()})
+		row = append(row, ui.TableCell{Contents: p.Description})
 
 		table.Data = append(table.Data, row)
 	}
@@ -113,7 +114,7 @@ func (command *PipelinesCommand) buildHeader() []string {
 	if command.IncludeArchived {
 		headers = append(headers, "archived")
 	}
-	headers = append(headers, "last updated")
+	headers = append(headers, "last updated", "description")
 
 	return headers
 }
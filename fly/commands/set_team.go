@@ -101,6 +101,14 @@ func (command *SetTeamCommand) Execute([]string) error {
 		} else {
 			fmt.Printf("    %s\n", ui.OffColor.Sprint("none"))
 		}
+
+		if authTeams := authRoles[role]["teams"]; len(authTeams) > 0 {
+			fmt.Println()
+			fmt.Printf("  teams:\n")
+			for _, team := range authTeams {
+				fmt.Printf("  - %s\n", team)
+			}
+		}
 	}
 
 	if len(warnings) > 0 {
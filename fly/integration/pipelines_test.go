@@ -35,7 +35,7 @@ var _ = Describe("Fly CLI", func() {
 						ghttp.CombineHandlers(
 							ghttp.VerifyRequest("GET", "/api/v1/teams/main/pipelines"),
 							ghttp.RespondWithJSONEncoded(200, []atc.Pipeline{
-								{ID: 1, Name: "pipeline-1-longer", Paused: false, Public: false, LastUpdated: 1},
+								{ID: 1, Name: "pipeline-1-longer", Paused: false, Public: false, LastUpdated: 1, Description: "Deploys the API service"},
 								{ID: 2, Name: "pipeline-2", Paused: true, Public: false, LastUpdated: 1},
 								{ID: 3, Name: "pipeline-3", Paused: false, Public: true, LastUpdated: 1},
 								{ID: 4, Name: "archived-pipeline", Paused: false, Archived: true, Public: true, LastUpdated: 1},
@@ -62,7 +62,8 @@ var _ = Describe("Fly CLI", func() {
                   "public": false,
                   "archived": false,
                   "team_name": "",
-                  "last_updated": 1
+                  "last_updated": 1,
+                  "description": "Deploys the API service"
                 },
                 {
                   "id": 2,
@@ -98,11 +99,12 @@ var _ = Describe("Fly CLI", func() {
 							{Contents: "paused", Color: color.New(color.Bold)},
 							{Contents: "public", Color: color.New(color.Bold)},
 							{Contents: "last updated", Color: color.New(color.Bold)},
+							{Contents: "description", Color: color.New(color.Bold)},
 						},
 						Data: []ui.TableRow{
-							{{Contents: "1"}, {Contents: "pipeline-1-longer"}, {Contents: "no"}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "2"}, {Contents: "pipeline-2"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "3"}, {Contents: "pipeline-3"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}},
+							{{Contents: "1"}, {Contents: "pipeline-1-longer"}, {Contents: "no"}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}, {Contents: "Deploys the API service"}},
+							{{Contents: "2"}, {Contents: "pipeline-2"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
+							{{Contents: "3"}, {Contents: "pipeline-3"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
 						},
 					}))
 				})
@@ -208,13 +210,14 @@ var _ = Describe("Fly CLI", func() {
 							{Contents: "paused", Color: color.New(color.Bold)},
 							{Contents: "public", Color: color.New(color.Bold)},
 							{Contents: "last updated", Color: color.New(color.Bold)},
+							{Contents: "description", Color: color.New(color.Bold)},
 						},
 						Data: []ui.TableRow{
-							{{Contents: "1"}, {Contents: "pipeline-1-longer"}, {Contents: "main"}, {Contents: "no"}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "2"}, {Contents: "pipeline-2"}, {Contents: "main"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "3"}, {Contents: "pipeline-3"}, {Contents: "main"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "5"}, {Contents: "foreign-pipeline-1"}, {Contents: "other"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "6"}, {Contents: "foreign-pipeline-2"}, {Contents: "other"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}},
+							{{Contents: "1"}, {Contents: "pipeline-1-longer"}, {Contents: "main"}, {Contents: "no"}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
+							{{Contents: "2"}, {Contents: "pipeline-2"}, {Contents: "main"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
+							{{Contents: "3"}, {Contents: "pipeline-3"}, {Contents: "main"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
+							{{Contents: "5"}, {Contents: "foreign-pipeline-1"}, {Contents: "other"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
+							{{Contents: "6"}, {Contents: "foreign-pipeline-2"}, {Contents: "other"}, {Contents: "no"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
 						},
 					}))
 				})
@@ -256,10 +259,11 @@ var _ = Describe("Fly CLI", func() {
 							{Contents: "public", Color: color.New(color.Bold)},
 							{Contents: "archived", Color: color.New(color.Bold)},
 							{Contents: "last updated", Color: color.New(color.Bold)},
+							{Contents: "description", Color: color.New(color.Bold)},
 						},
 						Data: []ui.TableRow{
-							{{Contents: "1"}, {Contents: "pipeline-1-longer"}, {Contents: "no"}, {Contents: "no"}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}},
-							{{Contents: "2"}, {Contents: "archived-pipeline"}, {Contents: "yes"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: "yes"}, {Contents: time.Unix(1, 0).String()}},
+							{{Contents: "1"}, {Contents: "pipeline-1-longer"}, {Contents: "no"}, {Contents: "no"}, {Contents: "no"}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
+							{{Contents: "2"}, {Contents: "archived-pipeline"}, {Contents: "yes"}, {Contents: "yes", Color: color.New(color.FgCyan)}, {Contents: "yes"}, {Contents: time.Unix(1, 0).String()}, {Contents: ""}},
 						},
 					}))
 				})
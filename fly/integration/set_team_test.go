@@ -122,6 +122,32 @@ var _ = Describe("Fly CLI", func() {
 				})
 			})
 
+			Context("Granting a role to other teams", func() {
+				BeforeEach(func() {
+					cmdParams = []string{"-c", "fixtures/team_config_with_team_grants.yml"}
+				})
+
+				It("shows the teams granted the role", func() {
+					sess, err := gexec.Start(flyCmd, ginkgo.GinkgoWriter, ginkgo.GinkgoWriter)
+					Expect(err).ToNot(HaveOccurred())
+
+					Eventually(sess.Out).Should(gbytes.Say("setting team: venture"))
+
+					Eventually(sess.Out).Should(gbytes.Say("role owner:"))
+					Eventually(sess.Out).Should(gbytes.Say("- local:some-owner"))
+
+					Eventually(sess.Out).Should(gbytes.Say("role pipeline-operator:"))
+					Eventually(sess.Out).Should(gbytes.Say("users:"))
+					Eventually(sess.Out).Should(gbytes.Say("none"))
+					Eventually(sess.Out).Should(gbytes.Say("groups:"))
+					Eventually(sess.Out).Should(gbytes.Say("none"))
+					Eventually(sess.Out).Should(gbytes.Say("teams:"))
+					Eventually(sess.Out).Should(gbytes.Say("- some-team"))
+
+					Eventually(sess).Should(gexec.Exit(1))
+				})
+			})
+
 			Context("Setting github auth", func() {
 				BeforeEach(func() {
 					cmdParams = []string{"-c", "fixtures/team_config_with_github_auth.yml"}
@@ -403,6 +429,52 @@ var _ = Describe("Fly CLI", func() {
 			})
 		})
 
+		Describe("sending team grants", func() {
+			BeforeEach(func() {
+				cmdParams = []string{"-c", "fixtures/team_config_with_team_grants.yml"}
+
+				atcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/api/v1/teams/venture"),
+						ghttp.VerifyJSON(`{
+							"auth": {
+								"owner":{
+									"users": [
+										"local:some-owner"
+									],
+									"groups": []
+								},
+								"pipeline-operator":{
+									"users": [],
+									"groups": [],
+									"teams": [
+										"some-team"
+									]
+								}
+							}
+						}`),
+						ghttp.RespondWithJSONEncoded(http.StatusCreated, atc.Team{
+							Name: "venture",
+							ID:   8,
+						}),
+					),
+				)
+			})
+
+			It("sends the teams granted each role", func() {
+				stdin, err := flyCmd.StdinPipe()
+				Expect(err).NotTo(HaveOccurred())
+
+				sess, err := gexec.Start(flyCmd, ginkgo.GinkgoWriter, ginkgo.GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+
+				Eventually(sess).Should(gbytes.Say(`apply team configuration\? \[yN\]: `))
+				yes(stdin)
+
+				Eventually(sess).Should(gexec.Exit(0))
+			})
+		})
+
 		Describe("handling server response", func() {
 			BeforeEach(func() {
 				cmdParams = []string{"-c", "fixtures/team_config_mixed.yml"}
@@ -277,6 +277,20 @@ type FakeTeam struct {
 	destroyTeamReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DiffPipelineConfigStub        func(atc.PipelineRef, []byte) (atc.DiffConfigResponse, error)
+	diffPipelineConfigMutex       sync.RWMutex
+	diffPipelineConfigArgsForCall []struct {
+		arg1 atc.PipelineRef
+		arg2 []byte
+	}
+	diffPipelineConfigReturns struct {
+		result1 atc.DiffConfigResponse
+		result2 error
+	}
+	diffPipelineConfigReturnsOnCall map[int]struct {
+		result1 atc.DiffConfigResponse
+		result2 error
+	}
 	DisableResourceVersionStub        func(atc.PipelineRef, string, int) (bool, error)
 	disableResourceVersionMutex       sync.RWMutex
 	disableResourceVersionArgsForCall []struct {
@@ -1958,6 +1972,76 @@ func (fake *FakeTeam) DestroyTeamReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeTeam) DiffPipelineConfig(arg1 atc.PipelineRef, arg2 []byte) (atc.DiffConfigResponse, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.diffPipelineConfigMutex.Lock()
+	ret, specificReturn := fake.diffPipelineConfigReturnsOnCall[len(fake.diffPipelineConfigArgsForCall)]
+	fake.diffPipelineConfigArgsForCall = append(fake.diffPipelineConfigArgsForCall, struct {
+		arg1 atc.PipelineRef
+		arg2 []byte
+	}{arg1, arg2Copy})
+	stub := fake.DiffPipelineConfigStub
+	fakeReturns := fake.diffPipelineConfigReturns
+	fake.recordInvocation("DiffPipelineConfig", []interface{}{arg1, arg2Copy})
+	fake.diffPipelineConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTeam) DiffPipelineConfigCallCount() int {
+	fake.diffPipelineConfigMutex.RLock()
+	defer fake.diffPipelineConfigMutex.RUnlock()
+	return len(fake.diffPipelineConfigArgsForCall)
+}
+
+func (fake *FakeTeam) DiffPipelineConfigCalls(stub func(atc.PipelineRef, []byte) (atc.DiffConfigResponse, error)) {
+	fake.diffPipelineConfigMutex.Lock()
+	defer fake.diffPipelineConfigMutex.Unlock()
+	fake.DiffPipelineConfigStub = stub
+}
+
+func (fake *FakeTeam) DiffPipelineConfigArgsForCall(i int) (atc.PipelineRef, []byte) {
+	fake.diffPipelineConfigMutex.RLock()
+	defer fake.diffPipelineConfigMutex.RUnlock()
+	argsForCall := fake.diffPipelineConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTeam) DiffPipelineConfigReturns(result1 atc.DiffConfigResponse, result2 error) {
+	fake.diffPipelineConfigMutex.Lock()
+	defer fake.diffPipelineConfigMutex.Unlock()
+	fake.DiffPipelineConfigStub = nil
+	fake.diffPipelineConfigReturns = struct {
+		result1 atc.DiffConfigResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeam) DiffPipelineConfigReturnsOnCall(i int, result1 atc.DiffConfigResponse, result2 error) {
+	fake.diffPipelineConfigMutex.Lock()
+	defer fake.diffPipelineConfigMutex.Unlock()
+	fake.DiffPipelineConfigStub = nil
+	if fake.diffPipelineConfigReturnsOnCall == nil {
+		fake.diffPipelineConfigReturnsOnCall = make(map[int]struct {
+			result1 atc.DiffConfigResponse
+			result2 error
+		})
+	}
+	fake.diffPipelineConfigReturnsOnCall[i] = struct {
+		result1 atc.DiffConfigResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTeam) DisableResourceVersion(arg1 atc.PipelineRef, arg2 string, arg3 int) (bool, error) {
 	fake.disableResourceVersionMutex.Lock()
 	ret, specificReturn := fake.disableResourceVersionReturnsOnCall[len(fake.disableResourceVersionArgsForCall)]
@@ -4208,6 +4292,8 @@ func (fake *FakeTeam) Invocations() map[string][][]interface{} {
 	defer fake.deletePipelineMutex.RUnlock()
 	fake.destroyTeamMutex.RLock()
 	defer fake.destroyTeamMutex.RUnlock()
+	fake.diffPipelineConfigMutex.RLock()
+	defer fake.diffPipelineConfigMutex.RUnlock()
 	fake.disableResourceVersionMutex.RLock()
 	defer fake.disableResourceVersionMutex.RUnlock()
 	fake.enableResourceVersionMutex.RLock()
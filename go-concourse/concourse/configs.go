@@ -112,6 +112,57 @@ func (team *team) CreateOrUpdatePipelineConfig(pipelineRef atc.PipelineRef, conf
 	}
 }
 
+func (team *team) DiffPipelineConfig(pipelineRef atc.PipelineRef, passedConfig []byte) (atc.DiffConfigResponse, error) {
+	params := rata.Params{
+		"pipeline_name": pipelineRef.Name,
+		"team_name":     team.Name(),
+	}
+
+	response, err := team.httpAgent.Send(internal.Request{
+		ReturnResponseBody: true,
+		RequestName:        atc.DiffConfig,
+		Params:             params,
+		Query:              pipelineRef.QueryParams(),
+		Body:               bytes.NewBuffer(passedConfig),
+		Header: http.Header{
+			"Content-Type": {"application/x-yaml"},
+		},
+	})
+	if err != nil {
+		return atc.DiffConfigResponse{}, err
+	}
+
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		var diff atc.DiffConfigResponse
+		err = json.Unmarshal(body, &diff)
+		if err != nil {
+			return atc.DiffConfigResponse{}, err
+		}
+		return diff, nil
+	case http.StatusBadRequest:
+		var validationErr atc.SaveConfigResponse
+		err = json.Unmarshal(body, &validationErr)
+		if err != nil {
+			return atc.DiffConfigResponse{}, err
+		}
+		return atc.DiffConfigResponse{}, InvalidConfigError{Errors: validationErr.Errors}
+	case http.StatusForbidden:
+		return atc.DiffConfigResponse{}, internal.ForbiddenError{
+			Reason: string(body),
+		}
+	default:
+		return atc.DiffConfigResponse{}, internal.UnexpectedResponseError{
+			StatusCode: response.StatusCode,
+			Status:     response.Status,
+			Body:       string(body),
+		}
+	}
+}
+
 func merge(base, extra url.Values) url.Values {
 	if extra != nil {
 		for key, values := range extra {
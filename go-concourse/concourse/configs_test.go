@@ -395,4 +395,69 @@ var _ = Describe("ATC Handler Configs", func() {
 			})
 		})
 	})
+
+	Describe("DiffPipelineConfig", func() {
+		var (
+			returnHeader int
+			returnBody   []byte
+		)
+
+		BeforeEach(func() {
+			atcServer.RouteToHandler("POST", "/api/v1/teams/some-team/pipelines/mypipeline/diff",
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Content-Type", "application/x-yaml"),
+					ghttp.VerifyBody([]byte("some-config")),
+					func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(returnHeader)
+						w.Write(returnBody)
+					},
+				),
+			)
+		})
+
+		Context("when the ATC returns the diff", func() {
+			BeforeEach(func() {
+				returnHeader = http.StatusOK
+				returnBody = []byte(`{
+					"added": [{"type": "job", "name": "some-job"}],
+					"removed": [],
+					"modified": [{"type": "resource", "name": "some-resource"}]
+				}`)
+			})
+
+			It("returns the diff", func() {
+				diff, err := team.DiffPipelineConfig(pipelineRef, []byte("some-config"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(diff).To(Equal(atc.DiffConfigResponse{
+					Added:    []atc.ConfigDiffEntry{{Type: "job", Name: "some-job"}},
+					Removed:  []atc.ConfigDiffEntry{},
+					Modified: []atc.ConfigDiffEntry{{Type: "resource", Name: "some-resource"}},
+				}))
+			})
+		})
+
+		Context("when the config is invalid", func() {
+			BeforeEach(func() {
+				returnHeader = http.StatusBadRequest
+				returnBody = []byte(`{"errors": ["some-error"]}`)
+			})
+
+			It("returns an invalid config error", func() {
+				_, err := team.DiffPipelineConfig(pipelineRef, []byte("some-config"))
+				Expect(err).To(Equal(concourse.InvalidConfigError{Errors: []string{"some-error"}}))
+			})
+		})
+
+		Context("when the ATC returns an unexpected response", func() {
+			BeforeEach(func() {
+				returnHeader = http.StatusInternalServerError
+				returnBody = []byte("oops")
+			})
+
+			It("returns an error", func() {
+				_, err := team.DiffPipelineConfig(pipelineRef, []byte("some-config"))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
 })
@@ -133,7 +133,18 @@ func (flag *AuthTeamFlags) formatFromFile() (atc.TeamAuth, error) {
 			}
 		}
 
-		if len(users) == 0 && len(groups) == 0 {
+		// teams granted the role, e.g. `pipeline-operator`, which lets their
+		// builds set this team's pipelines
+		teams := []string{}
+		if conf, ok := role["teams"].([]interface{}); ok {
+			for _, team := range conf {
+				if name, ok := team.(string); ok && name != "" {
+					teams = append(teams, name)
+				}
+			}
+		}
+
+		if len(users) == 0 && len(groups) == 0 && len(teams) == 0 {
 			continue
 		}
 
@@ -141,6 +152,10 @@ func (flag *AuthTeamFlags) formatFromFile() (atc.TeamAuth, error) {
 			"users":  users,
 			"groups": groups,
 		}
+
+		if len(teams) > 0 {
+			auth[roleName]["teams"] = teams
+		}
 	}
 
 	if err := auth.Validate(); err != nil {
@@ -144,7 +144,7 @@ var _ = Describe("set-pipeline Step", func() {
 				execS := spawnFly("trigger-job", "-w", "-j", pipelineName+"/sp")
 				<-execS.Exited
 				Expect(execS).To(gexec.Exit(2))
-				Expect(execS.Out).To(gbytes.Say("only main team can set another team's pipeline"))
+				Expect(execS.Out).To(gbytes.Say("team " + teamName + " does not have permission to set pipelines on team main"))
 				Expect(execS.Out).To(gbytes.Say("errored"))
 			})
 
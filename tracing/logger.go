@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+// LoggerWithSpan returns a logger that includes the trace and span IDs of the
+// span carried by the context in every log line.
+//
+// If the context has no valid span (e.g. tracing is not configured), the
+// logger is returned untouched.
+func LoggerWithSpan(logger lager.Logger, ctx context.Context) lager.Logger {
+	spanContext := trace.SpanFromContext(ctx).SpanContext()
+	if !spanContext.IsValid() {
+		return logger
+	}
+
+	return logger.WithData(lager.Data{
+		"trace_id": spanContext.TraceID.String(),
+		"span_id":  spanContext.SpanID.String(),
+	})
+}
@@ -0,0 +1,62 @@
+package tracing_test
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/concourse/tracing"
+	"github.com/concourse/concourse/tracing/tracingfakes"
+	"go.opentelemetry.io/otel/api/trace"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoggerWithSpan", func() {
+	var (
+		ctx        context.Context
+		testLogger *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		testLogger = lagertest.NewTestLogger("test")
+	})
+
+	JustBeforeEach(func() {
+		tracing.LoggerWithSpan(testLogger, ctx).Info("some-message", lager.Data{"foo": "bar"})
+	})
+
+	Context("when the context has a valid span", func() {
+		var spanContext trace.SpanContext
+
+		BeforeEach(func() {
+			spanContext = trace.SpanContext{
+				TraceID: trace.ID{1},
+				SpanID:  trace.SpanID{2},
+			}
+
+			fakeSpan := new(tracingfakes.FakeSpan)
+			fakeSpan.SpanContextReturns(spanContext)
+
+			ctx = trace.ContextWithSpan(ctx, fakeSpan)
+		})
+
+		It("includes the trace and span ids in the log data", func() {
+			Expect(testLogger.Logs()).To(HaveLen(1))
+			Expect(testLogger.Logs()[0].Data).To(Equal(lager.Data{
+				"foo":      "bar",
+				"trace_id": spanContext.TraceID.String(),
+				"span_id":  spanContext.SpanID.String(),
+			}))
+		})
+	})
+
+	Context("when the context has no span", func() {
+		It("logs without any tracing data", func() {
+			Expect(testLogger.Logs()).To(HaveLen(1))
+			Expect(testLogger.Logs()[0].Data).To(Equal(lager.Data{"foo": "bar"}))
+		})
+	})
+})
@@ -40,6 +40,7 @@ type PipelineEndpoint
     | HidePipeline
     | PipelineJobsList
     | PipelineResourcesList
+    | PipelineOwnership
 
 
 type JobEndpoint
@@ -199,6 +200,9 @@ pipelineEndpoint endpoint =
 
         PipelineResourcesList ->
             [ "resources" ]
+
+        PipelineOwnership ->
+            [ "ownership" ]
     , []
     )
 
@@ -221,6 +221,16 @@ handleEvent event ( model, effects ) =
             , effects
             )
 
+        SetPipelineProgress origin message ->
+            ( updateStep origin.id (setSetPipelineProgress message) model
+            , effects
+            )
+
+        VarFileFetched _ _ _ ->
+            -- only recorded for auditing; the fetch is already reported as
+            -- set pipeline progress
+            ( model, effects )
+
         BuildStatus status _ ->
             let
                 newSt =
@@ -353,6 +363,11 @@ setSetPipelineChanged changed step =
     { step | changed = changed }
 
 
+setSetPipelineProgress : String -> Step -> Step
+setSetPipelineProgress message step =
+    { step | progress = Just message }
+
+
 view :
     { timeZone : Time.Zone, hovered : HoverState.HoverState }
     -> OutputModel
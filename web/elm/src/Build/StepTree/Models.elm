@@ -49,6 +49,7 @@ type StepTree
     | Put StepID
     | SetPipeline StepID
     | LoadVar StepID
+    | ValidatePipeline StepID
     | ArtifactInput StepID
     | ArtifactOutput StepID
     | InParallel (Array StepTree)
@@ -84,6 +85,7 @@ type alias Step =
     , version : Maybe Version
     , metadata : List MetadataField
     , changed : Bool
+    , progress : Maybe String
     , timestamps : Dict Int Time.Posix
     , initialize : Maybe Time.Posix
     , start : Maybe Time.Posix
@@ -199,6 +201,8 @@ type BuildEvent
     | StartPut Origin Time.Posix
     | FinishPut Origin Int Concourse.Version Concourse.Metadata (Maybe Time.Posix)
     | SetPipelineChanged Origin Bool
+    | SetPipelineProgress Origin String
+    | VarFileFetched Origin String String
     | Log Origin String (Maybe Time.Posix)
     | SelectedWorker Origin String (Maybe Time.Posix)
     | Error Origin String Time.Posix
@@ -271,6 +275,9 @@ activeStepIds model tree =
         LoadVar stepId ->
             [ stepId ]
 
+        ValidatePipeline stepId ->
+            [ stepId ]
+
         InParallel trees ->
             List.concatMap (activeStepIds model) (Array.toList trees)
 
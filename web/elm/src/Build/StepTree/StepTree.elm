@@ -97,6 +97,9 @@ init hl resources plan =
         Concourse.BuildStepLoadVar _ ->
             step |> initBottom hl resources plan LoadVar
 
+        Concourse.BuildStepValidatePipeline _ ->
+            step |> initBottom hl resources plan ValidatePipeline
+
         Concourse.BuildStepInParallel plans ->
             initMultiStep hl resources plan.id InParallel plans Nothing
 
@@ -277,6 +280,7 @@ constructStep { id, step } =
     , version = Nothing
     , metadata = []
     , changed = False
+    , progress = Nothing
     , timestamps = Dict.empty
     , initialize = Nothing
     , start = Nothing
@@ -505,6 +509,9 @@ viewTree session model tree depth =
         LoadVar stepId ->
             viewStep model session depth stepId
 
+        ValidatePipeline stepId ->
+            viewStep model session depth stepId
+
         Try subTree ->
             viewTree session model subTree depth
 
@@ -1104,10 +1111,14 @@ viewStepHeader step =
                             , viewKeyValuePairHeaderLabels (Dict.toList instanceVars)
                             ]
                        )
+                    ++ viewSetPipelineProgress step
 
         Concourse.BuildStepLoadVar name ->
             simpleHeader "load_var:" Nothing name
 
+        Concourse.BuildStepValidatePipeline name ->
+            simpleHeader "validate_pipeline:" Nothing name
+
         Concourse.BuildStepCheck name ->
             simpleHeader "check:" Nothing name
 
@@ -1169,6 +1180,9 @@ stepName header =
         Concourse.BuildStepLoadVar name ->
             Just name
 
+        Concourse.BuildStepValidatePipeline name ->
+            Just name
+
         Concourse.BuildStepArtifactInput name ->
             Just name
 
@@ -1218,6 +1232,19 @@ stepName header =
             Nothing
 
 
+viewSetPipelineProgress : Step -> List (Html Message)
+viewSetPipelineProgress step =
+    case ( step.state, step.progress ) of
+        ( StepStateRunning, Just message ) ->
+            [ Html.span
+                (class "set-pipeline-progress" :: Styles.setPipelineProgress)
+                [ Html.text message ]
+            ]
+
+        _ ->
+            []
+
+
 viewStepHeaderLabel : String -> Maybe String -> Bool -> StepID -> Html Message
 viewStepHeaderLabel label changedTooltip changed stepID =
     let
@@ -13,6 +13,7 @@ module Build.Styles exposing
     , metadataCell
     , metadataTable
     , retryTabList
+    , setPipelineProgress
     , stepHeader
     , stepHeaderLabel
     , stepStatusIcon
@@ -192,6 +193,14 @@ keyValuePairHeaderLabel =
     ]
 
 
+setPipelineProgress : List (Html.Attribute msg)
+setPipelineProgress =
+    [ style "color" Colors.pending
+    , style "font-weight" "normal"
+    , style "margin-left" "10px"
+    ]
+
+
 stepStatusIcon : List (Html.Attribute msg)
 stepStatusIcon =
     [ style "background-size" "14px 14px"
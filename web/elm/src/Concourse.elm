@@ -33,6 +33,7 @@ module Concourse exposing
     , PipelineGrouping(..)
     , PipelineIdentifier
     , PipelineName
+    , PipelineOwnership
     , Resource
     , ResourceIdentifier
     , Team
@@ -57,6 +58,7 @@ module Concourse exposing
     , decodeJsonValue
     , decodeMetadata
     , decodePipeline
+    , decodePipelineOwnership
     , decodeResource
     , decodeTeam
     , decodeUser
@@ -374,6 +376,9 @@ mapBuildPlan fn plan =
                 BuildStepLoadVar _ ->
                     []
 
+                BuildStepValidatePipeline _ ->
+                    []
+
                 BuildStepArtifactInput _ ->
                     []
 
@@ -433,6 +438,7 @@ type BuildStep
     = BuildStepTask StepName
     | BuildStepSetPipeline StepName InstanceVars
     | BuildStepLoadVar StepName
+    | BuildStepValidatePipeline StepName
     | BuildStepArtifactInput StepName
     | BuildStepCheck StepName
     | BuildStepGet StepName (Maybe Version)
@@ -644,6 +650,8 @@ decodeBuildPlan =
                     lazy (\_ -> decodeBuildSetPipeline)
                 , Json.Decode.field "load_var" <|
                     lazy (\_ -> decodeBuildStepLoadVar)
+                , Json.Decode.field "validate_pipeline" <|
+                    lazy (\_ -> decodeBuildStepValidatePipeline)
                 , Json.Decode.field "across" <|
                     lazy (\_ -> decodeBuildStepAcross)
                 ]
@@ -775,6 +783,12 @@ decodeBuildStepLoadVar =
         |> andMap (Json.Decode.field "name" Json.Decode.string)
 
 
+decodeBuildStepValidatePipeline : Json.Decode.Decoder BuildStep
+decodeBuildStepValidatePipeline =
+    Json.Decode.succeed BuildStepValidatePipeline
+        |> andMap (Json.Decode.field "name" Json.Decode.string)
+
+
 decodeBuildStepAcross : Json.Decode.Decoder BuildStep
 decodeBuildStepAcross =
     Json.Decode.map BuildStepAcross
@@ -1040,6 +1054,19 @@ decodePipelineGroup =
         |> andMap (defaultTo [] <| Json.Decode.field "resources" <| Json.Decode.list Json.Decode.string)
 
 
+type alias PipelineOwnership =
+    { team : String
+    , contact : String
+    }
+
+
+decodePipelineOwnership : Json.Decode.Decoder PipelineOwnership
+decodePipelineOwnership =
+    Json.Decode.succeed PipelineOwnership
+        |> andMap (defaultTo "" <| Json.Decode.field "team" Json.Decode.string)
+        |> andMap (defaultTo "" <| Json.Decode.field "contact" Json.Decode.string)
+
+
 type alias InstanceGroupIdentifier =
     { teamName : TeamName
     , name : PipelineName
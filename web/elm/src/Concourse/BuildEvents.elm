@@ -194,6 +194,23 @@ decodeBuildEvent =
                                 (Json.Decode.field "changed" Json.Decode.bool)
                             )
 
+                    "set-pipeline-progress" ->
+                        Json.Decode.field
+                            "data"
+                            (Json.Decode.map2 SetPipelineProgress
+                                (Json.Decode.field "origin" decodeOrigin)
+                                (Json.Decode.field "message" Json.Decode.string)
+                            )
+
+                    "var-file-fetched" ->
+                        Json.Decode.field
+                            "data"
+                            (Json.Decode.map3 VarFileFetched
+                                (Json.Decode.field "origin" decodeOrigin)
+                                (Json.Decode.field "path" Json.Decode.string)
+                                (Json.Decode.field "digest_hex" Json.Decode.string)
+                            )
+
                     "image-check" ->
                         Json.Decode.field "data"
                             (Json.Decode.map2 ImageCheck
@@ -27,6 +27,7 @@ type Callback
     | JobFetched (Fetched Concourse.Job)
     | JobsFetched (Fetched (List Concourse.Job))
     | PipelineFetched (Fetched Concourse.Pipeline)
+    | PipelineOwnershipFetched (Fetched Concourse.PipelineOwnership)
     | PipelinesFetched (Fetched (List Concourse.Pipeline))
     | PipelineToggled Concourse.PipelineIdentifier (Fetched ())
     | PipelinesOrdered String (Fetched ())
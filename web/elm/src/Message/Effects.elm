@@ -123,6 +123,7 @@ type Effect
     | FetchResources Concourse.PipelineIdentifier
     | FetchBuildResources Concourse.BuildId
     | FetchPipeline Concourse.PipelineIdentifier
+    | FetchPipelineOwnership Concourse.PipelineIdentifier
     | FetchPipelines String
     | FetchClusterInfo
     | FetchInputTo Concourse.VersionedResourceIdentifier
@@ -266,6 +267,12 @@ runEffect effect key csrfToken =
                 |> Api.request
                 |> Task.attempt PipelineFetched
 
+        FetchPipelineOwnership id ->
+            Api.get (Endpoints.PipelineOwnership |> Endpoints.Pipeline id)
+                |> Api.expectJson Concourse.decodePipelineOwnership
+                |> Api.request
+                |> Task.attempt PipelineOwnershipFetched
+
         FetchPipelines team ->
             Api.get (Endpoints.TeamPipelinesList |> Endpoints.Team team)
                 |> Api.expectJson (Json.Decode.list Concourse.decodePipeline)
@@ -65,6 +65,7 @@ type alias Model =
     Login.Model
         { pipelineLocator : Concourse.PipelineIdentifier
         , pipeline : WebData Concourse.Pipeline
+        , ownership : Maybe Concourse.PipelineOwnership
         , fetchedJobs : Maybe (List Concourse.Job)
         , fetchedResources : Maybe (List Concourse.Resource)
         , renderedJobs : Maybe (List Concourse.Job)
@@ -93,6 +94,7 @@ init flags =
             { turbulenceImgSrc = flags.turbulenceImgSrc
             , pipelineLocator = flags.pipelineLocator
             , pipeline = RemoteData.NotAsked
+            , ownership = Nothing
             , fetchedJobs = Nothing
             , fetchedResources = Nothing
             , renderedJobs = Nothing
@@ -108,6 +110,7 @@ init flags =
     in
     ( model
     , [ FetchPipeline flags.pipelineLocator
+      , FetchPipelineOwnership flags.pipelineLocator
       , ResetPipelineFocus
       , FetchAllPipelines
       ]
@@ -197,6 +200,12 @@ handleCallback callback ( model, effects ) =
                         , effects
                         )
 
+        PipelineOwnershipFetched (Ok ownership) ->
+            ( { model | ownership = Just ownership }, effects )
+
+        PipelineOwnershipFetched (Err _) ->
+            ( { model | ownership = Nothing }, effects )
+
         PipelineToggled _ (Ok ()) ->
             ( { model
                 | pipeline =
@@ -568,42 +577,62 @@ viewSubPage session model =
                     , Html.dd [] [ Html.text "dependency (trigger)" ]
                     ]
             , Html.table [ class "lower-right-info" ]
-                [ Html.tr []
-                    [ Html.td [ class "label" ] [ Html.text "cli:" ]
-                    , Html.td []
-                        [ Html.ul [ class "cli-downloads" ] <|
-                            List.map
-                                (\cli ->
-                                    Html.li []
-                                        [ Html.a
-                                            ([ href <| Cli.downloadUrl cli
-                                             , ariaLabel <| Cli.label cli
-                                             , download ""
-                                             ]
-                                                ++ Styles.cliIcon cli
-                                            )
-                                            []
-                                        ]
-                                )
-                                Cli.clis
-                        ]
-                    ]
-                , Html.tr []
-                    [ Html.td [ class "label" ] [ Html.text "version:" ]
-                    , Html.td []
-                        [ Html.div [ id "concourse-version" ]
-                            [ Html.text "v"
-                            , Html.span
-                                [ class "number" ]
-                                [ Html.text session.version ]
+                (viewOwnership model.ownership
+                    ++ [ Html.tr []
+                            [ Html.td [ class "label" ] [ Html.text "cli:" ]
+                            , Html.td []
+                                [ Html.ul [ class "cli-downloads" ] <|
+                                    List.map
+                                        (\cli ->
+                                            Html.li []
+                                                [ Html.a
+                                                    ([ href <| Cli.downloadUrl cli
+                                                     , ariaLabel <| Cli.label cli
+                                                     , download ""
+                                                     ]
+                                                        ++ Styles.cliIcon cli
+                                                    )
+                                                    []
+                                                ]
+                                        )
+                                        Cli.clis
+                                ]
                             ]
-                        ]
-                    ]
-                ]
+                       , Html.tr []
+                            [ Html.td [ class "label" ] [ Html.text "version:" ]
+                            , Html.td []
+                                [ Html.div [ id "concourse-version" ]
+                                    [ Html.text "v"
+                                    , Html.span
+                                        [ class "number" ]
+                                        [ Html.text session.version ]
+                                    ]
+                                ]
+                            ]
+                       ]
+                )
             ]
         ]
 
 
+viewOwnership : Maybe Concourse.PipelineOwnership -> List (Html Message)
+viewOwnership ownership =
+    case ownership of
+        Just { team, contact } ->
+            [ Html.tr [ id "pipeline-owner" ]
+                [ Html.td [ class "label" ] [ Html.text "owner:" ]
+                , Html.td [] [ Html.text team ]
+                ]
+            , Html.tr [ id "pipeline-contact" ]
+                [ Html.td [ class "label" ] [ Html.text "contact:" ]
+                , Html.td [] [ Html.text contact ]
+                ]
+            ]
+
+        Nothing ->
+            []
+
+
 viewGroupsBar : { a | hovered : HoverState.HoverState } -> Model -> Html Message
 viewGroupsBar session model =
     let
@@ -68,6 +68,12 @@ testEndpoints =
                         |> basePipelineEndpoint
                         |> toPath
                         |> Expect.equal "/api/v1/teams/team/pipelines/pipeline/resources"
+            , test "Ownership" <|
+                \_ ->
+                    E.PipelineOwnership
+                        |> basePipelineEndpoint
+                        |> toPath
+                        |> Expect.equal "/api/v1/teams/team/pipelines/pipeline/ownership"
             ]
         , test "Pipeline with instance vars" <|
             \_ ->
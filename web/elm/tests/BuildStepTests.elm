@@ -387,6 +387,13 @@ all =
                     >> when iAmLookingAtTheStepBody
                     >> then_ iSeeTheLoadVarName
             ]
+        , describe "validate_pipeline step"
+            [ test "should show pipeline name" <|
+                given iVisitABuildWithAValidatePipelineStep
+                    >> given theValidatePipelineStepIsExpanded
+                    >> when iAmLookingAtTheStepBody
+                    >> then_ iSeeTheValidatedPipelineName
+            ]
         ]
 
 
@@ -555,6 +562,12 @@ iVisitABuildWithALoadVarStep =
         >> thePlanContainsALoadVarStep
 
 
+iVisitABuildWithAValidatePipelineStep =
+    iOpenTheBuildPage
+        >> myBrowserFetchedTheBuild
+        >> thePlanContainsAValidatePipelineStep
+
+
 theGetStepIsExpanded =
     Tuple.first
         >> Application.update (Update <| Message.Click <| StepHeader "getStepId")
@@ -595,6 +608,11 @@ theLoadVarStepIsExpanded =
         >> Application.update (Update <| Message.Click <| StepHeader setLoadVarStepId)
 
 
+theValidatePipelineStepIsExpanded =
+    Tuple.first
+        >> Application.update (Update <| Message.Click <| StepHeader validatePipelineStepId)
+
+
 theAcrossStepIsExpanded =
     Tuple.first
         >> Application.update (Update <| Message.Click <| StepHeader acrossStepId)
@@ -842,6 +860,25 @@ setLoadVarStepId =
     "loadVarStep"
 
 
+thePlanContainsAValidatePipelineStep =
+    Tuple.first
+        >> Application.handleCallback
+            (Callback.PlanAndResourcesFetched 1 <|
+                Ok
+                    ( { id = validatePipelineStepId
+                      , step = Concourse.BuildStepValidatePipeline "pipeline-name"
+                      }
+                    , { inputs = []
+                      , outputs = []
+                      }
+                    )
+            )
+
+
+validatePipelineStepId =
+    "validatePipelineStep"
+
+
 acrossStepId =
     "acrossStep"
 
@@ -1166,6 +1203,10 @@ iSeeTheLoadVarName =
     Query.has [ text "var-name" ]
 
 
+iSeeTheValidatedPipelineName =
+    Query.has [ text "pipeline-name" ]
+
+
 iSeeTheVarNames =
     Query.has [ text "var1, var2" ]
 
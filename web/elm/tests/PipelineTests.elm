@@ -423,6 +423,38 @@ all =
                             )
                         |> Tuple.second
                         |> Expect.equal [ Effects.FetchClusterInfo ]
+            , test "fetches the pipeline ownership" <|
+                \_ ->
+                    Application.init
+                        flags
+                        { protocol = Url.Http
+                        , host = ""
+                        , port_ = Nothing
+                        , path = "/teams/team/pipelines/pipeline"
+                        , query = Nothing
+                        , fragment = Nothing
+                        }
+                        |> Tuple.second
+                        |> Common.contains (Effects.FetchPipelineOwnership Data.pipelineId)
+            , test "shows the pipeline's owner and contact" <|
+                \_ ->
+                    Common.init "/teams/team/pipelines/pipeline"
+                        |> Application.handleCallback
+                            (Callback.PipelineOwnershipFetched
+                                (Ok
+                                    { team = "platform-eng"
+                                    , contact = "platform@example.com"
+                                    }
+                                )
+                            )
+                        |> Tuple.first
+                        |> Common.queryView
+                        |> Expect.all
+                            [ Query.find [ id "pipeline-owner" ]
+                                >> Query.has [ text "platform-eng" ]
+                            , Query.find [ id "pipeline-contact" ]
+                                >> Query.has [ text "platform@example.com" ]
+                            ]
             , describe "Legend" <|
                 let
                     clockTick =
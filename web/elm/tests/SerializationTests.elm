@@ -86,4 +86,14 @@ all =
                     |> Concourse.encodeTeam
                     |> Json.Decode.decodeValue Concourse.decodeTeam
                     |> Expect.equal (Ok team)
+        , test "validate_pipeline plans are decoded" <|
+            \_ ->
+                """{"id": "plan", "validate_pipeline": {"name": "some-pipeline"}}"""
+                    |> Json.Decode.decodeString Concourse.decodeBuildPlan
+                    |> Expect.equal
+                        (Ok
+                            { id = "plan"
+                            , step = Concourse.BuildStepValidatePipeline "some-pipeline"
+                            }
+                        )
         ]
@@ -29,6 +29,7 @@ all =
         [ initTask
         , initSetPipeline
         , initLoadVar
+        , initValidatePipeline
         , initCheck
         , initGet
         , initPut
@@ -61,6 +62,7 @@ someVersionedStep version id buildStep state =
     , version = version
     , metadata = []
     , changed = False
+    , progress = Nothing
     , timestamps = Dict.empty
     , initialize = Nothing
     , start = Nothing
@@ -156,6 +158,29 @@ initLoadVar =
         ]
 
 
+initValidatePipeline : Test
+initValidatePipeline =
+    let
+        step =
+            BuildStepValidatePipeline "some-name"
+
+        { tree, steps } =
+            StepTree.init Routes.HighlightNothing
+                emptyResources
+                { id = "some-id"
+                , step = step
+                }
+    in
+    describe "init with ValidatePipeline"
+        [ test "the tree" <|
+            \_ ->
+                Expect.equal (Models.ValidatePipeline "some-id") tree
+        , test "the step" <|
+            \_ ->
+                assertSteps [ someStep "some-id" step Models.StepStatePending ] steps
+        ]
+
+
 initCheck : Test
 initCheck =
     let